@@ -31,6 +31,8 @@ func init() {
 	// Mark required flags
 	addCmd.MarkFlagRequired("tracking")
 	addCmd.MarkFlagRequired("carrier")
+
+	addCmd.RegisterFlagCompletionFunc("carrier", completeCarriers)
 }
 
 func runAdd(cmd *cobra.Command, args []string) error {
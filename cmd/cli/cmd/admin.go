@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	cliapi "package-tracking/internal/cli"
+	"package-tracking/internal/services"
+)
+
+var adminCmd = &cobra.Command{
+	Use:   "admin",
+	Short: "Manage the server's tracking updater",
+	Long: `Administrative commands backed by the server's /api/admin endpoints. They need the
+server's admin API key (ADMIN_API_KEY on the server), passed with --api-key,
+PACKAGE_TRACKER_API_KEY or api_key in the config file, unless the server runs with
+DISABLE_ADMIN_AUTH=true.
+
+Failures exit with the usual exit codes, so the commands can be used from scripts and cron:
+
+  package-tracker admin pause && run-maintenance && package-tracker admin resume`,
+}
+
+var adminStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the tracking updater's status",
+	Long: `Show whether the tracking updater is running or paused, when it last ran and will run
+next, and per-carrier statistics. With --quiet only "running", "paused" or "stopped" is printed.`,
+	Args: cobra.NoArgs,
+	RunE: runAdminStatus,
+}
+
+var adminPauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Pause automatic tracking updates",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAdminAction((*cliapi.Client).PauseUpdater)
+	},
+}
+
+var adminResumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Resume automatic tracking updates",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAdminAction((*cliapi.Client).ResumeUpdater)
+	},
+}
+
+var adminEnhanceCmd = &cobra.Command{
+	Use:   "enhance-descriptions",
+	Short: "Improve shipment descriptions on the server using linked emails",
+	Long: `Ask the server to improve poor shipment descriptions using the content of linked emails.
+Unlike the top-level enhance-descriptions command, this runs on the server, so it doesn't need
+access to the database or LLM settings.
+
+Without --shipment-id every shipment with a poor description is processed.`,
+	Args: cobra.NoArgs,
+	RunE: runAdminEnhance,
+}
+
+var (
+	adminEnhanceShipmentID int
+	adminEnhanceLimit      int
+	adminEnhanceDryRun     bool
+	adminEnhanceAssociate  bool
+)
+
+func init() {
+	rootCmd.AddCommand(adminCmd)
+	adminCmd.AddCommand(adminStatusCmd, adminPauseCmd, adminResumeCmd, adminEnhanceCmd)
+
+	adminEnhanceCmd.Flags().IntVar(&adminEnhanceShipmentID, "shipment-id", 0, "Process a specific shipment by ID")
+	adminEnhanceCmd.Flags().IntVar(&adminEnhanceLimit, "limit", 0, "Limit number of shipments to process (0 = no limit)")
+	adminEnhanceCmd.Flags().BoolVar(&adminEnhanceDryRun, "dry-run", false, "Show what would be changed without making updates")
+	adminEnhanceCmd.Flags().BoolVar(&adminEnhanceAssociate, "associate", false, "First associate existing emails with shipments")
+	adminEnhanceCmd.RegisterFlagCompletionFunc("shipment-id", completeShipmentIDs)
+}
+
+// updaterState summarizes whether the tracking updater is running
+func updaterState(status *cliapi.UpdaterStatus) string {
+	switch {
+	case status.Paused:
+		return "paused"
+	case status.Running:
+		return "running"
+	default:
+		return "stopped"
+	}
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// printUpdaterStatus prints the tracking updater's status as text
+func printUpdaterStatus(status *cliapi.UpdaterStatus) {
+	fmt.Printf("Tracking updater: %s\n", updaterState(status))
+	fmt.Printf("Last run: %s\n", formatOptionalTime(status.LastRun))
+	fmt.Printf("Next run: %s\n", formatOptionalTime(status.NextRun))
+
+	if len(status.Carriers) == 0 {
+		return
+	}
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "CARRIER\tENABLED\tLAST RUN\tPROCESSED\tERRORS\tCALLS TODAY\tBACKOFF UNTIL")
+	for _, carrier := range status.Carriers {
+		calls := fmt.Sprintf("%d", carrier.CallsToday)
+		if carrier.DailyBudget > 0 {
+			calls = fmt.Sprintf("%d/%d", carrier.CallsToday, carrier.DailyBudget)
+		}
+		backoff := "-"
+		if carrier.InBackoff {
+			backoff = formatOptionalTime(carrier.BackoffUntil)
+		}
+		fmt.Fprintf(w, "%s\t%v\t%s\t%d\t%d\t%s\t%s\n",
+			carrier.Carrier,
+			carrier.Enabled,
+			formatOptionalTime(carrier.LastRun),
+			carrier.ShipmentsProcessed,
+			carrier.ErrorCount,
+			calls,
+			backoff)
+	}
+}
+
+func runAdminStatus(cmd *cobra.Command, args []string) error {
+	config, formatter, client, err := initializeClient()
+	if err != nil {
+		return err
+	}
+
+	status, err := client.GetUpdaterStatus()
+	if err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+
+	switch {
+	case formatter.IsJSON():
+		return formatter.PrintResult(status, nil)
+	case config.Quiet:
+		fmt.Println(updaterState(status))
+	default:
+		printUpdaterStatus(status)
+	}
+	return nil
+}
+
+// runAdminAction pauses or resumes the tracking updater
+func runAdminAction(action func(*cliapi.Client) (*cliapi.AdminActionResponse, error)) error {
+	_, formatter, client, err := initializeClient()
+	if err != nil {
+		return err
+	}
+
+	result, err := action(client)
+	if err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+
+	if formatter.IsJSON() {
+		return formatter.PrintResult(result, nil)
+	}
+	formatter.PrintSuccess(result.Message)
+	return nil
+}
+
+func runAdminEnhance(cmd *cobra.Command, args []string) error {
+	config, formatter, client, err := initializeClient()
+	if err != nil {
+		return err
+	}
+
+	req := &cliapi.EnhanceDescriptionsRequest{
+		Limit:     adminEnhanceLimit,
+		DryRun:    adminEnhanceDryRun,
+		Associate: adminEnhanceAssociate,
+	}
+	if cmd.Flags().Changed("shipment-id") {
+		if adminEnhanceShipmentID <= 0 {
+			err := cliapi.NewValidationError("invalid shipment ID '%d': must be a positive integer", adminEnhanceShipmentID)
+			formatter.PrintError(err)
+			return err
+		}
+		req.ShipmentID = &adminEnhanceShipmentID
+	}
+
+	var spinner *cliapi.ProgressSpinner
+	if !config.Quiet && config.Format == "table" {
+		spinner = cliapi.NewProgressSpinner("Enhancing descriptions", noColor)
+		spinner.SetTheme(formatter.Theme())
+		spinner.Start()
+	}
+	resp, err := client.EnhanceDescriptions(req)
+	if spinner != nil {
+		spinner.Stop()
+	}
+	if err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+
+	var resultErr error
+	if !resp.Success {
+		message := resp.Error
+		if message == "" {
+			message = resp.Message
+		}
+		resultErr = errors.New("description enhancement failed: " + message)
+	}
+
+	if formatter.IsJSON() {
+		if err := formatter.PrintResult(resp, resultErr); err != nil {
+			return err
+		}
+		return resultErr
+	}
+
+	if req.ShipmentID != nil {
+		var result services.DescriptionEnhancementResult
+		if err := json.Unmarshal(resp.Summary, &result); err == nil {
+			printSingleResult(result, req.DryRun)
+		}
+	} else {
+		var summary services.DescriptionEnhancementSummary
+		if err := json.Unmarshal(resp.Summary, &summary); err == nil {
+			printSummary(&summary, req.DryRun)
+		}
+	}
+
+	if resultErr != nil {
+		formatter.PrintError(resultErr)
+	}
+	return resultErr
+}
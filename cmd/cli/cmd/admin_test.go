@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"testing"
+
+	cliapi "package-tracking/internal/cli"
+)
+
+func TestUpdaterState(t *testing.T) {
+	tests := []struct {
+		status   cliapi.UpdaterStatus
+		expected string
+	}{
+		{cliapi.UpdaterStatus{Running: true}, "running"},
+		{cliapi.UpdaterStatus{Running: true, Paused: true}, "paused"},
+		{cliapi.UpdaterStatus{}, "stopped"},
+	}
+
+	for _, tt := range tests {
+		if got := updaterState(&tt.status); got != tt.expected {
+			t.Errorf("updaterState(%+v) = %q, want %q", tt.status, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"regexp"
+	"strings"
+)
+
+// carrierPatterns suggest a carrier from the shape of a tracking number, most specific first.
+// They are a lightweight subset of the carrier clients' ValidateTrackingNumber rules; the
+// server still validates the number when the shipment is created.
+var carrierPatterns = []struct {
+	carrier string
+	pattern *regexp.Regexp
+}{
+	{"ups", regexp.MustCompile(`^1Z[A-Z0-9]{16}$`)},
+	{"amazon", regexp.MustCompile(`^TBA\d{12}$`)},
+	{"usps", regexp.MustCompile(`^(9[1-4]\d{20}|82\d{8}|7\d{19}|[A-Z]{2}\d{9}US)$`)},
+	{"fedex", regexp.MustCompile(`^(\d{12}|\d{14}|\d{15})$`)},
+	{"dhl", regexp.MustCompile(`^\d{10,11}$`)},
+}
+
+// detectCarrier suggests the carrier for a tracking number, or "" if it isn't recognized
+func detectCarrier(trackingNumber string) string {
+	cleaned := strings.ToUpper(strings.ReplaceAll(trackingNumber, " ", ""))
+	for _, p := range carrierPatterns {
+		if p.pattern.MatchString(cleaned) {
+			return p.carrier
+		}
+	}
+	return ""
+}
+
+// checkDigitValid reports whether an all-digit tracking number carries a valid check digit for
+// its carrier. known is false when there is no check digit rule for that carrier and length.
+func checkDigitValid(carrier, number string) (valid, known bool) {
+	digits := make([]int, len(number))
+	for i, r := range number {
+		if r < '0' || r > '9' {
+			return false, false
+		}
+		digits[i] = int(r - '0')
+	}
+
+	switch {
+	case carrier == "fedex" && len(digits) == 12:
+		return fedexExpressCheckDigit(digits[:11]) == digits[11], true
+	case carrier == "fedex" && len(digits) == 15, carrier == "usps" && len(digits) >= 20:
+		return mod10CheckDigit(digits[:len(digits)-1]) == digits[len(digits)-1], true
+	case carrier == "dhl" && len(digits) == 10:
+		remainder := 0
+		for _, d := range digits[:9] {
+			remainder = (remainder*10 + d) % 7
+		}
+		return remainder == digits[9], true
+	}
+	return false, false
+}
+
+// fedexExpressCheckDigit weights the digits 1, 3, 7 from the right and takes the sum mod 11
+func fedexExpressCheckDigit(digits []int) int {
+	weights := []int{1, 3, 7}
+	sum := 0
+	for i := range digits {
+		sum += digits[len(digits)-1-i] * weights[i%3]
+	}
+	return sum % 11 % 10
+}
+
+// mod10CheckDigit is the GS1 check digit used by USPS and FedEx Ground: digits are weighted 3, 1
+// from the right
+func mod10CheckDigit(digits []int) int {
+	sum := 0
+	for i := range digits {
+		weight := 1
+		if i%2 == 0 {
+			weight = 3
+		}
+		sum += digits[len(digits)-1-i] * weight
+	}
+	return (10 - sum%10) % 10
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import "testing"
+
+func TestDetectCarrier(t *testing.T) {
+	tests := []struct {
+		trackingNumber string
+		expected       string
+	}{
+		{"1Z999AA10123456784", "ups"},
+		{"1z999aa10123456784", "ups"},
+		{"9400111899562537866361", "usps"},
+		{"EK123456789US", "usps"},
+		{"TBA123456789012", "amazon"},
+		{"123456789012", "fedex"},
+		{"1234567890", "dhl"},
+		{"not a tracking number", ""},
+	}
+
+	for _, tt := range tests {
+		if got := detectCarrier(tt.trackingNumber); got != tt.expected {
+			t.Errorf("detectCarrier(%q) = %q, want %q", tt.trackingNumber, got, tt.expected)
+		}
+	}
+}
+
+func TestCheckDigitValid(t *testing.T) {
+	tests := []struct {
+		carrier string
+		number  string
+		valid   bool
+		known   bool
+	}{
+		{"usps", "9400111899562537866361", true, true},
+		{"usps", "9400111899562537866362", false, true},
+		{"fedex", "123456789012", true, true},
+		{"fedex", "123456789013", false, true},
+		{"dhl", "1234567891", true, true},
+		{"dhl", "5551234567", false, true},
+		{"dhl", "12345678901", false, false},
+		{"ups", "1Z999AA10123456784", false, false},
+	}
+
+	for _, tt := range tests {
+		valid, known := checkDigitValid(tt.carrier, tt.number)
+		if valid != tt.valid || known != tt.known {
+			t.Errorf("checkDigitValid(%q, %q) = %v, %v, want %v, %v", tt.carrier, tt.number, valid, known, tt.valid, tt.known)
+		}
+	}
+}
@@ -1,15 +1,32 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	cliapi "package-tracking/internal/cli"
+	"package-tracking/internal/database"
 )
 
+// completionTimeout bounds API lookups during shell completion so <TAB> never hangs
+const completionTimeout = 2 * time.Second
+
+// defaultCarrierCompletions are offered when the server can't be reached
+var defaultCarrierCompletions = []string{"ups", "usps", "fedex", "dhl", "amazon"}
+
 var completionCmd = &cobra.Command{
 	Use:   "completion [bash|zsh|fish|powershell]",
 	Short: "Generate completion script",
-	Long: `To load completions:
+	Long: `Generate a shell completion script. Shipment IDs and carrier names are
+completed dynamically from the API server, so "package-tracker refresh <TAB>"
+suggests your real shipments.
+
+To load completions:
 
 Bash:
   $ source <(package-tracker completion bash)
@@ -65,4 +82,94 @@ func runCompletion(cmd *cobra.Command, args []string) error {
 		return rootCmd.GenPowerShellCompletion(os.Stdout)
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// completionClient returns an API client for dynamic completions. Completion runs before
+// the usual command initialization, so configuration is loaded here.
+func completionClient() (*cliapi.Client, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	client := cliapi.NewClientWithTimeout(config.ServerURL, completionTimeout)
+	client.SetAPIKey(config.APIKey)
+	return client, nil
+}
+
+// completeShipmentIDArg completes the single <shipment-id> argument with shipments from the API
+func completeShipmentIDArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeShipmentIDs(cmd, args, toComplete)
+}
+
+// completeShipmentIDs completes shipment IDs fetched from the API
+func completeShipmentIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	client, err := completionClient()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	shipments, err := client.GetShipments()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return shipmentCompletions(shipments, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeCarriers completes carrier codes fetched from the API, falling back to the
+// built-in list when the server is unavailable
+func completeCarriers(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	client, err := completionClient()
+	if err != nil {
+		return filterPrefix(defaultCarrierCompletions, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	carriers, err := client.GetCarriers(false)
+	if err != nil || len(carriers) == 0 {
+		return filterPrefix(defaultCarrierCompletions, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+	return carrierCompletions(carriers, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// shipmentCompletions formats shipments as "id<TAB>description" completions matching toComplete
+func shipmentCompletions(shipments []database.Shipment, toComplete string) []string {
+	var completions []string
+	for _, shipment := range shipments {
+		id := strconv.Itoa(shipment.ID)
+		if !strings.HasPrefix(id, toComplete) {
+			continue
+		}
+		description := shipment.Description
+		if description == "" {
+			description = shipment.TrackingNumber
+		}
+		completions = append(completions, fmt.Sprintf("%s\t%s (%s, %s)", id, description, shipment.Carrier, shipment.Status))
+	}
+	return completions
+}
+
+// carrierCompletions formats carriers as "code<TAB>name" completions matching toComplete
+func carrierCompletions(carriers []database.Carrier, toComplete string) []string {
+	var completions []string
+	for _, carrier := range carriers {
+		if !strings.HasPrefix(carrier.Code, strings.ToLower(toComplete)) {
+			continue
+		}
+		completions = append(completions, carrier.Code+"\t"+carrier.Name)
+	}
+	return completions
+}
+
+// filterPrefix returns the values that start with prefix
+func filterPrefix(values []string, prefix string) []string {
+	var matches []string
+	for _, value := range values {
+		if strings.HasPrefix(value, strings.ToLower(prefix)) {
+			matches = append(matches, value)
+		}
+	}
+	return matches
+}
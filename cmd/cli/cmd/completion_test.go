@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"package-tracking/internal/database"
+)
+
+func TestShipmentCompletions(t *testing.T) {
+	shipments := []database.Shipment{
+		{ID: 1, TrackingNumber: "1Z999AA10123456784", Carrier: "ups", Description: "Laptop", Status: "in_transit"},
+		{ID: 12, TrackingNumber: "9400111899562537866361", Carrier: "usps", Description: "", Status: "pending"},
+		{ID: 20, TrackingNumber: "1234567890", Carrier: "dhl", Description: "Books", Status: "delivered"},
+	}
+
+	tests := []struct {
+		name       string
+		toComplete string
+		expected   []string
+	}{
+		{
+			name:       "all shipments",
+			toComplete: "",
+			expected: []string{
+				"1\tLaptop (ups, in_transit)",
+				"12\t9400111899562537866361 (usps, pending)",
+				"20\tBooks (dhl, delivered)",
+			},
+		},
+		{
+			name:       "prefix match",
+			toComplete: "1",
+			expected: []string{
+				"1\tLaptop (ups, in_transit)",
+				"12\t9400111899562537866361 (usps, pending)",
+			},
+		},
+		{
+			name:       "no match",
+			toComplete: "3",
+			expected:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := shipmentCompletions(shipments, tt.toComplete)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("shipmentCompletions(%q) = %q, want %q", tt.toComplete, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCarrierCompletions(t *testing.T) {
+	carriers := []database.Carrier{
+		{Code: "ups", Name: "United Parcel Service"},
+		{Code: "usps", Name: "United States Postal Service"},
+		{Code: "fedex", Name: "FedEx"},
+	}
+
+	result := carrierCompletions(carriers, "U")
+	expected := []string{"ups\tUnited Parcel Service", "usps\tUnited States Postal Service"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("carrierCompletions(%q) = %q, want %q", "U", result, expected)
+	}
+
+	if result := filterPrefix(defaultCarrierCompletions, "d"); !reflect.DeepEqual(result, []string{"dhl"}) {
+		t.Errorf("filterPrefix(%q) = %q, want [dhl]", "d", result)
+	}
+}
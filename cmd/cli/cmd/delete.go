@@ -1,20 +1,104 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
 	"github.com/spf13/cobra"
+
+	cliapi "package-tracking/internal/cli"
 )
 
 var deleteCmd = &cobra.Command{
-	Use:     "delete <shipment-id>",
+	Use:     "delete <shipment-id|range>...",
 	Aliases: []string{"del", "rm"},
-	Short:   "Delete a shipment",
-	Long:    `Delete a shipment from the tracking system.`,
-	Args:    cobra.ExactArgs(1),
-	RunE:    runDelete,
+	Short:   "Delete shipments",
+	Long: `Delete one or more shipments from the tracking system. IDs can be given individually or
+as inclusive ranges:
+
+  package-tracker delete 3 5 7-9
+
+You are asked to confirm before anything is deleted; use --yes to skip the prompt in scripts.`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeShipmentIDs,
+	RunE:              runDelete,
 }
 
+var deleteYes bool
+
 func init() {
 	rootCmd.AddCommand(deleteCmd)
+
+	deleteCmd.Flags().BoolVarP(&deleteYes, "yes", "y", false, "Delete without asking for confirmation")
+}
+
+// deleteResult records what happened to each shipment a delete was requested for
+type deleteResult struct {
+	deleted  []int
+	notFound []int
+	failed   []string
+}
+
+// deleteOutput is the JSON result of a delete
+type deleteOutput struct {
+	Deleted  []int    `json:"deleted"`
+	NotFound []int    `json:"not_found"`
+	Failed   []string `json:"failed"`
+}
+
+func (r deleteResult) output() deleteOutput {
+	// Empty lists rather than nulls, so scripts can always iterate them
+	output := deleteOutput{Deleted: []int{}, NotFound: []int{}, Failed: []string{}}
+	output.Deleted = append(output.Deleted, r.deleted...)
+	output.NotFound = append(output.NotFound, r.notFound...)
+	output.Failed = append(output.Failed, r.failed...)
+	return output
+}
+
+// confirmDelete asks on stderr whether to delete ids, reading the answer from in. Anything
+// other than y or yes, including no input, is a no.
+func confirmDelete(in io.Reader, ids []int) bool {
+	if len(ids) == 1 {
+		fmt.Fprintf(os.Stderr, "Delete shipment %d? [y/N] ", ids[0])
+	} else {
+		fmt.Fprintf(os.Stderr, "Delete %d shipments (%s)? [y/N] ", len(ids), joinIDs(ids))
+	}
+
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// deleteShipments deletes each shipment, separating shipments that didn't exist from other
+// failures
+func deleteShipments(client *cliapi.Client, ids []int) deleteResult {
+	var result deleteResult
+	for _, id := range ids {
+		err := client.DeleteShipment(id)
+		var apiErr *cliapi.APIError
+		switch {
+		case err == nil:
+			result.deleted = append(result.deleted, id)
+		case errors.As(err, &apiErr) && apiErr.Code == 404:
+			result.notFound = append(result.notFound, id)
+		default:
+			result.failed = append(result.failed, fmt.Sprintf("%d: %v", id, err))
+		}
+	}
+	return result
+}
+
+func joinIDs(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ", ")
 }
 
 func runDelete(cmd *cobra.Command, args []string) error {
@@ -23,21 +107,52 @@ func runDelete(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	id, err := validateAndParseID(args[0])
+	ids, err := parseIDArgs(args)
 	if err != nil {
 		formatter.PrintError(err)
 		return err
 	}
 
-	err = client.DeleteShipment(id)
-	if err != nil {
-		formatter.PrintError(err)
-		return err
+	if !deleteYes && !confirmDelete(os.Stdin, ids) {
+		if !config.Quiet {
+			formatter.PrintInfo("Delete cancelled")
+		}
+		return nil
+	}
+
+	result := deleteShipments(client, ids)
+
+	var resultErr error
+	if len(result.failed) > 0 {
+		resultErr = fmt.Errorf("failed to delete %d shipments:\n  %s", len(result.failed), strings.Join(result.failed, "\n  "))
+	} else if len(result.deleted) == 0 {
+		resultErr = &cliapi.NotFoundError{Message: "no shipments were deleted: none of them exist"}
 	}
 
-	if !config.Quiet {
-		formatter.PrintSuccess("Shipment deleted successfully")
+	if formatter.IsJSON() {
+		if err := formatter.PrintResult(result.output(), resultErr); err != nil {
+			return err
+		}
+		return resultErr
 	}
 
-	return nil
-}
\ No newline at end of file
+	if config.Quiet {
+		for _, id := range result.deleted {
+			fmt.Printf("%d\n", id)
+		}
+	} else {
+		if len(result.deleted) == 1 && len(ids) == 1 {
+			formatter.PrintSuccess("Shipment deleted successfully")
+		} else if len(result.deleted) > 0 {
+			formatter.PrintSuccess(fmt.Sprintf("Deleted %d shipments: %s", len(result.deleted), joinIDs(result.deleted)))
+		}
+		if len(result.notFound) > 0 {
+			formatter.PrintInfo(fmt.Sprintf("Not found: %s", joinIDs(result.notFound)))
+		}
+	}
+
+	if resultErr != nil {
+		formatter.PrintError(resultErr)
+	}
+	return resultErr
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	cliapi "package-tracking/internal/cli"
+)
+
+func TestDeleteShipments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/shipments/2":
+			http.Error(w, "Shipment not found", http.StatusNotFound)
+		case "/api/shipments/3":
+			http.Error(w, "Failed to delete shipment: database locked", http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	result := deleteShipments(cliapi.NewClient(server.URL), []int{1, 2, 3, 4})
+
+	if !reflect.DeepEqual(result.deleted, []int{1, 4}) {
+		t.Errorf("Expected 1 and 4 deleted, got %v", result.deleted)
+	}
+	if !reflect.DeepEqual(result.notFound, []int{2}) {
+		t.Errorf("Expected 2 not found, got %v", result.notFound)
+	}
+	if len(result.failed) != 1 || !strings.HasPrefix(result.failed[0], "3: ") {
+		t.Errorf("Expected 3 to fail, got %v", result.failed)
+	}
+}
+
+func TestConfirmDelete(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := confirmDelete(strings.NewReader(tt.input), []int{1, 2}); got != tt.expected {
+			t.Errorf("confirmDelete(%q) = %v, want %v", tt.input, got, tt.expected)
+		}
+	}
+}
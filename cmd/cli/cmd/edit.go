@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	cliapi "package-tracking/internal/cli"
+)
+
+var editCmd = &cobra.Command{
+	Use:   "edit <shipment-id>",
+	Short: "Edit a shipment's description in your editor",
+	Long: `Open a shipment's description in $VISUAL or $EDITOR and save the result, which makes
+multi-line descriptions practical. Lines starting with # are ignored; saving an empty
+description cancels the edit.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeShipmentIDArg,
+	RunE:              runEdit,
+}
+
+// editTemplateHelp is appended to the text being edited
+const editTemplateHelp = `
+# Edit the description of shipment %d (%s).
+# Lines starting with '#' are ignored, and an empty description cancels the edit.
+`
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+}
+
+// editorCommand returns the user's editor and its arguments, from $VISUAL or $EDITOR
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
+
+// editText opens text in the user's editor and returns the saved contents
+func editText(text string) (string, error) {
+	file, err := os.CreateTemp("", "package-tracker-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(text); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	editor := editorCommand()
+	cmd := exec.Command(editor[0], append(editor[1:], file.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %s failed: %w", editor[0], err)
+	}
+
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// parseEditedDescription removes comment lines and surrounding blank lines from edited text
+func parseEditedDescription(text string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, " \t"))
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+func runEdit(cmd *cobra.Command, args []string) error {
+	config, formatter, client, err := initializeClient()
+	if err != nil {
+		return err
+	}
+
+	id, err := validateAndParseID(args[0])
+	if err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+
+	shipment, err := client.GetShipment(id)
+	if err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+
+	edited, err := editText(shipment.Description + "\n" + fmt.Sprintf(editTemplateHelp, shipment.ID, shipment.TrackingNumber))
+	if err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+
+	description := parseEditedDescription(edited)
+	if description == "" {
+		if !config.Quiet {
+			formatter.PrintInfo("Empty description, edit cancelled")
+		}
+		return nil
+	}
+	if description == shipment.Description {
+		if !config.Quiet {
+			formatter.PrintInfo("Description unchanged")
+		}
+		return nil
+	}
+
+	updated, err := client.UpdateShipment(id, &cliapi.UpdateShipmentRequest{Description: description})
+	if err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+
+	if !config.Quiet {
+		formatter.PrintSuccess("Shipment updated successfully")
+	}
+	return formatter.PrintShipment(updated)
+}
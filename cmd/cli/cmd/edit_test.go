@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestParseEditedDescription(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"single line", "Headphones\n\n# Edit the description\n", "Headphones"},
+		{"multi-line", "Gift for Sam\n\nWrap before Friday  \n# comment\n", "Gift for Sam\n\nWrap before Friday"},
+		{"windows line endings", "Books\r\n# comment\r\n", "Books"},
+		{"only comments", "\n# Edit the description\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseEditedDescription(tt.text); got != tt.expected {
+				t.Errorf("parseEditedDescription(%q) = %q, want %q", tt.text, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEditText(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the editor")
+	}
+
+	// The "editor" replaces the file's contents
+	editor := filepath.Join(t.TempDir(), "editor.sh")
+	if err := os.WriteFile(editor, []byte("#!/bin/sh\necho 'New description' > \"$1\"\n"), 0700); err != nil {
+		t.Fatalf("Failed to write editor script: %v", err)
+	}
+	t.Setenv("VISUAL", editor)
+
+	edited, err := editText("Old description\n")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if edited != "New description\n" {
+		t.Errorf("Expected edited text, got %q", edited)
+	}
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var emailsCmd = &cobra.Command{
+	Use:   "emails",
+	Short: "View and manage emails linked to shipments",
+	Long: `View the emails the email tracker linked to a shipment, read them, and add or remove
+links by hand.
+
+  package-tracker emails list 12
+  package-tracker emails show 18c2f0a9d4b7e611
+  package-tracker emails link 345 12
+  package-tracker emails unlink 345 12`,
+}
+
+var emailsListCmd = &cobra.Command{
+	Use:               "list <shipment-id>",
+	Aliases:           []string{"ls"},
+	Short:             "List the emails linked to a shipment",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeShipmentIDArg,
+	RunE:              runEmailsList,
+}
+
+var emailsShowCmd = &cobra.Command{
+	Use:   "show <message-id>",
+	Short: "Show an email's content",
+	Long: `Show an email's headers and content. The message ID is the Gmail message ID shown in the
+MESSAGE ID column of 'emails list'. With --quiet only the content is printed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEmailsShow,
+}
+
+var emailsLinkCmd = &cobra.Command{
+	Use:   "link <email-id> <shipment-id>",
+	Short: "Link an email to a shipment",
+	Long: `Link an email to a shipment by hand. The email ID is the number in the ID column of
+'emails list'.`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeEmailLinkArgs,
+	RunE:              runEmailsLink,
+}
+
+var emailsUnlinkCmd = &cobra.Command{
+	Use:               "unlink <email-id> <shipment-id>",
+	Short:             "Remove the link between an email and a shipment",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeEmailLinkArgs,
+	RunE:              runEmailsUnlink,
+}
+
+func init() {
+	rootCmd.AddCommand(emailsCmd)
+	emailsCmd.AddCommand(emailsListCmd, emailsShowCmd, emailsLinkCmd, emailsUnlinkCmd)
+}
+
+// completeEmailLinkArgs completes the shipment ID, the second argument of link and unlink
+func completeEmailLinkArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeShipmentIDs(cmd, args, toComplete)
+}
+
+// emailLinkOutput is the JSON result of link and unlink
+type emailLinkOutput struct {
+	EmailID    int  `json:"email_id"`
+	ShipmentID int  `json:"shipment_id"`
+	Linked     bool `json:"linked"`
+}
+
+// parseEmailLinkArgs parses the email and shipment IDs given to link and unlink
+func parseEmailLinkArgs(args []string) (int, int, error) {
+	emailID, err := validateAndParseID(args[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	shipmentID, err := validateAndParseID(args[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return emailID, shipmentID, nil
+}
+
+func runEmailsList(cmd *cobra.Command, args []string) error {
+	_, formatter, client, err := initializeClient()
+	if err != nil {
+		return err
+	}
+
+	id, err := validateAndParseID(args[0])
+	if err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+
+	// The emails endpoint returns an empty list for unknown shipments, so check it exists
+	if _, err := client.GetShipment(id); err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+
+	emails, err := client.GetShipmentEmails(id)
+	if err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+
+	return formatter.PrintEmails(emails)
+}
+
+func runEmailsShow(cmd *cobra.Command, args []string) error {
+	_, formatter, client, err := initializeClient()
+	if err != nil {
+		return err
+	}
+
+	body, err := client.GetEmailBody(args[0])
+	if err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+
+	return formatter.PrintEmailBody(body)
+}
+
+func runEmailsLink(cmd *cobra.Command, args []string) error {
+	config, formatter, client, err := initializeClient()
+	if err != nil {
+		return err
+	}
+
+	emailID, shipmentID, err := parseEmailLinkArgs(args)
+	if err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+
+	// Look the shipment up first so a missing one is reported as such, and so the link
+	// records its tracking number
+	shipment, err := client.GetShipment(shipmentID)
+	if err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+
+	if err := client.LinkEmail(emailID, shipmentID, shipment.TrackingNumber); err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+
+	if formatter.IsJSON() {
+		return formatter.PrintResult(emailLinkOutput{EmailID: emailID, ShipmentID: shipmentID, Linked: true}, nil)
+	}
+	if !config.Quiet {
+		formatter.PrintSuccess(fmt.Sprintf("Linked email %d to shipment %d", emailID, shipmentID))
+	}
+	return nil
+}
+
+func runEmailsUnlink(cmd *cobra.Command, args []string) error {
+	config, formatter, client, err := initializeClient()
+	if err != nil {
+		return err
+	}
+
+	emailID, shipmentID, err := parseEmailLinkArgs(args)
+	if err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+
+	if err := client.UnlinkEmail(emailID, shipmentID); err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+
+	if formatter.IsJSON() {
+		return formatter.PrintResult(emailLinkOutput{EmailID: emailID, ShipmentID: shipmentID, Linked: false}, nil)
+	}
+	if !config.Quiet {
+		formatter.PrintSuccess(fmt.Sprintf("Unlinked email %d from shipment %d", emailID, shipmentID))
+	}
+	return nil
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"log/slog"
 	"os"
@@ -11,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"package-tracking/internal/carriers"
+	cliapi "package-tracking/internal/cli"
 	"package-tracking/internal/config"
 	"package-tracking/internal/database"
 	"package-tracking/internal/parser"
@@ -46,17 +46,23 @@ func init() {
 	enhanceDescriptionsCmd.Flags().StringVar(&enhanceFormat, "format", "table", "Output format: table, json")
 	enhanceDescriptionsCmd.Flags().BoolVar(&enhanceAssociate, "associate", false, "First associate existing emails with shipments")
 
+	enhanceDescriptionsCmd.RegisterFlagCompletionFunc("shipment-id", completeShipmentIDs)
+
 	rootCmd.AddCommand(enhanceDescriptionsCmd)
 }
 
 func runEnhanceDescriptions(cmd *cobra.Command, args []string) error {
+	// This command has its own --format, so report errors in a JSON envelope from here
+	formatter := cliapi.NewOutputFormatter(enhanceFormat, false)
+	activeFormatter = formatter
+
 	// Validate flags
 	if !enhanceAll && enhanceShipmentID == 0 {
-		return fmt.Errorf("must specify either --all or --shipment-id")
+		return cliapi.NewValidationError("must specify either --all or --shipment-id")
 	}
 
 	if enhanceAll && enhanceShipmentID != 0 {
-		return fmt.Errorf("cannot specify both --all and --shipment-id")
+		return cliapi.NewValidationError("cannot specify both --all and --shipment-id")
 	}
 
 	// Load server configuration to get database path
@@ -156,9 +162,8 @@ func runEnhanceDescriptions(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to enhance shipment %d: %w", enhanceShipmentID, err)
 		}
 
-		if enhanceFormat == "json" {
-			output, _ := json.MarshalIndent(result, "", "  ")
-			fmt.Println(string(output))
+		if formatter.IsJSON() {
+			return formatter.PrintResult(result, nil)
 		} else {
 			printSingleResult(*result, enhanceDryRun)
 		}
@@ -169,9 +174,8 @@ func runEnhanceDescriptions(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to enhance shipments: %w", err)
 		}
 
-		if enhanceFormat == "json" {
-			output, _ := json.MarshalIndent(summary, "", "  ")
-			fmt.Println(string(output))
+		if formatter.IsJSON() {
+			return formatter.PrintResult(summary, nil)
 		} else {
 			printSummary(summary, enhanceDryRun)
 		}
@@ -1,23 +1,50 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
 	"github.com/spf13/cobra"
+
+	cliapi "package-tracking/internal/cli"
+	"package-tracking/internal/database"
 )
 
 var eventsCmd = &cobra.Command{
 	Use:   "events <shipment-id>",
 	Short: "View tracking events for a shipment",
-	Long:  `View the tracking history and events for a specific shipment.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runEvents,
+	Long: `View the tracking history and events for a specific shipment.
+
+With --follow, keep polling and print new events as they appear, like tail -f, until the
+shipment is delivered or you press Ctrl+C. JSON and CSV output is written one event per line.
+
+Without --follow, if the server can't be reached the last successfully fetched events are
+shown with a warning saying how old they are. Use --refresh-cache to require fresh results.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeShipmentIDArg,
+	RunE:              runEvents,
 }
 
+var (
+	eventsFollow   bool
+	eventsInterval time.Duration
+)
+
 func init() {
 	rootCmd.AddCommand(eventsCmd)
+
+	eventsCmd.Flags().BoolVar(&eventsFollow, "follow", false, "Keep printing new events until the shipment is delivered")
+	eventsCmd.Flags().DurationVar(&eventsInterval, "interval", 30*time.Second, "How often to check for new events with --follow")
+	addRefreshCacheFlag(eventsCmd)
 }
 
 func runEvents(cmd *cobra.Command, args []string) error {
-	_, formatter, client, err := initializeClient()
+	config, formatter, client, err := initializeReadClient()
 	if err != nil {
 		return err
 	}
@@ -28,11 +55,92 @@ func runEvents(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	events, err := client.GetEvents(id)
+	if eventsFollow {
+		if eventsInterval <= 0 {
+			err := cliapi.NewValidationError("interval must be positive")
+			formatter.PrintError(err)
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if err := formatter.PrintEventStreamHeader(); err != nil {
+			return err
+		}
+
+		delivered, err := followEvents(ctx, client, id, eventsInterval, formatter.PrintEventStream)
+		if err != nil {
+			formatter.PrintError(err)
+			return err
+		}
+		if delivered && config.Format == "table" {
+			formatter.PrintInfo("Shipment delivered")
+		}
+		return nil
+	}
+
+	events, err := newCachedReader(config, formatter, client).Events(id)
 	if err != nil {
 		formatter.PrintError(err)
 		return err
 	}
 
 	return formatter.PrintEvents(events)
-}
\ No newline at end of file
+}
+
+// followEvents prints a shipment's events in chronological order, then polls every interval
+// and prints events it hasn't seen before. It returns once the shipment is delivered or ctx
+// is cancelled, reporting whether the shipment was delivered. Polling errors are reported and
+// retried, since the server may only be briefly unavailable; an error fetching the initial
+// events is returned.
+func followEvents(ctx context.Context, client *cliapi.Client, id int, interval time.Duration, print func([]database.TrackingEvent) error) (bool, error) {
+	seen := make(map[int]bool)
+
+	poll := func() (delivered bool, err error) {
+		events, err := client.GetEvents(id)
+		if err != nil {
+			return false, err
+		}
+
+		var fresh []database.TrackingEvent
+		for _, event := range events {
+			if !seen[event.ID] {
+				seen[event.ID] = true
+				fresh = append(fresh, event)
+			}
+		}
+		sort.SliceStable(fresh, func(i, j int) bool {
+			return fresh[i].Timestamp.Before(fresh[j].Timestamp)
+		})
+		if err := print(fresh); err != nil {
+			return false, err
+		}
+
+		shipment, err := client.GetShipment(id)
+		if err != nil {
+			return false, err
+		}
+		return shipment.IsDelivered, nil
+	}
+
+	delivered, err := poll()
+	if err != nil {
+		return false, err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for !delivered {
+		select {
+		case <-ctx.Done():
+			return false, nil
+		case <-ticker.C:
+			if delivered, err = poll(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to check for new events: %v\n", err)
+			}
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cliapi "package-tracking/internal/cli"
+	"package-tracking/internal/database"
+)
+
+func TestFollowEvents(t *testing.T) {
+	base := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	allEvents := []database.TrackingEvent{
+		{ID: 2, ShipmentID: 1, Timestamp: base.Add(time.Hour), Status: "in_transit"},
+		{ID: 1, ShipmentID: 1, Timestamp: base, Status: "pending"},
+		{ID: 3, ShipmentID: 1, Timestamp: base.Add(2 * time.Hour), Status: "delivered"},
+	}
+
+	var mu sync.Mutex
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/events") {
+			polls++
+			// The delivery event appears on the second poll
+			if polls == 1 {
+				json.NewEncoder(w).Encode(allEvents[:2])
+			} else {
+				json.NewEncoder(w).Encode(allEvents)
+			}
+			return
+		}
+		json.NewEncoder(w).Encode(database.Shipment{ID: 1, IsDelivered: polls > 1})
+	}))
+	defer server.Close()
+
+	var printed []int
+	print := func(events []database.TrackingEvent) error {
+		for _, event := range events {
+			printed = append(printed, event.ID)
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	delivered, err := followEvents(ctx, cliapi.NewClient(server.URL), 1, 10*time.Millisecond, print)
+	if err != nil {
+		t.Fatalf("followEvents returned error: %v", err)
+	}
+	if !delivered {
+		t.Fatal("Expected following to stop because the shipment was delivered")
+	}
+
+	// Events print once each, oldest first
+	expected := []int{1, 2, 3}
+	if len(printed) != len(expected) {
+		t.Fatalf("Expected events %v, got %v", expected, printed)
+	}
+	for i := range expected {
+		if printed[i] != expected[i] {
+			t.Fatalf("Expected events %v, got %v", expected, printed)
+		}
+	}
+}
+
+func TestFollowEvents_Cancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/events") {
+			w.Write([]byte("[]"))
+			return
+		}
+		json.NewEncoder(w).Encode(database.Shipment{ID: 1})
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	delivered, err := followEvents(ctx, cliapi.NewClient(server.URL), 1, 10*time.Millisecond, func([]database.TrackingEvent) error { return nil })
+	if err != nil || delivered {
+		t.Errorf("Expected cancellation to stop following without error, got delivered=%v err=%v", delivered, err)
+	}
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	cliapi "package-tracking/internal/cli"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export all shipments as CSV or JSON",
+	Long: `Write every shipment to stdout in a format that import can read back. The output is CSV
+unless --format json or --format yaml is given.
+
+  package-tracker export > shipments.csv
+  package-tracker export --format json > shipments.json`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	config, formatter, client, err := initializeClient()
+	if err != nil {
+		return err
+	}
+
+	shipments, err := client.GetShipments()
+	if err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+
+	// A table can't be imported, so it means the default, CSV
+	exportFormat := config.Format
+	if exportFormat == "table" {
+		exportFormat = "csv"
+	}
+
+	// Export ignores --quiet, which would otherwise print only IDs
+	return cliapi.NewOutputFormatter(exportFormat, false).PrintShipments(shipments)
+}
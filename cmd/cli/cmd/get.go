@@ -8,17 +8,23 @@ var getCmd = &cobra.Command{
 	Use:     "get <shipment-id>",
 	Aliases: []string{"show", "info"},
 	Short:   "Get shipment details by ID",
-	Long:    `Get detailed information about a specific shipment by its ID.`,
-	Args:    cobra.ExactArgs(1),
-	RunE:    runGet,
+	Long: `Get detailed information about a specific shipment by its ID.
+
+If the server can't be reached, the last successfully fetched copy is shown with a warning
+saying how old it is. Use --refresh-cache to require fresh results.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeShipmentIDArg,
+	RunE:              runGet,
 }
 
 func init() {
 	rootCmd.AddCommand(getCmd)
+
+	addRefreshCacheFlag(getCmd)
 }
 
 func runGet(cmd *cobra.Command, args []string) error {
-	_, formatter, client, err := initializeClient()
+	config, formatter, client, err := initializeReadClient()
 	if err != nil {
 		return err
 	}
@@ -29,7 +35,7 @@ func runGet(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	shipment, err := client.GetShipment(id)
+	shipment, err := newCachedReader(config, formatter, client).Shipment(id)
 	if err != nil {
 		formatter.PrintError(err)
 		return err
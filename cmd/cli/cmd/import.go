@@ -0,0 +1,368 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	cliapi "package-tracking/internal/cli"
+	"package-tracking/internal/database"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Add shipments from a CSV or JSON file",
+	Long: `Add shipments from a CSV file with a header row, or a JSON array like the one export writes.
+Use - to read CSV from stdin.
+
+CSV columns are matched by name: tracking_number (or tracking, tracking number), carrier
+and description; other columns are ignored. When the carrier is blank it is detected from
+the tracking number format. Rows whose tracking number is already tracked, or appears
+earlier in the file, are skipped.
+
+Use --dry-run to preview how rows will be mapped without adding anything.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImport,
+}
+
+var importDryRun bool
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Show what would be imported without adding anything")
+}
+
+// importRow is a shipment read from an import file
+type importRow struct {
+	row            int // 1-based position in the file, not counting the CSV header
+	trackingNumber string
+	carrier        string
+	description    string
+	detected       bool   // The carrier was detected from the tracking number
+	skip           string // Why the row won't be imported, empty to import it
+}
+
+// importColumnAliases are the CSV header names accepted for each field, after lowercasing and
+// replacing spaces and dashes with underscores
+var importColumnAliases = map[string][]string{
+	"tracking_number": {"tracking_number", "tracking", "tracking_no", "tracking_id"},
+	"carrier":         {"carrier"},
+	"description":     {"description", "desc", "name", "item"},
+}
+
+// importColumns maps each field to its CSV column index, or -1 if the file doesn't have it
+type importColumns map[string]int
+
+// findImportColumns matches a CSV header row to the import fields
+func findImportColumns(header []string) (importColumns, error) {
+	columns := importColumns{"tracking_number": -1, "carrier": -1, "description": -1}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		name = strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+		for field, aliases := range importColumnAliases {
+			for _, alias := range aliases {
+				if name == alias && columns[field] == -1 {
+					columns[field] = i
+				}
+			}
+		}
+	}
+
+	if columns["tracking_number"] == -1 {
+		return nil, fmt.Errorf("no tracking number column found in header (expected one of: %s)",
+			strings.Join(importColumnAliases["tracking_number"], ", "))
+	}
+	return columns, nil
+}
+
+// describe explains which CSV column each field is read from
+func (c importColumns) describe(header []string) string {
+	var parts []string
+	for _, field := range []string{"tracking_number", "carrier", "description"} {
+		if i := c[field]; i >= 0 {
+			parts = append(parts, fmt.Sprintf("%s <- %q (column %d)", field, header[i], i+1))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s <- (none)", field))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// readImportCSV reads shipments from CSV, returning them with a description of the column
+// mapping
+func readImportCSV(r io.Reader) ([]importRow, string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, "", fmt.Errorf("file is empty")
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read header: %w", err)
+	}
+
+	columns, err := findImportColumns(header)
+	if err != nil {
+		return nil, "", err
+	}
+
+	field := func(record []string, name string) string {
+		if i := columns[name]; i >= 0 && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var rows []importRow
+	for n := 1; ; n++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read row %d: %w", n, err)
+		}
+		rows = append(rows, importRow{
+			row:            n,
+			trackingNumber: field(record, "tracking_number"),
+			carrier:        strings.ToLower(field(record, "carrier")),
+			description:    field(record, "description"),
+		})
+	}
+
+	return rows, columns.describe(header), nil
+}
+
+// readImportJSON reads shipments from a JSON array of objects with tracking_number, carrier and
+// description fields, or the envelope export writes around one
+func readImportJSON(r io.Reader) ([]importRow, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '{' {
+		var envelope struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(raw, &envelope); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		raw = envelope.Data
+	}
+
+	var records []struct {
+		TrackingNumber string `json:"tracking_number"`
+		Carrier        string `json:"carrier"`
+		Description    string `json:"description"`
+	}
+	if err := json.Unmarshal(raw, &records); err != nil {
+		return nil, fmt.Errorf("expected a list of shipments: %w", err)
+	}
+
+	rows := make([]importRow, len(records))
+	for i, record := range records {
+		rows[i] = importRow{
+			row:            i + 1,
+			trackingNumber: strings.TrimSpace(record.TrackingNumber),
+			carrier:        strings.ToLower(strings.TrimSpace(record.Carrier)),
+			description:    strings.TrimSpace(record.Description),
+		}
+	}
+	return rows, nil
+}
+
+// planImport fills in detected carriers and marks the rows that will be skipped: rows without
+// a tracking number or recognizable carrier, tracking numbers that are already tracked, and
+// repeats of an earlier row
+func planImport(rows []importRow, existing []database.Shipment) {
+	tracked := make(map[string]bool, len(existing))
+	for _, shipment := range existing {
+		tracked[strings.ToUpper(shipment.TrackingNumber)] = true
+	}
+	seen := make(map[string]int)
+
+	for i := range rows {
+		row := &rows[i]
+		key := strings.ToUpper(row.trackingNumber)
+
+		switch {
+		case row.trackingNumber == "":
+			row.skip = "no tracking number"
+			continue
+		case tracked[key]:
+			row.skip = "already tracked"
+			continue
+		case seen[key] != 0:
+			row.skip = fmt.Sprintf("duplicate of row %d", seen[key])
+			continue
+		}
+		seen[key] = row.row
+
+		if row.carrier == "" {
+			row.carrier = detectCarrier(row.trackingNumber)
+			row.detected = row.carrier != ""
+		}
+		if row.carrier == "" {
+			row.skip = "carrier not detected"
+		}
+	}
+}
+
+// importOutput is the JSON result of an import
+type importOutput struct {
+	Added   int      `json:"added"`
+	Skipped int      `json:"skipped"`
+	Failed  []string `json:"failed"`
+}
+
+// importPreviewRow is a row of the JSON dry-run preview
+type importPreviewRow struct {
+	Row             int    `json:"row"`
+	TrackingNumber  string `json:"tracking_number"`
+	Carrier         string `json:"carrier"`
+	CarrierDetected bool   `json:"carrier_detected"`
+	Description     string `json:"description"`
+	Action          string `json:"action"` // "add" or "skip"
+	SkipReason      string `json:"skip_reason,omitempty"`
+}
+
+func importPreviewOutput(rows []importRow) []importPreviewRow {
+	preview := make([]importPreviewRow, len(rows))
+	for i, row := range rows {
+		preview[i] = importPreviewRow{
+			Row:             row.row,
+			TrackingNumber:  row.trackingNumber,
+			Carrier:         row.carrier,
+			CarrierDetected: row.detected,
+			Description:     row.description,
+			Action:          "add",
+			SkipReason:      row.skip,
+		}
+		if row.skip != "" {
+			preview[i].Action = "skip"
+		}
+	}
+	return preview
+}
+
+// printImportPreview shows how each row will be imported
+func printImportPreview(rows []importRow) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ROW\tTRACKING NUMBER\tCARRIER\tDESCRIPTION\tACTION")
+	for _, row := range rows {
+		carrier := row.carrier
+		if row.detected {
+			carrier += " (detected)"
+		}
+		action := "add"
+		if row.skip != "" {
+			action = "skip: " + row.skip
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", row.row, row.trackingNumber, carrier, row.description, action)
+	}
+	w.Flush()
+}
+
+// readImportFile reads the rows of a CSV or JSON file, choosing the format by extension
+func readImportFile(path string) ([]importRow, string, error) {
+	if path == "-" {
+		return readImportCSV(os.Stdin)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		rows, err := readImportJSON(file)
+		return rows, "", err
+	}
+	return readImportCSV(file)
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	config, formatter, client, err := initializeClient()
+	if err != nil {
+		return err
+	}
+
+	rows, mapping, err := readImportFile(args[0])
+	if err != nil {
+		err = fmt.Errorf("failed to read %s: %w", args[0], err)
+		formatter.PrintError(err)
+		return err
+	}
+
+	existing, err := client.GetShipments()
+	if err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+	planImport(rows, existing)
+
+	if importDryRun {
+		if formatter.IsJSON() {
+			return formatter.PrintResult(importPreviewOutput(rows), nil)
+		}
+		if mapping != "" {
+			fmt.Printf("Columns: %s\n\n", mapping)
+		}
+		printImportPreview(rows)
+		return nil
+	}
+
+	var added, skipped int
+	var failures []string
+	for _, row := range rows {
+		if row.skip != "" {
+			skipped++
+			continue
+		}
+
+		_, err := client.CreateShipment(&cliapi.CreateShipmentRequest{
+			TrackingNumber: row.trackingNumber,
+			Carrier:        row.carrier,
+			Description:    row.description,
+		})
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("row %d (%s): %v", row.row, row.trackingNumber, err))
+			continue
+		}
+		added++
+	}
+
+	var resultErr error
+	if len(failures) > 0 {
+		resultErr = fmt.Errorf("failed to import %d rows:\n  %s", len(failures), strings.Join(failures, "\n  "))
+	}
+
+	if formatter.IsJSON() {
+		output := importOutput{Added: added, Skipped: skipped, Failed: append([]string{}, failures...)}
+		if err := formatter.PrintResult(output, resultErr); err != nil {
+			return err
+		}
+		return resultErr
+	}
+
+	if !config.Quiet {
+		formatter.PrintSuccess(fmt.Sprintf("Imported %d shipments, skipped %d", added, skipped))
+	}
+	if resultErr != nil {
+		formatter.PrintError(resultErr)
+	}
+	return resultErr
+}
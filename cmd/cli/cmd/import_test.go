@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"package-tracking/internal/database"
+)
+
+func TestReadImportCSV(t *testing.T) {
+	input := `Order,Tracking Number,Carrier,Item
+1001,1Z999AA10123456784,UPS,Headphones
+1002,9400111899562537866361,,Books
+`
+	rows, mapping, err := readImportCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(rows))
+	}
+	if rows[0].trackingNumber != "1Z999AA10123456784" || rows[0].carrier != "ups" || rows[0].description != "Headphones" {
+		t.Errorf("Unexpected first row: %+v", rows[0])
+	}
+	if rows[1].carrier != "" || rows[1].row != 2 {
+		t.Errorf("Unexpected second row: %+v", rows[1])
+	}
+	if !strings.Contains(mapping, `tracking_number <- "Tracking Number" (column 2)`) {
+		t.Errorf("Unexpected mapping: %s", mapping)
+	}
+
+	if _, _, err := readImportCSV(strings.NewReader("order,carrier\n1,ups\n")); err == nil {
+		t.Error("Expected error without a tracking number column")
+	}
+}
+
+func TestReadImportJSON(t *testing.T) {
+	rows, err := readImportJSON(strings.NewReader(`[{"id":3,"tracking_number":"TBA123456789012","carrier":"amazon","description":"Cable"}]`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rows) != 1 || rows[0].trackingNumber != "TBA123456789012" || rows[0].carrier != "amazon" {
+		t.Errorf("Unexpected rows: %+v", rows)
+	}
+}
+
+func TestPlanImport(t *testing.T) {
+	rows := []importRow{
+		{row: 1, trackingNumber: "1Z999AA10123456784", carrier: "ups"},
+		{row: 2, trackingNumber: "9400111899562537866361"},
+		{row: 3, trackingNumber: "1z999aa10123456784", carrier: "ups"},
+		{row: 4, trackingNumber: "EK123456789US", carrier: "usps"},
+		{row: 5, trackingNumber: "not-a-number"},
+		{row: 6},
+	}
+	existing := []database.Shipment{{ID: 1, TrackingNumber: "EK123456789US"}}
+
+	planImport(rows, existing)
+
+	expected := []string{"", "", "duplicate of row 1", "already tracked", "carrier not detected", "no tracking number"}
+	for i, skip := range expected {
+		if rows[i].skip != skip {
+			t.Errorf("Row %d: expected skip %q, got %q", rows[i].row, skip, rows[i].skip)
+		}
+	}
+	if rows[1].carrier != "usps" || !rows[1].detected {
+		t.Errorf("Expected detected usps carrier for row 2, got %q detected=%v", rows[1].carrier, rows[1].detected)
+	}
+}
@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	cliapi "package-tracking/internal/cli"
+	"package-tracking/internal/database"
+)
+
+// Add form field indexes
+const (
+	addFieldTracking = iota
+	addFieldCarrier
+	addFieldDescription
+)
+
+// addForm holds the state of the add shipment form
+type addForm struct {
+	inputs        []textinput.Model
+	focus         int
+	carrierEdited bool // The user typed a carrier, so stop replacing it with suggestions
+	submitting    bool
+	err           error
+}
+
+// addCompleteMsg is sent when creating a shipment from the add form completes
+type addCompleteMsg struct {
+	shipment *database.Shipment
+	err      error
+}
+
+// newAddForm creates an empty add form with the tracking number focused
+func newAddForm() *addForm {
+	tracking := textinput.New()
+	tracking.Prompt = "Tracking number: "
+	tracking.Placeholder = "1Z999AA10123456784"
+
+	carrier := textinput.New()
+	carrier.Prompt = "Carrier:         "
+	carrier.Placeholder = strings.Join(defaultCarrierCompletions, ", ")
+
+	description := textinput.New()
+	description.Prompt = "Description:     "
+	description.Placeholder = "optional"
+
+	form := &addForm{inputs: []textinput.Model{tracking, carrier, description}}
+	form.inputs[addFieldTracking].Focus()
+	return form
+}
+
+// setFocus moves focus to the field at index i
+func (f *addForm) setFocus(i int) tea.Cmd {
+	f.inputs[f.focus].Blur()
+	f.focus = (i + len(f.inputs)) % len(f.inputs)
+	return f.inputs[f.focus].Focus()
+}
+
+// request builds the create request from the form, validating the required fields
+func (f *addForm) request() (*cliapi.CreateShipmentRequest, error) {
+	trackingNumber := strings.TrimSpace(f.inputs[addFieldTracking].Value())
+	if trackingNumber == "" {
+		return nil, fmt.Errorf("tracking number is required")
+	}
+
+	carrier := strings.ToLower(strings.TrimSpace(f.inputs[addFieldCarrier].Value()))
+	if carrier == "" {
+		return nil, fmt.Errorf("carrier is required")
+	}
+
+	return &cliapi.CreateShipmentRequest{
+		TrackingNumber: trackingNumber,
+		Carrier:        carrier,
+		Description:    strings.TrimSpace(f.inputs[addFieldDescription].Value()),
+	}, nil
+}
+
+// openAddForm shows the add shipment form
+func (m InteractiveTable) openAddForm() (InteractiveTable, tea.Cmd) {
+	m.adding = newAddForm()
+	m.message = ""
+	m.err = nil
+	return m, textinput.Blink
+}
+
+// updateAddForm handles keys while the add form is open
+func (m InteractiveTable) updateAddForm(msg tea.KeyMsg) (InteractiveTable, tea.Cmd) {
+	// Copy the form so earlier model values aren't changed underneath them
+	form := *m.adding
+	form.inputs = append([]textinput.Model(nil), m.adding.inputs...)
+	m.adding = &form
+
+	if form.submitting {
+		if msg.String() == "ctrl+c" {
+			m.quitting = true
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "ctrl+c":
+		m.quitting = true
+		return m, tea.Quit
+	case "esc":
+		m.adding = nil
+		m.message = "Add cancelled"
+		return m, nil
+	case "tab", "down":
+		return m, form.setFocus(form.focus + 1)
+	case "shift+tab", "up":
+		return m, form.setFocus(form.focus - 1)
+	case "enter":
+		if form.focus < len(form.inputs)-1 {
+			return m, form.setFocus(form.focus + 1)
+		}
+		return m.submitAddForm()
+	}
+
+	var cmd tea.Cmd
+	form.inputs[form.focus], cmd = form.inputs[form.focus].Update(msg)
+
+	switch form.focus {
+	case addFieldCarrier:
+		form.carrierEdited = form.inputs[addFieldCarrier].Value() != ""
+	case addFieldTracking:
+		// Suggest a carrier as the tracking number is typed, unless one was entered by hand
+		if !form.carrierEdited {
+			form.inputs[addFieldCarrier].SetValue(detectCarrier(form.inputs[addFieldTracking].Value()))
+		}
+	}
+
+	return m, cmd
+}
+
+// submitAddForm validates the form and creates the shipment
+func (m InteractiveTable) submitAddForm() (InteractiveTable, tea.Cmd) {
+	req, err := m.adding.request()
+	if err != nil {
+		m.adding.err = err
+		return m, nil
+	}
+
+	m.adding.submitting = true
+	m.adding.err = nil
+	m.loading = true
+
+	client := m.client
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg {
+		shipment, err := client.CreateShipment(req)
+		return addCompleteMsg{shipment: shipment, err: err}
+	})
+}
+
+// handleAddComplete adds the created shipment to the table, or reopens the form with the error
+func (m InteractiveTable) handleAddComplete(msg addCompleteMsg) (InteractiveTable, tea.Cmd) {
+	m.loading = false
+	if m.adding == nil {
+		return m, nil
+	}
+
+	if msg.err != nil {
+		form := *m.adding
+		form.submitting = false
+		form.err = msg.err
+		m.adding = &form
+		return m, nil
+	}
+
+	m.adding = nil
+	m.allShipments = append(append([]database.Shipment(nil), m.allShipments...), *msg.shipment)
+	if m.sortField != "" {
+		m.allShipments = sortShipments(m.allShipments, m.sortField, m.sortDesc)
+	}
+	m = m.applyFilter()
+
+	// Select the new shipment if it is visible
+	for i, shipment := range m.shipments {
+		if shipment.ID == msg.shipment.ID {
+			m.table.SetCursor(i)
+			break
+		}
+	}
+
+	m.err = nil
+	m.message = fmt.Sprintf("Added shipment ID %d (%s)", msg.shipment.ID, msg.shipment.TrackingNumber)
+	return m, nil
+}
+
+// addFormView renders the add shipment form
+func (m InteractiveTable) addFormView() string {
+	var b strings.Builder
+
+	title := "Add Shipment"
+	if m.useColor {
+		title = lipgloss.NewStyle().Bold(true).Foreground(m.theme.Accent).Render(title)
+	}
+	b.WriteString(title)
+	b.WriteString("\n\n")
+
+	for _, input := range m.adding.inputs {
+		b.WriteString(input.View())
+		b.WriteString("\n")
+	}
+
+	if !m.adding.carrierEdited {
+		if carrier := detectCarrier(m.adding.inputs[addFieldTracking].Value()); carrier != "" {
+			b.WriteString(fmt.Sprintf("\nDetected carrier: %s\n", carrier))
+		}
+	}
+
+	if m.adding.err != nil {
+		errMsg := fmt.Sprintf("\nError: %v", m.adding.err)
+		if m.useColor {
+			errMsg = lipgloss.NewStyle().Foreground(m.theme.Error).Render(errMsg)
+		}
+		b.WriteString(errMsg)
+		b.WriteString("\n")
+	}
+
+	if m.adding.submitting {
+		b.WriteString(fmt.Sprintf("\n%s Adding shipment...\n", m.spinner.View()))
+	} else {
+		b.WriteString("\ntab/↑/↓ to move, enter on the last field to add, esc to cancel\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	cliapi "package-tracking/internal/cli"
+	"package-tracking/internal/database"
+)
+
+func typeString(model tea.Model, s string) tea.Model {
+	for _, r := range s {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return model
+}
+
+func TestInteractiveTable_AddForm(t *testing.T) {
+	var received cliapi.CreateShipmentRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/shipments" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(database.Shipment{
+			ID:             10,
+			TrackingNumber: received.TrackingNumber,
+			Carrier:        received.Carrier,
+			Description:    received.Description,
+			Status:         "pending",
+		})
+	}))
+	defer server.Close()
+
+	shipments := []database.Shipment{{ID: 1, TrackingNumber: "1234567890", Carrier: "dhl", Status: "delivered"}}
+	table, err := NewInteractiveTable(shipments, cliapi.NewClient(server.URL), nil, "id,tracking,carrier", &cliapi.Config{NoColor: true})
+	if err != nil {
+		t.Fatalf("Failed to create interactive table: %v", err)
+	}
+
+	var model tea.Model = *table
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	if model.(InteractiveTable).adding == nil {
+		t.Fatal("Expected a to open the add form")
+	}
+
+	// Keys bound in the table (q, d) are typed into the form
+	model = typeString(model, "1Z999AA10123456784")
+	m := model.(InteractiveTable)
+	if carrier := m.adding.inputs[addFieldCarrier].Value(); carrier != "ups" {
+		t.Errorf("Expected carrier to be detected as ups, got %q", carrier)
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = typeString(model, "quad desk")
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m = model.(InteractiveTable); m.quitting || !m.adding.submitting || cmd == nil {
+		t.Fatalf("Expected enter on the last field to submit, got quitting=%v submitting=%v", m.quitting, m.adding.submitting)
+	}
+
+	// The batch runs the spinner and the create request; only the request is needed here
+	var done addCompleteMsg
+	for _, c := range cmd().(tea.BatchMsg) {
+		if msg, ok := c().(addCompleteMsg); ok {
+			done = msg
+		}
+	}
+	model, _ = model.Update(done)
+	m = model.(InteractiveTable)
+
+	if received.Carrier != "ups" || received.Description != "quad desk" {
+		t.Errorf("Unexpected create request %+v", received)
+	}
+	if m.adding != nil || len(m.allShipments) != 2 {
+		t.Fatalf("Expected form closed and shipment added, got adding=%v shipments=%d", m.adding != nil, len(m.allShipments))
+	}
+	if selected := m.shipments[m.table.Cursor()]; selected.ID != 10 {
+		t.Errorf("Expected the new shipment to be selected, got ID %d", selected.ID)
+	}
+}
+
+func TestInteractiveTable_AddFormValidation(t *testing.T) {
+	table, err := NewInteractiveTable(nil, nil, nil, "", &cliapi.Config{NoColor: true})
+	if err != nil {
+		t.Fatalf("Failed to create interactive table: %v", err)
+	}
+
+	var model tea.Model = *table
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	model = typeString(model, "unknown")
+	for i := 0; i < 3; i++ {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+
+	m := model.(InteractiveTable)
+	if m.adding == nil || m.adding.err == nil || !strings.Contains(m.View(), "carrier is required") {
+		t.Fatalf("Expected a missing carrier error, got %v", m.adding)
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = model.(InteractiveTable); m.adding != nil {
+		t.Error("Expected esc to close the add form")
+	}
+}
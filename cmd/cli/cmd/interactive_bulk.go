@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// selectionMarker prefixes the first cell of selected rows; unselected rows get the same
+// width of padding so columns stay aligned
+const (
+	selectionMarker  = "✓ "
+	selectionPadding = "  "
+)
+
+// bulkAction identifies an operation applied to every selected shipment
+type bulkAction int
+
+const (
+	bulkRefresh bulkAction = iota
+	bulkDelete
+)
+
+// bulkOperation tracks the progress of a bulk action run one shipment at a time
+type bulkOperation struct {
+	action    bulkAction
+	ids       []int
+	next      int
+	succeeded []int
+	failures  []string
+}
+
+// bulkStepMsg is sent when the bulk action for one shipment completes
+type bulkStepMsg struct {
+	shipmentID int
+	err        error
+}
+
+// verb returns the progressive form of the action for progress messages
+func (a bulkAction) verb() string {
+	if a == bulkDelete {
+		return "Deleting"
+	}
+	return "Refreshing"
+}
+
+// pastTense returns the past form of the action for the summary message
+func (a bulkAction) pastTense() string {
+	if a == bulkDelete {
+		return "Deleted"
+	}
+	return "Refreshed"
+}
+
+// toggleSelection selects or deselects the shipment under the cursor and moves down
+func (m InteractiveTable) toggleSelection() InteractiveTable {
+	cursor := m.table.Cursor()
+	if cursor < 0 || cursor >= len(m.shipments) {
+		return m
+	}
+
+	id := m.shipments[cursor].ID
+	if m.selected[id] {
+		delete(m.selected, id)
+	} else {
+		m.selected[id] = true
+	}
+
+	m = m.applyFilter()
+	m.table.SetCursor(cursor + 1)
+	return m
+}
+
+// clearSelection deselects every shipment
+func (m InteractiveTable) clearSelection() InteractiveTable {
+	m.selected = make(map[int]bool)
+	return m.reapplyKeepingCursor()
+}
+
+// selectedIDs returns the IDs of the selected shipments in ascending order
+func (m InteractiveTable) selectedIDs() []int {
+	ids := make([]int, 0, len(m.selected))
+	for id := range m.selected {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// startBulk begins running action against every selected shipment
+func (m InteractiveTable) startBulk(action bulkAction) (InteractiveTable, tea.Cmd) {
+	if m.bulk != nil {
+		m.message = "A bulk operation is already running"
+		return m, nil
+	}
+
+	ids := m.selectedIDs()
+	if len(ids) == 0 {
+		m.message = "No shipments selected"
+		return m, nil
+	}
+
+	m.bulk = &bulkOperation{action: action, ids: ids}
+	m.loading = true
+	m.err = nil
+	m.message = fmt.Sprintf("%s %d of %d...", action.verb(), 1, len(ids))
+
+	return m, tea.Batch(m.spinner.Tick, m.bulkStep(action, ids[0]))
+}
+
+// bulkStep runs the bulk action for a single shipment
+func (m InteractiveTable) bulkStep(action bulkAction, id int) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		switch action {
+		case bulkRefresh:
+			_, err = m.client.RefreshShipment(id)
+		case bulkDelete:
+			err = m.client.DeleteShipment(id)
+		}
+		return bulkStepMsg{shipmentID: id, err: err}
+	}
+}
+
+// handleBulkStep records the result for one shipment and starts the next, or summarizes
+// the operation once every selected shipment has been processed
+func (m InteractiveTable) handleBulkStep(msg bulkStepMsg) (InteractiveTable, tea.Cmd) {
+	if m.bulk == nil {
+		return m, nil
+	}
+
+	// Copy the operation so earlier model values aren't changed underneath them
+	bulk := *m.bulk
+	if msg.err != nil {
+		bulk.failures = append(bulk.failures, fmt.Sprintf("ID %d: %v", msg.shipmentID, msg.err))
+	} else {
+		bulk.succeeded = append(bulk.succeeded, msg.shipmentID)
+	}
+	bulk.next++
+	m.bulk = &bulk
+
+	if bulk.next < len(bulk.ids) {
+		m.message = fmt.Sprintf("%s %d of %d...", bulk.action.verb(), bulk.next+1, len(bulk.ids))
+		return m, m.bulkStep(bulk.action, bulk.ids[bulk.next])
+	}
+
+	return m.finishBulk(), nil
+}
+
+// finishBulk applies the results of a completed bulk operation and reports them
+func (m InteractiveTable) finishBulk() InteractiveTable {
+	bulk := m.bulk
+	m.bulk = nil
+	m.loading = false
+
+	// Successful shipments leave the selection; failed ones stay selected to retry
+	for _, id := range bulk.succeeded {
+		delete(m.selected, id)
+	}
+
+	if bulk.action == bulkDelete {
+		for _, id := range bulk.succeeded {
+			m = m.removeShipmentFromTable(id)
+		}
+	} else {
+		m = m.reapplyKeepingCursor()
+	}
+
+	m.message = fmt.Sprintf("%s %d of %d shipments", bulk.action.pastTense(), len(bulk.succeeded), len(bulk.ids))
+	if len(bulk.failures) > 0 {
+		m.err = fmt.Errorf("%d failed", len(bulk.failures))
+		m.message += fmt.Sprintf(" - %d failed:\n  %s", len(bulk.failures), strings.Join(bulk.failures, "\n  "))
+	}
+	return m
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	cliapi "package-tracking/internal/cli"
+	"package-tracking/internal/database"
+)
+
+// runBulk drives a started bulk operation to completion, one shipment at a time
+func runBulk(t *testing.T, model tea.Model) InteractiveTable {
+	t.Helper()
+	m := model.(InteractiveTable)
+	for m.bulk != nil {
+		msg := m.bulkStep(m.bulk.action, m.bulk.ids[m.bulk.next])()
+		model, _ = model.Update(msg)
+		m = model.(InteractiveTable)
+	}
+	return m
+}
+
+func TestInteractiveTable_BulkDelete(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("Expected DELETE, got %s", r.Method)
+		}
+		if r.URL.Path == "/api/shipments/2" {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":"database locked"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	shipments := []database.Shipment{
+		{ID: 1, TrackingNumber: "1Z999AA10123456784", Carrier: "ups", Status: "in_transit"},
+		{ID: 2, TrackingNumber: "9400111899562537866361", Carrier: "usps", Status: "pending"},
+		{ID: 3, TrackingNumber: "1234567890", Carrier: "dhl", Status: "delivered"},
+	}
+
+	table, err := NewInteractiveTable(shipments, cliapi.NewClient(server.URL), nil, "id,carrier", &cliapi.Config{NoColor: true})
+	if err != nil {
+		t.Fatalf("Failed to create interactive table: %v", err)
+	}
+
+	// Space selects the current row and moves down, so this selects 1 and 2
+	var model tea.Model = *table
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+	model, _ = model.Update(space)
+	model, _ = model.Update(space)
+	m := model.(InteractiveTable)
+	if len(m.selected) != 2 || !m.selected[1] || !m.selected[2] {
+		t.Fatalf("Expected shipments 1 and 2 selected, got %v", m.selected)
+	}
+	if rows := m.table.Rows(); !strings.HasPrefix(rows[0][0], selectionMarker) || strings.HasPrefix(rows[2][0], selectionMarker) {
+		t.Errorf("Expected selection markers on selected rows only, got %v", rows)
+	}
+	if !strings.Contains(m.statusLine(), "2 selected") {
+		t.Errorf("Expected status line to show the selection, got %q", m.statusLine())
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if m = model.(InteractiveTable); !m.showDeleteConfirm || !strings.Contains(m.View(), "Delete 2 selected shipments?") {
+		t.Fatal("Expected confirmation for deleting the selected shipments")
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = runBulk(t, model)
+
+	if len(m.allShipments) != 2 || m.allShipments[0].ID != 2 {
+		t.Errorf("Expected only shipment 1 to be removed, got %v", shipmentIDs(m.allShipments))
+	}
+	if len(m.selected) != 1 || !m.selected[2] {
+		t.Errorf("Expected the failed shipment to stay selected, got %v", m.selected)
+	}
+	if m.err == nil || !strings.Contains(m.message, "Deleted 1 of 2 shipments") || !strings.Contains(m.message, "ID 2:") {
+		t.Errorf("Expected aggregated result with the failure, got %q", m.message)
+	}
+}
+
+func TestInteractiveTable_ClearSelection(t *testing.T) {
+	table, err := NewInteractiveTable(sortTestShipments(), nil, nil, "id", &cliapi.Config{NoColor: true})
+	if err != nil {
+		t.Fatalf("Failed to create interactive table: %v", err)
+	}
+
+	var model tea.Model = *table
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m := model.(InteractiveTable); len(m.selected) != 0 {
+		t.Errorf("Expected esc to clear the selection, got %v", m.selected)
+	}
+}
@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"package-tracking/internal/database"
+)
+
+// detailLayout is where the detail pane is shown relative to the table
+type detailLayout int
+
+const (
+	detailRight detailLayout = iota
+	detailBottom
+)
+
+const (
+	defaultTableHeight = 15
+	defaultDetailSize  = 40 // Percent of the window the detail pane takes
+	minDetailSize      = 20
+	maxDetailSize      = 70
+	detailSizeStep     = 10
+
+	// Window size assumed until the terminal reports its own
+	defaultWindowWidth  = 80
+	defaultWindowHeight = 24
+)
+
+// detailEventsMsg carries the events fetched for the detail pane
+type detailEventsMsg struct {
+	shipmentID int
+	events     []database.TrackingEvent
+	err        error
+}
+
+// detailEventsResult is the outcome of fetching a shipment's events for the detail pane
+type detailEventsResult struct {
+	events []database.TrackingEvent
+	err    error
+}
+
+// currentShipment returns the shipment under the cursor
+func (m InteractiveTable) currentShipment() (database.Shipment, bool) {
+	cursor := m.table.Cursor()
+	if cursor < 0 || cursor >= len(m.shipments) {
+		return database.Shipment{}, false
+	}
+	return m.shipments[cursor], true
+}
+
+// windowSize returns the terminal size, or a typical size if it hasn't been reported yet
+func (m InteractiveTable) windowSize() (int, int) {
+	width, height := m.width, m.height
+	if width <= 0 {
+		width = defaultWindowWidth
+	}
+	if height <= 0 {
+		height = defaultWindowHeight
+	}
+	return width, height
+}
+
+// toggleDetail opens or closes the detail pane
+func (m InteractiveTable) toggleDetail() (InteractiveTable, tea.Cmd) {
+	if !m.detailOpen && len(m.shipments) == 0 {
+		m.message = "No shipments to view"
+		return m, nil
+	}
+	m.detailOpen = !m.detailOpen
+	m.message = ""
+	return m.layoutTable(), nil
+}
+
+// switchDetailLayout moves the detail pane between the right of the table and below it
+func (m InteractiveTable) switchDetailLayout() InteractiveTable {
+	if m.detailLayout == detailRight {
+		m.detailLayout = detailBottom
+	} else {
+		m.detailLayout = detailRight
+	}
+	return m.layoutTable()
+}
+
+// resizeDetail grows or shrinks the detail pane by delta percent of the window
+func (m InteractiveTable) resizeDetail(delta int) InteractiveTable {
+	m.detailSize += delta
+	if m.detailSize < minDetailSize {
+		m.detailSize = minDetailSize
+	}
+	if m.detailSize > maxDetailSize {
+		m.detailSize = maxDetailSize
+	}
+	return m.layoutTable()
+}
+
+// detailPaneSize returns the width or height of the detail pane, depending on its layout
+func (m InteractiveTable) detailPaneSize() int {
+	width, height := m.windowSize()
+	if m.detailLayout == detailBottom {
+		return height * m.detailSize / 100
+	}
+	return width * m.detailSize / 100
+}
+
+// layoutTable shortens the table to make room for a detail pane below it
+func (m InteractiveTable) layoutTable() InteractiveTable {
+	height := defaultTableHeight
+	if m.detailOpen && m.detailLayout == detailBottom {
+		_, windowHeight := m.windowSize()
+		// Leave room for the table header, the message and the status line
+		height = windowHeight - m.detailPaneSize() - 5
+		if height > defaultTableHeight {
+			height = defaultTableHeight
+		}
+		if height < 3 {
+			height = 3
+		}
+	}
+	m.table.SetHeight(height)
+	return m
+}
+
+// loadDetailEvents fetches the events of the shipment under the cursor when the detail pane
+// is open and they haven't been fetched yet
+func (m InteractiveTable) loadDetailEvents() tea.Cmd {
+	if !m.detailOpen || m.client == nil {
+		return nil
+	}
+	shipment, ok := m.currentShipment()
+	if !ok {
+		return nil
+	}
+	if _, cached := m.detailEvents[shipment.ID]; cached || m.detailPending[shipment.ID] {
+		return nil
+	}
+
+	m.detailPending[shipment.ID] = true
+	client := m.client
+	return func() tea.Msg {
+		events, err := client.GetEvents(shipment.ID)
+		return detailEventsMsg{shipmentID: shipment.ID, events: events, err: err}
+	}
+}
+
+// handleDetailEvents caches events fetched for the detail pane. Errors are cached too, so a
+// failing shipment isn't fetched again on every key press.
+func (m InteractiveTable) handleDetailEvents(msg detailEventsMsg) InteractiveTable {
+	delete(m.detailPending, msg.shipmentID)
+	m.detailEvents[msg.shipmentID] = detailEventsResult{events: msg.events, err: msg.err}
+	return m
+}
+
+// forgetDetailEvents discards cached events for a shipment so they are fetched again
+func (m InteractiveTable) forgetDetailEvents(id int) {
+	delete(m.detailEvents, id)
+}
+
+// withDetailPane places the detail pane next to or below the rendered table
+func (m InteractiveTable) withDetailPane(tableView string) string {
+	width, _ := m.windowSize()
+	size := m.detailPaneSize()
+
+	if m.detailLayout == detailBottom {
+		return lipgloss.JoinVertical(lipgloss.Left, tableView, m.detailPaneView(width, size))
+	}
+
+	tableView = lipgloss.NewStyle().MaxWidth(width - size).Render(tableView)
+	height := lipgloss.Height(tableView)
+	if height < 8 {
+		height = 8
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, tableView, m.detailPaneView(size, height))
+}
+
+// detailPaneView renders the shipment under the cursor and its most recent events in a
+// bordered box of the given outer size
+func (m InteractiveTable) detailPaneView(width, height int) string {
+	// The border takes two columns and rows, and the padding two more columns
+	innerWidth := width - 4
+	innerHeight := height - 2
+	if innerWidth < 10 {
+		innerWidth = 10
+	}
+	if innerHeight < 1 {
+		innerHeight = 1
+	}
+
+	lines := m.detailLines(innerWidth)
+	if len(lines) > innerHeight {
+		lines = lines[:innerHeight]
+	}
+
+	style := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		Padding(0, 1).
+		Width(innerWidth + 2).
+		Height(innerHeight)
+	if m.useColor {
+		style = style.BorderForeground(m.theme.Border)
+	}
+	return style.Render(strings.Join(lines, "\n"))
+}
+
+// detailLines returns the contents of the detail pane, each line at most width characters
+func (m InteractiveTable) detailLines(width int) []string {
+	shipment, ok := m.currentShipment()
+	if !ok {
+		return []string{"No shipment selected"}
+	}
+
+	heading := func(text string) string {
+		text = truncateString(text, width)
+		if m.useColor {
+			return lipgloss.NewStyle().Bold(true).Foreground(m.theme.Accent).Render(text)
+		}
+		return text
+	}
+	field := func(name, value string) string {
+		return truncateString(fmt.Sprintf("%-10s %s", name+":", value), width)
+	}
+
+	expected := "N/A"
+	if shipment.ExpectedDelivery != nil {
+		expected = shipment.ExpectedDelivery.Format("2006-01-02")
+	}
+	status := truncateString(fmt.Sprintf("%-10s ", "Status:"), width)
+	if len(status) < width {
+		statusText := truncateString(shipment.Status, width-len(status))
+		if m.useColor {
+			statusText = m.getStatusColorForEvent(statusText)
+		}
+		status += statusText
+	}
+
+	lines := []string{
+		heading(fmt.Sprintf("Shipment %d", shipment.ID)),
+		field("Tracking", shipment.TrackingNumber),
+		field("Carrier", shipment.Carrier),
+		status,
+		field("Desc", shipment.Description),
+		field("Expected", expected),
+		field("Updated", shipment.UpdatedAt.Format("2006-01-02 15:04")),
+		"",
+		heading("Recent events"),
+	}
+
+	result, fetched := m.detailEvents[shipment.ID]
+	switch {
+	case m.client == nil:
+		lines = append(lines, "Events unavailable")
+	case !fetched:
+		lines = append(lines, "Loading events...")
+	case result.err != nil:
+		lines = append(lines, truncateString(fmt.Sprintf("Error: %v", result.err), width))
+	case len(result.events) == 0:
+		lines = append(lines, "No tracking events")
+	default:
+		// Events come oldest first; show the newest first
+		for i := len(result.events) - 1; i >= 0; i-- {
+			event := result.events[i]
+			line := event.Timestamp.Format("01-02 15:04") + " " + event.Description
+			if event.Location != "" {
+				line += " (" + event.Location + ")"
+			}
+			lines = append(lines, truncateString(line, width))
+		}
+	}
+	return lines
+}
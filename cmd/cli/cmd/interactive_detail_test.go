@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	cliapi "package-tracking/internal/cli"
+	"package-tracking/internal/database"
+)
+
+func TestInteractiveTable_DetailPane(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		events := []database.TrackingEvent{
+			{ID: 1, ShipmentID: 1, Timestamp: time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC), Description: "Label created"},
+			{ID: 2, ShipmentID: 1, Timestamp: time.Date(2025, 6, 2, 14, 30, 0, 0, time.UTC), Location: "Memphis, TN", Description: "Departed facility"},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(events)
+	}))
+	defer server.Close()
+
+	shipments := []database.Shipment{
+		{ID: 1, TrackingNumber: "1Z999AA10123456784", Carrier: "ups", Status: "in_transit", Description: "Laptop"},
+		{ID: 2, TrackingNumber: "9400111899562537866361", Carrier: "usps", Status: "pre_ship"},
+	}
+
+	table, err := NewInteractiveTable(shipments, cliapi.NewClient(server.URL), nil, "id,tracking,status", &cliapi.Config{NoColor: true})
+	if err != nil {
+		t.Fatalf("Failed to create interactive table: %v", err)
+	}
+
+	var model tea.Model = *table
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m := model.(InteractiveTable)
+	if !m.detailOpen || cmd == nil {
+		t.Fatal("Expected enter to open the detail pane and fetch events")
+	}
+	if view := m.View(); !strings.Contains(view, "Loading events...") || !strings.Contains(view, "Laptop") {
+		t.Errorf("Expected the pane to show the shipment while events load, got:\n%s", view)
+	}
+
+	model, _ = model.Update(cmd())
+	m = model.(InteractiveTable)
+	view := m.View()
+	if !strings.Contains(view, "Departed") || !strings.Contains(view, "Label created") {
+		t.Errorf("Expected the pane to show recent events, got:\n%s", view)
+	}
+	if strings.Index(view, "Departed") > strings.Index(view, "Label created") {
+		t.Error("Expected the newest event first")
+	}
+	if !strings.Contains(view, "Shipment 1 of 2") {
+		t.Error("Expected the table to stay visible with the pane open")
+	}
+
+	// Cached events aren't fetched again
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("Expected events to be fetched once per shipment, got %d requests", got)
+	}
+
+	// The pane can move below the table, which shrinks to make room
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	m = model.(InteractiveTable)
+	if m.detailLayout != detailBottom || m.table.Height() >= defaultTableHeight {
+		t.Errorf("Expected the pane below a shorter table, got layout %v height %d", m.detailLayout, m.table.Height())
+	}
+
+	for i := 0; i < 10; i++ {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("+")})
+	}
+	if size := model.(InteractiveTable).detailSize; size != maxDetailSize {
+		t.Errorf("Expected the pane to stop growing at %d%%, got %d%%", maxDetailSize, size)
+	}
+	for i := 0; i < 10; i++ {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-")})
+	}
+	if size := model.(InteractiveTable).detailSize; size != minDetailSize {
+		t.Errorf("Expected the pane to stop shrinking at %d%%, got %d%%", minDetailSize, size)
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = model.(InteractiveTable)
+	if m.detailOpen || m.table.Height() != defaultTableHeight {
+		t.Errorf("Expected esc to close the pane and restore the table, got open=%v height %d", m.detailOpen, m.table.Height())
+	}
+}
@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+
+	"package-tracking/internal/database"
+)
+
+// filterableFields are the shipment fields the interactive filter searches
+var filterableFields = []string{"tracking", "description", "carrier", "status"}
+
+// filterHighlightStyle marks the characters that matched the filter
+func (m InteractiveTable) filterHighlightStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Bold(true).Foreground(m.theme.Highlight)
+}
+
+// fuzzyMatch reports the rune positions in text that match query as a case-insensitive
+// subsequence, or nil if text doesn't match. Whitespace in the query is ignored, and
+// consecutive matches are preferred so "1z99" highlights a contiguous run when possible.
+func fuzzyMatch(query, text string) []int {
+	var pattern []rune
+	for _, r := range strings.ToLower(query) {
+		if !unicode.IsSpace(r) {
+			pattern = append(pattern, r)
+		}
+	}
+	if len(pattern) == 0 {
+		return nil
+	}
+
+	runes := []rune(strings.ToLower(text))
+
+	// A contiguous substring match reads best, so try that first
+	if start := indexRunes(runes, pattern); start >= 0 {
+		positions := make([]int, len(pattern))
+		for i := range pattern {
+			positions[i] = start + i
+		}
+		return positions
+	}
+
+	positions := make([]int, 0, len(pattern))
+	p := 0
+	for i, r := range runes {
+		if p < len(pattern) && r == pattern[p] {
+			positions = append(positions, i)
+			p++
+		}
+	}
+	if p < len(pattern) {
+		return nil
+	}
+	return positions
+}
+
+// indexRunes returns the index of the first occurrence of sub in s, or -1
+func indexRunes(s, sub []rune) int {
+	for i := 0; i+len(sub) <= len(s); i++ {
+		match := true
+		for j := range sub {
+			if s[i+j] != sub[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+// shipmentMatches returns the matched positions for each filterable field of a shipment,
+// keyed by field name. ok is false if no field matches the query.
+func shipmentMatches(shipment database.Shipment, query string) (matches map[string][]int, ok bool) {
+	matches = make(map[string][]int)
+	for _, field := range filterableFields {
+		if positions := fuzzyMatch(query, getFieldValue(shipment, field)); positions != nil {
+			matches[field] = positions
+		}
+	}
+	return matches, len(matches) > 0
+}
+
+// highlightCell truncates value to width and highlights the matched rune positions. The
+// bubbles table measures cells including escape sequences, so the returned overhead is how
+// much wider than its visible text the cell is.
+func highlightCell(value string, positions []int, width int, style lipgloss.Style) (cell string, overhead int) {
+	runes := []rune(value)
+	truncated := false
+	if width > 0 && len(runes) > width {
+		runes = runes[:width-1]
+		truncated = true
+	}
+
+	matched := make(map[int]bool, len(positions))
+	for _, pos := range positions {
+		matched[pos] = true
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(runes); {
+		j := i
+		for j < len(runes) && matched[j] == matched[i] {
+			j++
+		}
+		run := string(runes[i:j])
+		if matched[i] {
+			run = style.Render(run)
+		}
+		b.WriteString(run)
+		i = j
+	}
+	if truncated {
+		b.WriteString("…")
+		runes = append(runes, '…')
+	}
+
+	cell = b.String()
+	return cell, utf8.RuneCountInString(cell) - len(runes)
+}
+
+// applyFilter narrows the visible shipments to those matching the filter input and rebuilds
+// the table rows, highlighting matches when color is enabled and marking selected rows
+func (m InteractiveTable) applyFilter() InteractiveTable {
+	query := strings.TrimSpace(m.filterInput.Value())
+
+	columns := make([]table.Column, len(m.baseColumns))
+	copy(columns, m.baseColumns)
+
+	// Mark the sorted column, widening it if the indicator wouldn't fit
+	for i, field := range m.fields {
+		if field == m.sortField {
+			columns[i].Title += m.sortIndicator()
+			if width := utf8.RuneCountInString(columns[i].Title); width > columns[i].Width {
+				columns[i].Width = width
+			}
+		}
+	}
+
+	filtered := make([]database.Shipment, 0, len(m.allShipments))
+	rows := make([]table.Row, 0, len(m.allShipments))
+	extraWidth := make([]int, len(columns))
+
+	for _, shipment := range m.allShipments {
+		row := shipmentToRow(shipment, m.fields)
+		overheads := make([]int, len(row))
+
+		if query != "" {
+			matches, ok := shipmentMatches(shipment, query)
+			if !ok {
+				continue
+			}
+			if m.useColor {
+				for i, field := range m.fields {
+					positions, matched := matches[field]
+					if !matched {
+						continue
+					}
+					cell, overhead := highlightCell(row[i], positions, columns[i].Width, m.filterHighlightStyle())
+					row[i] = cell
+					overheads[i] = overhead
+				}
+			}
+		}
+
+		if m.changed[shipment.ID] {
+			i := m.changedColumn()
+			before := utf8.RuneCountInString(row[i])
+			row[i] = m.markChanged(row[i])
+			overheads[i] += utf8.RuneCountInString(row[i]) - before
+		}
+
+		for i, overhead := range overheads {
+			if overhead > extraWidth[i] {
+				extraWidth[i] = overhead
+			}
+		}
+
+		if m.selected[shipment.ID] {
+			row[0] = selectionMarker + row[0]
+		} else {
+			row[0] = selectionPadding + row[0]
+		}
+
+		filtered = append(filtered, shipment)
+		rows = append(rows, row)
+	}
+
+	// Widen highlighted columns so escape sequences and markers don't cause the table to
+	// truncate them
+	for i := range columns {
+		columns[i].Width += extraWidth[i]
+	}
+	columns[0].Width += utf8.RuneCountInString(selectionPadding)
+
+	m.shipments = filtered
+	m.columns = columns
+	m.table.SetColumns(columns)
+	m.table.SetRows(rows)
+	m.table.SetCursor(0)
+	return m
+}
+
+// reapplyKeepingCursor rebuilds the rows while keeping the selected shipment under the cursor
+func (m InteractiveTable) reapplyKeepingCursor() InteractiveTable {
+	cursor := m.table.Cursor()
+	selectedID := 0
+	if cursor >= 0 && cursor < len(m.shipments) {
+		selectedID = m.shipments[cursor].ID
+	}
+
+	m = m.applyFilter()
+
+	for i, shipment := range m.shipments {
+		if shipment.ID == selectedID {
+			m.table.SetCursor(i)
+			return m
+		}
+	}
+	m.table.SetCursor(cursor)
+	return m
+}
+
+// changedColumn returns the index of the column marked for shipments whose status changed:
+// the status column if displayed, otherwise the first column
+func (m InteractiveTable) changedColumn() int {
+	for i, field := range m.fields {
+		if field == "status" {
+			return i
+		}
+	}
+	return 0
+}
+
+// filterActive reports whether a filter is narrowing the table
+func (m InteractiveTable) filterActive() bool {
+	return strings.TrimSpace(m.filterInput.Value()) != ""
+}
+
+// clearFilter removes the filter and shows all shipments again
+func (m InteractiveTable) clearFilter() InteractiveTable {
+	m.filtering = false
+	m.filterInput.Blur()
+	m.filterInput.Reset()
+	return m.applyFilter()
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	cliapi "package-tracking/internal/cli"
+	"package-tracking/internal/database"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		text     string
+		expected []int
+	}{
+		{"contiguous substring", "top", "Laptop", []int{3, 4, 5}},
+		{"case insensitive", "UPS", "ups", []int{0, 1, 2}},
+		{"subsequence", "lpt", "Laptop", []int{0, 2, 3}},
+		{"whitespace ignored", "in tr", "in_transit", []int{0, 1, 3, 4}},
+		{"no match", "xyz", "Laptop", nil},
+		{"empty query", "", "Laptop", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := fuzzyMatch(tt.query, tt.text)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("fuzzyMatch(%q, %q) = %v, want %v", tt.query, tt.text, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHighlightCell(t *testing.T) {
+	plain := lipgloss.NewStyle()
+
+	cell, overhead := highlightCell("Laptop", []int{3, 4, 5}, 20, plain)
+	if cell != "Laptop" || overhead != 0 {
+		t.Errorf("Expected unstyled cell unchanged, got %q (overhead %d)", cell, overhead)
+	}
+
+	cell, _ = highlightCell("A very long description", []int{0}, 8, plain)
+	if cell != "A very …" {
+		t.Errorf("Expected cell truncated to width, got %q", cell)
+	}
+}
+
+func TestInteractiveTable_Filter(t *testing.T) {
+	shipments := []database.Shipment{
+		{ID: 1, TrackingNumber: "1Z999AA10123456784", Carrier: "ups", Description: "Laptop", Status: "in_transit"},
+		{ID: 2, TrackingNumber: "9400111899562537866361", Carrier: "usps", Description: "Books", Status: "pending"},
+		{ID: 3, TrackingNumber: "1234567890", Carrier: "dhl", Description: "Headphones", Status: "delivered"},
+	}
+
+	table, err := NewInteractiveTable(shipments, nil, nil, "", &cliapi.Config{NoColor: true})
+	if err != nil {
+		t.Fatalf("Failed to create interactive table: %v", err)
+	}
+
+	var model tea.Model = *table
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	if !model.(InteractiveTable).filtering {
+		t.Fatal("Expected / to open the filter input")
+	}
+
+	for _, r := range "usps" {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	m := model.(InteractiveTable)
+	if len(m.shipments) != 1 || m.shipments[0].ID != 2 {
+		t.Fatalf("Expected only the USPS shipment to match, got %+v", m.shipments)
+	}
+	if len(m.table.Rows()) != 1 {
+		t.Errorf("Expected 1 table row, got %d", len(m.table.Rows()))
+	}
+	if !strings.Contains(m.statusLine(), "filtered from 3") {
+		t.Errorf("Expected status line to mention the filter, got %q", m.statusLine())
+	}
+
+	// Typing keys that are normally bound (q) must go to the filter, not quit
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	m = model.(InteractiveTable)
+	if m.quitting || len(m.shipments) != 0 {
+		t.Errorf("Expected q to narrow the filter to no matches, got quitting=%v matches=%d", m.quitting, len(m.shipments))
+	}
+
+	// Esc clears the filter and shows everything again
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = model.(InteractiveTable)
+	if m.filtering || m.filterActive() || len(m.shipments) != 3 {
+		t.Errorf("Expected esc to clear the filter, got filtering=%v active=%v shipments=%d",
+			m.filtering, m.filterActive(), len(m.shipments))
+	}
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	cliapi "package-tracking/internal/cli"
+	"package-tracking/internal/database"
+)
+
+// sortCellPadding is the horizontal padding the table's default styles add around each cell
+const sortCellPadding = 2
+
+// sortSavedMsg is sent when the chosen sort has been written to the config file
+type sortSavedMsg struct {
+	err error
+}
+
+// compareShipments orders two shipments by a field, returning -1, 0 or 1. Dates compare
+// chronologically and shipments without an expected delivery sort last.
+func compareShipments(a, b database.Shipment, field string) int {
+	switch field {
+	case "id":
+		return compareInts(a.ID, b.ID)
+	case "created":
+		return compareTimes(a.CreatedAt, b.CreatedAt)
+	case "updated":
+		return compareTimes(a.UpdatedAt, b.UpdatedAt)
+	case "delivery":
+		switch {
+		case a.ExpectedDelivery == nil && b.ExpectedDelivery == nil:
+			return 0
+		case a.ExpectedDelivery == nil:
+			return 1
+		case b.ExpectedDelivery == nil:
+			return -1
+		}
+		return compareTimes(*a.ExpectedDelivery, *b.ExpectedDelivery)
+	default:
+		return strings.Compare(
+			strings.ToLower(getFieldValue(a, field)),
+			strings.ToLower(getFieldValue(b, field)))
+	}
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
+
+// sortShipments returns a copy of shipments stably sorted by field
+func sortShipments(shipments []database.Shipment, field string, descending bool) []database.Shipment {
+	sorted := make([]database.Shipment, len(shipments))
+	copy(sorted, shipments)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		cmp := compareShipments(sorted[i], sorted[j], field)
+		if descending {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+	return sorted
+}
+
+// sortIndicator returns the header suffix marking the sorted column
+func (m InteractiveTable) sortIndicator() string {
+	if m.sortDesc {
+		return " ▼"
+	}
+	return " ▲"
+}
+
+// sortBy sorts the table by field, keeping the selected shipment selected, and returns a
+// command that saves the choice to the CLI config
+func (m InteractiveTable) sortBy(field string, descending bool) (InteractiveTable, tea.Cmd) {
+	m.sortField = field
+	m.sortDesc = descending
+	m.allShipments = sortShipments(m.allShipments, field, descending)
+	m = m.reapplyKeepingCursor()
+
+	direction := "ascending"
+	if descending {
+		direction = "descending"
+	}
+	m.message = fmt.Sprintf("Sorted by %s (%s)", getFieldDisplayName(field), direction)
+	m.err = nil
+
+	return m, m.saveSort()
+}
+
+// cycleSort sorts by the next displayed column, starting from the first
+func (m InteractiveTable) cycleSort() (InteractiveTable, tea.Cmd) {
+	next := 0
+	for i, field := range m.fields {
+		if field == m.sortField {
+			next = (i + 1) % len(m.fields)
+			break
+		}
+	}
+	return m.sortBy(m.fields[next], false)
+}
+
+// reverseSort flips the direction of the current sort
+func (m InteractiveTable) reverseSort() (InteractiveTable, tea.Cmd) {
+	if m.sortField == "" {
+		return m.sortBy(m.fields[0], true)
+	}
+	return m.sortBy(m.sortField, !m.sortDesc)
+}
+
+// handleHeaderClick sorts by the clicked column, reversing the direction if it is already
+// the sorted column
+func (m InteractiveTable) handleHeaderClick(msg tea.MouseMsg) (InteractiveTable, tea.Cmd) {
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft || msg.Y != m.headerLine() {
+		return m, nil
+	}
+
+	column := m.columnAt(msg.X)
+	if column < 0 {
+		return m, nil
+	}
+
+	field := m.fields[column]
+	if field == m.sortField {
+		return m.sortBy(field, !m.sortDesc)
+	}
+	return m.sortBy(field, false)
+}
+
+// headerLine returns the screen line the table header is drawn on, counting the lines View
+// writes above the table
+func (m InteractiveTable) headerLine() int {
+	line := 0
+	if m.showHelp {
+		line += strings.Count(m.helpView(), "\n") + 1
+	}
+	if m.loading {
+		line++
+	}
+	if m.filtering || m.filterActive() {
+		line++
+	}
+	return line
+}
+
+// columnAt returns the index of the column drawn at screen column x, or -1
+func (m InteractiveTable) columnAt(x int) int {
+	start := 0
+	for i, column := range m.columns {
+		end := start + column.Width + sortCellPadding
+		if x >= start && x < end {
+			return i
+		}
+		start = end
+	}
+	return -1
+}
+
+// saveSort persists the current sort to the CLI config file
+func (m InteractiveTable) saveSort() tea.Cmd {
+	m.config.SortBy = m.sortField
+	m.config.SortDescending = m.sortDesc
+	profile, field, descending := m.config.Profile, m.sortField, m.sortDesc
+
+	return func() tea.Msg {
+		return sortSavedMsg{err: cliapi.SaveSortPreference(profile, field, descending)}
+	}
+}
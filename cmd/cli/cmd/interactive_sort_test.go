@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	cliapi "package-tracking/internal/cli"
+	"package-tracking/internal/database"
+)
+
+func sortTestShipments() []database.Shipment {
+	base := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	soon := base.Add(48 * time.Hour)
+	later := base.Add(96 * time.Hour)
+	return []database.Shipment{
+		{ID: 1, TrackingNumber: "1Z999AA10123456784", Carrier: "ups", Status: "in_transit", CreatedAt: base.Add(2 * time.Hour), ExpectedDelivery: &later},
+		{ID: 2, TrackingNumber: "9400111899562537866361", Carrier: "usps", Status: "pending", CreatedAt: base},
+		{ID: 3, TrackingNumber: "1234567890", Carrier: "dhl", Status: "delivered", CreatedAt: base.Add(time.Hour), ExpectedDelivery: &soon},
+	}
+}
+
+func shipmentIDs(shipments []database.Shipment) []int {
+	ids := make([]int, len(shipments))
+	for i, shipment := range shipments {
+		ids[i] = shipment.ID
+	}
+	return ids
+}
+
+func TestSortShipments(t *testing.T) {
+	tests := []struct {
+		field      string
+		descending bool
+		expected   []int
+	}{
+		{"created", false, []int{2, 3, 1}},
+		{"created", true, []int{1, 3, 2}},
+		{"carrier", false, []int{3, 1, 2}},
+		{"status", false, []int{3, 1, 2}},
+		{"delivery", false, []int{3, 1, 2}}, // No expected delivery sorts last
+	}
+
+	for _, tt := range tests {
+		sorted := sortShipments(sortTestShipments(), tt.field, tt.descending)
+		ids := shipmentIDs(sorted)
+		for i := range ids {
+			if ids[i] != tt.expected[i] {
+				t.Errorf("sortShipments(%s, desc=%v) = %v, want %v", tt.field, tt.descending, ids, tt.expected)
+				break
+			}
+		}
+	}
+}
+
+func TestInteractiveTable_Sort(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	config := &cliapi.Config{NoColor: true}
+	table, err := NewInteractiveTable(sortTestShipments(), nil, nil, "id,carrier,status,created", config)
+	if err != nil {
+		t.Fatalf("Failed to create interactive table: %v", err)
+	}
+
+	var model tea.Model = *table
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m := model.(InteractiveTable)
+	if m.sortField != "carrier" || m.sortDesc {
+		t.Fatalf("Expected s twice to sort by carrier ascending, got %q desc=%v", m.sortField, m.sortDesc)
+	}
+	if ids := shipmentIDs(m.shipments); ids[0] != 3 || ids[2] != 2 {
+		t.Errorf("Expected shipments sorted by carrier, got %v", ids)
+	}
+	if title := m.columns[1].Title; !strings.HasSuffix(title, "▲") {
+		t.Errorf("Expected sort indicator on carrier header, got %q", title)
+	}
+
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	m = model.(InteractiveTable)
+	if !m.sortDesc || m.shipments[0].ID != 2 {
+		t.Errorf("Expected S to reverse the sort, got desc=%v first=%d", m.sortDesc, m.shipments[0].ID)
+	}
+
+	// The chosen sort is saved to the config file
+	if saved, ok := cmd().(sortSavedMsg); !ok || saved.err != nil {
+		t.Fatalf("Expected sort to be saved, got %+v", saved)
+	}
+	loaded, err := cliapi.LoadConfigWithProfile("", "", "", false)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if loaded.SortBy != "carrier" || !loaded.SortDescending {
+		t.Errorf("Expected saved sort carrier desc, got %q desc=%v", loaded.SortBy, loaded.SortDescending)
+	}
+
+	// A new table restores the saved sort
+	restored, err := NewInteractiveTable(sortTestShipments(), nil, nil, "id,carrier,status,created", loaded)
+	if err != nil {
+		t.Fatalf("Failed to create interactive table: %v", err)
+	}
+	if restored.sortField != "carrier" || restored.shipments[0].ID != 2 {
+		t.Errorf("Expected saved sort to be restored, got %q first=%d", restored.sortField, restored.shipments[0].ID)
+	}
+}
+
+func TestInteractiveTable_HeaderClick(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	table, err := NewInteractiveTable(sortTestShipments(), nil, nil, "id,created", &cliapi.Config{NoColor: true})
+	if err != nil {
+		t.Fatalf("Failed to create interactive table: %v", err)
+	}
+
+	// The created column starts after the id column and its padding
+	x := table.columns[0].Width + sortCellPadding + 1
+	click := tea.MouseMsg{X: x, Y: 0, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft}
+
+	var model tea.Model = *table
+	model, _ = model.Update(click)
+	m := model.(InteractiveTable)
+	if m.sortField != "created" || m.sortDesc {
+		t.Fatalf("Expected header click to sort by created ascending, got %q desc=%v", m.sortField, m.sortDesc)
+	}
+
+	model, _ = model.Update(click)
+	if m = model.(InteractiveTable); !m.sortDesc {
+		t.Error("Expected a second click on the same header to reverse the sort")
+	}
+
+	// Clicks on table rows don't sort
+	click.Y = 2
+	model, _ = model.Update(click)
+	if m = model.(InteractiveTable); m.sortField != "created" || !m.sortDesc {
+		t.Errorf("Expected row click to leave the sort unchanged, got %q desc=%v", m.sortField, m.sortDesc)
+	}
+}
@@ -5,10 +5,12 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/mattn/go-isatty"
@@ -23,9 +25,18 @@ type KeyMap struct {
 	Down     key.Binding
 	Refresh  key.Binding
 	Update   key.Binding
+	Add      key.Binding
 	Delete   key.Binding
 	Details  key.Binding
+	Layout   key.Binding
+	Grow     key.Binding
+	Shrink   key.Binding
 	Events   key.Binding
+	Filter   key.Binding
+	Select   key.Binding
+	Sort     key.Binding
+	Reverse  key.Binding
+	Watch    key.Binding
 	Help     key.Binding
 	Quit     key.Binding
 	Confirm  key.Binding
@@ -51,6 +62,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("u"),
 			key.WithHelp("u", "update"),
 		),
+		Add: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "add"),
+		),
 		Delete: key.NewBinding(
 			key.WithKeys("d"),
 			key.WithHelp("d", "delete"),
@@ -59,10 +74,42 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("enter"),
 			key.WithHelp("enter", "details"),
 		),
+		Layout: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "move details pane"),
+		),
+		Grow: key.NewBinding(
+			key.WithKeys("+", "="),
+			key.WithHelp("+", "grow details pane"),
+		),
+		Shrink: key.NewBinding(
+			key.WithKeys("-"),
+			key.WithHelp("-", "shrink details pane"),
+		),
 		Events: key.NewBinding(
 			key.WithKeys("e"),
 			key.WithHelp("e", "events"),
 		),
+		Filter: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "filter"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "select"),
+		),
+		Sort: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "sort"),
+		),
+		Reverse: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "reverse sort"),
+		),
+		Watch: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "watch"),
+		),
 		Help: key.NewBinding(
 			key.WithKeys("?"),
 			key.WithHelp("?", "help"),
@@ -85,7 +132,10 @@ func DefaultKeyMap() KeyMap {
 // InteractiveTable represents the interactive table model
 type InteractiveTable struct {
 	table             table.Model
-	shipments         []database.Shipment
+	baseColumns       []table.Column
+	columns           []table.Column // Displayed columns, with sort and highlight adjustments
+	allShipments      []database.Shipment
+	shipments         []database.Shipment // Visible shipments, narrowed by the filter
 	client            *cliapi.Client
 	formatter         *cliapi.OutputFormatter
 	fields            []string
@@ -98,12 +148,31 @@ type InteractiveTable struct {
 	quitting          bool
 	config            *cliapi.Config
 	useColor          bool
+	theme             cliapi.Theme
 	showDeleteConfirm bool
 	deleteTarget      int // ID of shipment to delete
 	showEvents        bool
 	eventsData        []database.TrackingEvent
 	eventsShipmentID  int
 	eventsScroll      int
+	filterInput       textinput.Model
+	filtering         bool // Filter input has focus
+	sortField         string
+	sortDesc          bool
+	selected          map[int]bool // Selected shipment IDs for bulk actions
+	bulk              *bulkOperation
+	watching          bool
+	watchInterval     time.Duration
+	watchSeq          int          // Incremented when watch is toggled to discard stale ticks
+	changed           map[int]bool // Shipments whose status changed in the last watch refresh
+	adding            *addForm     // Add shipment form, when open
+	detailOpen        bool         // Detail pane is shown alongside the table
+	detailLayout      detailLayout
+	detailSize        int                        // Percent of the window the detail pane takes
+	detailEvents      map[int]detailEventsResult // Events fetched for the detail pane, by shipment ID
+	detailPending     map[int]bool               // Shipments whose events are being fetched
+	width             int                        // Window size, once reported
+	height            int
 }
 
 // NewInteractiveTable creates a new interactive table
@@ -134,13 +203,18 @@ func NewInteractiveTable(shipments []database.Shipment, client *cliapi.Client, f
 		table.WithColumns(columns),
 		table.WithRows(rows),
 		table.WithFocused(true),
-		table.WithHeight(15),
+		table.WithHeight(defaultTableHeight),
 	)
 
+	theme, err := cliapi.ThemeByName(config.Theme)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create spinner
 	s := spinner.New()
 	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	s.Style = lipgloss.NewStyle().Foreground(theme.Accent)
 
 	// Determine if colors should be used
 	useColor := !config.NoColor && isatty.IsTerminal(os.Stdout.Fd())
@@ -150,36 +224,76 @@ func NewInteractiveTable(shipments []database.Shipment, client *cliapi.Client, f
 		s := table.DefaultStyles()
 		s.Header = s.Header.
 			BorderStyle(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color("240")).
+			BorderForeground(theme.Border).
 			BorderBottom(true).
 			Bold(false)
 		s.Selected = s.Selected.
-			Foreground(lipgloss.Color("229")).
-			Background(lipgloss.Color("57")).
+			Foreground(theme.SelectedFg).
+			Background(theme.SelectedBg).
 			Bold(false)
 		t.SetStyles(s)
 	}
 
-	return &InteractiveTable{
-		table:     t,
-		shipments: shipments,
-		client:    client,
-		formatter: formatter,
-		fields:    fields,
-		keys:      DefaultKeyMap(),
-		spinner:   s,
-		config:    config,
-		useColor:  useColor,
-	}, nil
+	// Create filter input
+	filterInput := textinput.New()
+	filterInput.Prompt = "/"
+	filterInput.Placeholder = "tracking number, description, carrier, or status"
+
+	m := &InteractiveTable{
+		table:         t,
+		baseColumns:   columns,
+		columns:       columns,
+		allShipments:  shipments,
+		shipments:     shipments,
+		client:        client,
+		formatter:     formatter,
+		fields:        fields,
+		keys:          DefaultKeyMap(),
+		spinner:       s,
+		config:        config,
+		useColor:      useColor,
+		theme:         theme,
+		filterInput:   filterInput,
+		selected:      make(map[int]bool),
+		watchInterval: defaultWatchInterval,
+		changed:       make(map[int]bool),
+		detailSize:    defaultDetailSize,
+		detailEvents:  make(map[int]detailEventsResult),
+		detailPending: make(map[int]bool),
+	}
+
+	// Restore the sort saved from a previous session
+	if config.SortBy != "" && validateFields([]string{config.SortBy}) == nil {
+		m.sortField = config.SortBy
+		m.sortDesc = config.SortDescending
+		m.allShipments = sortShipments(shipments, m.sortField, m.sortDesc)
+	}
+	*m = m.applyFilter()
+
+	return m, nil
 }
 
 // Init initializes the interactive table
 func (m InteractiveTable) Init() tea.Cmd {
+	if m.watching {
+		return m.watchTick()
+	}
 	return nil
 }
 
-// Update handles messages and updates the model
+// Update handles messages and updates the model, then fetches events for the detail pane
+// if the shipment under the cursor changed
 func (m InteractiveTable) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	if updated, ok := model.(InteractiveTable); ok {
+		if load := updated.loadDetailEvents(); load != nil {
+			return updated, tea.Batch(cmd, load)
+		}
+	}
+	return model, cmd
+}
+
+func (m InteractiveTable) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
@@ -229,7 +343,77 @@ func (m InteractiveTable) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		// Handle the add form: typed keys go to its fields
+		if m.adding != nil {
+			return m.updateAddForm(msg)
+		}
+
+		// Handle filter input: typed keys narrow the table as you type
+		if m.filtering {
+			switch msg.String() {
+			case "ctrl+c":
+				m.quitting = true
+				return m, tea.Quit
+			case "esc":
+				m = m.clearFilter()
+				return m, nil
+			case "enter":
+				// Keep the filter and return to navigating the matches
+				m.filtering = false
+				m.filterInput.Blur()
+				return m, nil
+			case "up", "down":
+				m.table, cmd = m.table.Update(msg)
+				return m, cmd
+			}
+			m.filterInput, cmd = m.filterInput.Update(msg)
+			m = m.applyFilter()
+			return m, cmd
+		}
+
 		switch {
+		case key.Matches(msg, m.keys.Filter):
+			m.filtering = true
+			m.message = ""
+			m.err = nil
+			return m, m.filterInput.Focus()
+
+		case key.Matches(msg, m.keys.Sort):
+			return m.cycleSort()
+
+		case key.Matches(msg, m.keys.Reverse):
+			return m.reverseSort()
+
+		case key.Matches(msg, m.keys.Add):
+			return m.openAddForm()
+
+		case key.Matches(msg, m.keys.Watch):
+			return m.toggleWatch()
+
+		case key.Matches(msg, m.keys.Select):
+			return m.toggleSelection(), nil
+
+		case msg.String() == "esc" && m.filterActive():
+			m = m.clearFilter()
+			return m, nil
+
+		case msg.String() == "esc" && len(m.selected) > 0:
+			m = m.clearSelection()
+			m.message = "Selection cleared"
+			return m, nil
+
+		case msg.String() == "esc" && m.detailOpen:
+			return m.toggleDetail()
+
+		case key.Matches(msg, m.keys.Layout) && m.detailOpen:
+			return m.switchDetailLayout(), nil
+
+		case key.Matches(msg, m.keys.Grow) && m.detailOpen:
+			return m.resizeDetail(detailSizeStep), nil
+
+		case key.Matches(msg, m.keys.Shrink) && m.detailOpen:
+			return m.resizeDetail(-detailSizeStep), nil
+
 		case key.Matches(msg, m.keys.Quit):
 			m.quitting = true
 			return m, tea.Quit
@@ -239,6 +423,9 @@ func (m InteractiveTable) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case key.Matches(msg, m.keys.Refresh):
+			if len(m.selected) > 0 {
+				return m.startBulk(bulkRefresh)
+			}
 			return m.handleRefresh()
 
 		case key.Matches(msg, m.keys.Up):
@@ -262,9 +449,41 @@ func (m InteractiveTable) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleDelete()
 		}
 
+	case tea.MouseMsg:
+		if m.showDeleteConfirm || m.showEvents || m.adding != nil {
+			return m, nil
+		}
+		return m.handleHeaderClick(msg)
+
+	case addCompleteMsg:
+		return m.handleAddComplete(msg)
+
+	case watchTickMsg:
+		if !m.watching || msg.seq != m.watchSeq {
+			return m, nil
+		}
+		return m, m.fetchShipments()
+
+	case watchFetchedMsg:
+		return m.handleWatchFetched(msg)
+
+	case bulkStepMsg:
+		return m.handleBulkStep(msg)
+
+	case sortSavedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			m.message = fmt.Sprintf("Error saving sort preference: %v", msg.err)
+		}
+		return m, nil
+
 	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
 		m.table.SetWidth(msg.Width)
-		return m, nil
+		return m.layoutTable(), nil
+
+	case detailEventsMsg:
+		return m.handleDetailEvents(msg), nil
 
 	case refreshCompleteMsg:
 		m.loading = false
@@ -272,6 +491,7 @@ func (m InteractiveTable) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.err = msg.err
 			m.message = fmt.Sprintf("Error refreshing shipment: %v", msg.err)
 		} else {
+			m.forgetDetailEvents(msg.shipmentID)
 			m.message = fmt.Sprintf("Refreshed successfully - %d events added", msg.response.EventsAdded)
 			// We need to fetch the updated shipment data since refresh response doesn't include it
 			// For now, just show the success message
@@ -330,26 +550,41 @@ func (m InteractiveTable) View() string {
 		b.WriteString("\n")
 	}
 
-	// Show spinner if loading
-	if m.loading {
+	// Show spinner if loading; the add form shows its own
+	if m.loading && m.adding == nil {
 		b.WriteString(fmt.Sprintf("%s Loading...\n", m.spinner.View()))
 	}
 
-	// Show events view if active
+	// Show events view or add form if active
 	if m.showEvents {
 		b.WriteString(m.eventsView())
 		b.WriteString("\n")
+	} else if m.adding != nil {
+		b.WriteString(m.addFormView())
 	} else {
-		// Show table
-		b.WriteString(m.table.View())
+		// Show filter input while filtering or when a filter is applied
+		if m.filtering || m.filterActive() {
+			b.WriteString(m.filterInput.View())
+			b.WriteString("\n")
+		}
+
+		// Show table, with the detail pane beside or below it when open
+		tableView := m.table.View()
+		if m.detailOpen {
+			tableView = m.withDetailPane(tableView)
+		}
+		b.WriteString(tableView)
 		b.WriteString("\n")
 	}
 
 	// Show confirmation dialog if needed
 	if m.showDeleteConfirm {
 		confirmMsg := fmt.Sprintf("Delete shipment ID %d? (y/N): ", m.deleteTarget)
+		if m.deleteTarget == 0 {
+			confirmMsg = fmt.Sprintf("Delete %d selected shipments? (y/N): ", len(m.selected))
+		}
 		if m.useColor {
-			b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("208")).Render(confirmMsg))
+			b.WriteString(lipgloss.NewStyle().Foreground(m.theme.Warning).Render(confirmMsg))
 		} else {
 			b.WriteString(confirmMsg)
 		}
@@ -360,13 +595,13 @@ func (m InteractiveTable) View() string {
 	if m.message != "" {
 		if m.err != nil {
 			if m.useColor {
-				b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(m.message))
+				b.WriteString(lipgloss.NewStyle().Foreground(m.theme.Error).Render(m.message))
 			} else {
 				b.WriteString(m.message)
 			}
 		} else {
 			if m.useColor {
-				b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("82")).Render(m.message))
+				b.WriteString(lipgloss.NewStyle().Foreground(m.theme.Success).Render(m.message))
 			} else {
 				b.WriteString(m.message)
 			}
@@ -387,10 +622,17 @@ func (m InteractiveTable) helpView() string {
 	help.WriteString("  ↑/k         - Move up\n")
 	help.WriteString("  ↓/j         - Move down\n")
 	help.WriteString("  r           - Refresh selected shipment\n")
+	help.WriteString("  a           - Add shipment\n")
 	help.WriteString("  u           - Update description\n")
 	help.WriteString("  d           - Delete shipment\n")
-	help.WriteString("  enter       - View details\n")
+	help.WriteString("  enter       - Toggle details pane (esc closes)\n")
+	help.WriteString("  v           - Move details pane right/below\n")
+	help.WriteString("  +/-         - Grow/shrink details pane\n")
 	help.WriteString("  e           - View events\n")
+	help.WriteString("  space       - Select shipment (r/d then act on all selected, esc clears)\n")
+	help.WriteString("  /           - Filter shipments (enter to keep, esc to clear)\n")
+	help.WriteString("  s/S         - Sort by next column / reverse sort (or click a header)\n")
+	help.WriteString("  w           - Toggle watch mode (re-fetch and highlight status changes)\n")
 	help.WriteString("  ?           - Toggle help\n")
 	help.WriteString("  q/ctrl+c    - Quit\n")
 	return help.String()
@@ -402,13 +644,29 @@ func (m InteractiveTable) statusLine() string {
 		return "Events View | Press q/esc to return to shipments list"
 	}
 	
+	if m.filterActive() {
+		if len(m.shipments) == 0 {
+			return fmt.Sprintf("No shipments match (%d total) | Press esc to clear filter", len(m.allShipments))
+		}
+		return fmt.Sprintf("Shipment %d of %d (filtered from %d) | Press esc to clear filter",
+			m.table.Cursor()+1, len(m.shipments), len(m.allShipments))
+	}
+
 	if len(m.shipments) == 0 {
 		return "No shipments found"
 	}
 
-	selected := m.table.Cursor()
-	total := len(m.shipments)
-	return fmt.Sprintf("Shipment %d of %d | Press ? for help", selected+1, total)
+	status := fmt.Sprintf("Shipment %d of %d", m.table.Cursor()+1, len(m.shipments))
+	if len(m.selected) > 0 {
+		status += fmt.Sprintf(" | %d selected", len(m.selected))
+	}
+	if m.watching {
+		status += fmt.Sprintf(" | Watching every %s", m.watchInterval)
+	}
+	if m.detailOpen {
+		status += " | v: move pane, +/-: resize"
+	}
+	return status + " | Press ? for help"
 }
 
 // calculateColumnWidth calculates the width for a column based on its content
@@ -483,7 +741,8 @@ func getFieldValue(shipment database.Shipment, field string) string {
 
 // refreshCompleteMsg is sent when a refresh operation completes
 type refreshCompleteMsg struct {
-	response *cliapi.RefreshResponse
+	shipmentID int
+	response   *cliapi.RefreshResponse
 	err      error
 }
 
@@ -530,9 +789,9 @@ func (m InteractiveTable) refreshShipment(id int) tea.Cmd {
 		// Use the client to refresh the shipment
 		response, err := m.client.RefreshShipment(id)
 		if err != nil {
-			return refreshCompleteMsg{err: err}
+			return refreshCompleteMsg{shipmentID: id, err: err}
 		}
-		return refreshCompleteMsg{response: response}
+		return refreshCompleteMsg{shipmentID: id, response: response}
 	}
 }
 
@@ -540,52 +799,9 @@ func (m InteractiveTable) refreshShipment(id int) tea.Cmd {
 // Note: This would require fetching updated shipment data from the API
 // For now, we'll just show the refresh success message
 
-// handleDetails handles viewing shipment details
+// handleDetails opens or closes the detail pane
 func (m InteractiveTable) handleDetails() (InteractiveTable, tea.Cmd) {
-	if len(m.shipments) == 0 {
-		m.message = "No shipments to view"
-		return m, nil
-	}
-
-	selected := m.table.Cursor()
-	if selected >= len(m.shipments) {
-		m.message = "Invalid selection"
-		return m, nil
-	}
-
-	shipment := m.shipments[selected]
-	
-	// Format shipment details
-	details := fmt.Sprintf(`
-Shipment Details:
-ID: %d
-Tracking Number: %s
-Carrier: %s
-Status: %s
-Description: %s
-Created: %s
-Updated: %s
-Expected Delivery: %s
-Delivered: %v
-`,
-		shipment.ID,
-		shipment.TrackingNumber,
-		shipment.Carrier,
-		shipment.Status,
-		shipment.Description,
-		shipment.CreatedAt.Format("2006-01-02 15:04:05"),
-		shipment.UpdatedAt.Format("2006-01-02 15:04:05"),
-		func() string {
-			if shipment.ExpectedDelivery != nil {
-				return shipment.ExpectedDelivery.Format("2006-01-02 15:04:05")
-			}
-			return "N/A"
-		}(),
-		shipment.IsDelivered,
-	)
-
-	m.message = details
-	return m, nil
+	return m.toggleDetail()
 }
 
 // handleEvents handles viewing tracking events
@@ -645,6 +861,15 @@ func (m InteractiveTable) handleDelete() (InteractiveTable, tea.Cmd) {
 		return m, nil
 	}
 
+	// With shipments selected, confirm deleting all of them instead
+	if len(m.selected) > 0 {
+		m.showDeleteConfirm = true
+		m.deleteTarget = 0
+		m.message = ""
+		m.err = nil
+		return m, nil
+	}
+
 	shipment := m.shipments[selected]
 	m.showDeleteConfirm = true
 	m.deleteTarget = shipment.ID
@@ -657,6 +882,10 @@ func (m InteractiveTable) handleDelete() (InteractiveTable, tea.Cmd) {
 // confirmDelete executes the delete operation after confirmation
 func (m InteractiveTable) confirmDelete() (InteractiveTable, tea.Cmd) {
 	m.showDeleteConfirm = false
+	if m.deleteTarget == 0 {
+		return m.startBulk(bulkDelete)
+	}
+
 	m.loading = true
 	m.message = ""
 	m.err = nil
@@ -678,36 +907,21 @@ func (m InteractiveTable) deleteShipment(id int) tea.Cmd {
 // removeShipmentFromTable removes a shipment from the table after successful deletion
 func (m InteractiveTable) removeShipmentFromTable(shipmentID int) InteractiveTable {
 	// Find the shipment to remove
-	newShipments := make([]database.Shipment, 0, len(m.shipments)-1)
-	for _, shipment := range m.shipments {
+	newShipments := make([]database.Shipment, 0, len(m.allShipments))
+	for _, shipment := range m.allShipments {
 		if shipment.ID != shipmentID {
 			newShipments = append(newShipments, shipment)
 		}
 	}
 
 	// Update the shipments slice
-	m.shipments = newShipments
-
-	// Recreate table rows
-	rows := make([]table.Row, len(m.shipments))
-	for i, shipment := range m.shipments {
-		rows[i] = shipmentToRow(shipment, m.fields)
-	}
+	m.allShipments = newShipments
 
-	// Update the table
-	m.table.SetRows(rows)
-
-	// Adjust cursor if necessary
-	if len(m.shipments) > 0 {
-		cursor := m.table.Cursor()
-		if cursor >= len(m.shipments) {
-			// Move cursor to the last item if it's beyond the new range
-			for cursor >= len(m.shipments) && cursor > 0 {
-				cursor--
-			}
-			// We can't directly set cursor, so we'll let the natural navigation handle it
-		}
-	}
+	// Recreate table rows, keeping any active filter. SetCursor clamps the cursor to the
+	// last row if the deleted shipment was at the end.
+	cursor := m.table.Cursor()
+	m = m.applyFilter()
+	m.table.SetCursor(cursor)
 
 	return m
 }
@@ -740,7 +954,7 @@ func (m InteractiveTable) eventsView() string {
 	// Header
 	title := fmt.Sprintf("Tracking Events for %s", shipmentDesc)
 	if m.useColor {
-		titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("39"))
+		titleStyle := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Accent)
 		b.WriteString(titleStyle.Render(title))
 	} else {
 		b.WriteString(title)
@@ -750,7 +964,7 @@ func (m InteractiveTable) eventsView() string {
 	// Instructions
 	instructions := "Use ↑/↓ to scroll, q/esc to close"
 	if m.useColor {
-		instrStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+		instrStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
 		b.WriteString(instrStyle.Render(instructions))
 	} else {
 		b.WriteString(instructions)
@@ -765,7 +979,7 @@ func (m InteractiveTable) eventsView() string {
 	// Table header
 	header := "TIMESTAMP         LOCATION              STATUS        DESCRIPTION"
 	if m.useColor {
-		headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("240"))
+		headerStyle := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Border)
 		b.WriteString(headerStyle.Render(header))
 	} else {
 		b.WriteString(header)
@@ -775,7 +989,7 @@ func (m InteractiveTable) eventsView() string {
 	// Add separator line
 	separator := strings.Repeat("-", len(header))
 	if m.useColor {
-		sepStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+		sepStyle := lipgloss.NewStyle().Foreground(m.theme.Border)
 		b.WriteString(sepStyle.Render(separator))
 	} else {
 		b.WriteString(separator)
@@ -821,7 +1035,7 @@ func (m InteractiveTable) eventsView() string {
 	if len(m.eventsData) > maxVisible {
 		scrollInfo := fmt.Sprintf("\nShowing %d-%d of %d events", start+1, end, len(m.eventsData))
 		if m.useColor {
-			scrollStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+			scrollStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
 			b.WriteString(scrollStyle.Render(scrollInfo))
 		} else {
 			b.WriteString(scrollInfo)
@@ -845,32 +1059,23 @@ func truncateString(s string, maxLen int) string {
 // getStatusColorForEvent returns colored status text
 func (m InteractiveTable) getStatusColorForEvent(status string) string {
 	if m.useColor {
-		var color lipgloss.Color
-		switch strings.ToLower(status) {
-		case "delivered":
-			color = lipgloss.Color("82") // Green
-		case "in transit", "in-transit", "transit":
-			color = lipgloss.Color("226") // Yellow
-		case "pending", "pre_ship":
-			color = lipgloss.Color("75") // Blue
-		case "failed", "error", "exception":
-			color = lipgloss.Color("196") // Red
-		default:
-			color = lipgloss.Color("244") // Gray
-		}
-		return lipgloss.NewStyle().Foreground(color).Render(status)
+		return lipgloss.NewStyle().Foreground(m.theme.StatusColor(status)).Render(status)
 	}
 	return status
 }
 
 // runInteractiveTable runs the interactive table
-func runInteractiveTable(shipments []database.Shipment, client *cliapi.Client, formatter *cliapi.OutputFormatter, fieldsFlag string, config *cliapi.Config) error {
+func runInteractiveTable(shipments []database.Shipment, client *cliapi.Client, formatter *cliapi.OutputFormatter, fieldsFlag string, config *cliapi.Config, watch bool, watchInterval time.Duration) error {
 	interactiveTable, err := NewInteractiveTable(shipments, client, formatter, fieldsFlag, config)
 	if err != nil {
 		return err
 	}
+	interactiveTable.watching = watch
+	if watchInterval > 0 {
+		interactiveTable.watchInterval = watchInterval
+	}
 
-	p := tea.NewProgram(interactiveTable, tea.WithAltScreen())
+	p := tea.NewProgram(interactiveTable, tea.WithAltScreen(), tea.WithMouseCellMotion())
 	_, err = p.Run()
 	return err
 }
\ No newline at end of file
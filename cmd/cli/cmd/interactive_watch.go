@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"package-tracking/internal/database"
+)
+
+// defaultWatchInterval is how often watch mode re-fetches shipments
+const defaultWatchInterval = 30 * time.Second
+
+// changedStatusStyle highlights the status of shipments that changed in the last refresh
+func (m InteractiveTable) changedStatusStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Bold(true).Foreground(m.theme.Success)
+}
+
+// watchTickMsg triggers a watch refresh. seq identifies the watch session that scheduled it,
+// so ticks left over from before watch was toggled off and on are ignored.
+type watchTickMsg struct {
+	seq int
+}
+
+// watchFetchedMsg carries the shipments fetched by a watch refresh
+type watchFetchedMsg struct {
+	seq       int
+	shipments []database.Shipment
+	err       error
+}
+
+// watchTick schedules the next watch refresh
+func (m InteractiveTable) watchTick() tea.Cmd {
+	seq := m.watchSeq
+	return tea.Tick(m.watchInterval, func(time.Time) tea.Msg {
+		return watchTickMsg{seq: seq}
+	})
+}
+
+// fetchShipments re-fetches every shipment for a watch refresh
+func (m InteractiveTable) fetchShipments() tea.Cmd {
+	seq := m.watchSeq
+	return func() tea.Msg {
+		shipments, err := m.client.GetShipments()
+		return watchFetchedMsg{seq: seq, shipments: shipments, err: err}
+	}
+}
+
+// toggleWatch turns watch mode on or off
+func (m InteractiveTable) toggleWatch() (InteractiveTable, tea.Cmd) {
+	m.watching = !m.watching
+	m.watchSeq++
+	m.err = nil
+
+	if !m.watching {
+		m.changed = make(map[int]bool)
+		m = m.reapplyKeepingCursor()
+		m.message = "Watch mode off"
+		return m, nil
+	}
+
+	m.message = fmt.Sprintf("Watching for changes every %s", m.watchInterval)
+	return m, m.fetchShipments()
+}
+
+// handleWatchFetched merges freshly fetched shipments into the table, highlighting those
+// whose status changed, and schedules the next refresh
+func (m InteractiveTable) handleWatchFetched(msg watchFetchedMsg) (InteractiveTable, tea.Cmd) {
+	if !m.watching || msg.seq != m.watchSeq {
+		return m, nil
+	}
+
+	if msg.err != nil {
+		// Keep watching; the server may only be briefly unavailable
+		m.err = msg.err
+		m.message = fmt.Sprintf("Error refreshing shipments: %v", msg.err)
+		return m, m.watchTick()
+	}
+
+	previous := make(map[int]string, len(m.allShipments))
+	for _, shipment := range m.allShipments {
+		previous[shipment.ID] = shipment.Status
+	}
+
+	changed := make(map[int]bool)
+	present := make(map[int]bool, len(msg.shipments))
+	for _, shipment := range msg.shipments {
+		present[shipment.ID] = true
+		if status, ok := previous[shipment.ID]; ok && status != shipment.Status {
+			changed[shipment.ID] = true
+		}
+	}
+
+	// Drop selections for shipments that no longer exist
+	for id := range m.selected {
+		if !present[id] {
+			delete(m.selected, id)
+		}
+	}
+
+	m.changed = changed
+	for id := range changed {
+		m.forgetDetailEvents(id)
+	}
+	m.allShipments = msg.shipments
+	if m.sortField != "" {
+		m.allShipments = sortShipments(m.allShipments, m.sortField, m.sortDesc)
+	}
+	m = m.reapplyKeepingCursor()
+
+	if len(changed) > 0 {
+		m.err = nil
+		m.message = fmt.Sprintf("%d shipment(s) changed status at %s", len(changed), time.Now().Format("15:04:05"))
+	}
+
+	return m, m.watchTick()
+}
+
+// markChanged highlights a cell of a shipment whose status changed in the last refresh.
+// Without color the cell is suffixed with an asterisk instead.
+func (m InteractiveTable) markChanged(cell string) string {
+	if m.useColor {
+		return m.changedStatusStyle().Render(cell)
+	}
+	return cell + " *"
+}
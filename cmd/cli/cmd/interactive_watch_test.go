@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	cliapi "package-tracking/internal/cli"
+	"package-tracking/internal/database"
+)
+
+func TestInteractiveTable_Watch(t *testing.T) {
+	updated := []database.Shipment{
+		{ID: 1, TrackingNumber: "1Z999AA10123456784", Carrier: "ups", Status: "delivered"},
+		{ID: 2, TrackingNumber: "9400111899562537866361", Carrier: "usps", Status: "pending"},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(updated)
+	}))
+	defer server.Close()
+
+	shipments := []database.Shipment{
+		{ID: 1, TrackingNumber: "1Z999AA10123456784", Carrier: "ups", Status: "in_transit"},
+		{ID: 2, TrackingNumber: "9400111899562537866361", Carrier: "usps", Status: "pending"},
+		{ID: 3, TrackingNumber: "1234567890", Carrier: "dhl", Status: "delivered"},
+	}
+
+	table, err := NewInteractiveTable(shipments, cliapi.NewClient(server.URL), nil, "id,status", &cliapi.Config{NoColor: true})
+	if err != nil {
+		t.Fatalf("Failed to create interactive table: %v", err)
+	}
+
+	var model tea.Model = *table
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	m := model.(InteractiveTable)
+	if !m.watching || cmd == nil {
+		t.Fatal("Expected w to turn on watch mode and fetch shipments")
+	}
+
+	model, _ = model.Update(cmd())
+	m = model.(InteractiveTable)
+	if len(m.shipments) != 2 {
+		t.Fatalf("Expected the fetched shipments to replace the table, got %d", len(m.shipments))
+	}
+	if !m.changed[1] || m.changed[2] {
+		t.Errorf("Expected only shipment 1 to be marked as changed, got %v", m.changed)
+	}
+	if status := m.table.Rows()[0][1]; status != "delivered *" {
+		t.Errorf("Expected changed status to be marked, got %q", status)
+	}
+	if !strings.Contains(m.statusLine(), "Watching every") {
+		t.Errorf("Expected status line to show watch mode, got %q", m.statusLine())
+	}
+
+	// Results from a watch session that was toggled off are ignored
+	stale := watchFetchedMsg{seq: m.watchSeq, shipments: shipments}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	model, _ = model.Update(stale)
+	m = model.(InteractiveTable)
+	if m.watching || len(m.shipments) != 2 || len(m.changed) != 0 {
+		t.Errorf("Expected watch off with stale results ignored, got watching=%v shipments=%d changed=%v",
+			m.watching, len(m.shipments), m.changed)
+	}
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"os"
+	"time"
 
 	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
@@ -12,14 +13,19 @@ import (
 var (
 	interactiveMode bool
 	fieldsFlag      string
+	watchMode       bool
+	watchInterval   time.Duration
 )
 
 var listCmd = &cobra.Command{
 	Use:     "list",
 	Aliases: []string{"ls"},
 	Short:   "List all shipments",
-	Long:    `List all shipments currently being tracked.`,
-	RunE:    runList,
+	Long: `List all shipments currently being tracked.
+
+If the server can't be reached, the last successfully fetched list is shown with a warning
+saying how old it is. Use --refresh-cache to require fresh results.`,
+	RunE: runList,
 }
 
 func init() {
@@ -28,23 +34,30 @@ func init() {
 	// Add flags for interactive mode and field selection
 	listCmd.Flags().BoolVarP(&interactiveMode, "interactive", "i", false, "Interactive table mode")
 	listCmd.Flags().StringVar(&fieldsFlag, "fields", "", "Comma-separated list of fields to display (id,tracking,carrier,status,description,created,updated,delivery,delivered)")
+	listCmd.Flags().BoolVarP(&watchMode, "watch", "w", false, "Periodically re-fetch shipments in the interactive table and highlight status changes")
+	listCmd.Flags().DurationVar(&watchInterval, "watch-interval", defaultWatchInterval, "How often to re-fetch shipments in watch mode")
+	addRefreshCacheFlag(listCmd)
 }
 
 func runList(cmd *cobra.Command, args []string) error {
-	config, formatter, client, err := initializeClient()
+	config, formatter, client, err := initializeReadClient()
 	if err != nil {
 		return err
 	}
 
-	shipments, err := client.GetShipments()
+	shipments, err := newCachedReader(config, formatter, client).Shipments()
 	if err != nil {
 		formatter.PrintError(err)
 		return err
 	}
 
-	// Determine if interactive mode should be used
-	if shouldUseInteractiveMode(config, interactiveMode, isatty.IsTerminal(os.Stdout.Fd())) {
-		return runInteractiveTable(shipments, client, formatter, fieldsFlag, config)
+	if watchInterval <= 0 {
+		return cliapi.NewValidationError("watch interval must be positive")
+	}
+
+	// Determine if interactive mode should be used; --watch implies it
+	if shouldUseInteractiveMode(config, interactiveMode || watchMode, isatty.IsTerminal(os.Stdout.Fd())) {
+		return runInteractiveTable(shipments, client, formatter, fieldsFlag, config, watchMode, watchInterval)
 	}
 
 	return formatter.PrintShipments(shipments)
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	cliapi "package-tracking/internal/cli"
+	"package-tracking/internal/database"
+)
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Send desktop notifications when shipment statuses change",
+	Long: `Poll the server and show a native desktop notification (notify-send on Linux, osascript on
+macOS, a toast on Windows) whenever a shipment's status changes. Runs until Ctrl+C.
+
+Use --status to only be notified about some statuses, for example --status delivered,exception.
+The notify_statuses config setting and PACKAGE_TRACKER_NOTIFY_STATUSES set the default.`,
+	Args: cobra.NoArgs,
+	RunE: runNotify,
+}
+
+var (
+	notifyInterval time.Duration
+	notifyStatuses []string
+)
+
+// shipmentStatusNames are the shipment statuses, for validating and completing --status
+var shipmentStatusNames = []string{"pre_ship", "in_transit", "out_for_delivery", "delivered", "exception", "returned", "unknown"}
+
+func init() {
+	rootCmd.AddCommand(notifyCmd)
+
+	notifyCmd.Flags().DurationVar(&notifyInterval, "interval", 5*time.Minute, "How often to check for status changes")
+	notifyCmd.Flags().StringSliceVar(&notifyStatuses, "status", nil, "Only notify about changes to these statuses (default: all)")
+	notifyCmd.RegisterFlagCompletionFunc("status", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return shipmentStatusNames, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// statusChange is a shipment whose status differs from the last poll
+type statusChange struct {
+	shipment database.Shipment
+	previous string
+}
+
+// detectStatusChanges compares shipments with the statuses seen on the last poll, updating
+// previous in place. Shipments seen for the first time are recorded but not reported.
+func detectStatusChanges(previous map[int]string, shipments []database.Shipment) []statusChange {
+	var changes []statusChange
+	for _, shipment := range shipments {
+		last, seen := previous[shipment.ID]
+		previous[shipment.ID] = shipment.Status
+		if seen && last != shipment.Status {
+			changes = append(changes, statusChange{shipment: shipment, previous: last})
+		}
+	}
+	return changes
+}
+
+// parseStatusFilter normalizes and validates the statuses given to --status, returning nil
+// when no status was given so that every status matches
+func parseStatusFilter(statuses []string) (map[string]bool, error) {
+	filter := make(map[string]bool)
+	for _, status := range statuses {
+		status = strings.ToLower(strings.TrimSpace(status))
+		if status == "" {
+			continue
+		}
+		valid := false
+		for _, name := range shipmentStatusNames {
+			if status == name {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, cliapi.NewValidationError("invalid status %q (must be one of: %s)", status, strings.Join(shipmentStatusNames, ", "))
+		}
+		filter[status] = true
+	}
+	if len(filter) == 0 {
+		return nil, nil
+	}
+	return filter, nil
+}
+
+// statusNotification returns the title and body of the notification for a status change
+func statusNotification(change statusChange) (string, string) {
+	shipment := change.shipment
+	name := shipment.Description
+	if name == "" {
+		name = shipment.TrackingNumber
+	}
+
+	title := fmt.Sprintf("Package %s", strings.ReplaceAll(shipment.Status, "_", " "))
+	body := fmt.Sprintf("%s (%s %s) is now %s", name, strings.ToUpper(shipment.Carrier), shipment.TrackingNumber,
+		strings.ReplaceAll(shipment.Status, "_", " "))
+	return title, body
+}
+
+func runNotify(cmd *cobra.Command, args []string) error {
+	config, formatter, client, err := initializeClient()
+	if err != nil {
+		return err
+	}
+
+	if notifyInterval <= 0 {
+		err := cliapi.NewValidationError("interval must be positive")
+		formatter.PrintError(err)
+		return err
+	}
+
+	statuses := config.NotifyStatuses
+	if cmd.Flags().Changed("status") {
+		statuses = notifyStatuses
+	}
+	filter, err := parseStatusFilter(statuses)
+	if err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+
+	// The first poll records the current statuses so only later changes are reported
+	previous := make(map[int]string)
+	shipments, err := client.GetShipments()
+	if err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+	detectStatusChanges(previous, shipments)
+
+	if !config.Quiet {
+		formatter.PrintInfo(fmt.Sprintf("Watching %d shipments for status changes every %s (Ctrl+C to stop)", len(shipments), notifyInterval))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(notifyInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		shipments, err := client.GetShipments()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to check for status changes: %v\n", err)
+			continue
+		}
+
+		for _, change := range detectStatusChanges(previous, shipments) {
+			if filter != nil && !filter[change.shipment.Status] {
+				continue
+			}
+
+			title, body := statusNotification(change)
+			if !config.Quiet {
+				formatter.PrintInfo(body)
+			}
+			if err := cliapi.SendDesktopNotification(title, body); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"testing"
+
+	"package-tracking/internal/database"
+)
+
+func TestDetectStatusChanges(t *testing.T) {
+	previous := make(map[int]string)
+
+	changes := detectStatusChanges(previous, []database.Shipment{
+		{ID: 1, Status: "in_transit"},
+		{ID: 2, Status: "pre_ship"},
+	})
+	if len(changes) != 0 {
+		t.Fatalf("Expected no changes on the first poll, got %d", len(changes))
+	}
+
+	changes = detectStatusChanges(previous, []database.Shipment{
+		{ID: 1, Status: "delivered"},
+		{ID: 2, Status: "pre_ship"},
+		{ID: 3, Status: "in_transit"},
+	})
+	if len(changes) != 1 {
+		t.Fatalf("Expected 1 change, got %d", len(changes))
+	}
+	if changes[0].shipment.ID != 1 || changes[0].previous != "in_transit" {
+		t.Errorf("Unexpected change: %+v", changes[0])
+	}
+	if previous[3] != "in_transit" {
+		t.Errorf("Expected new shipment to be recorded, got %q", previous[3])
+	}
+}
+
+func TestParseNotifyStatuses(t *testing.T) {
+	filter, err := parseStatusFilter(nil)
+	if err != nil || filter != nil {
+		t.Errorf("Expected no filter for no statuses, got %v, %v", filter, err)
+	}
+
+	filter, err = parseStatusFilter([]string{"Delivered", " exception "})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !filter["delivered"] || !filter["exception"] || len(filter) != 2 {
+		t.Errorf("Unexpected filter: %v", filter)
+	}
+
+	if _, err := parseStatusFilter([]string{"shipped"}); err == nil {
+		t.Error("Expected error for unknown status")
+	}
+}
+
+func TestStatusNotification(t *testing.T) {
+	title, body := statusNotification(statusChange{
+		shipment: database.Shipment{TrackingNumber: "1Z999AA10123456784", Carrier: "ups", Description: "Headphones", Status: "out_for_delivery"},
+		previous: "in_transit",
+	})
+	if title != "Package out for delivery" {
+		t.Errorf("Unexpected title %q", title)
+	}
+	if body != "Headphones (UPS 1Z999AA10123456784) is now out for delivery" {
+		t.Errorf("Unexpected body %q", body)
+	}
+}
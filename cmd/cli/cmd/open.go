@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"package-tracking/internal/database"
+)
+
+var openCmd = &cobra.Command{
+	Use:   "open <shipment-id>",
+	Short: "Open a shipment's carrier tracking page in your browser",
+	Long: `Open the carrier's public tracking page for a shipment in the default browser. Amazon
+shipments handed off to another carrier open that carrier's page for the delegated
+tracking number.
+
+Use --print to only print the URL.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeShipmentIDArg,
+	RunE:              runOpen,
+}
+
+var openPrint bool
+
+// trackingURLTemplates are the carriers' public tracking pages; %s is the escaped tracking number
+var trackingURLTemplates = map[string]string{
+	"ups":    "https://www.ups.com/track?tracknum=%s",
+	"usps":   "https://tools.usps.com/go/TrackConfirmAction?tLabels=%s",
+	"fedex":  "https://www.fedex.com/fedextrack/?trknbr=%s",
+	"dhl":    "https://www.dhl.com/global-en/home/tracking/tracking-express.html?submit=1&tracking-id=%s",
+	"amazon": "https://track.amazon.com/tracking/%s",
+}
+
+// amazonOrderURLTemplate shows an Amazon order when there is no tracking number to look up
+const amazonOrderURLTemplate = "https://www.amazon.com/gp/your-account/order-details?orderID=%s"
+
+func init() {
+	rootCmd.AddCommand(openCmd)
+
+	openCmd.Flags().BoolVar(&openPrint, "print", false, "Print the tracking URL instead of opening it")
+}
+
+// trackingURL returns the public tracking page for a shipment
+func trackingURL(shipment *database.Shipment) (string, error) {
+	carrier := strings.ToLower(shipment.Carrier)
+	trackingNumber := shipment.TrackingNumber
+
+	if carrier == "amazon" {
+		// Amazon hands many packages to other carriers, whose pages have more detail
+		if shipment.DelegatedCarrier != nil && shipment.DelegatedTrackingNumber != nil &&
+			*shipment.DelegatedCarrier != "" && *shipment.DelegatedTrackingNumber != "" {
+			carrier = strings.ToLower(*shipment.DelegatedCarrier)
+			trackingNumber = *shipment.DelegatedTrackingNumber
+		} else if shipment.AmazonOrderNumber != nil && *shipment.AmazonOrderNumber != "" &&
+			!strings.HasPrefix(strings.ToUpper(trackingNumber), "TBA") {
+			return fmt.Sprintf(amazonOrderURLTemplate, url.QueryEscape(*shipment.AmazonOrderNumber)), nil
+		}
+	}
+
+	template, ok := trackingURLTemplates[carrier]
+	if !ok {
+		return "", fmt.Errorf("no tracking page known for carrier %q", carrier)
+	}
+	return fmt.Sprintf(template, url.QueryEscape(trackingNumber)), nil
+}
+
+// openBrowser opens link in the default browser
+func openBrowser(link string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", link)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", link)
+	default:
+		cmd = exec.Command("xdg-open", link)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to open browser: %w", err)
+	}
+	// Don't wait for the browser, but reap the process when it exits
+	go cmd.Wait()
+	return nil
+}
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	config, formatter, client, err := initializeClient()
+	if err != nil {
+		return err
+	}
+
+	id, err := validateAndParseID(args[0])
+	if err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+
+	shipment, err := client.GetShipment(id)
+	if err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+
+	trackingPage, err := trackingURL(shipment)
+	if err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+
+	if openPrint {
+		fmt.Println(trackingPage)
+		return nil
+	}
+
+	if err := openBrowser(trackingPage); err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+	if !config.Quiet {
+		formatter.PrintInfo(fmt.Sprintf("Opened %s", trackingPage))
+	}
+	return nil
+}
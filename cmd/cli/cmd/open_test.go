@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"testing"
+
+	"package-tracking/internal/database"
+)
+
+func TestTrackingURL(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+
+	tests := []struct {
+		name     string
+		shipment database.Shipment
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "ups",
+			shipment: database.Shipment{Carrier: "ups", TrackingNumber: "1Z999AA10123456784"},
+			expected: "https://www.ups.com/track?tracknum=1Z999AA10123456784",
+		},
+		{
+			name:     "usps",
+			shipment: database.Shipment{Carrier: "USPS", TrackingNumber: "9400111899562537866361"},
+			expected: "https://tools.usps.com/go/TrackConfirmAction?tLabels=9400111899562537866361",
+		},
+		{
+			name:     "amazon logistics",
+			shipment: database.Shipment{Carrier: "amazon", TrackingNumber: "TBA123456789012", AmazonOrderNumber: strPtr("113-1234567-1234567")},
+			expected: "https://track.amazon.com/tracking/TBA123456789012",
+		},
+		{
+			name: "amazon delegated to ups",
+			shipment: database.Shipment{Carrier: "amazon", TrackingNumber: "113-1234567-1234567",
+				DelegatedCarrier: strPtr("ups"), DelegatedTrackingNumber: strPtr("1Z999AA10123456784")},
+			expected: "https://www.ups.com/track?tracknum=1Z999AA10123456784",
+		},
+		{
+			name:     "amazon order",
+			shipment: database.Shipment{Carrier: "amazon", TrackingNumber: "113-1234567-1234567", AmazonOrderNumber: strPtr("113-1234567-1234567")},
+			expected: "https://www.amazon.com/gp/your-account/order-details?orderID=113-1234567-1234567",
+		},
+		{
+			name:     "unknown carrier",
+			shipment: database.Shipment{Carrier: "ontrac", TrackingNumber: "C10000000000000"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := trackingURL(&tt.shipment)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("trackingURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("trackingURL() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
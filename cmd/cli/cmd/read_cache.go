@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	cliapi "package-tracking/internal/cli"
+	"package-tracking/internal/database"
+)
+
+// refreshCache makes read commands skip the read cache: cached data is discarded and, if the
+// server can't be reached, the command fails instead of showing stale results
+var refreshCache bool
+
+// addRefreshCacheFlag registers --refresh-cache on a read command
+func addRefreshCacheFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&refreshCache, "refresh-cache", false, "Discard cached results and require a response from the server")
+}
+
+// cachedReader fetches shipments and events from the server, saving successful results to
+// the read cache and falling back to them when the server is unreachable
+type cachedReader struct {
+	config    *cliapi.Config
+	formatter *cliapi.OutputFormatter
+	client    *cliapi.Client
+	cache     *cliapi.ReadCache // nil if the cache can't be used
+}
+
+// newCachedReader opens the read cache for the configured server, clearing it first when
+// --refresh-cache is given. Cache problems only disable caching.
+func newCachedReader(config *cliapi.Config, formatter *cliapi.OutputFormatter, client *cliapi.Client) *cachedReader {
+	r := &cachedReader{config: config, formatter: formatter, client: client}
+
+	cache, err := cliapi.NewReadCache(config.ServerURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: read cache disabled: %v\n", err)
+		return r
+	}
+	if refreshCache {
+		if err := cache.Clear(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to clear read cache: %v\n", err)
+		}
+	}
+	r.cache = cache
+	return r
+}
+
+// useCache reports whether err allows falling back to cached data
+func (r *cachedReader) useCache(err error) bool {
+	return r.cache != nil && !refreshCache && cliapi.IsUnreachable(err)
+}
+
+// warnStale prints the banner shown above cached results
+func (r *cachedReader) warnStale(err error, fetchedAt time.Time) {
+	r.formatter.PrintWarning(fmt.Sprintf("%v; showing cached data, stale as of %s", err, fetchedAt.Local().Format("2006-01-02 15:04:05")))
+	r.formatter.MarkStale(fetchedAt)
+}
+
+// saveWarning reports a failure to update the cache without failing the command
+func saveWarning(err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to update read cache: %v\n", err)
+	}
+}
+
+// Shipments returns all shipments
+func (r *cachedReader) Shipments() ([]database.Shipment, error) {
+	shipments, err := r.client.GetShipments()
+	if err == nil {
+		if r.cache != nil {
+			saveWarning(r.cache.StoreShipments(r.config.ServerURL, shipments))
+		}
+		return shipments, nil
+	}
+
+	if r.useCache(err) {
+		if cached, fetchedAt, ok := r.cache.Shipments(); ok {
+			r.warnStale(err, fetchedAt)
+			return cached, nil
+		}
+	}
+	return nil, err
+}
+
+// Shipment returns a shipment by ID
+func (r *cachedReader) Shipment(id int) (*database.Shipment, error) {
+	shipment, err := r.client.GetShipment(id)
+	if err == nil {
+		if r.cache != nil {
+			saveWarning(r.cache.StoreShipment(r.config.ServerURL, shipment))
+		}
+		return shipment, nil
+	}
+
+	if r.useCache(err) {
+		if cached, fetchedAt, ok := r.cache.Shipment(id); ok {
+			r.warnStale(err, fetchedAt)
+			return cached, nil
+		}
+	}
+	return nil, err
+}
+
+// Events returns a shipment's tracking events
+func (r *cachedReader) Events(id int) ([]database.TrackingEvent, error) {
+	events, err := r.client.GetEvents(id)
+	if err == nil {
+		if r.cache != nil {
+			saveWarning(r.cache.StoreEvents(r.config.ServerURL, id, events))
+		}
+		return events, nil
+	}
+
+	if r.useCache(err) {
+		if cached, fetchedAt, ok := r.cache.Events(id); ok {
+			r.warnStale(err, fetchedAt)
+			return cached, nil
+		}
+	}
+	return nil, err
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cliapi "package-tracking/internal/cli"
+)
+
+func TestCachedReader_FallsBackWhenUnreachable(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id":1,"tracking_number":"1Z999AA10123456784","carrier":"ups","status":"in_transit"}]`))
+	}))
+	serverURL := server.URL
+
+	config := &cliapi.Config{ServerURL: serverURL, Format: "table", Quiet: true}
+	formatter := cliapi.NewOutputFormatter("table", true)
+	client := cliapi.NewClient(serverURL)
+
+	if _, err := newCachedReader(config, formatter, client).Shipments(); err != nil {
+		t.Fatalf("Failed to fetch shipments: %v", err)
+	}
+
+	server.Close()
+
+	shipments, err := newCachedReader(config, formatter, client).Shipments()
+	if err != nil {
+		t.Fatalf("Expected cached shipments, got error: %v", err)
+	}
+	if len(shipments) != 1 || shipments[0].TrackingNumber != "1Z999AA10123456784" {
+		t.Errorf("Unexpected cached shipments: %+v", shipments)
+	}
+
+	shipment, err := newCachedReader(config, formatter, client).Shipment(1)
+	if err != nil || shipment.ID != 1 {
+		t.Errorf("Expected cached shipment 1, got %+v, %v", shipment, err)
+	}
+
+	refreshCache = true
+	defer func() { refreshCache = false }()
+	if _, err := newCachedReader(config, formatter, client).Shipments(); err == nil {
+		t.Error("Expected --refresh-cache to fail when the server is unreachable")
+	}
+}
@@ -9,16 +9,29 @@ import (
 )
 
 var refreshCmd = &cobra.Command{
-	Use:   "refresh <shipment-id>",
-	Short: "Manually refresh tracking data for a shipment",
-	Long:  `Manually refresh the tracking data for a specific shipment by fetching the latest information from the carrier.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runRefresh,
+	Use:   "refresh [shipment-id]",
+	Short: "Manually refresh tracking data for shipments",
+	Long: `Manually refresh the tracking data for a specific shipment by fetching the latest information from the carrier.
+
+Use --all to refresh every shipment that hasn't been delivered, or --carrier and --status to
+refresh the shipments matching them:
+
+  package-tracker refresh --all
+  package-tracker refresh --carrier ups --status in_transit,out_for_delivery
+
+A progress bar is shown while shipments are refreshed one by one, followed by a summary of
+how many got new events and how many were rate limited.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeShipmentIDArg,
+	RunE:              runRefresh,
 }
 
 var (
-	refreshVerbose bool
-	refreshForce   bool
+	refreshVerbose  bool
+	refreshForce    bool
+	refreshAll      bool
+	refreshCarrier  string
+	refreshStatuses []string
 )
 
 func init() {
@@ -26,6 +39,13 @@ func init() {
 
 	refreshCmd.Flags().BoolVar(&refreshVerbose, "verbose", false, "Show detailed refresh information")
 	refreshCmd.Flags().BoolVar(&refreshForce, "force", false, "Force refresh by bypassing cache")
+	refreshCmd.Flags().BoolVar(&refreshAll, "all", false, "Refresh every shipment that hasn't been delivered")
+	refreshCmd.Flags().StringVar(&refreshCarrier, "carrier", "", "Refresh the shipments of this carrier")
+	refreshCmd.Flags().StringSliceVar(&refreshStatuses, "status", nil, "Refresh the shipments with these statuses")
+	refreshCmd.RegisterFlagCompletionFunc("carrier", completeCarriers)
+	refreshCmd.RegisterFlagCompletionFunc("status", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return shipmentStatusNames, cobra.ShellCompDirectiveNoFileComp
+	})
 }
 
 func runRefresh(cmd *cobra.Command, args []string) error {
@@ -34,6 +54,21 @@ func runRefresh(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	bulk := refreshAll || refreshCarrier != "" || len(refreshStatuses) > 0
+	if bulk && len(args) > 0 {
+		err := cliapi.NewValidationError("a shipment ID can't be combined with --all, --carrier or --status")
+		formatter.PrintError(err)
+		return err
+	}
+	if bulk {
+		return runRefreshAll(config, formatter, client)
+	}
+	if len(args) == 0 {
+		err := cliapi.NewValidationError("specify a shipment ID, or --all, --carrier or --status")
+		formatter.PrintError(err)
+		return err
+	}
+
 	id, err := validateAndParseID(args[0])
 	if err != nil {
 		formatter.PrintError(err)
@@ -48,6 +83,7 @@ func runRefresh(cmd *cobra.Command, args []string) error {
 			spinnerText = "Force refreshing tracking data (bypassing cache)"
 		}
 		spinner = cliapi.NewProgressSpinner(spinnerText, noColor)
+		spinner.SetTheme(formatter.Theme())
 		spinner.Start()
 	}
 
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	cliapi "package-tracking/internal/cli"
+	"package-tracking/internal/database"
+)
+
+// refreshAllResult records what happened to each shipment in a bulk refresh
+type refreshAllResult struct {
+	updated     []int // Got new events
+	unchanged   []int
+	rateLimited []int
+	failed      []refreshFailure
+}
+
+type refreshFailure struct {
+	ID    int    `json:"id"`
+	Error string `json:"error"`
+}
+
+// refreshAllOutput is the JSON result of a bulk refresh
+type refreshAllOutput struct {
+	Updated     []int            `json:"updated"`
+	Unchanged   []int            `json:"unchanged"`
+	RateLimited []int            `json:"rate_limited"`
+	Failed      []refreshFailure `json:"failed"`
+}
+
+func (r refreshAllResult) output() refreshAllOutput {
+	// Empty lists rather than nulls, so scripts can always iterate them
+	output := refreshAllOutput{Updated: []int{}, Unchanged: []int{}, RateLimited: []int{}, Failed: []refreshFailure{}}
+	output.Updated = append(output.Updated, r.updated...)
+	output.Unchanged = append(output.Unchanged, r.unchanged...)
+	output.RateLimited = append(output.RateLimited, r.rateLimited...)
+	output.Failed = append(output.Failed, r.failed...)
+	return output
+}
+
+// selectRefreshShipments returns the shipments a bulk refresh applies to. Without a status
+// filter delivered shipments are skipped, since they won't change.
+func selectRefreshShipments(shipments []database.Shipment, carrier string, statuses map[string]bool) []database.Shipment {
+	var selected []database.Shipment
+	for _, shipment := range shipments {
+		if carrier != "" && !strings.EqualFold(shipment.Carrier, carrier) {
+			continue
+		}
+		if statuses != nil {
+			if !statuses[strings.ToLower(shipment.Status)] {
+				continue
+			}
+		} else if shipment.IsDelivered {
+			continue
+		}
+		selected = append(selected, shipment)
+	}
+	return selected
+}
+
+// refreshShipments refreshes each shipment in turn, calling progress after each one
+func refreshShipments(client *cliapi.Client, shipments []database.Shipment, force bool, progress func(database.Shipment)) refreshAllResult {
+	var result refreshAllResult
+	for _, shipment := range shipments {
+		response, err := client.RefreshShipmentWithForce(shipment.ID, force)
+		var apiErr *cliapi.APIError
+		switch {
+		case err == nil && response.EventsAdded > 0:
+			result.updated = append(result.updated, shipment.ID)
+		case err == nil:
+			result.unchanged = append(result.unchanged, shipment.ID)
+		case errors.As(err, &apiErr) && apiErr.Code == http.StatusTooManyRequests:
+			result.rateLimited = append(result.rateLimited, shipment.ID)
+		default:
+			result.failed = append(result.failed, refreshFailure{ID: shipment.ID, Error: err.Error()})
+		}
+		if progress != nil {
+			progress(shipment)
+		}
+	}
+	return result
+}
+
+// summary describes the result in one line
+func (r refreshAllResult) summary() string {
+	return fmt.Sprintf("Refreshed %d shipments: %d with new events, %d unchanged, %d rate limited, %d failed",
+		len(r.updated)+len(r.unchanged)+len(r.rateLimited)+len(r.failed),
+		len(r.updated), len(r.unchanged), len(r.rateLimited), len(r.failed))
+}
+
+func runRefreshAll(config *cliapi.Config, formatter *cliapi.OutputFormatter, client *cliapi.Client) error {
+	var statuses map[string]bool
+	if len(refreshStatuses) > 0 {
+		var err error
+		statuses, err = parseStatusFilter(refreshStatuses)
+		if err != nil {
+			formatter.PrintError(err)
+			return err
+		}
+	}
+
+	shipments, err := client.GetShipments()
+	if err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+
+	selected := selectRefreshShipments(shipments, refreshCarrier, statuses)
+	if len(selected) == 0 {
+		if formatter.IsJSON() {
+			return formatter.PrintResult(refreshAllResult{}.output(), nil)
+		}
+		if !config.Quiet {
+			formatter.PrintInfo("No shipments to refresh")
+		}
+		return nil
+	}
+
+	var bar *cliapi.ProgressBar
+	if !config.Quiet {
+		bar = cliapi.NewProgressBar(len(selected), noColor)
+		bar.SetTheme(formatter.Theme())
+	}
+	result := refreshShipments(client, selected, refreshForce, func(shipment database.Shipment) {
+		if bar != nil {
+			bar.Increment(shipment.TrackingNumber)
+		}
+	})
+	if bar != nil {
+		bar.Finish()
+	}
+
+	var resultErr error
+	if len(result.failed) > 0 {
+		messages := make([]string, len(result.failed))
+		for i, failure := range result.failed {
+			messages[i] = fmt.Sprintf("%d: %s", failure.ID, failure.Error)
+		}
+		resultErr = fmt.Errorf("failed to refresh %d shipments:\n  %s", len(result.failed), strings.Join(messages, "\n  "))
+	}
+
+	if formatter.IsJSON() {
+		if err := formatter.PrintResult(result.output(), resultErr); err != nil {
+			return err
+		}
+		return resultErr
+	}
+
+	if config.Quiet {
+		// Print the shipments that changed, for piping into other commands
+		for _, id := range result.updated {
+			fmt.Printf("%d\n", id)
+		}
+	} else {
+		formatter.PrintSuccess(result.summary())
+		if len(result.updated) > 0 {
+			formatter.PrintInfo(fmt.Sprintf("New events: %s", joinIDs(result.updated)))
+		}
+		if len(result.rateLimited) > 0 {
+			formatter.PrintWarning(fmt.Sprintf("Rate limited, try again later: %s", joinIDs(result.rateLimited)))
+		}
+	}
+
+	if resultErr != nil {
+		formatter.PrintError(resultErr)
+	}
+	return resultErr
+}
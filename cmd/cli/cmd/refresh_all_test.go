@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	cliapi "package-tracking/internal/cli"
+	"package-tracking/internal/database"
+)
+
+func TestSelectRefreshShipments(t *testing.T) {
+	shipments := []database.Shipment{
+		{ID: 1, Carrier: "ups", Status: "in_transit"},
+		{ID: 2, Carrier: "usps", Status: "in_transit"},
+		{ID: 3, Carrier: "UPS", Status: "delivered", IsDelivered: true},
+		{ID: 4, Carrier: "ups", Status: "pre_ship"},
+	}
+
+	ids := func(selected []database.Shipment) []int {
+		var result []int
+		for _, shipment := range selected {
+			result = append(result, shipment.ID)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		carrier  string
+		statuses map[string]bool
+		expected []int
+	}{
+		{"all skips delivered", "", nil, []int{1, 2, 4}},
+		{"carrier", "ups", nil, []int{1, 4}},
+		{"status includes delivered when asked", "ups", map[string]bool{"delivered": true}, []int{3}},
+		{"carrier and statuses", "ups", map[string]bool{"in_transit": true, "pre_ship": true}, []int{1, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ids(selectRefreshShipments(shipments, tt.carrier, tt.statuses)); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRefreshShipments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/shipments/1/refresh":
+			json.NewEncoder(w).Encode(cliapi.RefreshResponse{ShipmentID: 1, EventsAdded: 2})
+		case "/api/shipments/2/refresh":
+			json.NewEncoder(w).Encode(cliapi.RefreshResponse{ShipmentID: 2})
+		case "/api/shipments/3/refresh":
+			http.Error(w, "Rate limit exceeded. Please wait 4m0s before refreshing again", http.StatusTooManyRequests)
+		default:
+			http.Error(w, "Carrier unavailable", http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	shipments := []database.Shipment{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}}
+	var progressed []int
+	result := refreshShipments(cliapi.NewClient(server.URL), shipments, false, func(shipment database.Shipment) {
+		progressed = append(progressed, shipment.ID)
+	})
+
+	if !reflect.DeepEqual(result.updated, []int{1}) || !reflect.DeepEqual(result.unchanged, []int{2}) ||
+		!reflect.DeepEqual(result.rateLimited, []int{3}) || len(result.failed) != 1 || result.failed[0].ID != 4 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if !reflect.DeepEqual(progressed, []int{1, 2, 3, 4}) {
+		t.Errorf("Expected progress after every shipment, got %v", progressed)
+	}
+
+	expected := "Refreshed 4 shipments: 1 with new events, 1 unchanged, 1 rate limited, 1 failed"
+	if result.summary() != expected {
+		t.Errorf("Expected summary %q, got %q", expected, result.summary())
+	}
+}
@@ -11,11 +11,16 @@ import (
 )
 
 var (
+	profile         string
 	serverURL       string
 	format          string
 	quiet           bool
 	noColor         bool
 	skipHealthCheck bool
+	apiKey          string
+
+	// activeFormatter is the formatter set up for the running command, if it got that far
+	activeFormatter *cliapi.OutputFormatter
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -31,31 +36,72 @@ and view tracking events.`,
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// The exit code tells scripts why a command failed (see cliapi.ExitCode), and with
+// --format json a failure is also reported in a JSON envelope on stdout.
 func Execute() {
-	fang.Execute(context.Background(), rootCmd)
+	markUsageErrors(rootCmd)
+
+	if err := fang.Execute(context.Background(), rootCmd); err != nil {
+		if jsonOutputRequested() && (activeFormatter == nil || !activeFormatter.WroteEnvelope()) {
+			cliapi.PrintErrorEnvelope(err)
+		}
+		os.Exit(cliapi.ExitCode(err))
+	}
+}
+
+// markUsageErrors makes argument and flag errors ValidationErrors, so they get the
+// validation exit code
+func markUsageErrors(cmd *cobra.Command) {
+	cmd.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+		return &cliapi.ValidationError{Message: err.Error()}
+	})
+
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		if validateArgs := c.Args; validateArgs != nil {
+			c.Args = func(c *cobra.Command, args []string) error {
+				if err := validateArgs(c, args); err != nil {
+					return &cliapi.ValidationError{Message: err.Error()}
+				}
+				return nil
+			}
+		}
+		for _, child := range c.Commands() {
+			walk(child)
+		}
+	}
+	walk(cmd)
+}
+
+// jsonOutputRequested reports whether the running command writes JSON, falling back to the
+// flag and environment when it failed before loading its configuration
+func jsonOutputRequested() bool {
+	if activeFormatter != nil {
+		return activeFormatter.IsJSON()
+	}
+	if format != "" {
+		return format == "json"
+	}
+	return os.Getenv("PACKAGE_TRACKER_FORMAT") == "json"
 }
 
 func init() {
 	cobra.OnInitialize(initConfig)
 
 	// Global flags
+	rootCmd.PersistentFlags().StringVarP(&profile, "profile", "p", "", "Config file profile to use (from ~/.config/package-tracker/config.yaml)")
 	rootCmd.PersistentFlags().StringVarP(&serverURL, "server", "s", "", "API server address")
-	rootCmd.PersistentFlags().StringVarP(&format, "format", "f", "", "Output format (table, json)")
+	rootCmd.PersistentFlags().StringVarP(&format, "format", "f", "", "Output format (table, json, csv, yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Quiet mode (minimal output)")
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable color output")
+	rootCmd.PersistentFlags().StringVar(&apiKey, "api-key", "", "API key sent as a bearer token (overrides PACKAGE_TRACKER_API_KEY)")
 	rootCmd.PersistentFlags().BoolVar(&skipHealthCheck, "skip-health-check", false, "Skip API health check for faster execution")
 }
 
-// initConfig initializes configuration and environment variable binding
+// initConfig initializes configuration and environment variable binding. Server URL and
+// format are resolved by LoadConfigWithProfile so config file profiles aren't overridden
+// by defaults.
 func initConfig() {
-	// Set defaults and bind environment variables
-	if serverURL == "" {
-		serverURL = getEnvOrDefault("PACKAGE_TRACKER_SERVER", "http://localhost:8080")
-	}
-	if format == "" {
-		format = getEnvOrDefault("PACKAGE_TRACKER_FORMAT", "table")
-	}
-	
 	// Handle boolean environment variables
 	if os.Getenv("PACKAGE_TRACKER_QUIET") == "true" && !rootCmd.PersistentFlags().Changed("quiet") {
 		quiet = true
@@ -78,17 +124,52 @@ func getEnvOrDefault(envVar, defaultVal string) string {
 
 // initializeClient sets up configuration, formatter, and API client
 func initializeClient() (*cliapi.Config, *cliapi.OutputFormatter, *cliapi.Client, error) {
-	config, err := cliapi.LoadConfig(serverURL, format, quiet)
+	return setupClient(false)
+}
+
+// initializeReadClient is like initializeClient, but tolerates an unreachable server so read
+// commands can fall back to the read cache
+func initializeReadClient() (*cliapi.Config, *cliapi.OutputFormatter, *cliapi.Client, error) {
+	return setupClient(true)
+}
+
+// loadConfig loads the configuration, applying global flags that LoadConfigWithProfile
+// doesn't take
+func loadConfig() (*cliapi.Config, error) {
+	config, err := cliapi.LoadConfigWithProfile(profile, serverURL, format, quiet)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey != "" {
+		config.APIKey = apiKey
+	}
+	return config, nil
+}
+
+func setupClient(allowUnreachable bool) (*cliapi.Config, *cliapi.OutputFormatter, *cliapi.Client, error) {
+	config, err := loadConfig()
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
+	// A profile may disable color even when --no-color isn't given, and --no-color has to
+	// reach the interactive table, which only sees the config
+	noColor = noColor || config.NoColor
+	config.NoColor = noColor
+
 	formatter := cliapi.NewOutputFormatterWithColor(config.Format, config.Quiet, noColor)
+	theme, err := cliapi.ThemeByName(config.Theme)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	formatter.SetTheme(theme)
+	activeFormatter = formatter
 	client := cliapi.NewClientWithTimeout(config.ServerURL, config.RequestTimeout)
+	client.SetAPIKey(config.APIKey)
 
 	// Test connectivity (unless skipped for performance)
 	if !skipHealthCheck {
-		if err := client.HealthCheck(); err != nil {
+		if err := client.HealthCheck(); err != nil && !(allowUnreachable && cliapi.IsUnreachable(err)) {
 			formatter.PrintError(err)
 			return nil, nil, nil, err
 		}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	cliapi "package-tracking/internal/cli"
+)
+
+func TestMarkUsageErrors(t *testing.T) {
+	root := &cobra.Command{Use: "root"}
+	child := &cobra.Command{Use: "child", Args: cobra.ExactArgs(1), RunE: func(*cobra.Command, []string) error { return nil }}
+	root.AddCommand(child)
+
+	markUsageErrors(root)
+
+	if err := child.Args(child, nil); cliapi.ExitCode(err) != cliapi.ExitValidation {
+		t.Errorf("Expected argument error to have the validation exit code, got %d (%v)", cliapi.ExitCode(err), err)
+	}
+	if err := child.Args(child, []string{"1"}); err != nil {
+		t.Errorf("Expected valid arguments to pass, got %v", err)
+	}
+
+	flagErr := child.FlagErrorFunc()(child, errors.New("unknown flag: --bogus"))
+	if cliapi.ExitCode(flagErr) != cliapi.ExitValidation {
+		t.Errorf("Expected flag error to have the validation exit code, got %d", cliapi.ExitCode(flagErr))
+	}
+}
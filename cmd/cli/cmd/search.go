@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	cliapi "package-tracking/internal/cli"
+	"package-tracking/internal/database"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search shipments by tracking number, description, carrier or status",
+	Long: `Search shipments with the same fuzzy matching as the interactive table's / filter: the
+query matches a field when its characters appear in order, so "1z99" finds "1Z999AA1..." and
+"hdphn" finds "Headphones". Matched characters are highlighted.
+
+--json prints one JSON object per line, with the matched character positions of each field,
+for piping into pickers such as fzf:
+
+  package-tracker search ups --json | fzf | jq .id
+
+--format json, csv and yaml print the matching shipments like list does.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSearch,
+}
+
+var searchJSONLines bool
+
+// searchColumns are the fields shown by the search table, in list's order
+var searchColumns = []string{"id", "tracking", "carrier", "status", "description"}
+
+// searchFieldWidths are the widths the search table truncates fields to
+var searchFieldWidths = map[string]int{"tracking": 20, "carrier": 8, "status": 16, "description": 30}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+
+	searchCmd.Flags().BoolVar(&searchJSONLines, "json", false, "Print one JSON object per match and line, for piping into pickers")
+	addRefreshCacheFlag(searchCmd)
+}
+
+// searchMatch is a shipment that matched a search, with the matched rune positions of each
+// field that matched
+type searchMatch struct {
+	shipment database.Shipment
+	matches  map[string][]int
+}
+
+// searchMatchOutput is a line of --json output
+type searchMatchOutput struct {
+	ID             int              `json:"id"`
+	TrackingNumber string           `json:"tracking_number"`
+	Carrier        string           `json:"carrier"`
+	Status         string           `json:"status"`
+	Description    string           `json:"description"`
+	Matches        map[string][]int `json:"matches"`
+}
+
+// searchShipments returns the shipments matching query, in their original order
+func searchShipments(shipments []database.Shipment, query string) []searchMatch {
+	var results []searchMatch
+	for _, shipment := range shipments {
+		if matches, ok := shipmentMatches(shipment, query); ok {
+			results = append(results, searchMatch{shipment: shipment, matches: matches})
+		}
+	}
+	return results
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	config, formatter, client, err := initializeReadClient()
+	if err != nil {
+		return err
+	}
+
+	query := strings.Join(args, " ")
+	if strings.TrimSpace(query) == "" {
+		err := cliapi.NewValidationError("search query cannot be empty")
+		formatter.PrintError(err)
+		return err
+	}
+
+	shipments, err := newCachedReader(config, formatter, client).Shipments()
+	if err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+
+	results := searchShipments(shipments, query)
+
+	if searchJSONLines {
+		return writeSearchJSONLines(os.Stdout, results)
+	}
+	if config.Quiet || config.Format != "table" {
+		matched := make([]database.Shipment, len(results))
+		for i, result := range results {
+			matched[i] = result.shipment
+		}
+		return formatter.PrintShipments(matched)
+	}
+
+	if len(results) == 0 {
+		formatter.PrintInfo(fmt.Sprintf("No shipments match %q", query))
+		return nil
+	}
+	fmt.Print(renderSearchTable(results, formatter.Theme(), !config.NoColor))
+	return nil
+}
+
+// writeSearchJSONLines writes each match as a JSON object on its own line
+func writeSearchJSONLines(w io.Writer, results []searchMatch) error {
+	encoder := json.NewEncoder(w)
+	for _, result := range results {
+		output := searchMatchOutput{
+			ID:             result.shipment.ID,
+			TrackingNumber: result.shipment.TrackingNumber,
+			Carrier:        result.shipment.Carrier,
+			Status:         result.shipment.Status,
+			Description:    result.shipment.Description,
+			Matches:        result.matches,
+		}
+		if err := encoder.Encode(output); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderSearchTable renders the matches as a table, highlighting the matched characters when
+// color is on. The table is aligned by hand because tabwriter would count the highlights'
+// escape sequences.
+func renderSearchTable(results []searchMatch, theme cliapi.Theme, color bool) string {
+	columns := searchColumns
+	highlight := lipgloss.NewStyle().Bold(true).Foreground(theme.Highlight)
+
+	widths := make([]int, len(columns))
+	for i, column := range columns {
+		widths[i] = len(column)
+	}
+	cells := make([][]string, len(results))
+	visible := make([][]int, len(results))
+	for r, result := range results {
+		cells[r] = make([]string, len(columns))
+		visible[r] = make([]int, len(columns))
+		for i, column := range columns {
+			var positions []int
+			if color {
+				positions = result.matches[column]
+			}
+			cell, overhead := highlightCell(getFieldValue(result.shipment, column), positions, searchFieldWidths[column], highlight)
+			cells[r][i] = cell
+			visible[r][i] = utf8.RuneCountInString(cell) - overhead
+			if visible[r][i] > widths[i] {
+				widths[i] = visible[r][i]
+			}
+		}
+	}
+
+	var b strings.Builder
+	for i, column := range columns {
+		if i > 0 {
+			b.WriteString("  ")
+		}
+		if i < len(columns)-1 {
+			fmt.Fprintf(&b, "%-*s", widths[i], strings.ToUpper(column))
+		} else {
+			b.WriteString(strings.ToUpper(column))
+		}
+	}
+	b.WriteString("\n")
+	for r, row := range cells {
+		for i, cell := range row {
+			b.WriteString(cell)
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-visible[r][i]+2))
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	cliapi "package-tracking/internal/cli"
+	"package-tracking/internal/database"
+)
+
+var searchTestShipments = []database.Shipment{
+	{ID: 1, TrackingNumber: "1Z999AA10123456784", Carrier: "ups", Status: "in_transit", Description: "Headphones"},
+	{ID: 2, TrackingNumber: "9400111899223197428490", Carrier: "usps", Status: "delivered", Description: "Books"},
+	{ID: 3, TrackingNumber: "123456789012", Carrier: "fedex", Status: "pre_ship", Description: "Phone case"},
+}
+
+func TestSearchShipments(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected []int
+	}{
+		{"1z99", []int{1}},
+		{"hdphn", []int{1}},
+		{"phone", []int{1, 3}},
+		{"delivered", []int{2}},
+		{"nothing here", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var ids []int
+			for _, result := range searchShipments(searchTestShipments, tt.query) {
+				ids = append(ids, result.shipment.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, ids)
+			}
+		})
+	}
+}
+
+func TestWriteSearchJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeSearchJSONLines(&buf, searchShipments(searchTestShipments, "phone")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected one line per match, got %q", buf.String())
+	}
+
+	var first searchMatchOutput
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("Line isn't JSON: %v", err)
+	}
+	if first.ID != 1 || first.Description != "Headphones" {
+		t.Errorf("Unexpected match: %+v", first)
+	}
+	if !reflect.DeepEqual(first.Matches["description"], []int{4, 5, 6, 7, 8}) {
+		t.Errorf("Expected description match positions, got %v", first.Matches)
+	}
+}
+
+func TestRenderSearchTable(t *testing.T) {
+	output := renderSearchTable(searchShipments(searchTestShipments, "phone"), cliapi.Theme{}, false)
+
+	expected := "" +
+		"ID  TRACKING            CARRIER  STATUS      DESCRIPTION\n" +
+		"1   1Z999AA10123456784  ups      in_transit  Headphones\n" +
+		"3   123456789012        fedex    pre_ship    Phone case\n"
+	if output != expected {
+		t.Errorf("Unexpected table:\n%s\nexpected:\n%s", output, expected)
+	}
+}
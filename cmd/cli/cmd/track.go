@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	cliapi "package-tracking/internal/cli"
+)
+
+var trackCmd = &cobra.Command{
+	Use:   "track <tracking-number> [description]",
+	Short: "Add a shipment and fetch its status in one step",
+	Long: `Add a shipment, detecting the carrier from the tracking number format, then immediately
+refresh it and print its current status.
+
+Use --carrier when the tracking number format is ambiguous or not recognized.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runTrack,
+}
+
+var trackCarrier string
+
+func init() {
+	rootCmd.AddCommand(trackCmd)
+
+	trackCmd.Flags().StringVarP(&trackCarrier, "carrier", "c", "", "Carrier name, if it can't be detected (ups, fedex, usps, dhl, amazon)")
+	trackCmd.RegisterFlagCompletionFunc("carrier", completeCarriers)
+}
+
+func runTrack(cmd *cobra.Command, args []string) error {
+	config, formatter, client, err := initializeClient()
+	if err != nil {
+		return err
+	}
+
+	trackingNumber := strings.TrimSpace(args[0])
+	description := ""
+	if len(args) > 1 {
+		description = args[1]
+	}
+
+	carrier := strings.ToLower(trackCarrier)
+	if carrier == "" {
+		carrier = detectCarrier(trackingNumber)
+		if carrier == "" {
+			err := cliapi.NewValidationError("could not detect the carrier for %s; specify one with --carrier", trackingNumber)
+			formatter.PrintError(err)
+			return err
+		}
+		if !config.Quiet {
+			formatter.PrintInfo(fmt.Sprintf("Detected carrier: %s", carrier))
+		}
+	}
+
+	shipment, err := client.CreateShipment(&cliapi.CreateShipmentRequest{
+		TrackingNumber: trackingNumber,
+		Carrier:        carrier,
+		Description:    description,
+	})
+	if err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+
+	var spinner *cliapi.ProgressSpinner
+	if !config.Quiet {
+		spinner = cliapi.NewProgressSpinner("Fetching tracking data", noColor)
+		spinner.SetTheme(formatter.Theme())
+		spinner.Start()
+	}
+
+	_, refreshErr := client.RefreshShipment(shipment.ID)
+
+	if spinner != nil {
+		spinner.Stop()
+	}
+
+	// The shipment was created either way, so a failed refresh is only a warning; the
+	// background updater will pick it up later
+	if refreshErr != nil {
+		formatter.PrintWarning(fmt.Sprintf("Shipment added but refresh failed: %v", refreshErr))
+	} else if updated, err := client.GetShipment(shipment.ID); err == nil {
+		shipment = updated
+	}
+
+	if !config.Quiet {
+		formatter.PrintSuccess(fmt.Sprintf("Tracking shipment %d", shipment.ID))
+	}
+	return formatter.PrintShipment(shipment)
+}
@@ -7,12 +7,13 @@ import (
 )
 
 var updateCmd = &cobra.Command{
-	Use:     "update <shipment-id>",
-	Aliases: []string{"edit", "modify"},
-	Short:   "Update shipment description",
-	Long:    `Update the description of an existing shipment.`,
-	Args:    cobra.ExactArgs(1),
-	RunE:    runUpdate,
+	Use:               "update <shipment-id>",
+	Aliases:           []string{"modify"},
+	Short:             "Update shipment description",
+	Long:              `Update the description of an existing shipment.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeShipmentIDArg,
+	RunE:              runUpdate,
 }
 
 var updateDescription string
@@ -4,22 +4,72 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+
+	cliapi "package-tracking/internal/cli"
 )
 
 // validateAndParseID validates that the argument is a non-empty, valid integer ID
 func validateAndParseID(arg string) (int, error) {
 	if strings.TrimSpace(arg) == "" {
-		return 0, fmt.Errorf("ID cannot be empty")
+		return 0, cliapi.NewValidationError("ID cannot be empty")
 	}
 	
 	id, err := strconv.Atoi(arg)
 	if err != nil {
-		return 0, fmt.Errorf("invalid ID '%s': must be a positive integer", arg)
+		return 0, cliapi.NewValidationError("invalid ID '%s': must be a positive integer", arg)
 	}
 	
 	if id <= 0 {
-		return 0, fmt.Errorf("invalid ID '%d': must be a positive integer", id)
+		return 0, cliapi.NewValidationError("invalid ID '%d': must be a positive integer", id)
 	}
 	
 	return id, nil
-}
\ No newline at end of file
+}
+
+// maxIDRange limits how many IDs a single range like 1-1000 can expand to
+const maxIDRange = 1000
+
+// parseIDArgs parses shipment IDs and inclusive ranges like "7-9", returning the IDs in the
+// order given without duplicates
+func parseIDArgs(args []string) ([]int, error) {
+	var ids []int
+	seen := make(map[int]bool)
+	add := func(id int) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	for _, arg := range args {
+		start, end, isRange := strings.Cut(arg, "-")
+		if !isRange {
+			id, err := validateAndParseID(arg)
+			if err != nil {
+				return nil, err
+			}
+			add(id)
+			continue
+		}
+
+		first, err := validateAndParseID(start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid range '%s': %w", arg, err)
+		}
+		last, err := validateAndParseID(end)
+		if err != nil {
+			return nil, fmt.Errorf("invalid range '%s': %w", arg, err)
+		}
+		if last < first {
+			return nil, cliapi.NewValidationError("invalid range '%s': end is before start", arg)
+		}
+		if last-first >= maxIDRange {
+			return nil, cliapi.NewValidationError("invalid range '%s': ranges are limited to %d IDs", arg, maxIDRange)
+		}
+		for id := first; id <= last; id++ {
+			add(id)
+		}
+	}
+
+	return ids, nil
+}
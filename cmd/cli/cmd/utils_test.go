@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseIDArgs(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected []int
+		wantErr  bool
+	}{
+		{[]string{"3"}, []int{3}, false},
+		{[]string{"3", "5", "7-9"}, []int{3, 5, 7, 8, 9}, false},
+		{[]string{"2-4", "3", "1"}, []int{2, 3, 4, 1}, false},
+		{[]string{"5-5"}, []int{5}, false},
+		{[]string{"9-7"}, nil, true},
+		{[]string{"abc"}, nil, true},
+		{[]string{"0"}, nil, true},
+		{[]string{"3-"}, nil, true},
+		{[]string{"1-5000"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseIDArgs(tt.args)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseIDArgs(%v) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("parseIDArgs(%v) = %v, want %v", tt.args, got, tt.expected)
+		}
+	}
+}
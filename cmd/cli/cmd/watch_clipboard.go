@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/spf13/cobra"
+
+	cliapi "package-tracking/internal/cli"
+)
+
+var watchClipboardCmd = &cobra.Command{
+	Use:   "watch-clipboard",
+	Short: "Add shipments from tracking numbers copied to the clipboard",
+	Long: `Watch the system clipboard and offer to add any tracking numbers copied to it, detecting
+the carrier from the number format. Numbers that are already being tracked are ignored.
+Numbers made only of digits must pass the carrier's check digit, or be the only thing copied,
+so phone and order numbers aren't mistaken for shipments.
+
+Use --yes to add them without asking. Press Ctrl+C to stop.`,
+	Args: cobra.NoArgs,
+	RunE: runWatchClipboard,
+}
+
+var (
+	watchClipboardYes      bool
+	watchClipboardInterval time.Duration
+)
+
+func init() {
+	rootCmd.AddCommand(watchClipboardCmd)
+
+	watchClipboardCmd.Flags().BoolVarP(&watchClipboardYes, "yes", "y", false, "Add detected tracking numbers without asking")
+	watchClipboardCmd.Flags().DurationVar(&watchClipboardInterval, "interval", time.Second, "How often to check the clipboard")
+}
+
+// clipboardCandidate is a tracking number found on the clipboard
+type clipboardCandidate struct {
+	trackingNumber string
+	carrier        string
+}
+
+// findTrackingNumbers returns the recognizable tracking numbers in text, in order and without
+// duplicates. Each word is checked on its own, and short text is also checked with its spaces
+// removed, since some sites display numbers in groups like "9400 1118 9956 ...".
+//
+// Numbers made only of digits are easily confused with phone and order numbers, so they must
+// pass their carrier's check digit. Where the carrier has no check digit rule for that length,
+// the number is only accepted when it is all that was copied.
+func findTrackingNumbers(text string) []clipboardCandidate {
+	var candidates []clipboardCandidate
+	seen := make(map[string]bool)
+	whole := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(text), " ", ""))
+
+	add := func(s string) {
+		s = strings.ToUpper(s)
+		if s == "" || seen[s] {
+			return
+		}
+		carrier := detectCarrier(s)
+		if carrier == "" {
+			return
+		}
+		if valid, known := checkDigitValid(carrier, s); known && !valid {
+			return
+		} else if !known && isAllDigits(s) && s != whole {
+			return
+		}
+		seen[s] = true
+		candidates = append(candidates, clipboardCandidate{trackingNumber: s, carrier: carrier})
+	}
+
+	for _, word := range strings.Fields(text) {
+		add(strings.Trim(word, ".,;:!?()[]{}<>\"'#"))
+	}
+
+	if trimmed := strings.TrimSpace(text); len(trimmed) <= 64 && !strings.ContainsAny(trimmed, "\r\n") {
+		add(whole)
+	}
+
+	return candidates
+}
+
+// isAllDigits reports whether s is non-empty and contains only ASCII digits
+func isAllDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// clipboardWatcher offers the tracking numbers that appear on the clipboard. It is driven by
+// poll so the watch loop can be tested without a real clipboard or server.
+type clipboardWatcher struct {
+	read    func() (string, error)
+	confirm func(clipboardCandidate) (bool, error)
+	add     func(clipboardCandidate)
+
+	// known holds tracked numbers and ones already offered; last is the previous clipboard text
+	known map[string]bool
+	last  string
+}
+
+// poll reads the clipboard once and offers any new tracking numbers on it. It returns the
+// confirm callback's error, if any.
+func (w *clipboardWatcher) poll() error {
+	text, err := w.read()
+	if err != nil || text == w.last {
+		return nil
+	}
+	w.last = text
+
+	for _, candidate := range findTrackingNumbers(text) {
+		if w.known[candidate.trackingNumber] {
+			continue
+		}
+
+		ok, err := w.confirm(candidate)
+		if err != nil {
+			return err
+		}
+		// Don't ask again about a number that was declined
+		w.known[candidate.trackingNumber] = true
+		if ok {
+			w.add(candidate)
+		}
+	}
+	return nil
+}
+
+func runWatchClipboard(cmd *cobra.Command, args []string) error {
+	config, formatter, client, err := initializeClient()
+	if err != nil {
+		return err
+	}
+
+	if clipboard.Unsupported {
+		err := fmt.Errorf("clipboard access is not supported on this system")
+		formatter.PrintError(err)
+		return err
+	}
+	if watchClipboardInterval <= 0 {
+		err := cliapi.NewValidationError("interval must be positive")
+		formatter.PrintError(err)
+		return err
+	}
+
+	// Skip numbers that are already tracked
+	shipments, err := client.GetShipments()
+	if err != nil {
+		formatter.PrintError(err)
+		return err
+	}
+	known := make(map[string]bool, len(shipments))
+	for _, shipment := range shipments {
+		known[strings.ToUpper(shipment.TrackingNumber)] = true
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Read answers in the background so Ctrl+C still works while waiting for one
+	answers := make(chan string)
+	if !watchClipboardYes {
+		go func() {
+			scanner := bufio.NewScanner(os.Stdin)
+			for scanner.Scan() {
+				answers <- scanner.Text()
+			}
+			close(answers)
+		}()
+	}
+
+	confirm := func(c clipboardCandidate) (bool, error) {
+		if watchClipboardYes {
+			return true, nil
+		}
+		fmt.Printf("Add %s (%s)? [y/N] ", c.trackingNumber, c.carrier)
+		select {
+		case <-ctx.Done():
+			fmt.Println()
+			return false, ctx.Err()
+		case answer, ok := <-answers:
+			if !ok {
+				return false, fmt.Errorf("no more input")
+			}
+			answer = strings.ToLower(strings.TrimSpace(answer))
+			return answer == "y" || answer == "yes", nil
+		}
+	}
+
+	watcher := &clipboardWatcher{
+		read:    clipboard.ReadAll,
+		confirm: confirm,
+		add: func(c clipboardCandidate) {
+			shipment, err := client.CreateShipment(&cliapi.CreateShipmentRequest{
+				TrackingNumber: c.trackingNumber,
+				Carrier:        c.carrier,
+			})
+			if err != nil {
+				formatter.PrintWarning(fmt.Sprintf("Failed to add %s: %v", c.trackingNumber, err))
+				return
+			}
+			formatter.PrintSuccess(fmt.Sprintf("Added shipment ID %d (%s, %s)", shipment.ID, shipment.TrackingNumber, shipment.Carrier))
+		},
+		known: known,
+	}
+
+	if !config.Quiet {
+		formatter.PrintInfo("Watching the clipboard for tracking numbers (Ctrl+C to stop)")
+	}
+
+	// Ignore whatever was already on the clipboard when we started
+	watcher.last, _ = clipboard.ReadAll()
+
+	ticker := time.NewTicker(watchClipboardInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if err := watcher.poll(); err == context.Canceled {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
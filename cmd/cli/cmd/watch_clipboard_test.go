@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFindTrackingNumbers(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []clipboardCandidate
+	}{
+		{"single number", "1Z999AA10123456784", []clipboardCandidate{{"1Z999AA10123456784", "ups"}}},
+		{"number in sentence", "Your order shipped (tracking: 1z999aa10123456784).", []clipboardCandidate{{"1Z999AA10123456784", "ups"}}},
+		{"grouped digits", "9400 1118 9956 2537 8663 61", []clipboardCandidate{{"9400111899562537866361", "usps"}}},
+		{"several numbers", "TBA123456789012 and EK123456789US, TBA123456789012", []clipboardCandidate{{"TBA123456789012", "amazon"}, {"EK123456789US", "usps"}}},
+		{"no numbers", "hello world", nil},
+		{"digits with check digit in sentence", "FedEx tracking 123456789012 arriving Friday", []clipboardCandidate{{"123456789012", "fedex"}}},
+		{"phone number", "5551234567", nil},
+		{"grouped phone number", "555 123 4567", nil},
+		{"phone number in sentence", "Call 5551234567 with questions", nil},
+		{"order number in sentence", "Order 987654321097 has shipped", nil},
+		{"unchecked number on its own", "12345678901", []clipboardCandidate{{"12345678901", "dhl"}}},
+		{"unchecked number in sentence", "Reference 12345678901 for your records", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findTrackingNumbers(tt.text)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("findTrackingNumbers(%q) = %v, want %v", tt.text, got, tt.expected)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("findTrackingNumbers(%q)[%d] = %v, want %v", tt.text, i, got[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+// fakeClipboardWatcher returns a watcher reading successive clipboard contents from texts and
+// recording which numbers were offered and added
+func fakeClipboardWatcher(texts []string, accept bool, offered, added *[]string) *clipboardWatcher {
+	return &clipboardWatcher{
+		read: func() (string, error) {
+			if len(texts) == 0 {
+				return "", nil
+			}
+			text := texts[0]
+			texts = texts[1:]
+			return text, nil
+		},
+		confirm: func(c clipboardCandidate) (bool, error) {
+			*offered = append(*offered, c.trackingNumber)
+			return accept, nil
+		},
+		add: func(c clipboardCandidate) {
+			*added = append(*added, c.trackingNumber)
+		},
+		known: map[string]bool{"1Z999AA10123456784": true},
+	}
+}
+
+func TestClipboardWatcherPoll(t *testing.T) {
+	var offered, added []string
+	w := fakeClipboardWatcher([]string{
+		"TBA123456789012",
+		"TBA123456789012", // unchanged clipboard
+		"1Z999AA10123456784",
+		"Call me at 5551234567",
+		"hello TBA123456789012 EK123456789US",
+	}, true, &offered, &added)
+
+	for i := 0; i < 5; i++ {
+		if err := w.poll(); err != nil {
+			t.Fatalf("poll %d: unexpected error: %v", i, err)
+		}
+	}
+
+	expected := []string{"TBA123456789012", "EK123456789US"}
+	if len(added) != len(expected) {
+		t.Fatalf("Expected %v to be added, got %v", expected, added)
+	}
+	for i := range expected {
+		if added[i] != expected[i] {
+			t.Errorf("added[%d] = %q, want %q", i, added[i], expected[i])
+		}
+	}
+	if len(offered) != len(added) {
+		t.Errorf("Expected only new numbers to be offered, got %v", offered)
+	}
+}
+
+func TestClipboardWatcherPollDeclined(t *testing.T) {
+	var offered, added []string
+	w := fakeClipboardWatcher([]string{"TBA123456789012", "other text", "TBA123456789012"}, false, &offered, &added)
+
+	for i := 0; i < 3; i++ {
+		if err := w.poll(); err != nil {
+			t.Fatalf("poll %d: unexpected error: %v", i, err)
+		}
+	}
+
+	if len(added) != 0 {
+		t.Errorf("Expected nothing added when declined, got %v", added)
+	}
+	if len(offered) != 1 {
+		t.Errorf("Expected a declined number to be offered once, got %v", offered)
+	}
+}
+
+func TestClipboardWatcherPollConfirmError(t *testing.T) {
+	var offered, added []string
+	w := fakeClipboardWatcher([]string{"TBA123456789012"}, true, &offered, &added)
+	w.confirm = func(clipboardCandidate) (bool, error) { return false, context.Canceled }
+
+	if err := w.poll(); err != context.Canceled {
+		t.Errorf("Expected confirm error to be returned, got %v", err)
+	}
+	if len(added) != 0 {
+		t.Errorf("Expected nothing added, got %v", added)
+	}
+}
@@ -394,7 +394,11 @@ func createEmailClient(cfg *config.EmailConfig, logger *slog.Logger) (email.Emai
 // startTimeBasedProcessor starts the time-based email processor with periodic scanning
 func startTimeBasedProcessor(processor *workers.TimeBasedEmailProcessor, logger *slog.Logger) {
 	// Perform initial scan after a short delay
-	time.Sleep(10 * time.Second)
+	select {
+	case <-processor.Done():
+		return
+	case <-time.After(10 * time.Second):
+	}
 	
 	// Get the last scan time (start from 7 days ago if no previous scan)
 	since := time.Now().AddDate(0, 0, -7)
@@ -410,6 +414,10 @@ func startTimeBasedProcessor(processor *workers.TimeBasedEmailProcessor, logger
 	
 	for {
 		select {
+		case <-processor.Done():
+			logger.Info("Time-based email processor loop stopped")
+			return
+
 		case <-ticker.C:
 			// Process emails since last 10 minutes to catch any new ones
 			since := time.Now().Add(-10 * time.Minute)
@@ -421,6 +429,9 @@ func startTimeBasedProcessor(processor *workers.TimeBasedEmailProcessor, logger
 	}
 }
 
+// shutdownDrainTimeout bounds how long shutdown waits for in-flight email processing
+const shutdownDrainTimeout = 30 * time.Second
+
 // handleSignals handles graceful shutdown on system signals
 func handleSignals(processor *workers.TimeBasedEmailProcessor, logger *slog.Logger) error {
 	// Create context for graceful shutdown
@@ -442,8 +453,8 @@ func handleSignals(processor *workers.TimeBasedEmailProcessor, logger *slog.Logg
 		// Start graceful shutdown
 		logger.Info("Starting graceful shutdown...")
 		
-		// Wait a bit for processor to finish current operations
-		time.Sleep(2 * time.Second)
+		// Let the processor finish the email in flight; unprocessed emails are picked up next run
+		processor.Stop(shutdownDrainTimeout)
 		
 		// Signal shutdown completion
 		close(shutdownChan)
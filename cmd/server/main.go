@@ -28,6 +28,8 @@ import (
 	"package-tracking/internal/config"
 	"package-tracking/internal/database"
 	"package-tracking/internal/handlers"
+	"package-tracking/internal/homeassistant"
+	"package-tracking/internal/notifications"
 	"package-tracking/internal/parser"
 	"package-tracking/internal/server"
 	"package-tracking/internal/services"
@@ -57,14 +59,28 @@ func main() {
 
 	log.Printf("Database initialized at %s", cfg.DBPath)
 
-	// Initialize cache manager with configurable TTL
-	cacheManager := cache.NewManager(db.RefreshCache, cfg.GetDisableCache(), cfg.GetCacheTTL())
+	// Initialize cache manager with configurable TTL. The Redis backend is shared with the
+	// other server instances using it.
+	var cacheManager *cache.Manager
+	if cfg.CacheBackend == "redis" {
+		redisStore, err := cache.OpenRedisStore(cfg.CacheRedisURL, cfg.CacheRedisPrefix)
+		if err != nil {
+			log.Fatalf("Failed to open Redis cache: %v", err)
+		}
+		defer redisStore.Close()
+		cacheManager = cache.NewSharedManager(redisStore, cfg.GetDisableCache(), cfg.GetCacheTTL())
+	} else {
+		cacheManager = cache.NewManager(db.RefreshCache, cfg.GetDisableCache(), cfg.GetCacheTTL())
+		if cfg.CacheMemorySize > 0 {
+			cacheManager.SetMemorySize(cfg.CacheMemorySize)
+		}
+	}
 	defer cacheManager.Close()
 
 	if cfg.GetDisableCache() {
 		log.Printf("Cache disabled via configuration")
 	} else {
-		log.Printf("Cache initialized with %v TTL", cfg.GetCacheTTL())
+		log.Printf("Cache initialized with %v TTL (%s backend)", cfg.GetCacheTTL(), cfg.CacheBackend)
 	}
 
 	// Initialize carrier factory
@@ -122,8 +138,42 @@ func main() {
 		Level: slog.LevelInfo,
 	}))
 
+	// Initialize the Home Assistant MQTT publisher, which also receives status changes
+	haPublisher, err := homeassistant.NewPublisher(cfg, db, logger)
+	if err != nil {
+		log.Fatalf("Failed to configure MQTT publishing: %v", err)
+	}
+	defer haPublisher.Stop()
+	haPublisher.Start()
+
+	var integrations []notifications.Notifier
+	if cfg.MQTTEnabled {
+		integrations = append(integrations, haPublisher)
+	}
+
+	// Initialize notification dispatcher. It is stopped after the workers that feed it, so
+	// their last status changes are still delivered.
+	notifier, err := notifications.NewDispatcherFromConfig(cfg, logger, integrations...)
+	if err != nil {
+		log.Fatalf("Failed to configure notifications: %v", err)
+	}
+	if notifier != nil {
+		notifier.SetRules(db.Rules)
+		notifier.SetHistory(db.Notifications)
+	}
+	defer notifier.Stop()
+	notifier.Start()
+
+	// Initialize exception and delay escalation, which the tracking updater reports
+	// expected delivery changes to
+	escalationWorker := workers.NewEscalationWorker(cfg, db, notifier, logger)
+	defer escalationWorker.Stop()
+	escalationWorker.Start()
+
 	// Initialize tracking updater with cache manager for unified rate limiting
-	trackingUpdater := workers.NewTrackingUpdater(cfg, db.Shipments, carrierFactory, cacheManager, logger)
+	trackingUpdater := workers.NewTrackingUpdater(cfg, db.Shipments, db.Quota, carrierFactory, cacheManager, logger)
+	trackingUpdater.SetNotifier(notifier)
+	trackingUpdater.SetEscalator(escalationWorker)
 	defer trackingUpdater.Stop()
 	
 	// Start the tracking updater
@@ -141,6 +191,51 @@ func main() {
 		log.Printf("Automatic tracking updates disabled")
 	}
 
+	// Initialize scheduled email maintenance
+	emailCleanup := workers.NewEmailCleanupWorker(cfg, db.Emails, logger)
+	defer emailCleanup.Stop()
+	emailCleanup.Start()
+
+	// Initialize stalled shipment detection
+	stalledDetector := workers.NewStalledShipmentDetector(cfg, db.Shipments, logger)
+	defer stalledDetector.Stop()
+	stalledDetector.Start()
+
+	// Initialize Telegram bot commands
+	telegramBot := workers.NewTelegramBot(cfg, db, logger)
+	defer telegramBot.Stop()
+	telegramBot.Start()
+
+	// Initialize scheduled digest notifications
+	digestWorker := workers.NewDigestWorker(cfg, db.Shipments, notifier, logger)
+	defer digestWorker.Stop()
+	digestWorker.Start()
+
+	// Reload the settings that can change while running on SIGHUP or through the admin
+	// API. The workers read them from cfg; the rest are copied at startup.
+	configReloader := config.NewReloader(cfg, config.LoadServerConfig)
+	configReloader.OnReload(func(next *config.Config) {
+		cacheManager.SetTTL(cfg.GetCacheTTL())
+		trackingUpdater.Reconfigure()
+		reloaded, err := notifications.NewDispatcherFromConfig(next, logger)
+		if err != nil {
+			logger.Error("Failed to reload notification channels", "error", err)
+			return
+		}
+		notifier.ReloadChannels(reloaded)
+	})
+	stopReloadSignal := server.HandleReloadSignal(func() {
+		report, err := configReloader.Reload()
+		if err != nil {
+			logger.Error("Failed to reload configuration", "error", err)
+			return
+		}
+		logger.Info("Configuration reloaded",
+			"applied", report.Applied,
+			"restart_required", report.RestartRequired)
+	})
+	defer stopReloadSignal()
+
 	// Initialize description enhancer for admin API
 	extractorConfig := &parser.ExtractorConfig{
 		EnableLLM:           false, // LLM can be enabled via environment variables
@@ -168,11 +263,17 @@ func main() {
 
 	// Create handlers
 	shipmentHandler := handlers.NewShipmentHandlerWithFactory(db, cfg, cacheManager, carrierFactory)
+	shipmentHandler.SetNotifier(notifier)
 	healthHandler := handlers.NewHealthHandler(db)
 	carrierHandler := handlers.NewCarrierHandler(db)
 	dashboardHandler := handlers.NewDashboardHandler(db)
-	adminHandler := handlers.NewAdminHandler(trackingUpdater, descriptionEnhancer, logger)
+	adminHandler := handlers.NewAdminHandler(trackingUpdater, emailCleanup, descriptionEnhancer, logger)
 	emailHandler := handlers.NewEmailHandler(db)
+	ruleHandler := handlers.NewNotificationRuleHandler(db, notifier)
+	alertHandler := handlers.NewAlertHandler(db)
+	historyHandler := handlers.NewNotificationHistoryHandler(db, notifier)
+	debugHandler := handlers.NewDebugHandler()
+	configHandler := handlers.NewConfigHandler(configReloader)
 	staticHandler := handlers.NewStaticHandler(staticFS)
 
 	// API routes
@@ -195,11 +296,28 @@ func main() {
 		r.Get("/health", healthHandler.HealthCheck)
 		r.Get("/carriers", carrierHandler.GetCarriers)
 		r.Get("/dashboard/stats", dashboardHandler.GetStats)
+		r.Get("/alerts", alertHandler.GetAlerts)
+		r.Post("/alerts/{id}/acknowledge", alertHandler.AcknowledgeAlert)
+		r.Get("/notifications", historyHandler.GetNotifications)
+		r.Post("/notifications/{id}/retry", historyHandler.RetryNotification)
 		
 		// Admin routes
 		r.Route("/admin", func(r chi.Router) {
 			// Apply authentication middleware if not disabled
-			if !cfg.GetDisableAdminAuth() {
+			if !cfg.GetDisableAdminAuth() && cfg.AdminAuthMode == "proxy" {
+				trustedProxies, err := cfg.TrustedProxyNets()
+				if err != nil {
+					log.Fatalf("Invalid trusted proxies: %v", err)
+				}
+				r.Use(server.ProxyAuthMiddleware(server.ProxyAuthConfig{
+					TrustedProxies: trustedProxies,
+					UserHeaders:    cfg.AuthProxyUserHeaders,
+					Users:          cfg.AuthProxyUsers,
+					ClientCerts:    cfg.TLSClientCAFile != "",
+					APIKey:         cfg.GetAdminAPIKey(),
+				}))
+				log.Printf("Admin API reverse proxy authentication enabled")
+			} else if !cfg.GetDisableAdminAuth() {
 				r.Use(server.AuthMiddleware(cfg.GetAdminAPIKey()))
 				log.Printf("Admin API authentication enabled")
 			} else {
@@ -209,7 +327,22 @@ func main() {
 			r.Get("/tracking-updater/status", adminHandler.GetTrackingUpdaterStatus)
 			r.Post("/tracking-updater/pause", adminHandler.PauseTrackingUpdater)
 			r.Post("/tracking-updater/resume", adminHandler.ResumeTrackingUpdater)
+			r.Get("/email-cleanup/status", adminHandler.GetEmailCleanupStatus)
+			r.Post("/email-cleanup/run", adminHandler.RunEmailCleanup)
 			r.Post("/enhance-descriptions", adminHandler.EnhanceDescriptions)
+			r.Post("/config/reload", configHandler.ReloadConfig)
+
+			r.Get("/notification-rules", ruleHandler.GetRules)
+			r.Post("/notification-rules", ruleHandler.CreateRule)
+			r.Get("/notification-rules/{id}", ruleHandler.GetRule)
+			r.Put("/notification-rules/{id}", ruleHandler.UpdateRule)
+			r.Delete("/notification-rules/{id}", ruleHandler.DeleteRule)
+			r.Post("/notification-rules/{id}/test", ruleHandler.TestRule)
+
+			// Runtime diagnostics: pprof profiles, expvar and a goroutine/heap snapshot
+			r.Get("/debug/runtime", debugHandler.GetRuntime)
+			r.Get("/debug/vars", debugHandler.GetVars)
+			r.Get("/debug/pprof/*", debugHandler.Pprof)
 		})
 	})
 
@@ -226,9 +359,22 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
-	// Handle server startup and graceful shutdown
+	// Listen on the socket systemd passed, if socket activated, so restarts don't refuse
+	// connections, or on a socket of our own
+	listener, err := server.Listen(cfg.Address(), cfg.ServerReusePort)
+	if err != nil {
+		log.Fatalf("Failed to listen on %s: %v", cfg.Address(), err)
+	}
+
+	// Handle server startup and graceful shutdown, over HTTPS when a certificate is set
 	shutdownTimeout := 30 * time.Second
-	if err := server.HandleSignals(srv, shutdownTimeout); err != nil {
+	if cfg.TLSCertFile != "" {
+		srv.TLSConfig, err = server.NewTLSConfig(cfg.TLSClientCAFile)
+		if err != nil {
+			log.Fatalf("Failed to configure TLS: %v", err)
+		}
+	}
+	if err := server.HandleSignalsOn(srv, listener, cfg.TLSCertFile, cfg.TLSKeyFile, shutdownTimeout); err != nil {
 		log.Fatalf("Server error: %v", err)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// defaultMemorySize is how many responses the in-memory layer holds by default
+const defaultMemorySize = 1000
+
+// lru is a bounded in-memory map of cached responses by shipment ID, which evicts the least
+// recently used response when full
+type lru struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List            // Most recently used first
+	entries  map[int]*list.Element // Values are *lruEntry
+}
+
+type lruEntry struct {
+	shipmentID int
+	cached     *CachedResponse
+}
+
+func newLRU(capacity int) *lru {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &lru{capacity: capacity, order: list.New(), entries: make(map[int]*list.Element)}
+}
+
+// Load returns a shipment's response and marks it recently used
+func (c *lru) Load(shipmentID int) (*CachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[shipmentID]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*lruEntry).cached, true
+}
+
+// Store adds or replaces a shipment's response, evicting the least recently used ones
+// beyond capacity
+func (c *lru) Store(shipmentID int, cached *CachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[shipmentID]; ok {
+		element.Value.(*lruEntry).cached = cached
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[shipmentID] = c.order.PushFront(&lruEntry{shipmentID: shipmentID, cached: cached})
+	c.evict()
+}
+
+// Delete removes a shipment's response
+func (c *lru) Delete(shipmentID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[shipmentID]; ok {
+		c.order.Remove(element)
+		delete(c.entries, shipmentID)
+	}
+}
+
+// Range calls fn for a snapshot of the responses, most recently used first, until fn
+// returns false. fn may modify the cache.
+func (c *lru) Range(fn func(shipmentID int, cached *CachedResponse) bool) {
+	c.mu.Lock()
+	entries := make([]lruEntry, 0, c.order.Len())
+	for element := c.order.Front(); element != nil; element = element.Next() {
+		entries = append(entries, *element.Value.(*lruEntry))
+	}
+	c.mu.Unlock()
+
+	for _, entry := range entries {
+		if !fn(entry.shipmentID, entry.cached) {
+			return
+		}
+	}
+}
+
+// Capacity returns the maximum number of responses held
+func (c *lru) Capacity() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.capacity
+}
+
+// Resize changes the capacity, evicting the least recently used responses beyond it
+func (c *lru) Resize(capacity int) {
+	if capacity < 1 {
+		capacity = 1
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.capacity = capacity
+	c.evict()
+}
+
+// evict drops the least recently used responses beyond capacity. Callers hold mu.
+func (c *lru) evict() {
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).shipmentID)
+	}
+}
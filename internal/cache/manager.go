@@ -4,10 +4,14 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"sync"
+	"strconv"
 	"time"
 
+	"golang.org/x/sync/singleflight"
+
+	"package-tracking/internal/carriers"
 	"package-tracking/internal/database"
+	"package-tracking/internal/ratelimit"
 )
 
 // CachedResponse represents an in-memory cached response with expiry
@@ -21,12 +25,33 @@ func (c *CachedResponse) IsExpired() bool {
 	return time.Now().After(c.ExpiresAt)
 }
 
+// Store persists cached refresh responses; *database.RefreshCacheStore by default
+type Store interface {
+	Get(shipmentID int) (*database.RefreshResponse, error)
+	Set(shipmentID int, response *database.RefreshResponse, ttl time.Duration) error
+	Delete(shipmentID int) error
+	DeleteExpired() error
+	LoadAll() (map[int]*database.RefreshResponse, error)
+	GetStats() (int, int, error)
+}
+
+// manualRefreshStore is implemented by stores shared between server instances, which
+// also share when shipments were last refreshed manually so rate limits hold across them
+type manualRefreshStore interface {
+	GetManualRefresh(shipmentID int) (*time.Time, error)
+	SetManualRefresh(shipmentID int, at time.Time, ttl time.Duration) error
+}
+
 // Manager manages both in-memory and persistent caching for refresh responses
 type Manager struct {
-	store    *database.RefreshCacheStore
-	memory   sync.Map // map[int]*CachedResponse
+	store    Store
+	memory   *lru
 	disabled bool
 	ttl      time.Duration
+	shared   bool // The store is shared with other instances, so nothing is kept in memory
+
+	// lookups shares carrier lookups of a shipment between concurrent refreshes
+	lookups singleflight.Group
 	
 	// Cleanup goroutine control
 	ctx    context.Context
@@ -34,18 +59,31 @@ type Manager struct {
 }
 
 // NewManager creates a new cache manager
-func NewManager(store *database.RefreshCacheStore, disabled bool, ttl time.Duration) *Manager {
+func NewManager(store Store, disabled bool, ttl time.Duration) *Manager {
+	return newManager(store, disabled, ttl, false)
+}
+
+// NewSharedManager creates a cache manager for a store shared between server instances,
+// such as Redis. Responses aren't kept in memory, so an entry invalidated by one instance
+// isn't served by another.
+func NewSharedManager(store Store, disabled bool, ttl time.Duration) *Manager {
+	return newManager(store, disabled, ttl, true)
+}
+
+func newManager(store Store, disabled bool, ttl time.Duration, shared bool) *Manager {
 	ctx, cancel := context.WithCancel(context.Background())
-	
+
 	manager := &Manager{
 		store:    store,
+		memory:   newLRU(defaultMemorySize),
 		disabled: disabled,
 		ttl:      ttl,
+		shared:   shared,
 		ctx:      ctx,
 		cancel:   cancel,
 	}
-	
-	if !disabled {
+
+	if !disabled && !shared {
 		// Load existing cache entries from database
 		if err := manager.loadFromDatabase(); err != nil {
 			log.Printf("WARN: Failed to load cache from database: %v", err)
@@ -65,8 +103,7 @@ func (m *Manager) Get(shipmentID int) (*database.RefreshResponse, error) {
 	}
 	
 	// Check in-memory cache first
-	if value, ok := m.memory.Load(shipmentID); ok {
-		cached := value.(*CachedResponse)
+	if cached, ok := m.memory.Load(shipmentID); ok {
 		if !cached.IsExpired() {
 			return cached.Response, nil
 		}
@@ -80,7 +117,7 @@ func (m *Manager) Get(shipmentID int) (*database.RefreshResponse, error) {
 		return nil, fmt.Errorf("failed to get from database cache: %w", err)
 	}
 	
-	if response != nil {
+	if response != nil && !m.shared {
 		// Store in memory for faster access next time
 		cached := &CachedResponse{
 			Response:  response,
@@ -103,6 +140,10 @@ func (m *Manager) Set(shipmentID int, response *database.RefreshResponse) error
 		return fmt.Errorf("failed to store in database cache: %w", err)
 	}
 	
+	if m.shared {
+		return nil
+	}
+
 	// Store in memory
 	cached := &CachedResponse{
 		Response:  response,
@@ -140,8 +181,7 @@ func (m *Manager) ForceInvalidate(shipmentID int) (*time.Duration, error) {
 	var cacheAge *time.Duration
 	
 	// Check if there was a cache entry and get its age
-	if value, ok := m.memory.Load(shipmentID); ok {
-		cached := value.(*CachedResponse)
+	if cached, ok := m.memory.Load(shipmentID); ok {
 		age := time.Since(cached.Response.UpdatedAt)
 		cacheAge = &age
 	} else {
@@ -164,6 +204,48 @@ func (m *Manager) ForceInvalidate(shipmentID int) (*time.Duration, error) {
 	return cacheAge, nil
 }
 
+// ManualRefreshTime returns when a shipment was last refreshed manually: recorded, from
+// the shipment, or a later refresh by another instance sharing the cache
+func (m *Manager) ManualRefreshTime(shipmentID int, recorded *time.Time) *time.Time {
+	store, ok := m.store.(manualRefreshStore)
+	if !ok {
+		return recorded
+	}
+	shared, err := store.GetManualRefresh(shipmentID)
+	if err != nil {
+		log.Printf("WARN: Failed to get shared manual refresh time for shipment %d: %v", shipmentID, err)
+		return recorded
+	}
+	if shared != nil && (recorded == nil || shared.After(*recorded)) {
+		return shared
+	}
+	return recorded
+}
+
+// RecordManualRefresh shares a manual refresh with the other instances using the cache,
+// for as long as it rate limits further refreshes. Caches that aren't shared ignore it.
+func (m *Manager) RecordManualRefresh(shipmentID int, at time.Time) {
+	store, ok := m.store.(manualRefreshStore)
+	if !ok {
+		return
+	}
+	if err := store.SetManualRefresh(shipmentID, at, ratelimit.GetRateLimitDuration()); err != nil {
+		log.Printf("WARN: Failed to share manual refresh of shipment %d: %v", shipmentID, err)
+	}
+}
+
+// Track looks a shipment up with its carrier through track, sharing the call with any
+// lookup of the same shipment already in flight, so a manual refresh and an auto-update
+// running at once make one carrier call. shared reports whether the response came from
+// another caller's lookup. Lookups are shared even when caching is disabled.
+func (m *Manager) Track(shipmentID int, track func() (*carriers.TrackingResponse, error)) (resp *carriers.TrackingResponse, shared bool, err error) {
+	value, err, shared := m.lookups.Do(strconv.Itoa(shipmentID), func() (interface{}, error) {
+		return track()
+	})
+	resp, _ = value.(*carriers.TrackingResponse)
+	return resp, shared, err
+}
+
 // IsEnabled returns true if caching is enabled
 func (m *Manager) IsEnabled() bool {
 	return !m.disabled
@@ -179,6 +261,12 @@ func (m *Manager) SetTTL(ttl time.Duration) {
 	m.ttl = ttl
 }
 
+// SetMemorySize bounds the number of responses kept in memory; the least recently used
+// ones are evicted beyond it and served from the database instead
+func (m *Manager) SetMemorySize(size int) {
+	m.memory.Resize(size)
+}
+
 // loadFromDatabase loads all non-expired cache entries from database into memory
 func (m *Manager) loadFromDatabase() error {
 	entries, err := m.store.LoadAll()
@@ -222,10 +310,9 @@ func (m *Manager) cleanupLoop() {
 func (m *Manager) cleanup() {
 	// Clean up memory
 	memoryCount := 0
-	m.memory.Range(func(key, value interface{}) bool {
-		cached := value.(*CachedResponse)
+	m.memory.Range(func(shipmentID int, cached *CachedResponse) bool {
 		if cached.IsExpired() {
-			m.memory.Delete(key)
+			m.memory.Delete(shipmentID)
 			memoryCount++
 		}
 		return true
@@ -244,8 +331,9 @@ func (m *Manager) cleanup() {
 // GetStats returns cache statistics
 func (m *Manager) GetStats() (CacheStats, error) {
 	stats := CacheStats{
-		Disabled: m.disabled,
-		TTL:      m.ttl,
+		Disabled:       m.disabled,
+		TTL:            m.ttl,
+		MemoryCapacity: m.memory.Capacity(),
 	}
 	
 	if m.disabled {
@@ -255,9 +343,8 @@ func (m *Manager) GetStats() (CacheStats, error) {
 	// Count memory entries
 	memoryTotal := 0
 	memoryExpired := 0
-	m.memory.Range(func(key, value interface{}) bool {
+	m.memory.Range(func(shipmentID int, cached *CachedResponse) bool {
 		memoryTotal++
-		cached := value.(*CachedResponse)
 		if cached.IsExpired() {
 			memoryExpired++
 		}
@@ -291,6 +378,7 @@ type CacheStats struct {
 	Disabled        bool          `json:"disabled"`
 	TTL             time.Duration `json:"ttl"`
 	MemoryTotal     int           `json:"memory_total"`
+	MemoryCapacity  int           `json:"memory_capacity"`
 	MemoryExpired   int           `json:"memory_expired"`
 	DatabaseTotal   int           `json:"database_total"`
 	DatabaseExpired int           `json:"database_expired"`
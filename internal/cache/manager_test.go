@@ -1,9 +1,14 @@
 package cache
 
 import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"package-tracking/internal/carriers"
 	"package-tracking/internal/database"
 )
 
@@ -286,4 +291,104 @@ func TestCachedResponse(t *testing.T) {
 			t.Error("Expected expired")
 		}
 	})
-}
\ No newline at end of file
+}
+func TestCacheManager_MemorySize(t *testing.T) {
+	db, err := database.Open(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	var ids []int
+	for i := 0; i < 3; i++ {
+		shipment := &database.Shipment{TrackingNumber: fmt.Sprintf("TEST%d", i), Carrier: "ups", Status: "pending"}
+		if err := db.Shipments.Create(shipment); err != nil {
+			t.Fatalf("Failed to create test shipment: %v", err)
+		}
+		ids = append(ids, shipment.ID)
+	}
+
+	manager := NewManager(db.RefreshCache, false, 5*time.Minute)
+	defer manager.Close()
+	manager.SetMemorySize(2)
+
+	for _, id := range ids[:2] {
+		if err := manager.Set(id, &database.RefreshResponse{ShipmentID: id, UpdatedAt: time.Now()}); err != nil {
+			t.Fatalf("Failed to set cache: %v", err)
+		}
+	}
+	// Using the first response makes the second the least recently used
+	if _, ok := manager.memory.Load(ids[0]); !ok {
+		t.Fatal("Expected the first response in memory")
+	}
+	if err := manager.Set(ids[2], &database.RefreshResponse{ShipmentID: ids[2], UpdatedAt: time.Now()}); err != nil {
+		t.Fatalf("Failed to set cache: %v", err)
+	}
+
+	if _, ok := manager.memory.Load(ids[1]); ok {
+		t.Error("Expected the least recently used response to be evicted from memory")
+	}
+	stats, err := manager.GetStats()
+	if err != nil {
+		t.Fatalf("Failed to get stats: %v", err)
+	}
+	if stats.MemoryTotal != 2 || stats.MemoryCapacity != 2 || stats.DatabaseTotal != 3 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+
+	// The evicted response is still served from the database
+	cached, err := manager.Get(ids[1])
+	if err != nil || cached == nil || cached.ShipmentID != ids[1] {
+		t.Errorf("Expected the evicted response from the database, got %+v, %v", cached, err)
+	}
+}
+
+func TestCacheManager_Track(t *testing.T) {
+	manager := NewManager(nil, true, 5*time.Minute)
+	defer manager.Close()
+
+	var calls int32
+	release := make(chan struct{})
+	started := make(chan struct{})
+	track := func() (*carriers.TrackingResponse, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+		}
+		<-release
+		return &carriers.TrackingResponse{Results: []carriers.TrackingInfo{{TrackingNumber: "TEST123"}}}, nil
+	}
+
+	var wg sync.WaitGroup
+	responses := make([]*carriers.TrackingResponse, 2)
+	shared := make([]bool, 2)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		responses[0], shared[0], _ = manager.Track(1, track)
+	}()
+	<-started
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		responses[1], shared[1], _ = manager.Track(1, track)
+	}()
+
+	// Give the second lookup time to join the first before it completes
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("Expected one carrier call for simultaneous lookups, got %d", calls)
+	}
+	if responses[0] == nil || responses[0] != responses[1] || !shared[0] || !shared[1] {
+		t.Errorf("Expected both lookups to share the response, got %v (shared %v)", responses, shared)
+	}
+
+	// Lookups that don't overlap each call the carrier
+	if _, shared, err := manager.Track(1, func() (*carriers.TrackingResponse, error) {
+		return nil, errors.New("carrier unavailable")
+	}); err == nil || shared {
+		t.Errorf("Expected a separate failed lookup, got shared %v, error %v", shared, err)
+	}
+}
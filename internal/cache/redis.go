@@ -0,0 +1,141 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"package-tracking/internal/database"
+)
+
+// RedisStore keeps refresh responses and manual refresh times in Redis, so server
+// instances sharing it share their caches and rate limits. Redis expires entries itself.
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisStore creates a store using client, with keys starting with prefix
+func NewRedisStore(client *redis.Client, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+// OpenRedisStore connects to the Redis server at url, e.g. redis://localhost:6379/0, and
+// checks that it is reachable
+func OpenRedisStore(url, prefix string) (*RedisStore, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+	client := redis.NewClient(options)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	return NewRedisStore(client, prefix), nil
+}
+
+func (s *RedisStore) responseKey(shipmentID int) string {
+	return s.prefix + "refresh:" + strconv.Itoa(shipmentID)
+}
+
+func (s *RedisStore) manualRefreshKey(shipmentID int) string {
+	return s.prefix + "manual_refresh:" + strconv.Itoa(shipmentID)
+}
+
+// Get retrieves a cached refresh response, or nil on a cache miss
+func (s *RedisStore) Get(shipmentID int) (*database.RefreshResponse, error) {
+	data, err := s.client.Get(context.Background(), s.responseKey(shipmentID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cached response: %w", err)
+	}
+
+	var response database.RefreshResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to deserialize cached response: %w", err)
+	}
+	return &response, nil
+}
+
+// Set stores a refresh response that expires after ttl
+func (s *RedisStore) Set(shipmentID int, response *database.RefreshResponse, ttl time.Duration) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to serialize response: %w", err)
+	}
+	if err := s.client.Set(context.Background(), s.responseKey(shipmentID), data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to cache response: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a shipment's cached response
+func (s *RedisStore) Delete(shipmentID int) error {
+	if err := s.client.Del(context.Background(), s.responseKey(shipmentID)).Err(); err != nil {
+		return fmt.Errorf("failed to delete cached entry: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired does nothing, as Redis expires entries itself
+func (s *RedisStore) DeleteExpired() error {
+	return nil
+}
+
+// LoadAll returns no entries; a shared cache isn't copied into memory
+func (s *RedisStore) LoadAll() (map[int]*database.RefreshResponse, error) {
+	return map[int]*database.RefreshResponse{}, nil
+}
+
+// GetStats returns the number of cached responses. None are ever expired, as Redis
+// removes them.
+func (s *RedisStore) GetStats() (int, int, error) {
+	ctx := context.Background()
+	total := 0
+	iter := s.client.Scan(ctx, 0, s.prefix+"refresh:*", 100).Iterator()
+	for iter.Next(ctx) {
+		total++
+	}
+	if err := iter.Err(); err != nil {
+		return 0, 0, fmt.Errorf("failed to count cached responses: %w", err)
+	}
+	return total, 0, nil
+}
+
+// GetManualRefresh returns when a shipment was last refreshed manually by any instance,
+// or nil if it wasn't within the rate limit window
+func (s *RedisStore) GetManualRefresh(shipmentID int) (*time.Time, error) {
+	value, err := s.client.Get(context.Background(), s.manualRefreshKey(shipmentID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	at, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manual refresh time %q: %w", value, err)
+	}
+	return &at, nil
+}
+
+// SetManualRefresh records a manual refresh, forgotten after ttl
+func (s *RedisStore) SetManualRefresh(shipmentID int, at time.Time, ttl time.Duration) error {
+	return s.client.Set(context.Background(), s.manualRefreshKey(shipmentID), at.Format(time.RFC3339Nano), ttl).Err()
+}
+
+// Close closes the connection to Redis
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"package-tracking/internal/database"
+)
+
+func TestRedisStore(t *testing.T) {
+	server := miniredis.RunT(t)
+	store, err := OpenRedisStore("redis://"+server.Addr()+"/0", "test:")
+	if err != nil {
+		t.Fatalf("Failed to open Redis store: %v", err)
+	}
+	defer store.Close()
+
+	// Two managers sharing the store, as two server instances would
+	first := NewSharedManager(store, false, 5*time.Minute)
+	defer first.Close()
+	second := NewSharedManager(store, false, 5*time.Minute)
+	defer second.Close()
+
+	response := &database.RefreshResponse{ShipmentID: 1, UpdatedAt: time.Now().Truncate(time.Second), EventsAdded: 2, TotalEvents: 5}
+	if err := first.Set(1, response); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if !server.Exists("test:refresh:1") || server.TTL("test:refresh:1") != 5*time.Minute {
+		t.Errorf("Expected the response to be stored with the TTL, keys %v", server.Keys())
+	}
+
+	cached, err := second.Get(1)
+	if err != nil || cached == nil {
+		t.Fatalf("Expected the other manager to see the response, got %+v, %v", cached, err)
+	}
+	if cached.TotalEvents != 5 || !cached.UpdatedAt.Equal(response.UpdatedAt) {
+		t.Errorf("Unexpected cached response %+v", cached)
+	}
+
+	stats, err := second.GetStats()
+	if err != nil || stats.DatabaseTotal != 1 || stats.MemoryTotal != 0 {
+		t.Errorf("Unexpected stats %+v, %v", stats, err)
+	}
+
+	// Invalidating on one instance is seen by the other, as nothing is kept in memory
+	if err := first.Delete(1); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if cached, err := second.Get(1); err != nil || cached != nil {
+		t.Errorf("Expected a cache miss after delete, got %+v, %v", cached, err)
+	}
+
+	// Entries expire in Redis
+	if err := first.Set(2, response); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	server.FastForward(6 * time.Minute)
+	if cached, err := second.Get(2); err != nil || cached != nil {
+		t.Errorf("Expected an expired entry to miss, got %+v, %v", cached, err)
+	}
+}
+
+func TestRedisStore_ManualRefresh(t *testing.T) {
+	server := miniredis.RunT(t)
+	store, err := OpenRedisStore("redis://"+server.Addr()+"/0", "test:")
+	if err != nil {
+		t.Fatalf("Failed to open Redis store: %v", err)
+	}
+	defer store.Close()
+	manager := NewSharedManager(store, false, 5*time.Minute)
+	defer manager.Close()
+
+	older := time.Now().Add(-time.Hour)
+	if got := manager.ManualRefreshTime(1, &older); got != &older {
+		t.Errorf("Expected the shipment's time without a shared refresh, got %v", got)
+	}
+
+	refreshed := time.Now().Truncate(time.Second)
+	manager.RecordManualRefresh(1, refreshed)
+	if got := manager.ManualRefreshTime(1, &older); got == nil || !got.Equal(refreshed) {
+		t.Errorf("Expected the shared refresh time %v, got %v", refreshed, got)
+	}
+	if got := manager.ManualRefreshTime(1, nil); got == nil || !got.Equal(refreshed) {
+		t.Errorf("Expected the shared refresh time without one on the shipment, got %v", got)
+	}
+
+	// A local cache relies on the shipment alone
+	db, err := database.Open(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+	local := NewManager(db.RefreshCache, false, 5*time.Minute)
+	defer local.Close()
+	local.RecordManualRefresh(1, refreshed)
+	if got := local.ManualRefreshTime(1, &older); got != &older {
+		t.Errorf("Expected the shipment's time with a local cache, got %v", got)
+	}
+
+	if _, err := OpenRedisStore("http://example.com", "test:"); err == nil {
+		t.Error("Expected an invalid URL to fail")
+	}
+}
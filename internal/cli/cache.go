@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"package-tracking/internal/database"
+)
+
+// ReadCache keeps the last successful shipment list, shipments and events fetched from a
+// server so read commands can still show them, marked as stale, when the server is
+// unreachable. Each server gets its own cache file.
+type ReadCache struct {
+	path string
+}
+
+// cacheContents is the on-disk format of the read cache
+type cacheContents struct {
+	ServerURL string                  `json:"server_url"`
+	Shipments *cachedShipments        `json:"shipments,omitempty"`
+	Shipment  map[int]cachedShipment  `json:"shipment,omitempty"`
+	Events    map[int]cachedEventList `json:"events,omitempty"`
+}
+
+type cachedShipments struct {
+	FetchedAt time.Time           `json:"fetched_at"`
+	Shipments []database.Shipment `json:"shipments"`
+}
+
+type cachedShipment struct {
+	FetchedAt time.Time         `json:"fetched_at"`
+	Shipment  database.Shipment `json:"shipment"`
+}
+
+type cachedEventList struct {
+	FetchedAt time.Time                `json:"fetched_at"`
+	Events    []database.TrackingEvent `json:"events"`
+}
+
+// CacheDir returns the directory holding the read cache,
+// $XDG_CACHE_HOME/package-tracker or the platform's user cache directory
+func CacheDir() (string, error) {
+	cacheHome, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheHome, "package-tracker"), nil
+}
+
+// NewReadCache returns the read cache for the given server
+func NewReadCache(serverURL string) (*ReadCache, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("cannot locate cache directory: %w", err)
+	}
+
+	sum := sha256.Sum256([]byte(serverURL))
+	return &ReadCache{path: filepath.Join(dir, "cache-"+hex.EncodeToString(sum[:8])+".json")}, nil
+}
+
+// load reads the cache file, returning empty contents if it doesn't exist or can't be parsed
+func (c *ReadCache) load() *cacheContents {
+	contents := &cacheContents{}
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return contents
+	}
+	if err := json.Unmarshal(data, contents); err != nil {
+		return &cacheContents{}
+	}
+	return contents
+}
+
+// save writes the cache file, replacing it atomically so a concurrent reader never sees a
+// partial file
+func (c *ReadCache) save(contents *cacheContents) error {
+	data, err := json.Marshal(contents)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), ".cache-*.json")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
+
+// StoreShipments records the shipment list
+func (c *ReadCache) StoreShipments(serverURL string, shipments []database.Shipment) error {
+	contents := c.load()
+	contents.ServerURL = serverURL
+	contents.Shipments = &cachedShipments{FetchedAt: time.Now(), Shipments: shipments}
+	return c.save(contents)
+}
+
+// Shipments returns the cached shipment list and when it was fetched
+func (c *ReadCache) Shipments() ([]database.Shipment, time.Time, bool) {
+	contents := c.load()
+	if contents.Shipments == nil {
+		return nil, time.Time{}, false
+	}
+	return contents.Shipments.Shipments, contents.Shipments.FetchedAt, true
+}
+
+// StoreShipment records a single shipment
+func (c *ReadCache) StoreShipment(serverURL string, shipment *database.Shipment) error {
+	contents := c.load()
+	contents.ServerURL = serverURL
+	if contents.Shipment == nil {
+		contents.Shipment = make(map[int]cachedShipment)
+	}
+	contents.Shipment[shipment.ID] = cachedShipment{FetchedAt: time.Now(), Shipment: *shipment}
+	return c.save(contents)
+}
+
+// Shipment returns the most recently fetched copy of a shipment, whether it was fetched on
+// its own or as part of the shipment list
+func (c *ReadCache) Shipment(id int) (*database.Shipment, time.Time, bool) {
+	contents := c.load()
+
+	var found *database.Shipment
+	var fetchedAt time.Time
+	if cached, ok := contents.Shipment[id]; ok {
+		shipment := cached.Shipment
+		found, fetchedAt = &shipment, cached.FetchedAt
+	}
+	if contents.Shipments != nil && contents.Shipments.FetchedAt.After(fetchedAt) {
+		for _, shipment := range contents.Shipments.Shipments {
+			if shipment.ID == id {
+				shipment := shipment
+				found, fetchedAt = &shipment, contents.Shipments.FetchedAt
+				break
+			}
+		}
+	}
+
+	return found, fetchedAt, found != nil
+}
+
+// StoreEvents records a shipment's tracking events
+func (c *ReadCache) StoreEvents(serverURL string, shipmentID int, events []database.TrackingEvent) error {
+	contents := c.load()
+	contents.ServerURL = serverURL
+	if contents.Events == nil {
+		contents.Events = make(map[int]cachedEventList)
+	}
+	contents.Events[shipmentID] = cachedEventList{FetchedAt: time.Now(), Events: events}
+	return c.save(contents)
+}
+
+// Events returns a shipment's cached tracking events and when they were fetched
+func (c *ReadCache) Events(shipmentID int) ([]database.TrackingEvent, time.Time, bool) {
+	cached, ok := c.load().Events[shipmentID]
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	return cached.Events, cached.FetchedAt, true
+}
+
+// Clear removes everything cached for the server
+func (c *ReadCache) Clear() error {
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// IsUnreachable reports whether err means the server couldn't be reached, as opposed to the
+// server rejecting the request, so that cached data may be shown instead
+func IsUnreachable(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.Code {
+	case 0, 502, 503, 504:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"testing"
+
+	"package-tracking/internal/database"
+)
+
+func TestReadCache(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	cache, err := NewReadCache("http://localhost:8080")
+	if err != nil {
+		t.Fatalf("Failed to open cache: %v", err)
+	}
+
+	if _, _, ok := cache.Shipments(); ok {
+		t.Error("Expected empty cache")
+	}
+
+	shipments := []database.Shipment{
+		{ID: 1, TrackingNumber: "1Z999AA10123456784", Status: "in_transit"},
+		{ID: 2, TrackingNumber: "9400111899562537866361", Status: "delivered"},
+	}
+	if err := cache.StoreShipments("http://localhost:8080", shipments); err != nil {
+		t.Fatalf("Failed to store shipments: %v", err)
+	}
+	events := []database.TrackingEvent{{ID: 7, ShipmentID: 1, Status: "in_transit"}}
+	if err := cache.StoreEvents("http://localhost:8080", 1, events); err != nil {
+		t.Fatalf("Failed to store events: %v", err)
+	}
+
+	cached, fetchedAt, ok := cache.Shipments()
+	if !ok || len(cached) != 2 || fetchedAt.IsZero() {
+		t.Errorf("Expected 2 cached shipments with a fetch time, got %d at %v", len(cached), fetchedAt)
+	}
+
+	// A shipment in the cached list can be found on its own
+	shipment, _, ok := cache.Shipment(2)
+	if !ok || shipment.Status != "delivered" {
+		t.Errorf("Expected cached shipment 2, got %+v", shipment)
+	}
+
+	// A newer individual fetch wins over the list
+	if err := cache.StoreShipment("http://localhost:8080", &database.Shipment{ID: 1, Status: "delivered"}); err != nil {
+		t.Fatalf("Failed to store shipment: %v", err)
+	}
+	if shipment, _, _ := cache.Shipment(1); shipment.Status != "delivered" {
+		t.Errorf("Expected latest copy of shipment 1, got status %q", shipment.Status)
+	}
+
+	if cachedEvents, _, ok := cache.Events(1); !ok || len(cachedEvents) != 1 {
+		t.Errorf("Expected 1 cached event, got %d", len(cachedEvents))
+	}
+
+	// Each server has its own cache
+	other, err := NewReadCache("https://tracker.example.com")
+	if err != nil {
+		t.Fatalf("Failed to open cache: %v", err)
+	}
+	if _, _, ok := other.Shipments(); ok {
+		t.Error("Expected another server's cache to be empty")
+	}
+
+	if err := cache.Clear(); err != nil {
+		t.Fatalf("Failed to clear cache: %v", err)
+	}
+	if _, _, ok := cache.Shipments(); ok {
+		t.Error("Expected cache to be empty after Clear")
+	}
+	if err := cache.Clear(); err != nil {
+		t.Errorf("Clearing an empty cache should succeed, got %v", err)
+	}
+}
+
+func TestIsUnreachable(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected bool
+	}{
+		{&APIError{Code: 0, Message: "Network error: connection refused"}, true},
+		{&APIError{Code: 503, Message: "Service Unavailable"}, true},
+		{&APIError{Code: 404, Message: "Not Found"}, false},
+		{&APIError{Code: 500, Message: "Internal Server Error"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsUnreachable(tt.err); got != tt.expected {
+			t.Errorf("IsUnreachable(%v) = %v, want %v", tt.err, got, tt.expected)
+		}
+	}
+}
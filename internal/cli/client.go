@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -16,6 +17,7 @@ import (
 // Client represents an HTTP client for the package tracking API
 type Client struct {
 	baseURL    string
+	apiKey     string
 	httpClient *http.Client
 }
 
@@ -34,6 +36,11 @@ func NewClientWithTimeout(baseURL string, timeout time.Duration) *Client {
 	}
 }
 
+// SetAPIKey sets the API key sent as a bearer token with every request
+func (c *Client) SetAPIKey(apiKey string) {
+	c.apiKey = apiKey
+}
+
 // APIError represents an error from the API
 type APIError struct {
 	Code    int    `json:"code"`
@@ -71,6 +78,74 @@ type RefreshResponse struct {
 	PreviousCacheAge string                   `json:"previous_cache_age,omitempty"` // Age of cache that was invalidated
 }
 
+// EmailBody is the content of an email, as returned by the email body endpoint
+type EmailBody struct {
+	PlainText string `json:"plain_text"`
+	HTMLText  string `json:"html_text"`
+	Subject   string `json:"subject"`
+	From      string `json:"from"`
+	Date      string `json:"date"`
+}
+
+// linkEmailRequest represents a request to link an email to a shipment
+type linkEmailRequest struct {
+	LinkType       string `json:"link_type"`
+	TrackingNumber string `json:"tracking_number,omitempty"`
+	CreatedBy      string `json:"created_by"`
+}
+
+// UpdaterStatus is the tracking updater's state, as reported by the admin API
+type UpdaterStatus struct {
+	Running  bool            `json:"running"`
+	Paused   bool            `json:"paused"`
+	LastRun  *time.Time      `json:"last_run,omitempty"`
+	NextRun  *time.Time      `json:"next_run,omitempty"`
+	Carriers []CarrierStatus `json:"carriers"`
+}
+
+// CarrierStatus is the tracking updater's state for one carrier
+type CarrierStatus struct {
+	Carrier            string     `json:"carrier"`
+	Enabled            bool       `json:"enabled"`
+	LastRun            *time.Time `json:"last_run,omitempty"`
+	NextRun            *time.Time `json:"next_run,omitempty"`
+	ShipmentsProcessed int        `json:"shipments_processed"`
+	SuccessCount       int        `json:"success_count"`
+	ErrorCount         int        `json:"error_count"`
+	CacheHits          int        `json:"cache_hits"`
+	LastError          string     `json:"last_error,omitempty"`
+	InBackoff          bool       `json:"in_backoff"`
+	BackoffUntil       *time.Time `json:"backoff_until,omitempty"`
+	ConsecutiveErrors  int        `json:"consecutive_rate_limits"`
+	DailyBudget        int        `json:"daily_budget,omitempty"`
+	CallsToday         int        `json:"calls_today"`
+	CycleAllowance     *int       `json:"cycle_allowance,omitempty"`
+}
+
+// AdminActionResponse is the response to pausing or resuming the tracking updater
+type AdminActionResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// EnhanceDescriptionsRequest represents a request to enhance shipment descriptions. Without
+// a shipment ID every shipment with a poor description is processed.
+type EnhanceDescriptionsRequest struct {
+	ShipmentID *int `json:"shipment_id,omitempty"`
+	Limit      int  `json:"limit,omitempty"`
+	DryRun     bool `json:"dry_run,omitempty"`
+	Associate  bool `json:"associate,omitempty"`
+}
+
+// EnhanceDescriptionsResponse represents the response from enhancing descriptions. Summary
+// holds a single result when a shipment ID was given, and a summary of every result otherwise.
+type EnhanceDescriptionsResponse struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Summary json.RawMessage `json:"summary,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
 // doRequest performs an HTTP request and handles errors
 func (c *Client) doRequest(method, path string, body interface{}) (*http.Response, error) {
 	url := c.baseURL + path
@@ -98,6 +173,9 @@ func (c *Client) doRequest(method, path string, body interface{}) (*http.Respons
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -111,13 +189,9 @@ func (c *Client) doRequest(method, path string, body interface{}) (*http.Respons
 	if resp.StatusCode >= 400 {
 		defer resp.Body.Close()
 		
-		var apiErr APIError
-		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil {
-			// If we can't decode the error, create a generic one
-			apiErr = APIError{
-				Code:    resp.StatusCode,
-				Message: resp.Status,
-			}
+		apiErr := APIError{Code: resp.StatusCode, Message: errorMessage(resp)}
+		if resp.StatusCode == http.StatusUnauthorized {
+			apiErr = APIError{Code: resp.StatusCode, Message: c.unauthorizedMessage()}
 		}
 		return nil, &apiErr
 	}
@@ -125,6 +199,43 @@ func (c *Client) doRequest(method, path string, body interface{}) (*http.Respons
 	return resp, nil
 }
 
+// errorMessage extracts the reason for an error response. Endpoints report it in a JSON
+// "message" or "error" field, or as a line of plain text; anything else falls back to the
+// HTTP status.
+func errorMessage(resp *http.Response) string {
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return resp.Status
+	}
+
+	var body struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if json.Unmarshal(data, &body) == nil {
+		if body.Message != "" {
+			return body.Message
+		}
+		if body.Error != "" {
+			return body.Error
+		}
+		return resp.Status
+	}
+
+	if text := strings.TrimSpace(string(data)); text != "" && len(text) <= 200 && !strings.ContainsAny(text, "\n<") {
+		return text
+	}
+	return resp.Status
+}
+
+// unauthorizedMessage explains a 401 response, which depends on whether a key was sent
+func (c *Client) unauthorizedMessage() string {
+	if c.apiKey == "" {
+		return "the server requires an API key; set one with --api-key, PACKAGE_TRACKER_API_KEY or api_key in the config file"
+	}
+	return "the server rejected the API key; check --api-key, PACKAGE_TRACKER_API_KEY or api_key in the config file"
+}
+
 // HealthCheck checks if the API server is healthy
 func (c *Client) HealthCheck() error {
 	resp, err := c.doRequest("GET", "/api/health", nil)
@@ -244,6 +355,29 @@ func (c *Client) GetEvents(shipmentID int) ([]database.TrackingEvent, error) {
 	return events, nil
 }
 
+// GetCarriers returns the carriers known to the server, optionally only active ones
+func (c *Client) GetCarriers(activeOnly bool) ([]database.Carrier, error) {
+	path := "/api/carriers"
+	if activeOnly {
+		path += "?active=true"
+	}
+	resp, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var carriers []database.Carrier
+	if err := json.NewDecoder(resp.Body).Decode(&carriers); err != nil {
+		return nil, &APIError{
+			Code:    resp.StatusCode,
+			Message: fmt.Sprintf("Invalid response format: %v", err),
+		}
+	}
+
+	return carriers, nil
+}
+
 // RefreshShipment manually refreshes tracking data for a shipment
 func (c *Client) RefreshShipment(shipmentID int) (*RefreshResponse, error) {
 	return c.RefreshShipmentWithForce(shipmentID, false)
@@ -270,4 +404,138 @@ func (c *Client) RefreshShipmentWithForce(shipmentID int, force bool) (*RefreshR
 	}
 
 	return &refreshResp, nil
-}
\ No newline at end of file
+}
+
+// GetShipmentEmails returns the emails linked to a shipment
+func (c *Client) GetShipmentEmails(shipmentID int) ([]database.EmailBodyEntry, error) {
+	path := "/api/shipments/" + strconv.Itoa(shipmentID) + "/emails"
+	resp, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var emails []database.EmailBodyEntry
+	if err := json.NewDecoder(resp.Body).Decode(&emails); err != nil {
+		return nil, &APIError{
+			Code:    resp.StatusCode,
+			Message: fmt.Sprintf("Invalid response format: %v", err),
+		}
+	}
+
+	return emails, nil
+}
+
+// GetEmailBody returns the content of an email by its Gmail message ID
+func (c *Client) GetEmailBody(messageID string) (*EmailBody, error) {
+	path := "/api/emails/" + url.PathEscape(messageID) + "/body"
+	resp, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body EmailBody
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, &APIError{
+			Code:    resp.StatusCode,
+			Message: fmt.Sprintf("Invalid response format: %v", err),
+		}
+	}
+
+	return &body, nil
+}
+
+// LinkEmail manually links an email to a shipment. trackingNumber records which of the
+// shipment's numbers the email mentions and may be empty.
+func (c *Client) LinkEmail(emailID, shipmentID int, trackingNumber string) error {
+	path := "/api/emails/" + strconv.Itoa(emailID) + "/link/" + strconv.Itoa(shipmentID)
+	resp, err := c.doRequest("POST", path, &linkEmailRequest{
+		LinkType:       "manual",
+		TrackingNumber: trackingNumber,
+		CreatedBy:      "cli",
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+// UnlinkEmail removes the link between an email and a shipment
+func (c *Client) UnlinkEmail(emailID, shipmentID int) error {
+	path := "/api/emails/" + strconv.Itoa(emailID) + "/link/" + strconv.Itoa(shipmentID)
+	resp, err := c.doRequest("DELETE", path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+// GetUpdaterStatus returns the tracking updater's status. Admin endpoints require the
+// server's admin API key unless admin auth is disabled.
+func (c *Client) GetUpdaterStatus() (*UpdaterStatus, error) {
+	resp, err := c.doRequest("GET", "/api/admin/tracking-updater/status", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var status UpdaterStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, &APIError{
+			Code:    resp.StatusCode,
+			Message: fmt.Sprintf("Invalid response format: %v", err),
+		}
+	}
+
+	return &status, nil
+}
+
+// PauseUpdater pauses automatic tracking updates
+func (c *Client) PauseUpdater() (*AdminActionResponse, error) {
+	return c.adminAction("/api/admin/tracking-updater/pause")
+}
+
+// ResumeUpdater resumes automatic tracking updates
+func (c *Client) ResumeUpdater() (*AdminActionResponse, error) {
+	return c.adminAction("/api/admin/tracking-updater/resume")
+}
+
+func (c *Client) adminAction(path string) (*AdminActionResponse, error) {
+	resp, err := c.doRequest("POST", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var action AdminActionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&action); err != nil {
+		return nil, &APIError{
+			Code:    resp.StatusCode,
+			Message: fmt.Sprintf("Invalid response format: %v", err),
+		}
+	}
+
+	return &action, nil
+}
+
+// EnhanceDescriptions asks the server to improve shipment descriptions using linked emails
+func (c *Client) EnhanceDescriptions(req *EnhanceDescriptionsRequest) (*EnhanceDescriptionsResponse, error) {
+	resp, err := c.doRequest("POST", "/api/admin/enhance-descriptions", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var enhanceResp EnhanceDescriptionsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&enhanceResp); err != nil {
+		return nil, &APIError{
+			Code:    resp.StatusCode,
+			Message: fmt.Sprintf("Invalid response format: %v", err),
+		}
+	}
+
+	return &enhanceResp, nil
+}
@@ -393,6 +393,37 @@ func TestGetEvents_Success(t *testing.T) {
 	}
 }
 
+func TestGetCarriers_Success(t *testing.T) {
+	expectedCarriers := []database.Carrier{
+		{ID: 1, Name: "United Parcel Service", Code: "ups", Active: true},
+		{ID: 2, Name: "United States Postal Service", Code: "usps", Active: true},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/carriers" {
+			t.Errorf("Expected path '/api/carriers', got '%s'", r.URL.Path)
+		}
+		if r.URL.Query().Get("active") != "true" {
+			t.Errorf("Expected active=true query, got '%s'", r.URL.RawQuery)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(expectedCarriers)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	carriers, err := client.GetCarriers(true)
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if len(carriers) != 2 || carriers[0].Code != "ups" {
+		t.Errorf("Expected carriers to be decoded, got %+v", carriers)
+	}
+}
+
 func TestAPIError_Error(t *testing.T) {
 	apiErr := &APIError{
 		Code:    404,
@@ -452,4 +483,200 @@ func TestDoRequest_InvalidJSON(t *testing.T) {
 	if apiErr.Code != 400 {
 		t.Errorf("Expected error code 400, got %d", apiErr.Code)
 	}
-}
\ No newline at end of file
+}
+func TestAPIKey_SentAsBearerToken(t *testing.T) {
+	var authHeaders []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.GetShipments()
+	client.SetAPIKey("secret-key")
+	client.GetShipments()
+
+	if authHeaders[0] != "" {
+		t.Errorf("Expected no Authorization header without a key, got %q", authHeaders[0])
+	}
+	if authHeaders[1] != "Bearer secret-key" {
+		t.Errorf("Expected bearer token, got %q", authHeaders[1])
+	}
+}
+
+func TestUnauthorized_ExplainsAPIKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	_, err := client.GetShipments()
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.Code != 401 {
+		t.Fatalf("Expected 401 APIError, got %v", err)
+	}
+	if !strings.Contains(apiErr.Message, "requires an API key") || !strings.Contains(apiErr.Message, "--api-key") {
+		t.Errorf("Expected a hint to set an API key, got %q", apiErr.Message)
+	}
+
+	client.SetAPIKey("wrong-key")
+	_, err = client.GetShipments()
+	if apiErr, ok := err.(*APIError); !ok || !strings.Contains(apiErr.Message, "rejected the API key") {
+		t.Errorf("Expected a rejected key message, got %v", err)
+	}
+}
+
+func TestGetShipmentEmails_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/api/shipments/3/emails" {
+			t.Errorf("Expected GET /api/shipments/3/emails, got %s %s", r.Method, r.URL.Path)
+		}
+		json.NewEncoder(w).Encode([]database.EmailBodyEntry{
+			{ID: 7, GmailMessageID: "18c2f0a9d4b7e611", Subject: "Your order has shipped"},
+		})
+	}))
+	defer server.Close()
+
+	emails, err := NewClient(server.URL).GetShipmentEmails(3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(emails) != 1 || emails[0].ID != 7 || emails[0].GmailMessageID != "18c2f0a9d4b7e611" {
+		t.Errorf("Unexpected emails: %+v", emails)
+	}
+}
+
+func TestGetEmailBody_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/emails/18c2f0a9d4b7e611/body" {
+			t.Errorf("Expected path '/api/emails/18c2f0a9d4b7e611/body', got '%s'", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(EmailBody{Subject: "Your order has shipped", PlainText: "Tracking: 1Z999AA10123456784"})
+	}))
+	defer server.Close()
+
+	body, err := NewClient(server.URL).GetEmailBody("18c2f0a9d4b7e611")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if body.Subject != "Your order has shipped" || body.PlainText != "Tracking: 1Z999AA10123456784" {
+		t.Errorf("Unexpected email body: %+v", body)
+	}
+}
+
+func TestLinkAndUnlinkEmail(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == "POST" {
+			var req linkEmailRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("Failed to decode link request: %v", err)
+			}
+			if req.LinkType != "manual" || req.TrackingNumber != "1Z999AA10123456784" {
+				t.Errorf("Unexpected link request: %+v", req)
+			}
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	if err := client.LinkEmail(7, 3, "1Z999AA10123456784"); err != nil {
+		t.Fatalf("LinkEmail failed: %v", err)
+	}
+	if err := client.UnlinkEmail(7, 3); err != nil {
+		t.Fatalf("UnlinkEmail failed: %v", err)
+	}
+
+	expected := []string{"POST /api/emails/7/link/3", "DELETE /api/emails/7/link/3"}
+	if strings.Join(requests, ", ") != strings.Join(expected, ", ") {
+		t.Errorf("Expected requests %v, got %v", expected, requests)
+	}
+}
+
+func TestAdminEndpoints(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer admin-secret" {
+			t.Errorf("Expected admin API key on %s, got %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/admin/tracking-updater/status":
+			w.Write([]byte(`{"running":true,"paused":true,"carriers":[{"carrier":"ups","enabled":true,"calls_today":4}]}`))
+		case "POST /api/admin/tracking-updater/pause":
+			w.Write([]byte(`{"status":"paused","message":"Tracking updater has been paused"}`))
+		case "POST /api/admin/enhance-descriptions":
+			var req EnhanceDescriptionsRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.ShipmentID == nil || *req.ShipmentID != 5 || !req.DryRun {
+				t.Errorf("Unexpected enhance request: %+v", req)
+			}
+			w.Write([]byte(`{"success":true,"message":"Dry run completed successfully","summary":{"shipment_id":5}}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.SetAPIKey("admin-secret")
+
+	status, err := client.GetUpdaterStatus()
+	if err != nil {
+		t.Fatalf("GetUpdaterStatus failed: %v", err)
+	}
+	if !status.Paused || len(status.Carriers) != 1 || status.Carriers[0].CallsToday != 4 {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+
+	action, err := client.PauseUpdater()
+	if err != nil || action.Status != "paused" {
+		t.Errorf("Expected paused, got %+v (%v)", action, err)
+	}
+
+	id := 5
+	resp, err := client.EnhanceDescriptions(&EnhanceDescriptionsRequest{ShipmentID: &id, DryRun: true})
+	if err != nil {
+		t.Fatalf("EnhanceDescriptions failed: %v", err)
+	}
+	if !resp.Success || !strings.Contains(string(resp.Summary), `"shipment_id":5`) {
+		t.Errorf("Unexpected enhance response: %+v", resp)
+	}
+}
+
+func TestErrorResponseMessages(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{"message field", `{"code":400,"message":"Invalid carrier"}`, "Invalid carrier"},
+		{"error field", `{"success":false,"error":"Description enhancement service not available"}`, "Description enhancement service not available"},
+		{"plain text", "Email not found\n", "Email not found"},
+		{"html", "<html><body>Bad Gateway</body></html>", "503 Service Unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			_, err := NewClient(server.URL).GetShipments()
+			apiErr, ok := err.(*APIError)
+			if !ok {
+				t.Fatalf("Expected *APIError, got %T", err)
+			}
+			// The status code always comes from the response, even when the body has its own
+			if apiErr.Code != http.StatusServiceUnavailable || apiErr.Message != tt.expected {
+				t.Errorf("Expected 503 %q, got %d %q", tt.expected, apiErr.Code, apiErr.Message)
+			}
+		})
+	}
+}
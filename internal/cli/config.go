@@ -2,6 +2,7 @@ package cli
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
@@ -9,15 +10,29 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/spf13/viper"
 )
 
 // Config holds CLI configuration
 type Config struct {
 	ServerURL      string        `json:"server_url"`
+	APIKey         string        `json:"api_key,omitempty"`
 	Format         string        `json:"format"`
 	Quiet          bool          `json:"quiet"`
 	NoColor        bool          `json:"no_color"`
+	Theme          string        `json:"theme,omitempty"`
 	RequestTimeout time.Duration `json:"request_timeout"`
+
+	// SortBy and SortDescending remember the interactive table's sort column
+	SortBy         string `json:"sort_by,omitempty"`
+	SortDescending bool   `json:"sort_desc,omitempty"`
+
+	// NotifyStatuses limits desktop notifications to these statuses; empty means every change
+	NotifyStatuses []string `json:"notify_statuses,omitempty"`
+
+	// Profile is the name of the config file profile in use, if any
+	Profile string `json:"-"`
 }
 
 // DefaultConfig returns the default configuration
@@ -33,13 +48,29 @@ func DefaultConfig() *Config {
 
 // LoadConfig loads configuration from file, environment variables, and CLI flags
 func LoadConfig(serverFlag, formatFlag string, quietFlag bool) (*Config, error) {
+	return LoadConfigWithProfile("", serverFlag, formatFlag, quietFlag)
+}
+
+// LoadConfigWithProfile loads configuration like LoadConfig, applying the named profile from
+// the YAML config file. An empty profile selects PACKAGE_TRACKER_PROFILE or the file's
+// default_profile. Settings are applied in order: defaults, ~/.package-tracker.json, the
+// YAML file's top-level settings, the profile, environment variables, then CLI flags.
+func LoadConfigWithProfile(profile, serverFlag, formatFlag string, quietFlag bool) (*Config, error) {
 	config := DefaultConfig()
 
-	// Try to load from config file
+	// Try to load from the legacy config file
 	if err := config.loadFromFile(); err != nil {
 		// Config file is optional, continue with defaults
 	}
 
+	// Apply the YAML config file and the selected profile
+	if profile == "" {
+		profile = os.Getenv("PACKAGE_TRACKER_PROFILE")
+	}
+	if err := config.loadFromProfileFile(profile); err != nil {
+		return nil, err
+	}
+
 	// Override with environment variables
 	config.loadFromEnv()
 
@@ -73,11 +104,121 @@ func (c *Config) loadFromFile() error {
 	return json.Unmarshal(data, c)
 }
 
+// ConfigFilePath returns the location of the YAML config file,
+// $XDG_CONFIG_HOME/package-tracker/config.yaml or ~/.config/package-tracker/config.yaml
+func ConfigFilePath() (string, error) {
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+		return filepath.Join(configHome, "package-tracker", "config.yaml"), nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "package-tracker", "config.yaml"), nil
+}
+
+// loadFromProfileFile applies the YAML config file's top-level settings followed by the named
+// profile (or default_profile when name is empty). The file is optional unless a profile was
+// requested explicitly.
+func (c *Config) loadFromProfileFile(name string) error {
+	configPath, err := ConfigFilePath()
+	if err != nil {
+		if name != "" {
+			return fmt.Errorf("cannot locate config file for profile %q: %w", name, err)
+		}
+		return nil
+	}
+
+	if _, err := os.Stat(configPath); errors.Is(err, os.ErrNotExist) {
+		if name != "" {
+			return fmt.Errorf("profile %q requested but config file %s does not exist", name, configPath)
+		}
+		return nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(configPath)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", configPath, err)
+	}
+
+	// Top-level settings apply to every profile
+	if err := c.applySettings(v); err != nil {
+		return fmt.Errorf("invalid settings in %s: %w", configPath, err)
+	}
+
+	if name == "" {
+		name = v.GetString("default_profile")
+	}
+	if name == "" {
+		return nil
+	}
+
+	profile := v.Sub("profiles." + name)
+	if profile == nil {
+		return fmt.Errorf("profile %q not found in %s", name, configPath)
+	}
+	if err := c.applySettings(profile); err != nil {
+		return fmt.Errorf("invalid settings in profile %q: %w", name, err)
+	}
+	c.Profile = name
+
+	return nil
+}
+
+// applySettings overrides the configuration with the settings present in v
+func (c *Config) applySettings(v *viper.Viper) error {
+	if v.IsSet("server_url") {
+		c.ServerURL = v.GetString("server_url")
+	}
+	if v.IsSet("api_key") {
+		c.APIKey = v.GetString("api_key")
+	}
+	if v.IsSet("format") {
+		c.Format = v.GetString("format")
+	}
+	if v.IsSet("quiet") {
+		c.Quiet = v.GetBool("quiet")
+	}
+	if v.IsSet("no_color") {
+		c.NoColor = v.GetBool("no_color")
+	}
+	if v.IsSet("theme") {
+		c.Theme = v.GetString("theme")
+	}
+	if v.IsSet("sort_by") {
+		c.SortBy = v.GetString("sort_by")
+	}
+	if v.IsSet("sort_desc") {
+		c.SortDescending = v.GetBool("sort_desc")
+	}
+	if v.IsSet("notify_statuses") {
+		c.NotifyStatuses = v.GetStringSlice("notify_statuses")
+	}
+	if v.IsSet("request_timeout") {
+		// Accept a duration ("90s") or a number of seconds
+		timeoutStr := v.GetString("request_timeout")
+		if duration, err := time.ParseDuration(timeoutStr); err == nil {
+			c.RequestTimeout = duration
+		} else if seconds, err := strconv.Atoi(timeoutStr); err == nil {
+			c.RequestTimeout = time.Duration(seconds) * time.Second
+		} else {
+			return fmt.Errorf("invalid request timeout: %s", timeoutStr)
+		}
+	}
+	return nil
+}
+
 // loadFromEnv loads configuration from environment variables
 func (c *Config) loadFromEnv() {
 	if serverURL := os.Getenv("PACKAGE_TRACKER_SERVER"); serverURL != "" {
 		c.ServerURL = serverURL
 	}
+	if apiKey := os.Getenv("PACKAGE_TRACKER_API_KEY"); apiKey != "" {
+		c.APIKey = apiKey
+	}
 	if format := os.Getenv("PACKAGE_TRACKER_FORMAT"); format != "" {
 		c.Format = format
 	}
@@ -88,6 +229,12 @@ func (c *Config) loadFromEnv() {
 	if os.Getenv("NO_COLOR") != "" || os.Getenv("PACKAGE_TRACKER_NO_COLOR") == "true" {
 		c.NoColor = true
 	}
+	if theme := os.Getenv("PACKAGE_TRACKER_THEME"); theme != "" {
+		c.Theme = theme
+	}
+	if statuses := os.Getenv("PACKAGE_TRACKER_NOTIFY_STATUSES"); statuses != "" {
+		c.NotifyStatuses = strings.Split(statuses, ",")
+	}
 	if timeoutStr := os.Getenv("PACKAGE_TRACKER_TIMEOUT"); timeoutStr != "" {
 		if timeoutSec, err := strconv.Atoi(timeoutStr); err == nil && timeoutSec > 0 {
 			c.RequestTimeout = time.Duration(timeoutSec) * time.Second
@@ -109,7 +256,7 @@ func (c *Config) validate() error {
 		fmt.Fprintf(os.Stderr, "Warning: Using HTTP instead of HTTPS for server URL. This is not recommended for production use.\n")
 	}
 
-	validFormats := []string{"table", "json"}
+	validFormats := []string{"table", "json", "csv", "yaml"}
 	isValidFormat := false
 	for _, format := range validFormats {
 		if c.Format == format {
@@ -118,7 +265,11 @@ func (c *Config) validate() error {
 		}
 	}
 	if !isValidFormat {
-		return fmt.Errorf("invalid format: %s (must be one of: table, json)", c.Format)
+		return fmt.Errorf("invalid format: %s (must be one of: table, json, csv, yaml)", c.Format)
+	}
+
+	if _, err := ThemeByName(c.Theme); err != nil {
+		return err
 	}
 
 	if c.RequestTimeout <= 0 {
@@ -142,4 +293,38 @@ func (c *Config) SaveConfig() error {
 	}
 
 	return os.WriteFile(configPath, data, 0600)
-}
\ No newline at end of file
+}
+
+// SaveSortPreference records the interactive table's sort column in the YAML config file,
+// under the named profile if one is in use. Other settings in the file are preserved.
+func SaveSortPreference(profile, field string, descending bool) error {
+	configPath, err := ConfigFilePath()
+	if err != nil {
+		return err
+	}
+
+	v := viper.New()
+	v.SetConfigFile(configPath)
+	v.SetConfigType("yaml")
+	v.SetConfigPermissions(0600)
+	if _, err := os.Stat(configPath); err == nil {
+		if err := v.ReadInConfig(); err != nil {
+			return fmt.Errorf("failed to read config file %s: %w", configPath, err)
+		}
+	}
+
+	prefix := ""
+	if profile != "" {
+		prefix = "profiles." + profile + "."
+	}
+	v.Set(prefix+"sort_by", field)
+	v.Set(prefix+"sort_desc", descending)
+
+	if err := os.MkdirAll(filepath.Dir(configPath), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := v.WriteConfigAs(configPath); err != nil {
+		return fmt.Errorf("failed to write config file %s: %w", configPath, err)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -117,4 +118,169 @@ func TestConfigValidation(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func writeProfileConfig(t *testing.T, contents string) {
+	t.Helper()
+
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("HOME", t.TempDir()) // Keep a real ~/.package-tracker.json out of the test
+
+	dir := filepath.Join(configHome, "package-tracker")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(contents), 0600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+}
+
+func TestLoadConfigWithProfile(t *testing.T) {
+	writeProfileConfig(t, `
+default_profile: home
+request_timeout: 90s
+profiles:
+  home:
+    server_url: http://localhost:8080
+  work:
+    server_url: https://tracker.work.example.com
+    api_key: work-secret
+    format: json
+    no_color: true
+    request_timeout: 30
+`)
+
+	t.Run("default profile", func(t *testing.T) {
+		config, err := LoadConfigWithProfile("", "", "", false)
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		if config.Profile != "home" || config.ServerURL != "http://localhost:8080" {
+			t.Errorf("Expected home profile, got profile %q server %q", config.Profile, config.ServerURL)
+		}
+		if config.RequestTimeout != 90*time.Second {
+			t.Errorf("Expected top-level timeout 90s, got %v", config.RequestTimeout)
+		}
+	})
+
+	t.Run("named profile", func(t *testing.T) {
+		config, err := LoadConfigWithProfile("work", "", "", false)
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		if config.ServerURL != "https://tracker.work.example.com" || config.APIKey != "work-secret" {
+			t.Errorf("Expected work server and API key, got %q / %q", config.ServerURL, config.APIKey)
+		}
+		if config.Format != "json" || !config.NoColor {
+			t.Errorf("Expected json format without color, got %q / %v", config.Format, config.NoColor)
+		}
+		if config.RequestTimeout != 30*time.Second {
+			t.Errorf("Expected profile timeout 30s, got %v", config.RequestTimeout)
+		}
+	})
+
+	t.Run("profile from environment", func(t *testing.T) {
+		t.Setenv("PACKAGE_TRACKER_PROFILE", "work")
+		config, err := LoadConfigWithProfile("", "", "", false)
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		if config.Profile != "work" {
+			t.Errorf("Expected work profile from environment, got %q", config.Profile)
+		}
+	})
+
+	t.Run("flags override profile", func(t *testing.T) {
+		config, err := LoadConfigWithProfile("work", "http://flag.example.com", "table", false)
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		if config.ServerURL != "http://flag.example.com" || config.Format != "table" {
+			t.Errorf("Expected flags to override profile, got %q / %q", config.ServerURL, config.Format)
+		}
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		if _, err := LoadConfigWithProfile("missing", "", "", false); err == nil {
+			t.Error("Expected error for unknown profile")
+		}
+	})
+}
+
+func TestLoadConfigWithProfile_NoConfigFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	config, err := LoadConfigWithProfile("", "", "", false)
+	if err != nil {
+		t.Fatalf("Expected missing config file to be optional, got %v", err)
+	}
+	if config.ServerURL != "http://localhost:8080" {
+		t.Errorf("Expected default server URL, got %q", config.ServerURL)
+	}
+
+	if _, err := LoadConfigWithProfile("work", "", "", false); err == nil {
+		t.Error("Expected error when a profile is requested without a config file")
+	}
+}
+
+func TestLoadConfigTheme(t *testing.T) {
+	writeProfileConfig(t, "theme: light\n")
+
+	config, err := LoadConfigWithProfile("", "", "", false)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.Theme != "light" {
+		t.Errorf("Expected light theme from config file, got %q", config.Theme)
+	}
+
+	t.Setenv("PACKAGE_TRACKER_THEME", "high-contrast")
+	config, err = LoadConfigWithProfile("", "", "", false)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.Theme != "high-contrast" {
+		t.Errorf("Expected environment to override theme, got %q", config.Theme)
+	}
+
+	t.Setenv("PACKAGE_TRACKER_THEME", "solarized")
+	if _, err := LoadConfigWithProfile("", "", "", false); err == nil {
+		t.Error("Expected error for unknown theme")
+	}
+}
+
+func TestSaveSortPreference(t *testing.T) {
+	writeProfileConfig(t, `
+profiles:
+  work:
+    server_url: https://tracker.work.example.com
+`)
+
+	if err := SaveSortPreference("work", "delivery", true); err != nil {
+		t.Fatalf("Failed to save sort preference: %v", err)
+	}
+
+	config, err := LoadConfigWithProfile("work", "", "", false)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.SortBy != "delivery" || !config.SortDescending {
+		t.Errorf("Expected saved sort delivery desc, got %q desc=%v", config.SortBy, config.SortDescending)
+	}
+	if config.ServerURL != "https://tracker.work.example.com" {
+		t.Errorf("Expected existing profile settings to be kept, got server %q", config.ServerURL)
+	}
+
+	// Without a profile the preference is saved at the top level
+	if err := SaveSortPreference("", "carrier", false); err != nil {
+		t.Fatalf("Failed to save sort preference: %v", err)
+	}
+	config, err = LoadConfigWithProfile("", "", "", false)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.SortBy != "carrier" || config.SortDescending {
+		t.Errorf("Expected saved sort carrier asc, got %q desc=%v", config.SortBy, config.SortDescending)
+	}
+}
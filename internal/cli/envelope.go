@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// JSONSchemaVersion identifies the shape of the JSON envelope. It only changes when a field
+// is removed or changes meaning; new fields may be added at any time.
+const JSONSchemaVersion = 1
+
+// Exit codes returned by the CLI. Scripts can rely on these staying the same.
+const (
+	ExitOK           = 0
+	ExitError        = 1 // Any error not covered below
+	ExitValidation   = 2 // Invalid arguments or flags, or a request the server rejected as invalid
+	ExitNotFound     = 3 // The shipment or other resource doesn't exist
+	ExitUnreachable  = 4 // The server couldn't be reached or is unavailable
+	ExitUnauthorized = 5 // The server rejected the API key
+)
+
+// Error codes used in the JSON envelope's error object
+const (
+	ErrorCodeGeneric      = "error"
+	ErrorCodeValidation   = "validation_error"
+	ErrorCodeNotFound     = "not_found"
+	ErrorCodeUnreachable  = "server_unreachable"
+	ErrorCodeUnauthorized = "unauthorized"
+)
+
+// Envelope wraps every JSON document the CLI writes. Data holds the command's result (a
+// shipment, a list, ...) and is null on failure; Error is null on success.
+type Envelope struct {
+	Data  interface{}    `json:"data"`
+	Error *EnvelopeError `json:"error"`
+	Meta  EnvelopeMeta   `json:"meta"`
+}
+
+// EnvelopeError describes why a command failed
+type EnvelopeError struct {
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// EnvelopeMeta holds information about the result rather than the result itself
+type EnvelopeMeta struct {
+	SchemaVersion int        `json:"schema_version"`
+	Count         *int       `json:"count,omitempty"`       // Number of items, for lists
+	StaleAsOf     *time.Time `json:"stale_as_of,omitempty"` // Set when showing cached data because the server was unreachable
+}
+
+// ValidationError is an error in the user's input, detected before contacting the server
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// NewValidationError returns a ValidationError with a formatted message
+func NewValidationError(format string, args ...interface{}) error {
+	return &ValidationError{Message: fmt.Sprintf(format, args...)}
+}
+
+// NotFoundError is returned when none of the things a command was asked to act on exist
+type NotFoundError struct {
+	Message string
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Message
+}
+
+// classifyError returns the envelope error code and exit code for err
+func classifyError(err error) (string, int) {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return ErrorCodeValidation, ExitValidation
+	}
+
+	var notFoundErr *NotFoundError
+	if errors.As(err, &notFoundErr) {
+		return ErrorCodeNotFound, ExitNotFound
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case IsUnreachable(apiErr):
+			return ErrorCodeUnreachable, ExitUnreachable
+		case apiErr.Code == 404:
+			return ErrorCodeNotFound, ExitNotFound
+		case apiErr.Code == 401 || apiErr.Code == 403:
+			return ErrorCodeUnauthorized, ExitUnauthorized
+		case apiErr.Code == 400 || apiErr.Code == 409 || apiErr.Code == 422:
+			return ErrorCodeValidation, ExitValidation
+		}
+	}
+
+	return ErrorCodeGeneric, ExitError
+}
+
+// ExitCode returns the process exit code for the error a command returned
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	_, code := classifyError(err)
+	return code
+}
+
+// NewErrorEnvelope describes err in an envelope
+func NewErrorEnvelope(err error) *EnvelopeError {
+	code, exitCode := classifyError(err)
+	return &EnvelopeError{Code: code, Message: err.Error(), ExitCode: exitCode}
+}
+
+// writeEnvelope writes a single-line JSON envelope to w
+func writeEnvelope(w io.Writer, envelope Envelope) error {
+	envelope.Meta.SchemaVersion = JSONSchemaVersion
+	return json.NewEncoder(w).Encode(envelope)
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"success", nil, ExitOK},
+		{"generic", errors.New("something broke"), ExitError},
+		{"validation", NewValidationError("invalid ID '%s'", "abc"), ExitValidation},
+		{"wrapped validation", fmt.Errorf("invalid range: %w", NewValidationError("bad")), ExitValidation},
+		{"not found", &APIError{Code: 404, Message: "Not Found"}, ExitNotFound},
+		{"nothing found", &NotFoundError{Message: "none of them exist"}, ExitNotFound},
+		{"bad request", &APIError{Code: 400, Message: "Invalid carrier"}, ExitValidation},
+		{"network", &APIError{Code: 0, Message: "Network error: connection refused"}, ExitUnreachable},
+		{"unavailable", &APIError{Code: 503, Message: "Service Unavailable"}, ExitUnreachable},
+		{"unauthorized", &APIError{Code: 401, Message: "Unauthorized"}, ExitUnauthorized},
+		{"server error", &APIError{Code: 500, Message: "Internal Server Error"}, ExitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.expected {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestJSONEnvelope_Shipments(t *testing.T) {
+	formatter := NewOutputFormatter("json", false)
+	output := captureStdout(t, func() error {
+		return formatter.PrintShipments(formatTestShipments())
+	})
+
+	var envelope struct {
+		Data  []map[string]interface{} `json:"data"`
+		Error *EnvelopeError           `json:"error"`
+		Meta  map[string]interface{}   `json:"meta"`
+	}
+	if err := json.Unmarshal([]byte(output), &envelope); err != nil {
+		t.Fatalf("Output is not a JSON envelope: %v\n%s", err, output)
+	}
+	if len(envelope.Data) != 2 || envelope.Data[0]["tracking_number"] != "1Z999AA1234567890" {
+		t.Errorf("Unexpected data: %v", envelope.Data)
+	}
+	if envelope.Error != nil {
+		t.Errorf("Expected null error, got %+v", envelope.Error)
+	}
+	if envelope.Meta["schema_version"] != float64(JSONSchemaVersion) || envelope.Meta["count"] != float64(2) {
+		t.Errorf("Unexpected meta: %v", envelope.Meta)
+	}
+	if _, ok := envelope.Meta["stale_as_of"]; ok {
+		t.Error("Fresh data should not have stale_as_of")
+	}
+	if !formatter.WroteEnvelope() {
+		t.Error("Expected WroteEnvelope after printing")
+	}
+}
+
+func TestJSONEnvelope_StaleAndErrors(t *testing.T) {
+	formatter := NewOutputFormatter("json", false)
+	fetchedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	formatter.MarkStale(fetchedAt)
+
+	output := captureStdout(t, func() error {
+		shipments := formatTestShipments()
+		return formatter.PrintShipment(&shipments[0])
+	})
+	var envelope Envelope
+	if err := json.Unmarshal([]byte(output), &envelope); err != nil {
+		t.Fatalf("Output is not a JSON envelope: %v", err)
+	}
+	if envelope.Meta.StaleAsOf == nil || !envelope.Meta.StaleAsOf.Equal(fetchedAt) {
+		t.Errorf("Expected stale_as_of %v, got %v", fetchedAt, envelope.Meta.StaleAsOf)
+	}
+	if envelope.Meta.Count != nil {
+		t.Errorf("A single shipment should not have a count, got %d", *envelope.Meta.Count)
+	}
+
+	output = captureStdout(t, func() error {
+		return PrintErrorEnvelope(&APIError{Code: 404, Message: "Shipment not found"})
+	})
+	envelope = Envelope{}
+	if err := json.Unmarshal([]byte(output), &envelope); err != nil {
+		t.Fatalf("Output is not a JSON envelope: %v", err)
+	}
+	if envelope.Data != nil {
+		t.Errorf("Expected null data, got %v", envelope.Data)
+	}
+	if envelope.Error == nil || envelope.Error.Code != ErrorCodeNotFound || envelope.Error.ExitCode != ExitNotFound {
+		t.Errorf("Unexpected error: %+v", envelope.Error)
+	}
+}
+
+func TestPrintResult_OnlyForJSON(t *testing.T) {
+	output := captureStdout(t, func() error {
+		return NewOutputFormatter("table", false).PrintResult(map[string]int{"added": 1}, nil)
+	})
+	if output != "" {
+		t.Errorf("Expected no output for table format, got %q", output)
+	}
+
+	output = captureStdout(t, func() error {
+		return NewOutputFormatter("json", false).PrintResult(map[string]int{"added": 1}, errors.New("1 row failed"))
+	})
+	var envelope Envelope
+	if err := json.Unmarshal([]byte(output), &envelope); err != nil {
+		t.Fatalf("Output is not a JSON envelope: %v", err)
+	}
+	if envelope.Data == nil || envelope.Error == nil || envelope.Error.Message != "1 row failed" {
+		t.Errorf("Expected data and error, got %+v", envelope)
+	}
+}
@@ -1,11 +1,11 @@
 package cli
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"package-tracking/internal/database"
 	
@@ -27,6 +27,7 @@ type StyleConfig struct {
 	SuccessColor    lipgloss.Color
 	ErrorColor      lipgloss.Color
 	InfoColor       lipgloss.Color
+	WarningColor    lipgloss.Color
 	
 	// Table styling
 	HeaderStyle     lipgloss.Style
@@ -35,17 +36,23 @@ type StyleConfig struct {
 
 // DefaultStyleConfig returns the default style configuration
 func DefaultStyleConfig() *StyleConfig {
+	return StyleConfigForTheme(themes[DefaultThemeName])
+}
+
+// StyleConfigForTheme returns the style configuration using a theme's colors
+func StyleConfigForTheme(theme Theme) *StyleConfig {
 	return &StyleConfig{
-		DeliveredColor:  lipgloss.Color("10"), // Bright green
-		InTransitColor:  lipgloss.Color("11"), // Bright yellow
-		PendingColor:    lipgloss.Color("12"), // Bright blue
-		FailedColor:     lipgloss.Color("9"),  // Bright red
-		UnknownColor:    lipgloss.Color("8"),  // Gray
-		SuccessColor:    lipgloss.Color("10"), // Green
-		ErrorColor:      lipgloss.Color("9"),  // Red
-		InfoColor:       lipgloss.Color("12"), // Blue
-		HeaderStyle:     lipgloss.NewStyle().Bold(true),
-		CellStyle:       lipgloss.NewStyle(),
+		DeliveredColor: theme.Delivered,
+		InTransitColor: theme.InTransit,
+		PendingColor:   theme.Pending,
+		FailedColor:    theme.Failed,
+		UnknownColor:   theme.Unknown,
+		SuccessColor:   theme.Success,
+		ErrorColor:     theme.Error,
+		InfoColor:      theme.Info,
+		WarningColor:   theme.Warning,
+		HeaderStyle:    lipgloss.NewStyle().Bold(true),
+		CellStyle:      lipgloss.NewStyle(),
 	}
 }
 
@@ -55,7 +62,11 @@ type OutputFormatter struct {
 	quiet       bool
 	noColor     bool
 	styles      *StyleConfig
+	theme       Theme
 	colorOutput termenv.Profile
+
+	staleAsOf     *time.Time // Set by MarkStale when showing cached data
+	wroteEnvelope bool       // A JSON envelope has been written to stdout
 }
 
 // NewOutputFormatter creates a new output formatter
@@ -70,6 +81,7 @@ func NewOutputFormatterWithColor(format string, quiet bool, noColor bool) *Outpu
 		quiet:       quiet,
 		noColor:     noColor,
 		styles:      DefaultStyleConfig(),
+		theme:       themes[DefaultThemeName],
 		colorOutput: termenv.ColorProfile(),
 	}
 	
@@ -81,6 +93,17 @@ func NewOutputFormatterWithColor(format string, quiet bool, noColor bool) *Outpu
 	return f
 }
 
+// SetTheme switches the formatter to a theme's colors
+func (f *OutputFormatter) SetTheme(theme Theme) {
+	f.theme = theme
+	f.styles = StyleConfigForTheme(theme)
+}
+
+// Theme returns the formatter's color theme
+func (f *OutputFormatter) Theme() Theme {
+	return f.theme
+}
+
 // shouldUseColor determines if colors should be used based on environment
 func (f *OutputFormatter) shouldUseColor() bool {
 	// If explicitly disabled, don't use color
@@ -122,7 +145,11 @@ func (f *OutputFormatter) PrintShipments(shipments []database.Shipment) error {
 
 	switch f.format {
 	case "json":
-		return json.NewEncoder(os.Stdout).Encode(shipments)
+		return f.printEnvelope(shipments, len(shipments))
+	case "csv":
+		return writeCSV(shipmentColumns, shipmentRecords(shipments))
+	case "yaml":
+		return writeYAML(shipmentRecords(shipments), true)
 	case "table":
 		return f.printShipmentsTable(shipments)
 	default:
@@ -139,7 +166,11 @@ func (f *OutputFormatter) PrintShipment(shipment *database.Shipment) error {
 
 	switch f.format {
 	case "json":
-		return json.NewEncoder(os.Stdout).Encode(shipment)
+		return f.printEnvelope(shipment, -1)
+	case "csv":
+		return writeCSV(shipmentColumns, shipmentRecords([]database.Shipment{*shipment}))
+	case "yaml":
+		return writeYAML(shipmentRecords([]database.Shipment{*shipment}), false)
 	case "table":
 		return f.printShipmentTable(shipment)
 	default:
@@ -158,7 +189,11 @@ func (f *OutputFormatter) PrintEvents(events []database.TrackingEvent) error {
 
 	switch f.format {
 	case "json":
-		return json.NewEncoder(os.Stdout).Encode(events)
+		return f.printEnvelope(events, len(events))
+	case "csv":
+		return writeCSV(eventColumns, eventRecords(events))
+	case "yaml":
+		return writeYAML(eventRecords(events), true)
 	case "table":
 		return f.printEventsTable(events)
 	default:
@@ -166,37 +201,205 @@ func (f *OutputFormatter) PrintEvents(events []database.TrackingEvent) error {
 	}
 }
 
+// PrintEmails prints the emails linked to a shipment
+func (f *OutputFormatter) PrintEmails(emails []database.EmailBodyEntry) error {
+	if f.quiet {
+		for _, email := range emails {
+			fmt.Printf("%d\n", email.ID)
+		}
+		return nil
+	}
+
+	switch f.format {
+	case "json":
+		return f.printEnvelope(emails, len(emails))
+	case "csv":
+		return writeCSV(emailColumns, emailRecords(emails))
+	case "yaml":
+		return writeYAML(emailRecords(emails), true)
+	case "table":
+		return f.printEmailsTable(emails)
+	default:
+		return fmt.Errorf("unsupported format: %s", f.format)
+	}
+}
+
+// PrintEmailBody prints an email's headers and content. Quiet mode prints only the content.
+func (f *OutputFormatter) PrintEmailBody(body *EmailBody) error {
+	if f.quiet {
+		fmt.Println(emailText(body))
+		return nil
+	}
+
+	switch f.format {
+	case "json":
+		return f.printEnvelope(body, -1)
+	case "csv":
+		return writeCSV(emailBodyColumns, [][]recordField{emailBodyRecord(body)})
+	case "yaml":
+		return writeYAML([][]recordField{emailBodyRecord(body)}, false)
+	case "table":
+		fmt.Printf("Subject: %s\n", body.Subject)
+		fmt.Printf("From: %s\n", body.From)
+		fmt.Printf("Date: %s\n", body.Date)
+		fmt.Println()
+		fmt.Println(emailText(body))
+		return nil
+	default:
+		return fmt.Errorf("unsupported format: %s", f.format)
+	}
+}
+
+// emailText returns an email's plain text, falling back to its HTML when it has none
+func emailText(body *EmailBody) string {
+	if strings.TrimSpace(body.PlainText) != "" {
+		return body.PlainText
+	}
+	return body.HTMLText
+}
+
+// PrintEventStreamHeader prints the header that precedes PrintEventStream output, for the
+// formats that have one
+func (f *OutputFormatter) PrintEventStreamHeader() error {
+	if f.quiet {
+		return nil
+	}
+
+	switch f.format {
+	case "csv":
+		return writeCSV(eventColumns, nil)
+	case "table":
+		fmt.Printf("%-16s  %-20s  %-12s  %s\n", "TIMESTAMP", "LOCATION", "STATUS", "DESCRIPTION")
+	}
+	return nil
+}
+
+// PrintEventStream prints events as they arrive when following a shipment: one JSON envelope
+// or csv row per line, a YAML document per event, or table rows without a header so output
+// can be appended to
+func (f *OutputFormatter) PrintEventStream(events []database.TrackingEvent) error {
+	for _, event := range events {
+		if f.quiet {
+			fmt.Printf("%d\n", event.ID)
+			continue
+		}
+
+		switch f.format {
+		case "json":
+			if err := f.printEnvelope(event, -1); err != nil {
+				return err
+			}
+		case "csv":
+			if err := writeCSV(nil, eventRecords([]database.TrackingEvent{event})); err != nil {
+				return err
+			}
+		case "yaml":
+			fmt.Println("---")
+			if err := writeYAML(eventRecords([]database.TrackingEvent{event}), false); err != nil {
+				return err
+			}
+		case "table":
+			status := fmt.Sprintf("%-12s", event.Status)
+			if !f.noColor {
+				status = f.getStatusStyle(event.Status).Render(status)
+			}
+			fmt.Printf("%s  %-20s  %s  %s\n",
+				event.Timestamp.Format("2006-01-02 15:04"),
+				truncate(event.Location, 20),
+				status,
+				truncate(event.Description, 40))
+		default:
+			return fmt.Errorf("unsupported format: %s", f.format)
+		}
+	}
+	return nil
+}
+
+// MarkStale records that the data about to be printed is cached data fetched at fetchedAt,
+// which JSON output reports as meta.stale_as_of
+func (f *OutputFormatter) MarkStale(fetchedAt time.Time) {
+	f.staleAsOf = &fetchedAt
+}
+
+// IsJSON reports whether output is JSON, so commands without shipment or event output know
+// to print a result with PrintResult
+func (f *OutputFormatter) IsJSON() bool {
+	return f.format == "json"
+}
+
+// WroteEnvelope reports whether a JSON envelope has already been written, so a failing
+// command's error isn't reported in a second one
+func (f *OutputFormatter) WroteEnvelope() bool {
+	return f.wroteEnvelope
+}
+
+// PrintResult prints a command's result and, if it partly failed, its error in a JSON
+// envelope. It prints nothing for other formats.
+func (f *OutputFormatter) PrintResult(data interface{}, err error) error {
+	if !f.IsJSON() {
+		return nil
+	}
+	envelope := Envelope{Data: data}
+	if err != nil {
+		envelope.Error = NewErrorEnvelope(err)
+	}
+	return f.writeEnvelope(envelope)
+}
+
+// PrintErrorEnvelope prints a JSON envelope describing a failed command
+func PrintErrorEnvelope(err error) error {
+	return writeEnvelope(os.Stdout, Envelope{Error: NewErrorEnvelope(err)})
+}
+
+// printEnvelope prints data in a JSON envelope; count is the number of items in a list, or
+// -1 for a single item
+func (f *OutputFormatter) printEnvelope(data interface{}, count int) error {
+	envelope := Envelope{Data: data}
+	if count >= 0 {
+		envelope.Meta.Count = &count
+	}
+	return f.writeEnvelope(envelope)
+}
+
+func (f *OutputFormatter) writeEnvelope(envelope Envelope) error {
+	envelope.Meta.StaleAsOf = f.staleAsOf
+	f.wroteEnvelope = true
+	return writeEnvelope(os.Stdout, envelope)
+}
+
+// messageOutput is where status messages go: stdout for tables, stderr for the machine
+// readable formats so they don't get mixed into the data
+func (f *OutputFormatter) messageOutput() *os.File {
+	if f.format == "table" {
+		return os.Stdout
+	}
+	return os.Stderr
+}
+
 // getStatusStyle returns the appropriate style for a status
 func (f *OutputFormatter) getStatusStyle(status string) lipgloss.Style {
 	if f.noColor {
 		return lipgloss.NewStyle()
 	}
-	
-	var color lipgloss.Color
-	switch strings.ToLower(status) {
-	case "delivered":
-		color = f.styles.DeliveredColor
-	case "in transit", "in-transit", "transit":
-		color = f.styles.InTransitColor
-	case "pending":
-		color = f.styles.PendingColor
-	case "failed", "error", "exception":
-		color = f.styles.FailedColor
-	default:
-		color = f.styles.UnknownColor
+
+	statusColors := Theme{
+		Delivered: f.styles.DeliveredColor,
+		InTransit: f.styles.InTransitColor,
+		Pending:   f.styles.PendingColor,
+		Failed:    f.styles.FailedColor,
+		Unknown:   f.styles.UnknownColor,
 	}
-	
-	return lipgloss.NewStyle().Foreground(color)
+	return lipgloss.NewStyle().Foreground(statusColors.StatusColor(status))
 }
 
 // PrintSuccess prints a success message
 func (f *OutputFormatter) PrintSuccess(message string) {
 	if !f.quiet {
 		if f.noColor {
-			fmt.Printf("✓ %s\n", message)
+			fmt.Fprintf(f.messageOutput(), "✓ %s\n", message)
 		} else {
 			style := lipgloss.NewStyle().Foreground(f.styles.SuccessColor)
-			fmt.Printf("%s %s\n", style.Render("✓"), message)
+			fmt.Fprintf(f.messageOutput(), "%s %s\n", style.Render("✓"), message)
 		}
 	}
 }
@@ -213,14 +416,26 @@ func (f *OutputFormatter) PrintError(err error) {
 	}
 }
 
+// PrintWarning prints a warning to stderr, so it doesn't mix with json, csv or yaml output
+func (f *OutputFormatter) PrintWarning(message string) {
+	if !f.quiet {
+		if f.noColor {
+			fmt.Fprintf(os.Stderr, "⚠ %s\n", message)
+		} else {
+			style := lipgloss.NewStyle().Foreground(f.styles.WarningColor)
+			fmt.Fprintf(os.Stderr, "%s %s\n", style.Render("⚠"), message)
+		}
+	}
+}
+
 // PrintInfo prints an informational message
 func (f *OutputFormatter) PrintInfo(message string) {
 	if !f.quiet {
 		if f.noColor {
-			fmt.Printf("ℹ %s\n", message)
+			fmt.Fprintf(f.messageOutput(), "ℹ %s\n", message)
 		} else {
 			style := lipgloss.NewStyle().Foreground(f.styles.InfoColor)
-			fmt.Printf("%s %s\n", style.Render("ℹ"), message)
+			fmt.Fprintf(f.messageOutput(), "%s %s\n", style.Render("ℹ"), message)
 		}
 	}
 }
@@ -316,6 +531,29 @@ func (f *OutputFormatter) printEventsTable(events []database.TrackingEvent) erro
 	return nil
 }
 
+// printEmailsTable prints linked emails in table format
+func (f *OutputFormatter) printEmailsTable(emails []database.EmailBodyEntry) error {
+	if len(emails) == 0 {
+		fmt.Println("No linked emails found.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "ID\tMESSAGE ID\tDATE\tFROM\tSUBJECT")
+	for _, email := range emails {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n",
+			email.ID,
+			email.GmailMessageID,
+			email.Date.Format("2006-01-02 15:04"),
+			truncate(email.From, 30),
+			truncate(email.Subject, 50))
+	}
+
+	return nil
+}
+
 // truncate truncates a string to the specified length
 func truncate(s string, maxLen int) string {
 	if len(s) <= maxLen {
@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"encoding/csv"
+	"os"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"package-tracking/internal/database"
+)
+
+// recordField is one column of a shipment or event in csv and yaml output. Columns are
+// listed in a fixed order so output stays stable for scripts.
+type recordField struct {
+	name  string
+	tag   string // YAML scalar tag, so strings like "123" or "yes" aren't re-typed by readers
+	value string
+}
+
+// shipmentRecord returns a shipment's columns in output order
+func shipmentRecord(s database.Shipment) []recordField {
+	expectedDelivery := recordField{name: "expected_delivery", tag: "!!null", value: ""}
+	if s.ExpectedDelivery != nil {
+		expectedDelivery = recordField{name: "expected_delivery", tag: "!!timestamp", value: formatRecordTime(*s.ExpectedDelivery)}
+	}
+
+	return []recordField{
+		{name: "id", tag: "!!int", value: strconv.Itoa(s.ID)},
+		{name: "tracking_number", tag: "!!str", value: s.TrackingNumber},
+		{name: "carrier", tag: "!!str", value: s.Carrier},
+		{name: "description", tag: "!!str", value: s.Description},
+		{name: "status", tag: "!!str", value: s.Status},
+		{name: "created_at", tag: "!!timestamp", value: formatRecordTime(s.CreatedAt)},
+		{name: "updated_at", tag: "!!timestamp", value: formatRecordTime(s.UpdatedAt)},
+		expectedDelivery,
+		{name: "is_delivered", tag: "!!bool", value: strconv.FormatBool(s.IsDelivered)},
+	}
+}
+
+// eventRecord returns a tracking event's columns in output order
+func eventRecord(e database.TrackingEvent) []recordField {
+	return []recordField{
+		{name: "id", tag: "!!int", value: strconv.Itoa(e.ID)},
+		{name: "shipment_id", tag: "!!int", value: strconv.Itoa(e.ShipmentID)},
+		{name: "timestamp", tag: "!!timestamp", value: formatRecordTime(e.Timestamp)},
+		{name: "location", tag: "!!str", value: e.Location},
+		{name: "status", tag: "!!str", value: e.Status},
+		{name: "description", tag: "!!str", value: e.Description},
+	}
+}
+
+// emailRecord returns a linked email's columns in output order. Bodies are left out; they
+// are shown by emailBodyRecord.
+func emailRecord(e database.EmailBodyEntry) []recordField {
+	return []recordField{
+		{name: "id", tag: "!!int", value: strconv.Itoa(e.ID)},
+		{name: "gmail_message_id", tag: "!!str", value: e.GmailMessageID},
+		{name: "gmail_thread_id", tag: "!!str", value: e.GmailThreadID},
+		{name: "date", tag: "!!timestamp", value: formatRecordTime(e.Date)},
+		{name: "from", tag: "!!str", value: e.From},
+		{name: "subject", tag: "!!str", value: e.Subject},
+		{name: "status", tag: "!!str", value: e.Status},
+	}
+}
+
+// emailBodyRecord returns an email body's columns in output order
+func emailBodyRecord(b *EmailBody) []recordField {
+	return []recordField{
+		{name: "subject", tag: "!!str", value: b.Subject},
+		{name: "from", tag: "!!str", value: b.From},
+		{name: "date", tag: "!!str", value: b.Date},
+		{name: "plain_text", tag: "!!str", value: b.PlainText},
+		{name: "html_text", tag: "!!str", value: b.HTMLText},
+	}
+}
+
+// shipmentColumns, eventColumns and friends are the csv headers
+var (
+	shipmentColumns  = recordNames(shipmentRecord(database.Shipment{}))
+	eventColumns     = recordNames(eventRecord(database.TrackingEvent{}))
+	emailColumns     = recordNames(emailRecord(database.EmailBodyEntry{}))
+	emailBodyColumns = recordNames(emailBodyRecord(&EmailBody{}))
+)
+
+func formatRecordTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func recordNames(record []recordField) []string {
+	names := make([]string, len(record))
+	for i, field := range record {
+		names[i] = field.name
+	}
+	return names
+}
+
+func recordValues(record []recordField) []string {
+	values := make([]string, len(record))
+	for i, field := range record {
+		values[i] = field.value
+	}
+	return values
+}
+
+// writeCSV writes records to stdout, preceded by a header row if header is non-nil
+func writeCSV(header []string, records [][]recordField) error {
+	w := csv.NewWriter(os.Stdout)
+	if header != nil {
+		if err := w.Write(header); err != nil {
+			return err
+		}
+	}
+	for _, record := range records {
+		if err := w.Write(recordValues(record)); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// recordNode converts a record to a YAML mapping that keeps the column order
+func recordNode(record []recordField) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, field := range record {
+		value := field.value
+		if field.tag == "!!null" {
+			value = "null"
+		}
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: field.name},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: field.tag, Value: value})
+	}
+	return node
+}
+
+// writeYAML writes a single record, or a sequence of records if list is true, to stdout
+func writeYAML(records [][]recordField, list bool) error {
+	var node *yaml.Node
+	if list {
+		node = &yaml.Node{Kind: yaml.SequenceNode}
+		for _, record := range records {
+			node.Content = append(node.Content, recordNode(record))
+		}
+	} else {
+		node = recordNode(records[0])
+	}
+
+	enc := yaml.NewEncoder(os.Stdout)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func shipmentRecords(shipments []database.Shipment) [][]recordField {
+	records := make([][]recordField, len(shipments))
+	for i, shipment := range shipments {
+		records[i] = shipmentRecord(shipment)
+	}
+	return records
+}
+
+func eventRecords(events []database.TrackingEvent) [][]recordField {
+	records := make([][]recordField, len(events))
+	for i, event := range events {
+		records[i] = eventRecord(event)
+	}
+	return records
+}
+
+func emailRecords(emails []database.EmailBodyEntry) [][]recordField {
+	records := make([][]recordField, len(emails))
+	for i, email := range emails {
+		records[i] = emailRecord(email)
+	}
+	return records
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"package-tracking/internal/database"
+)
+
+// captureStdout returns what fn writes to stdout
+func captureStdout(t *testing.T, fn func() error) string {
+	t.Helper()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := fn()
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+
+	if err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+	return buf.String()
+}
+
+func formatTestShipments() []database.Shipment {
+	delivery := time.Date(2023, 12, 5, 0, 0, 0, 0, time.UTC)
+	return []database.Shipment{
+		{
+			ID:               1,
+			TrackingNumber:   "1Z999AA1234567890",
+			Carrier:          "ups",
+			Description:      "Books, \"signed\"",
+			Status:           "in_transit",
+			CreatedAt:        time.Date(2023, 12, 1, 10, 0, 0, 0, time.UTC),
+			UpdatedAt:        time.Date(2023, 12, 2, 10, 0, 0, 0, time.UTC),
+			ExpectedDelivery: &delivery,
+		},
+		{
+			ID:             2,
+			TrackingNumber: "1234567890",
+			Carrier:        "fedex",
+			Description:    "123",
+			Status:         "delivered",
+			IsDelivered:    true,
+		},
+	}
+}
+
+func TestOutputFormatterCSV(t *testing.T) {
+	formatter := NewOutputFormatter("csv", false)
+	output := captureStdout(t, func() error { return formatter.PrintShipments(formatTestShipments()) })
+
+	rows, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+	if err != nil {
+		t.Fatalf("Output is not valid CSV: %v\n%s", err, output)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("Expected header and 2 rows, got %d", len(rows))
+	}
+
+	expectedHeader := "id,tracking_number,carrier,description,status,created_at,updated_at,expected_delivery,is_delivered"
+	if header := strings.Join(rows[0], ","); header != expectedHeader {
+		t.Errorf("Expected header %q, got %q", expectedHeader, header)
+	}
+	if rows[1][3] != "Books, \"signed\"" || rows[1][7] != "2023-12-05T00:00:00Z" {
+		t.Errorf("Unexpected first row %v", rows[1])
+	}
+	if rows[2][7] != "" || rows[2][8] != "true" {
+		t.Errorf("Expected empty expected delivery and delivered flag, got %v", rows[2])
+	}
+
+	events := []database.TrackingEvent{{ID: 7, ShipmentID: 1, Location: "Memphis, TN", Status: "in_transit"}}
+	output = captureStdout(t, func() error { return formatter.PrintEvents(events) })
+	if !strings.HasPrefix(output, "id,shipment_id,timestamp,location,status,description\n7,1,") {
+		t.Errorf("Unexpected events CSV: %q", output)
+	}
+}
+
+func TestOutputFormatterYAML(t *testing.T) {
+	formatter := NewOutputFormatter("yaml", false)
+	output := captureStdout(t, func() error { return formatter.PrintShipments(formatTestShipments()) })
+
+	var decoded []map[string]interface{}
+	if err := yaml.Unmarshal([]byte(output), &decoded); err != nil {
+		t.Fatalf("Output is not valid YAML: %v\n%s", err, output)
+	}
+	if len(decoded) != 2 {
+		t.Fatalf("Expected 2 shipments, got %d", len(decoded))
+	}
+	if decoded[0]["id"] != 1 || decoded[0]["carrier"] != "ups" {
+		t.Errorf("Unexpected first shipment %v", decoded[0])
+	}
+	// Descriptions that look like numbers stay strings
+	if decoded[1]["description"] != "123" || decoded[1]["expected_delivery"] != nil || decoded[1]["is_delivered"] != true {
+		t.Errorf("Unexpected second shipment %v", decoded[1])
+	}
+
+	// Keys keep the stable column order
+	if strings.Index(output, "tracking_number:") > strings.Index(output, "carrier:") {
+		t.Errorf("Expected tracking_number before carrier, got:\n%s", output)
+	}
+
+	output = captureStdout(t, func() error { return formatter.PrintShipment(&formatTestShipments()[0]) })
+	if !strings.HasPrefix(output, "id: 1\n") {
+		t.Errorf("Expected a single shipment mapping, got:\n%s", output)
+	}
+}
@@ -2,12 +2,15 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"time"
 	
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-isatty"
 )
 
 // ProgressSpinner provides a simple spinner for long operations
@@ -34,6 +37,12 @@ func NewProgressSpinner(message string, noColor bool) *ProgressSpinner {
 	}
 }
 
+// SetTheme colors the spinner with a theme instead of the default colors
+func (p *ProgressSpinner) SetTheme(theme Theme) {
+	p.spinner.Style = lipgloss.NewStyle().Foreground(theme.Info)
+	p.style = lipgloss.NewStyle().Foreground(theme.Muted)
+}
+
 // Start begins the spinner in a goroutine
 func (p *ProgressSpinner) Start() {
 	if p.noColor || os.Getenv("CI") != "" {
@@ -107,4 +116,66 @@ func (s *spinnerProgram) waitForComplete() tea.Cmd {
 	}
 }
 
-type completeMsg struct{}
\ No newline at end of file
+type completeMsg struct{}
+// ProgressBar shows how far through a list of items a long operation is, redrawing a single
+// line on stderr. It does nothing unless stderr is a terminal, so piped output stays clean.
+type ProgressBar struct {
+	total   int
+	done    int
+	width   int
+	enabled bool
+	color   bool
+	out     io.Writer
+	style   lipgloss.Style
+}
+
+// NewProgressBar creates a progress bar for total items
+func NewProgressBar(total int, noColor bool) *ProgressBar {
+	p := &ProgressBar{
+		total:   total,
+		width:   30,
+		enabled: (isatty.IsTerminal(os.Stderr.Fd()) || isatty.IsCygwinTerminal(os.Stderr.Fd())) && os.Getenv("CI") == "",
+		color:   !noColor && os.Getenv("NO_COLOR") == "",
+		out:     os.Stderr,
+	}
+	p.SetTheme(themes[DefaultThemeName])
+	return p
+}
+
+// SetTheme colors the bar with a theme instead of the default colors
+func (p *ProgressBar) SetTheme(theme Theme) {
+	p.style = lipgloss.NewStyle()
+	if p.color {
+		p.style = p.style.Foreground(theme.Info)
+	}
+}
+
+// Increment marks one more item as done and redraws the bar with label, which describes the
+// item
+func (p *ProgressBar) Increment(label string) {
+	p.done++
+	if !p.enabled {
+		return
+	}
+	fmt.Fprintf(p.out, "\r\033[K%s", p.render(label))
+}
+
+// Finish clears the bar so later output starts on an empty line
+func (p *ProgressBar) Finish() {
+	if p.enabled {
+		fmt.Fprint(p.out, "\r\033[K")
+	}
+}
+
+// render returns the bar's line, like "[#####-----] 5/10 label"
+func (p *ProgressBar) render(label string) string {
+	filled := p.width
+	if p.total > 0 {
+		filled = p.width * p.done / p.total
+	}
+	if filled > p.width {
+		filled = p.width
+	}
+	bar := p.style.Render(strings.Repeat("█", filled)) + strings.Repeat("░", p.width-filled)
+	return fmt.Sprintf("%s %d/%d %s", bar, p.done, p.total, label)
+}
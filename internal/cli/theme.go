@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DefaultThemeName is the theme used when none is configured
+const DefaultThemeName = "default"
+
+// Theme is the set of colors used by the formatter, the progress spinner and the interactive
+// table. Colors are ANSI color numbers, which terminals map onto their own palettes.
+type Theme struct {
+	// Status colors
+	Delivered lipgloss.Color
+	InTransit lipgloss.Color
+	Pending   lipgloss.Color
+	Failed    lipgloss.Color
+	Unknown   lipgloss.Color
+
+	// Message colors
+	Success lipgloss.Color
+	Error   lipgloss.Color
+	Info    lipgloss.Color
+	Warning lipgloss.Color
+
+	Accent     lipgloss.Color // Titles and spinners
+	Muted      lipgloss.Color // Help text and secondary information
+	Border     lipgloss.Color // Table borders and separators
+	Highlight  lipgloss.Color // Filter matches and changed statuses
+	SelectedFg lipgloss.Color // Selected table row
+	SelectedBg lipgloss.Color
+}
+
+// themes are the built-in themes, selected with the theme config setting
+var themes = map[string]Theme{
+	// For dark terminal backgrounds
+	"default": {
+		Delivered:  lipgloss.Color("10"),
+		InTransit:  lipgloss.Color("11"),
+		Pending:    lipgloss.Color("12"),
+		Failed:     lipgloss.Color("9"),
+		Unknown:    lipgloss.Color("8"),
+		Success:    lipgloss.Color("10"),
+		Error:      lipgloss.Color("9"),
+		Info:       lipgloss.Color("12"),
+		Warning:    lipgloss.Color("11"),
+		Accent:     lipgloss.Color("39"),
+		Muted:      lipgloss.Color("244"),
+		Border:     lipgloss.Color("240"),
+		Highlight:  lipgloss.Color("214"),
+		SelectedFg: lipgloss.Color("229"),
+		SelectedBg: lipgloss.Color("57"),
+	},
+	// Darker colors that stay readable on white and other light backgrounds
+	"light": {
+		Delivered:  lipgloss.Color("28"),
+		InTransit:  lipgloss.Color("130"),
+		Pending:    lipgloss.Color("25"),
+		Failed:     lipgloss.Color("160"),
+		Unknown:    lipgloss.Color("242"),
+		Success:    lipgloss.Color("28"),
+		Error:      lipgloss.Color("160"),
+		Info:       lipgloss.Color("25"),
+		Warning:    lipgloss.Color("130"),
+		Accent:     lipgloss.Color("25"),
+		Muted:      lipgloss.Color("242"),
+		Border:     lipgloss.Color("248"),
+		Highlight:  lipgloss.Color("166"),
+		SelectedFg: lipgloss.Color("255"),
+		SelectedBg: lipgloss.Color("25"),
+	},
+	// Only the basic 16 colors, for terminals with limited palettes or users who need
+	// stronger contrast
+	"high-contrast": {
+		Delivered:  lipgloss.Color("2"),
+		InTransit:  lipgloss.Color("3"),
+		Pending:    lipgloss.Color("4"),
+		Failed:     lipgloss.Color("1"),
+		Unknown:    lipgloss.Color("5"),
+		Success:    lipgloss.Color("2"),
+		Error:      lipgloss.Color("1"),
+		Info:       lipgloss.Color("4"),
+		Warning:    lipgloss.Color("3"),
+		Accent:     lipgloss.Color("6"),
+		Muted:      lipgloss.Color("5"),
+		Border:     lipgloss.Color("6"),
+		Highlight:  lipgloss.Color("3"),
+		SelectedFg: lipgloss.Color("0"),
+		SelectedBg: lipgloss.Color("6"),
+	},
+}
+
+// ThemeNames returns the names of the built-in themes, sorted
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ThemeByName returns the named built-in theme. An empty name selects the default theme.
+func ThemeByName(name string) (Theme, error) {
+	if name == "" {
+		name = DefaultThemeName
+	}
+	theme, ok := themes[strings.ToLower(name)]
+	if !ok {
+		return Theme{}, fmt.Errorf("invalid theme: %s (must be one of: %s)", name, strings.Join(ThemeNames(), ", "))
+	}
+	return theme, nil
+}
+
+// StatusColor returns the theme's color for a shipment or event status
+func (t Theme) StatusColor(status string) lipgloss.Color {
+	switch strings.ToLower(status) {
+	case "delivered":
+		return t.Delivered
+	case "in_transit", "out_for_delivery", "in transit", "in-transit", "transit":
+		return t.InTransit
+	case "pre_ship", "pending":
+		return t.Pending
+	case "exception", "returned", "failed", "error":
+		return t.Failed
+	default:
+		return t.Unknown
+	}
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestThemeByName(t *testing.T) {
+	for _, name := range ThemeNames() {
+		if _, err := ThemeByName(name); err != nil {
+			t.Errorf("ThemeByName(%q) failed: %v", name, err)
+		}
+	}
+
+	theme, err := ThemeByName("")
+	if err != nil {
+		t.Fatalf("ThemeByName(\"\") failed: %v", err)
+	}
+	if theme != themes[DefaultThemeName] {
+		t.Error("Expected an empty name to select the default theme")
+	}
+
+	if _, err := ThemeByName("LIGHT"); err != nil {
+		t.Errorf("Expected theme names to be case-insensitive: %v", err)
+	}
+	if _, err := ThemeByName("solarized"); err == nil {
+		t.Error("Expected error for unknown theme")
+	}
+}
+
+func TestThemeStatusColor(t *testing.T) {
+	theme := themes["light"]
+
+	tests := []struct {
+		status string
+		want   lipgloss.Color
+	}{
+		{"delivered", theme.Delivered},
+		{"in_transit", theme.InTransit},
+		{"out_for_delivery", theme.InTransit},
+		{"pre_ship", theme.Pending},
+		{"exception", theme.Failed},
+		{"returned", theme.Failed},
+		{"unknown", theme.Unknown},
+		{"", theme.Unknown},
+	}
+
+	for _, tt := range tests {
+		if got := theme.StatusColor(tt.status); got != tt.want {
+			t.Errorf("StatusColor(%q) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestSetTheme(t *testing.T) {
+	f := NewOutputFormatterWithColor("table", false, true)
+	f.SetTheme(themes["light"])
+
+	if f.Theme() != themes["light"] {
+		t.Error("Expected Theme to return the theme that was set")
+	}
+	if f.styles.DeliveredColor != themes["light"].Delivered {
+		t.Errorf("Expected styles to use the theme's colors, got %q", f.styles.DeliveredColor)
+	}
+}
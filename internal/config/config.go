@@ -2,16 +2,19 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // Config holds all application configuration
 type Config struct {
 	// Server configuration
-	ServerPort string
-	ServerHost string
+	ServerPort      string
+	ServerHost      string
+	ServerReusePort bool // Bind with SO_REUSEPORT so an upgraded server can start alongside
 
 	// Database configuration
 	DBPath string
@@ -37,8 +40,17 @@ type Config struct {
 	DisableCache     bool
 
 	// Admin authentication
-	DisableAdminAuth bool
-	AdminAPIKey      string
+	DisableAdminAuth        bool
+	AdminAPIKey             string
+	AdminAuthMode           string   // "api_key", or "proxy" for users from a reverse proxy or client certificate
+	AuthProxyTrustedProxies []string // Addresses or CIDRs of the proxies whose user headers are trusted
+	AuthProxyUserHeaders    []string // Headers naming the user, in order of preference
+	AuthProxyUsers          []string // Users allowed to the admin API; empty allows any authenticated user
+
+	// TLS configuration
+	TLSCertFile     string
+	TLSKeyFile      string
+	TLSClientCAFile string // Requires client certificates signed by this CA (mTLS)
 
 	// Auto-update configuration
 	AutoUpdateEnabled           bool
@@ -53,12 +65,85 @@ type Config struct {
 	DHLAutoUpdateEnabled        bool
 	DHLAutoUpdateCutoffDays     int
 
+	// Per-carrier daily API budgets (0 means unlimited); auto-updates are paced so the
+	// remaining budget is spread evenly over the rest of the day
+	USPSDailyAPIBudget          int
+	UPSDailyAPIBudget           int
+	DHLDailyAPIBudget           int
+
 	// Cache configuration
 	CacheTTL                    time.Duration
+	CacheMemorySize             int    // Responses kept in memory in front of SQLite
+	CacheBackend                string // "sqlite" or "redis"
+	CacheRedisURL               string
+	CacheRedisPrefix            string
 
 	// Timeout configuration
 	AutoUpdateBatchTimeout      time.Duration
 	AutoUpdateIndividualTimeout time.Duration
+
+	// Catch-up configuration (runs after the server has been down for several intervals)
+	AutoUpdateCatchUpEnabled   bool
+	AutoUpdateCatchUpThreshold int
+	AutoUpdateCatchUpDelay     time.Duration
+
+	// Email maintenance configuration
+	EmailCleanupEnabled    bool
+	EmailCleanupInterval   time.Duration
+	EmailBodyRetentionDays int
+
+	// Stalled shipment detection configuration
+	StalledDetectionEnabled     bool
+	StalledCheckInterval        time.Duration
+	StalledThresholdDays        int
+	StalledCarrierThresholdDays map[string]int // Per-carrier overrides of StalledThresholdDays
+	StalledWebhookURL           string         // Optional URL notified when shipments become stalled
+
+	// Notification configuration. Status changes are sent to every enabled channel, with
+	// failed deliveries retried with exponential backoff.
+	NotificationsEnabled     bool
+	NotificationQueueSize    int
+	NotificationMaxAttempts  int
+	NotificationRetryBackoff time.Duration
+	NotificationMaxBackoff   time.Duration
+	NotificationBaseURL      string // Web UI address used to link to shipments, e.g. http://tracker.local:8080
+	NotificationLog          NotificationChannelConfig // Writes notifications to the server log
+	NotificationSlack        SlackNotificationConfig
+	NotificationDiscord      DiscordNotificationConfig
+	NotificationTelegram     TelegramNotificationConfig
+	NotificationNtfy         NtfyNotificationConfig
+	NotificationPushover     PushoverNotificationConfig
+	NotificationGotify       GotifyNotificationConfig
+	NotificationWebhooks     []WebhookNotificationConfig
+
+	// Digests are sent at NotificationDigestTime, HH:MM in the server's time zone, daily
+	// or, for weekly digests, on NotificationDigestWeekday
+	NotificationDigestTime    string
+	NotificationDigestWeekday string
+
+	// Channels with TodayAlert set get the shipments out for delivery or expected today
+	// at NotificationTodayAlertTime, HH:MM in the server's time zone
+	NotificationTodayAlertTime string
+
+	// Escalation of exceptions and delivery delays: each is notified when detected and
+	// again every NotificationEscalationRepeat, up to NotificationEscalationMaxReminders
+	// times, until it is acknowledged or resolved
+	NotificationEscalationEnabled      bool
+	NotificationEscalationInterval     time.Duration // How often unresolved problems are checked
+	NotificationEscalationRepeat       time.Duration
+	NotificationEscalationMaxReminders int
+
+	// MQTT publishing for Home Assistant. Shipments are published as sensors with discovery
+	// payloads, and status changes as events.
+	MQTTEnabled            bool
+	MQTTBrokerURL          string // e.g. tcp://localhost:1883, or ssl:// for TLS
+	MQTTUsername           string
+	MQTTPassword           string
+	MQTTClientID           string
+	MQTTTopicPrefix        string        // Prefix of state, attribute and event topics
+	MQTTDiscoveryPrefix    string        // Home Assistant's discovery prefix
+	MQTTSyncInterval       time.Duration // How often every shipment's state is republished
+	MQTTDeliveredRetention time.Duration // How long delivered shipments stay as sensors
 }
 
 // Load loads configuration from environment variables with defaults
@@ -73,6 +158,8 @@ func Load() (*Config, error) {
 		ServerPort: getEnvOrDefault("SERVER_PORT", "8080"),
 		ServerHost: getEnvOrDefault("SERVER_HOST", "localhost"),
 
+		ServerReusePort: getEnvBoolOrDefault("SERVER_REUSE_PORT", false),
+
 		// Database defaults
 		DBPath: getEnvOrDefault("DB_PATH", "./database.db"),
 
@@ -80,14 +167,14 @@ func Load() (*Config, error) {
 		UpdateInterval: getEnvDurationOrDefault("UPDATE_INTERVAL", "1h"),
 
 		// API keys (optional)
-		USPSAPIKey:      os.Getenv("USPS_API_KEY"),
-		UPSAPIKey:       os.Getenv("UPS_API_KEY"),
-		UPSClientID:     os.Getenv("UPS_CLIENT_ID"),
-		UPSClientSecret: os.Getenv("UPS_CLIENT_SECRET"),
-		FedExAPIKey:     os.Getenv("FEDEX_API_KEY"),
-		FedExSecretKey:  os.Getenv("FEDEX_SECRET_KEY"),
+		USPSAPIKey:      getSecretEnv("USPS_API_KEY"),
+		UPSAPIKey:       getSecretEnv("UPS_API_KEY"),
+		UPSClientID:     getSecretEnv("UPS_CLIENT_ID"),
+		UPSClientSecret: getSecretEnv("UPS_CLIENT_SECRET"),
+		FedExAPIKey:     getSecretEnv("FEDEX_API_KEY"),
+		FedExSecretKey:  getSecretEnv("FEDEX_SECRET_KEY"),
 		FedExAPIURL:     getEnvOrDefault("FEDEX_API_URL", "https://apis.fedex.com"),
-		DHLAPIKey:       os.Getenv("DHL_API_KEY"),
+		DHLAPIKey:       getSecretEnv("DHL_API_KEY"),
 
 		// Logging
 		LogLevel: getEnvOrDefault("LOG_LEVEL", "info"),
@@ -97,8 +184,17 @@ func Load() (*Config, error) {
 		DisableCache:     getEnvBoolOrDefault("DISABLE_CACHE", false),
 
 		// Admin authentication
-		DisableAdminAuth: getEnvBoolOrDefault("DISABLE_ADMIN_AUTH", false),
-		AdminAPIKey:      os.Getenv("ADMIN_API_KEY"),
+		DisableAdminAuth:        getEnvBoolOrDefault("DISABLE_ADMIN_AUTH", false),
+		AdminAPIKey:             getSecretEnv("ADMIN_API_KEY"),
+		AdminAuthMode:           getEnvOrDefault("ADMIN_AUTH_MODE", "api_key"),
+		AuthProxyTrustedProxies: splitAndTrim(getEnvOrDefault("AUTH_PROXY_TRUSTED_PROXIES", "127.0.0.1,::1"), ","),
+		AuthProxyUserHeaders:    splitAndTrim(getEnvOrDefault("AUTH_PROXY_USER_HEADERS", "Remote-User,X-Forwarded-User"), ","),
+		AuthProxyUsers:          splitAndTrim(os.Getenv("AUTH_PROXY_USERS"), ","),
+
+		// TLS
+		TLSCertFile:     os.Getenv("TLS_CERT_FILE"),
+		TLSKeyFile:      os.Getenv("TLS_KEY_FILE"),
+		TLSClientCAFile: os.Getenv("TLS_CLIENT_CA_FILE"),
 
 		// Auto-update configuration
 		AutoUpdateEnabled:          getEnvBoolOrDefault("AUTO_UPDATE_ENABLED", true),
@@ -113,13 +209,120 @@ func Load() (*Config, error) {
 		DHLAutoUpdateEnabled:    getEnvBoolOrDefault("DHL_AUTO_UPDATE_ENABLED", true),
 		DHLAutoUpdateCutoffDays: getEnvIntOrDefault("DHL_AUTO_UPDATE_CUTOFF_DAYS", 0),
 
+		// Per-carrier daily API budgets
+		USPSDailyAPIBudget: getEnvIntOrDefault("USPS_DAILY_API_BUDGET", 0),
+		UPSDailyAPIBudget:  getEnvIntOrDefault("UPS_DAILY_API_BUDGET", 0),
+		DHLDailyAPIBudget:  getEnvIntOrDefault("DHL_DAILY_API_BUDGET", 250),
+
 		// Cache configuration
 		CacheTTL:                    getEnvDurationOrDefault("CACHE_TTL", "5m"),
+		CacheMemorySize:             getEnvIntOrDefault("CACHE_MEMORY_SIZE", 1000),
+		CacheBackend:                getEnvOrDefault("CACHE_BACKEND", "sqlite"),
+		CacheRedisURL:               getEnvOrDefault("CACHE_REDIS_URL", "redis://localhost:6379/0"),
+		CacheRedisPrefix:            getEnvOrDefault("CACHE_REDIS_PREFIX", "package-tracking:"),
 
 		// Timeout configuration
 		AutoUpdateBatchTimeout:      getEnvDurationOrDefault("AUTO_UPDATE_BATCH_TIMEOUT", "60s"),
 		AutoUpdateIndividualTimeout: getEnvDurationOrDefault("AUTO_UPDATE_INDIVIDUAL_TIMEOUT", "30s"),
+
+		// Catch-up configuration
+		AutoUpdateCatchUpEnabled:   getEnvBoolOrDefault("AUTO_UPDATE_CATCHUP_ENABLED", true),
+		AutoUpdateCatchUpThreshold: getEnvIntOrDefault("AUTO_UPDATE_CATCHUP_THRESHOLD", 3),
+		AutoUpdateCatchUpDelay:     getEnvDurationOrDefault("AUTO_UPDATE_CATCHUP_DELAY", "500ms"),
+
+		// Email maintenance configuration
+		EmailCleanupEnabled:    getEnvBoolOrDefault("EMAIL_CLEANUP_ENABLED", true),
+		EmailCleanupInterval:   getEnvDurationOrDefault("EMAIL_CLEANUP_INTERVAL", "24h"),
+		EmailBodyRetentionDays: getEnvIntOrDefault("EMAIL_BODY_RETENTION_DAYS", 90),
+
+		// Stalled shipment detection configuration
+		StalledDetectionEnabled: getEnvBoolOrDefault("STALLED_DETECTION_ENABLED", true),
+		StalledCheckInterval:    getEnvDurationOrDefault("STALLED_CHECK_INTERVAL", "6h"),
+		StalledThresholdDays:    getEnvIntOrDefault("STALLED_THRESHOLD_DAYS", 7),
+		StalledWebhookURL:       os.Getenv("STALLED_WEBHOOK_URL"),
+
+		// Notification configuration
+		MQTTEnabled:              getEnvBoolOrDefault("MQTT_ENABLED", false),
+		MQTTBrokerURL:            os.Getenv("MQTT_BROKER_URL"),
+		MQTTUsername:             os.Getenv("MQTT_USERNAME"),
+		MQTTPassword:             getSecretEnv("MQTT_PASSWORD"),
+		MQTTClientID:             getEnvOrDefault("MQTT_CLIENT_ID", "package-tracker"),
+		MQTTTopicPrefix:          getEnvOrDefault("MQTT_TOPIC_PREFIX", "package_tracker"),
+		MQTTDiscoveryPrefix:      getEnvOrDefault("MQTT_DISCOVERY_PREFIX", "homeassistant"),
+		MQTTSyncInterval:         getEnvDurationOrDefault("MQTT_SYNC_INTERVAL", "5m"),
+		MQTTDeliveredRetention:   getEnvDurationOrDefault("MQTT_DELIVERED_RETENTION", "48h"),
+		NotificationsEnabled:     getEnvBoolOrDefault("NOTIFICATIONS_ENABLED", true),
+		NotificationQueueSize:    getEnvIntOrDefault("NOTIFICATIONS_QUEUE_SIZE", 100),
+		NotificationMaxAttempts:  getEnvIntOrDefault("NOTIFICATIONS_MAX_ATTEMPTS", 3),
+		NotificationRetryBackoff: getEnvDurationOrDefault("NOTIFICATIONS_RETRY_BACKOFF", "10s"),
+		NotificationMaxBackoff:   getEnvDurationOrDefault("NOTIFICATIONS_MAX_BACKOFF", "5m"),
+		NotificationBaseURL:      os.Getenv("NOTIFICATIONS_BASE_URL"),
+		NotificationLog:          notificationChannelFromEnv("NOTIFICATIONS_LOG"),
+		NotificationSlack: SlackNotificationConfig{
+			NotificationChannelConfig: notificationChannelFromEnv("NOTIFICATIONS_SLACK"),
+			WebhookURL:                getSecretEnv("NOTIFICATIONS_SLACK_WEBHOOK_URL"),
+			BotToken:                  getSecretEnv("NOTIFICATIONS_SLACK_BOT_TOKEN"),
+			Channel:                   os.Getenv("NOTIFICATIONS_SLACK_CHANNEL"),
+		},
+		NotificationDiscord: DiscordNotificationConfig{
+			NotificationChannelConfig: notificationChannelFromEnv("NOTIFICATIONS_DISCORD"),
+			WebhookURL:                getSecretEnv("NOTIFICATIONS_DISCORD_WEBHOOK_URL"),
+			Username:                  os.Getenv("NOTIFICATIONS_DISCORD_USERNAME"),
+		},
+		NotificationTelegram: TelegramNotificationConfig{
+			NotificationChannelConfig: notificationChannelFromEnv("NOTIFICATIONS_TELEGRAM"),
+			BotToken:                  getSecretEnv("NOTIFICATIONS_TELEGRAM_BOT_TOKEN"),
+			CommandsEnabled:           getEnvBoolOrDefault("NOTIFICATIONS_TELEGRAM_COMMANDS_ENABLED", false),
+		},
+		NotificationNtfy: NtfyNotificationConfig{
+			NotificationChannelConfig: notificationChannelFromEnv("NOTIFICATIONS_NTFY"),
+			ServerURL:                 os.Getenv("NOTIFICATIONS_NTFY_SERVER_URL"),
+			Topic:                     os.Getenv("NOTIFICATIONS_NTFY_TOPIC"),
+			Token:                     getSecretEnv("NOTIFICATIONS_NTFY_TOKEN"),
+		},
+		NotificationPushover: PushoverNotificationConfig{
+			NotificationChannelConfig: notificationChannelFromEnv("NOTIFICATIONS_PUSHOVER"),
+			AppToken:                  getSecretEnv("NOTIFICATIONS_PUSHOVER_APP_TOKEN"),
+			UserKey:                   getSecretEnv("NOTIFICATIONS_PUSHOVER_USER_KEY"),
+			Device:                    os.Getenv("NOTIFICATIONS_PUSHOVER_DEVICE"),
+		},
+		NotificationGotify: GotifyNotificationConfig{
+			NotificationChannelConfig: notificationChannelFromEnv("NOTIFICATIONS_GOTIFY"),
+			ServerURL:                 os.Getenv("NOTIFICATIONS_GOTIFY_SERVER_URL"),
+			AppToken:                  getSecretEnv("NOTIFICATIONS_GOTIFY_APP_TOKEN"),
+		},
+		NotificationDigestTime:    getEnvOrDefault("NOTIFICATIONS_DIGEST_TIME", "08:00"),
+		NotificationDigestWeekday: getEnvOrDefault("NOTIFICATIONS_DIGEST_WEEKDAY", "monday"),
+
+		NotificationTodayAlertTime: getEnvOrDefault("NOTIFICATIONS_TODAY_ALERT_TIME", "07:00"),
+
+		NotificationEscalationEnabled:      getEnvBoolOrDefault("NOTIFICATIONS_ESCALATION_ENABLED", false),
+		NotificationEscalationInterval:     getEnvDurationOrDefault("NOTIFICATIONS_ESCALATION_INTERVAL", "15m"),
+		NotificationEscalationRepeat:       getEnvDurationOrDefault("NOTIFICATIONS_ESCALATION_REPEAT", "12h"),
+		NotificationEscalationMaxReminders: getEnvIntOrDefault("NOTIFICATIONS_ESCALATION_MAX_REMINDERS", 3),
+	}
+
+	telegramChats, err := parseChatIDs(os.Getenv("NOTIFICATIONS_TELEGRAM_CHAT_IDS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid NOTIFICATIONS_TELEGRAM_CHAT_IDS: %w", err)
 	}
+	config.NotificationTelegram.ChatIDs = telegramChats
+
+	for prefix, priorities := range map[string]*map[string]string{
+		"NOTIFICATIONS_NTFY":     &config.NotificationNtfy.Priorities,
+		"NOTIFICATIONS_PUSHOVER": &config.NotificationPushover.Priorities,
+		"NOTIFICATIONS_GOTIFY":   &config.NotificationGotify.Priorities,
+	} {
+		if *priorities, err = parsePriorityMap(os.Getenv(prefix + "_PRIORITIES")); err != nil {
+			return nil, fmt.Errorf("invalid %s_PRIORITIES: %w", prefix, err)
+		}
+	}
+
+	carrierThresholds, err := parseCarrierDays(os.Getenv("STALLED_CARRIER_THRESHOLD_DAYS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid STALLED_CARRIER_THRESHOLD_DAYS: %w", err)
+	}
+	config.StalledCarrierThresholdDays = carrierThresholds
 
 	// Validate configuration
 	if err := config.validate(); err != nil {
@@ -183,9 +386,24 @@ func (c *Config) validate() error {
 	if c.DHLAutoUpdateCutoffDays < 0 {
 		return fmt.Errorf("DHL auto update cutoff days must be non-negative")
 	}
+	if c.USPSDailyAPIBudget < 0 || c.UPSDailyAPIBudget < 0 || c.DHLDailyAPIBudget < 0 {
+		return fmt.Errorf("carrier daily API budgets must be non-negative")
+	}
 	if c.CacheTTL <= 0 {
 		return fmt.Errorf("cache TTL must be positive")
 	}
+	if c.CacheMemorySize < 0 {
+		return fmt.Errorf("cache memory size must be non-negative")
+	}
+	switch c.CacheBackend {
+	case "", "sqlite":
+	case "redis":
+		if c.CacheRedisURL == "" {
+			return fmt.Errorf("cache Redis URL is required with the redis cache backend")
+		}
+	default:
+		return fmt.Errorf("cache backend must be sqlite or redis, got %q", c.CacheBackend)
+	}
 
 	// Validate timeout configuration
 	if c.AutoUpdateBatchTimeout <= 0 {
@@ -195,14 +413,140 @@ func (c *Config) validate() error {
 		return fmt.Errorf("auto update individual timeout must be positive")
 	}
 
+	// Validate catch-up configuration
+	if c.AutoUpdateCatchUpEnabled {
+		if c.AutoUpdateCatchUpThreshold < 1 {
+			return fmt.Errorf("auto update catch-up threshold must be at least 1 interval")
+		}
+		if c.AutoUpdateCatchUpDelay <= 0 {
+			return fmt.Errorf("auto update catch-up delay must be positive")
+		}
+	}
+
+	// Validate email maintenance configuration
+	if c.EmailCleanupEnabled {
+		if c.EmailCleanupInterval <= 0 {
+			return fmt.Errorf("email cleanup interval must be positive")
+		}
+		if c.EmailBodyRetentionDays < 1 {
+			return fmt.Errorf("email body retention days must be at least 1")
+		}
+	}
+
+	// Validate stalled shipment detection configuration
+	if c.StalledDetectionEnabled {
+		if c.StalledCheckInterval <= 0 {
+			return fmt.Errorf("stalled check interval must be positive")
+		}
+		if c.StalledThresholdDays < 1 {
+			return fmt.Errorf("stalled threshold days must be at least 1")
+		}
+		for carrier, days := range c.StalledCarrierThresholdDays {
+			if days < 1 {
+				return fmt.Errorf("stalled threshold days for %s must be at least 1", carrier)
+			}
+		}
+	}
+
+	// Validate notification configuration
+	if c.NotificationsEnabled {
+		if err := c.validateNotifications(); err != nil {
+			return err
+		}
+	}
+
+	// Validate MQTT configuration
+	if c.MQTTEnabled {
+		if c.MQTTBrokerURL == "" {
+			return fmt.Errorf("MQTT broker URL is required when MQTT is enabled")
+		}
+		if c.MQTTTopicPrefix == "" || c.MQTTDiscoveryPrefix == "" {
+			return fmt.Errorf("MQTT topic and discovery prefixes must not be empty")
+		}
+		if c.MQTTSyncInterval <= 0 {
+			return fmt.Errorf("MQTT sync interval must be positive")
+		}
+		if c.MQTTDeliveredRetention < 0 {
+			return fmt.Errorf("MQTT delivered retention must not be negative")
+		}
+	}
+
 	// Validate admin authentication
-	if !c.DisableAdminAuth && c.AdminAPIKey == "" {
-		return fmt.Errorf("ADMIN_API_KEY is required when admin authentication is enabled (set DISABLE_ADMIN_AUTH=true to disable)")
+	switch c.AdminAuthMode {
+	case "", "api_key":
+		if !c.DisableAdminAuth && c.AdminAPIKey == "" {
+			return fmt.Errorf("ADMIN_API_KEY is required when admin authentication is enabled (set DISABLE_ADMIN_AUTH=true to disable)")
+		}
+	case "proxy":
+		if _, err := c.TrustedProxyNets(); err != nil {
+			return err
+		}
+		if len(c.AuthProxyUserHeaders) == 0 && c.TLSClientCAFile == "" {
+			return fmt.Errorf("proxy authentication needs user headers or a TLS client CA")
+		}
+	default:
+		return fmt.Errorf("unknown admin auth mode %q (expected api_key or proxy)", c.AdminAuthMode)
+	}
+
+	// Validate TLS
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return fmt.Errorf("TLS certificate and key files must be set together")
+	}
+	if c.TLSClientCAFile != "" && c.TLSCertFile == "" {
+		return fmt.Errorf("a TLS client CA requires a TLS certificate and key")
 	}
 
 	return nil
 }
 
+// TrustedProxyNets parses the trusted proxies, each an address or CIDR
+func (c *Config) TrustedProxyNets() ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, proxy := range c.AuthProxyTrustedProxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", proxy)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+// DailyAPIBudgetFor returns the daily API call budget for a carrier, or 0 if unlimited
+func (c *Config) DailyAPIBudgetFor(carrier string) int {
+	switch carrier {
+	case "usps":
+		return c.USPSDailyAPIBudget
+	case "ups":
+		return c.UPSDailyAPIBudget
+	case "dhl":
+		return c.DHLDailyAPIBudget
+	default:
+		return 0
+	}
+}
+
+// StalledThresholdFor returns the number of days without new events after which a
+// shipment from the given carrier is considered stalled
+func (c *Config) StalledThresholdFor(carrier string) int {
+	if days, ok := c.StalledCarrierThresholdDays[carrier]; ok {
+		return days
+	}
+	return c.StalledThresholdDays
+}
+
 // Address returns the full server address
 func (c *Config) Address() string {
 	return c.ServerHost + ":" + c.ServerPort
@@ -414,6 +414,127 @@ func TestValidate(t *testing.T) {
 			t.Error("Expected error for negative DHL auto-update cutoff days")
 		}
 	})
+
+	t.Run("NotificationMaxBackoffBelowRetryBackoff", func(t *testing.T) {
+		config := &Config{
+			ServerPort:                  "8080",
+			ServerHost:                  "localhost",
+			DBPath:                      "./test.db",
+			UpdateInterval:              time.Hour,
+			LogLevel:                    "info",
+			AutoUpdateBatchSize:         5,
+			CacheTTL:                    5 * time.Minute,
+			AutoUpdateBatchTimeout:      30 * time.Second,
+			AutoUpdateIndividualTimeout: 10 * time.Second,
+			DisableAdminAuth:            true,
+			NotificationsEnabled:        true,
+			NotificationQueueSize:       100,
+			NotificationMaxAttempts:     3,
+			NotificationRetryBackoff:    time.Minute,
+			NotificationMaxBackoff:      time.Second, // Invalid
+		}
+
+		if err := config.validate(); err == nil {
+			t.Error("Expected error for a notification max backoff below the retry backoff")
+		}
+	})
+
+	t.Run("SlackNotificationsWithoutDestination", func(t *testing.T) {
+		config := &Config{
+			ServerPort:                  "8080",
+			ServerHost:                  "localhost",
+			DBPath:                      "./test.db",
+			UpdateInterval:              time.Hour,
+			LogLevel:                    "info",
+			AutoUpdateBatchSize:         5,
+			CacheTTL:                    5 * time.Minute,
+			AutoUpdateBatchTimeout:      30 * time.Second,
+			AutoUpdateIndividualTimeout: 10 * time.Second,
+			DisableAdminAuth:            true,
+			NotificationsEnabled:        true,
+			NotificationQueueSize:       100,
+			NotificationMaxAttempts:     3,
+			NotificationRetryBackoff:    time.Second,
+			NotificationMaxBackoff:      time.Minute,
+			NotificationSlack: SlackNotificationConfig{
+				NotificationChannelConfig: NotificationChannelConfig{Enabled: true},
+				BotToken:                  "xoxb-test", // Invalid without a channel
+			},
+		}
+
+		if err := config.validate(); err == nil {
+			t.Error("Expected error for slack notifications with a bot token but no channel")
+		}
+	})
+
+	t.Run("UnknownCacheBackend", func(t *testing.T) {
+		config := &Config{
+			ServerPort:                  "8080",
+			ServerHost:                  "localhost",
+			DBPath:                      "./test.db",
+			UpdateInterval:              time.Hour,
+			LogLevel:                    "info",
+			AutoUpdateBatchSize:         5,
+			CacheTTL:                    5 * time.Minute,
+			CacheBackend:                "memcached", // Invalid
+			AutoUpdateBatchTimeout:      30 * time.Second,
+			AutoUpdateIndividualTimeout: 10 * time.Second,
+			DisableAdminAuth:            true,
+		}
+
+		if err := config.validate(); err == nil {
+			t.Error("Expected error for an unknown cache backend")
+		}
+	})
+
+	t.Run("ProxyAuth", func(t *testing.T) {
+		config := &Config{
+			ServerPort:                  "8080",
+			ServerHost:                  "localhost",
+			DBPath:                      "./test.db",
+			UpdateInterval:              time.Hour,
+			LogLevel:                    "info",
+			AutoUpdateBatchSize:         5,
+			CacheTTL:                    5 * time.Minute,
+			AutoUpdateBatchTimeout:      30 * time.Second,
+			AutoUpdateIndividualTimeout: 10 * time.Second,
+			AdminAuthMode:               "proxy", // No API key needed
+			AuthProxyTrustedProxies:     []string{"127.0.0.1", "10.0.0.0/8", "::1"},
+			AuthProxyUserHeaders:        []string{"Remote-User"},
+		}
+		if err := config.validate(); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		nets, err := config.TrustedProxyNets()
+		if err != nil || len(nets) != 3 || nets[0].String() != "127.0.0.1/32" || nets[2].String() != "::1/128" {
+			t.Errorf("Unexpected trusted proxies %v: %v", nets, err)
+		}
+
+		config.AuthProxyTrustedProxies = []string{"traefik"}
+		if err := config.validate(); err == nil {
+			t.Error("Expected error for an invalid trusted proxy")
+		}
+
+		config.AuthProxyTrustedProxies = nil
+		config.AdminAuthMode = "oauth"
+		if err := config.validate(); err == nil {
+			t.Error("Expected error for an unknown auth mode")
+		}
+
+		config.AdminAuthMode = "proxy"
+		config.TLSClientCAFile = "ca.pem"
+		if err := config.validate(); err == nil {
+			t.Error("Expected error for a client CA without a certificate")
+		}
+		config.TLSCertFile = "cert.pem"
+		if err := config.validate(); err == nil {
+			t.Error("Expected error for a certificate without a key")
+		}
+		config.TLSKeyFile = "key.pem"
+		if err := config.validate(); err != nil {
+			t.Errorf("Expected no error with mTLS configured, got: %v", err)
+		}
+	})
 }
 
 func TestGetAdminAPIKeyForLogging(t *testing.T) {
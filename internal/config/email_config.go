@@ -138,13 +138,13 @@ func LoadEmailConfigWithEnvFile(envFile string) (*EmailConfig, error) {
 	}
 	config := &EmailConfig{
 		Gmail: GmailConfig{
-			ClientID:       getEnvOrDefault("GMAIL_CLIENT_ID", ""),
-			ClientSecret:   getEnvOrDefault("GMAIL_CLIENT_SECRET", ""),
-			RefreshToken:   getEnvOrDefault("GMAIL_REFRESH_TOKEN", ""),
-			AccessToken:    getEnvOrDefault("GMAIL_ACCESS_TOKEN", ""),
+			ClientID:       getSecretEnv("GMAIL_CLIENT_ID"),
+			ClientSecret:   getSecretEnv("GMAIL_CLIENT_SECRET"),
+			RefreshToken:   getSecretEnv("GMAIL_REFRESH_TOKEN"),
+			AccessToken:    getSecretEnv("GMAIL_ACCESS_TOKEN"),
 			TokenFile:      getEnvOrDefault("GMAIL_TOKEN_FILE", "./gmail-token.json"),
 			Username:       getEnvOrDefault("GMAIL_USERNAME", ""),
-			AppPassword:    getEnvOrDefault("GMAIL_APP_PASSWORD", ""),
+			AppPassword:    getSecretEnv("GMAIL_APP_PASSWORD"),
 			MaxResults:     getEnvInt64OrDefault("GMAIL_MAX_RESULTS", 100),
 			RequestTimeout: getEnvDurationOrDefault("GMAIL_REQUEST_TIMEOUT", "30s"),
 			RateLimitDelay: getEnvDurationOrDefault("GMAIL_RATE_LIMIT_DELAY", "100ms"),
@@ -195,7 +195,7 @@ func LoadEmailConfigWithEnvFile(envFile string) (*EmailConfig, error) {
 		LLM: LLMConfig{
 			Provider:    getEnvOrDefault("LLM_PROVIDER", LLMProviderDisabled),
 			Model:       getEnvOrDefault("LLM_MODEL", ""),
-			APIKey:      getEnvOrDefault("LLM_API_KEY", ""),
+			APIKey:      getSecretEnv("LLM_API_KEY"),
 			Endpoint:    getEnvOrDefault("LLM_ENDPOINT", ""),
 			MaxTokens:   getEnvIntOrDefault("LLM_MAX_TOKENS", 1000),
 			Temperature: getEnvFloatOrDefault("LLM_TEMPERATURE", 0.1),
@@ -55,6 +55,30 @@ func getEnvDurationOrDefault(key string, defaultValue string) time.Duration {
 	return duration
 }
 
+// parseCarrierDays parses a per-carrier day list such as "dhl=14,usps=10"
+func parseCarrierDays(value string) (map[string]int, error) {
+	result := make(map[string]int)
+	if strings.TrimSpace(value) == "" {
+		return result, nil
+	}
+
+	for _, entry := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid carrier entry %q, expected carrier=days", entry)
+		}
+
+		carrier := strings.ToLower(strings.TrimSpace(parts[0]))
+		days, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if carrier == "" || err != nil {
+			return nil, fmt.Errorf("invalid carrier entry %q, expected carrier=days", entry)
+		}
+		result[carrier] = days
+	}
+
+	return result, nil
+}
+
 // validateEnvFilePath validates that the env file path is safe and prevents directory traversal
 func validateEnvFilePath(filename string) error {
 	if filename == "" {
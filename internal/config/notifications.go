@@ -0,0 +1,365 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// NotificationChannelConfig holds the settings every notification channel shares. Each
+// channel reads them from its own prefix, e.g. NOTIFICATIONS_LOG_ENABLED or
+// notifications.log.enabled.
+type NotificationChannelConfig struct {
+	Enabled         bool
+	Statuses        []string // Statuses to notify about; empty means every status
+	SubjectTemplate string   // Go template for the subject; empty uses the default
+	BodyTemplate    string   // Go template for the body; empty uses the default
+	Digest          string   // "daily" or "weekly" to also send digests; empty for none
+	TodayAlert      bool     // Also send the morning list of today's deliveries
+}
+
+// SlackNotificationConfig configures the Slack channel, which posts through an incoming
+// webhook or, with a bot token, to a channel with chat.postMessage
+type SlackNotificationConfig struct {
+	NotificationChannelConfig
+	WebhookURL string
+	BotToken   string
+	Channel    string // Channel ID or name, required with BotToken
+}
+
+// DiscordNotificationConfig configures the Discord channel. Transitions the channel's
+// filters accept are posted to WebhookURL, and each route posts the transitions its own
+// filters accept to its webhook, e.g. deliveries to one server and exceptions to another.
+type DiscordNotificationConfig struct {
+	NotificationChannelConfig
+	WebhookURL string
+	Username   string // Overrides the webhook's name; empty keeps it
+	Routes     []DiscordRoute
+}
+
+// DiscordRoute posts some transitions to a webhook of its own. Routes are read from the
+// config file only.
+type DiscordRoute struct {
+	Name       string   `mapstructure:"name"`
+	WebhookURL string   `mapstructure:"webhook_url"`
+	Statuses   []string `mapstructure:"statuses"` // Empty uses the channel's statuses
+	Carriers   []string `mapstructure:"carriers"` // Empty means every carrier
+}
+
+// TelegramNotificationConfig configures the Telegram bot, which sends notifications to
+// ChatIDs and, with CommandsEnabled, answers commands sent from them
+type TelegramNotificationConfig struct {
+	NotificationChannelConfig
+	BotToken        string
+	ChatIDs         []int64 // Chats that are notified and may send commands
+	CommandsEnabled bool
+}
+
+// NtfyNotificationConfig configures the ntfy push channel
+type NtfyNotificationConfig struct {
+	NotificationChannelConfig
+	ServerURL  string // Empty uses https://ntfy.sh
+	Topic      string
+	Token      string            // Access token for protected topics
+	Priorities map[string]string // Status to priority name overrides, e.g. in_transit: low
+}
+
+// PushoverNotificationConfig configures the Pushover push channel
+type PushoverNotificationConfig struct {
+	NotificationChannelConfig
+	AppToken   string
+	UserKey    string // User or group key
+	Device     string // Limits delivery to one device; empty sends to all
+	Priorities map[string]string
+}
+
+// GotifyNotificationConfig configures the Gotify push channel
+type GotifyNotificationConfig struct {
+	NotificationChannelConfig
+	ServerURL  string
+	AppToken   string
+	Priorities map[string]string
+}
+
+// WebhookNotificationConfig configures a generic webhook, which sends a request rendered
+// from Go templates to any service. Webhooks are read from the config file only; values
+// in URL and Headers may reference environment variables, e.g. ${SERVICE_TOKEN}, to keep
+// secrets out of the file.
+type WebhookNotificationConfig struct {
+	Name            string            `mapstructure:"name"`
+	URL             string            `mapstructure:"url"`
+	Method          string            `mapstructure:"method"` // POST, PUT or PATCH; empty uses POST
+	Headers         map[string]string `mapstructure:"headers"`
+	ContentType     string            `mapstructure:"content_type"` // Empty uses application/json
+	Statuses        []string          `mapstructure:"statuses"`     // Empty means every status
+	Carriers        []string          `mapstructure:"carriers"`     // Empty means every carrier
+	SubjectTemplate string            `mapstructure:"subject_template"`
+	BodyTemplate    string            `mapstructure:"body_template"`
+	Digest          string            `mapstructure:"digest"`
+	TodayAlert      bool              `mapstructure:"today_alert"`
+
+	// PayloadTemplate renders the request body; PayloadTemplates overrides it for some new
+	// statuses. Without either, a JSON description of the status change is sent.
+	PayloadTemplate  string            `mapstructure:"payload_template"`
+	PayloadTemplates map[string]string `mapstructure:"payload_templates"`
+}
+
+// notificationChannelSettings are the settings every channel has
+var notificationChannelSettings = []string{"enabled", "statuses", "subject_template", "body_template", "digest", "today_alert"}
+
+// notificationChannelFromEnv reads a channel's settings from environment variables with
+// the given prefix
+func notificationChannelFromEnv(prefix string) NotificationChannelConfig {
+	return NotificationChannelConfig{
+		Enabled:         getEnvBoolOrDefault(prefix+"_ENABLED", false),
+		Statuses:        parseStatusList(os.Getenv(prefix + "_STATUSES")),
+		SubjectTemplate: os.Getenv(prefix + "_SUBJECT_TEMPLATE"),
+		BodyTemplate:    os.Getenv(prefix + "_BODY_TEMPLATE"),
+		Digest:          strings.ToLower(os.Getenv(prefix + "_DIGEST")),
+		TodayAlert:      getEnvBoolOrDefault(prefix+"_TODAY_ALERT", false),
+	}
+}
+
+// setNotificationChannelDefaults sets the defaults of the channel under key. extra are the
+// channel's own string settings, which default to empty.
+func setNotificationChannelDefaults(v *viper.Viper, key string, extra ...string) {
+	v.SetDefault(key+".enabled", false)
+	v.SetDefault(key+".statuses", "")
+	v.SetDefault(key+".subject_template", "")
+	v.SetDefault(key+".body_template", "")
+	v.SetDefault(key+".digest", "")
+	v.SetDefault(key+".today_alert", false)
+	for _, setting := range extra {
+		v.SetDefault(key+"."+setting, "")
+	}
+}
+
+// bindNotificationChannelEnv binds the channel under key, including its extra settings, to
+// environment variables with the given prefix
+func bindNotificationChannelEnv(v *viper.Viper, key, prefix string, extra ...string) {
+	for _, setting := range append(append([]string{}, notificationChannelSettings...), extra...) {
+		suffix := "_" + strings.ToUpper(setting)
+		v.BindEnv(key+"."+setting, "PKG_TRACKER_"+prefix+suffix, prefix+suffix)
+	}
+}
+
+// notificationChannelFromViper reads the channel under key. Statuses may be a YAML list or
+// a comma-separated string.
+func notificationChannelFromViper(v *viper.Viper, key string) NotificationChannelConfig {
+	return NotificationChannelConfig{
+		Enabled:         v.GetBool(key + ".enabled"),
+		Statuses:        parseStatusList(strings.Join(v.GetStringSlice(key+".statuses"), ",")),
+		SubjectTemplate: v.GetString(key + ".subject_template"),
+		BodyTemplate:    v.GetString(key + ".body_template"),
+		Digest:          strings.ToLower(v.GetString(key + ".digest")),
+		TodayAlert:      v.GetBool(key + ".today_alert"),
+	}
+}
+
+// discordRoutesFromViper reads the Discord routes under key
+func discordRoutesFromViper(v *viper.Viper, key string) ([]DiscordRoute, error) {
+	var routes []DiscordRoute
+	if err := v.UnmarshalKey(key, &routes); err != nil {
+		return nil, fmt.Errorf("invalid discord routes: %w", err)
+	}
+	for i := range routes {
+		routes[i].Statuses = parseStatusList(strings.Join(routes[i].Statuses, ","))
+		routes[i].Carriers = parseStatusList(strings.Join(routes[i].Carriers, ","))
+	}
+	return routes, nil
+}
+
+// webhooksFromViper reads the generic webhooks under key
+func webhooksFromViper(v *viper.Viper, key string) ([]WebhookNotificationConfig, error) {
+	var webhooks []WebhookNotificationConfig
+	if err := v.UnmarshalKey(key, &webhooks); err != nil {
+		return nil, fmt.Errorf("invalid webhooks: %w", err)
+	}
+	for i := range webhooks {
+		webhook := &webhooks[i]
+		webhook.URL = os.ExpandEnv(webhook.URL)
+		for name, value := range webhook.Headers {
+			webhook.Headers[name] = os.ExpandEnv(value)
+		}
+		webhook.Method = strings.ToUpper(webhook.Method)
+		webhook.Digest = strings.ToLower(webhook.Digest)
+		webhook.Statuses = parseStatusList(strings.Join(webhook.Statuses, ","))
+		webhook.Carriers = parseStatusList(strings.Join(webhook.Carriers, ","))
+
+		// Viper lowercases map keys, which suits statuses
+		templates := make(map[string]string, len(webhook.PayloadTemplates))
+		for status, template := range webhook.PayloadTemplates {
+			templates[strings.ToLower(status)] = template
+		}
+		webhook.PayloadTemplates = templates
+	}
+	return webhooks, nil
+}
+
+// priorityMapFromViper reads the status to priority map under key, which may be a YAML
+// map or a string such as "exception=urgent,in_transit=low"
+func priorityMapFromViper(v *viper.Viper, key string) (map[string]string, error) {
+	if value, ok := v.Get(key).(string); ok {
+		return parsePriorityMap(value)
+	}
+	return v.GetStringMapString(key), nil
+}
+
+// parsePriorityMap parses a comma-separated list of status=priority pairs
+func parsePriorityMap(value string) (map[string]string, error) {
+	priorities := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		status, priority, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid priority mapping %q: expected status=priority", pair)
+		}
+		priorities[strings.ToLower(strings.TrimSpace(status))] = strings.TrimSpace(priority)
+	}
+	return priorities, nil
+}
+
+// parseChatIDs parses a comma-separated list of Telegram chat IDs. Group chat IDs are
+// negative.
+func parseChatIDs(value string) ([]int64, error) {
+	var ids []int64
+	for _, field := range strings.Split(value, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chat ID %q", field)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// parseStatusList parses a comma-separated status list such as "delivered,exception"
+func parseStatusList(value string) []string {
+	var statuses []string
+	for _, status := range strings.Split(value, ",") {
+		if status = strings.ToLower(strings.TrimSpace(status)); status != "" {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+// ParseWeekday parses a day of the week such as "monday" or "Mon"
+func ParseWeekday(value string) (time.Weekday, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if value == name || (len(value) >= 3 && strings.HasPrefix(name, value)) {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid day of the week %q", value)
+}
+
+// validateNotifications checks the notification settings
+func (c *Config) validateNotifications() error {
+	if c.NotificationQueueSize < 1 {
+		return fmt.Errorf("notification queue size must be at least 1")
+	}
+	if c.NotificationMaxAttempts < 1 {
+		return fmt.Errorf("notification max attempts must be at least 1")
+	}
+	if c.NotificationRetryBackoff <= 0 {
+		return fmt.Errorf("notification retry backoff must be positive")
+	}
+	if c.NotificationMaxBackoff < c.NotificationRetryBackoff {
+		return fmt.Errorf("notification max backoff must be at least the retry backoff")
+	}
+	if _, err := time.Parse("15:04", c.NotificationDigestTime); err != nil {
+		return fmt.Errorf("invalid notification digest time %q, expected HH:MM", c.NotificationDigestTime)
+	}
+	if _, err := ParseWeekday(c.NotificationDigestWeekday); err != nil {
+		return err
+	}
+	if _, err := time.Parse("15:04", c.NotificationTodayAlertTime); err != nil {
+		return fmt.Errorf("invalid notification today alert time %q, expected HH:MM", c.NotificationTodayAlertTime)
+	}
+	if c.NotificationEscalationEnabled {
+		if c.NotificationEscalationInterval <= 0 {
+			return fmt.Errorf("notification escalation interval must be positive")
+		}
+		if c.NotificationEscalationRepeat <= 0 {
+			return fmt.Errorf("notification escalation repeat must be positive")
+		}
+		if c.NotificationEscalationMaxReminders < 0 {
+			return fmt.Errorf("notification escalation max reminders must not be negative")
+		}
+	}
+	digests := map[string]string{
+		"log":      c.NotificationLog.Digest,
+		"slack":    c.NotificationSlack.Digest,
+		"discord":  c.NotificationDiscord.Digest,
+		"telegram": c.NotificationTelegram.Digest,
+		"ntfy":     c.NotificationNtfy.Digest,
+		"pushover": c.NotificationPushover.Digest,
+		"gotify":   c.NotificationGotify.Digest,
+	}
+	for i, webhook := range c.NotificationWebhooks {
+		digests[fmt.Sprintf("webhook %d", i+1)] = webhook.Digest
+	}
+	for channel, digest := range digests {
+		if digest != "" && digest != "daily" && digest != "weekly" {
+			return fmt.Errorf("%s notifications have invalid digest %q (must be daily or weekly)", channel, digest)
+		}
+	}
+	if slack := c.NotificationSlack; slack.Enabled {
+		if slack.WebhookURL == "" && slack.BotToken == "" {
+			return fmt.Errorf("slack notifications need a webhook URL or a bot token")
+		}
+		if slack.WebhookURL == "" && slack.Channel == "" {
+			return fmt.Errorf("slack notifications with a bot token need a channel")
+		}
+	}
+	if discord := c.NotificationDiscord; discord.Enabled {
+		if discord.WebhookURL == "" && len(discord.Routes) == 0 {
+			return fmt.Errorf("discord notifications need a webhook URL or routes")
+		}
+		for i, route := range discord.Routes {
+			if route.WebhookURL == "" {
+				return fmt.Errorf("discord route %d (%s) needs a webhook URL", i+1, route.Name)
+			}
+		}
+	}
+	if telegram := c.NotificationTelegram; telegram.Enabled || telegram.CommandsEnabled {
+		if telegram.BotToken == "" {
+			return fmt.Errorf("telegram notifications need a bot token")
+		}
+		if len(telegram.ChatIDs) == 0 {
+			return fmt.Errorf("telegram notifications need at least one chat ID")
+		}
+	}
+	if c.NotificationNtfy.Enabled && c.NotificationNtfy.Topic == "" {
+		return fmt.Errorf("ntfy notifications need a topic")
+	}
+	if pushover := c.NotificationPushover; pushover.Enabled && (pushover.AppToken == "" || pushover.UserKey == "") {
+		return fmt.Errorf("pushover notifications need an app token and a user key")
+	}
+	if gotify := c.NotificationGotify; gotify.Enabled && (gotify.ServerURL == "" || gotify.AppToken == "") {
+		return fmt.Errorf("gotify notifications need a server URL and an app token")
+	}
+	for i, webhook := range c.NotificationWebhooks {
+		if webhook.URL == "" {
+			return fmt.Errorf("webhook %d (%s) needs a URL", i+1, webhook.Name)
+		}
+		switch webhook.Method {
+		case "", "POST", "PUT", "PATCH":
+		default:
+			return fmt.Errorf("webhook %d (%s) has unsupported method %s", i+1, webhook.Name, webhook.Method)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// ReloadReport lists the settings a configuration reload changed
+type ReloadReport struct {
+	Applied         []string `json:"applied"`          // Changed and in effect
+	RestartRequired []string `json:"restart_required"` // Changed, but only read at startup
+}
+
+// reloadableSetting is a setting that is read while running, so it can change without a
+// restart. Each is read at the start of the work that uses it, e.g. an update cycle.
+type reloadableSetting struct {
+	key   string // Configuration file key
+	field string // Config field
+}
+
+var reloadableSettings = []reloadableSetting{
+	{"update.interval", "UpdateInterval"},
+	{"update.batch_size", "AutoUpdateBatchSize"},
+	{"update.cutoff_days", "AutoUpdateCutoffDays"},
+	{"carriers.ups.auto_update_cutoff_days", "UPSAutoUpdateCutoffDays"},
+	{"carriers.dhl.auto_update_cutoff_days", "DHLAutoUpdateCutoffDays"},
+	{"carriers.usps.daily_api_budget", "USPSDailyAPIBudget"},
+	{"carriers.ups.daily_api_budget", "UPSDailyAPIBudget"},
+	{"carriers.dhl.daily_api_budget", "DHLDailyAPIBudget"},
+	{"rate_limit.disabled", "DisableRateLimit"},
+	{"cache.ttl", "CacheTTL"},
+}
+
+// notificationChannelFields are the Config fields of notification channels. Their status
+// and carrier filters and message templates can be reloaded; their other settings can't.
+var notificationChannelFields = map[string]string{
+	"NotificationLog":      "notifications.log",
+	"NotificationSlack":    "notifications.slack",
+	"NotificationDiscord":  "notifications.discord",
+	"NotificationTelegram": "notifications.telegram",
+	"NotificationNtfy":     "notifications.ntfy",
+	"NotificationPushover": "notifications.pushover",
+	"NotificationGotify":   "notifications.gotify",
+	"NotificationWebhooks": "notifications.webhooks",
+}
+
+// notificationFilterFields are the channel settings that can be reloaded
+var notificationFilterFields = map[string]bool{
+	"Statuses":        true,
+	"Carriers":        true,
+	"SubjectTemplate": true,
+	"BodyTemplate":    true,
+}
+
+// ApplyReload copies the settings that can change while running from next into c, and
+// reports them along with the changed settings that need a restart, which c keeps
+func (c *Config) ApplyReload(next *Config) ReloadReport {
+	report := ReloadReport{Applied: []string{}, RestartRequired: []string{}}
+	live := reflect.ValueOf(c).Elem()
+	updated := reflect.ValueOf(next).Elem()
+
+	reloadable := make(map[string]bool, len(reloadableSettings))
+	for _, setting := range reloadableSettings {
+		reloadable[setting.field] = true
+		from, to := live.FieldByName(setting.field), updated.FieldByName(setting.field)
+		if !reflect.DeepEqual(from.Interface(), to.Interface()) {
+			from.Set(to)
+			report.Applied = append(report.Applied, setting.key)
+		}
+	}
+
+	liveType := live.Type()
+	for i := 0; i < live.NumField(); i++ {
+		name := liveType.Field(i).Name
+		from, to := live.Field(i), updated.Field(i)
+		if reloadable[name] || reflect.DeepEqual(from.Interface(), to.Interface()) {
+			continue
+		}
+
+		if key, ok := notificationChannelFields[name]; ok && equalIgnoring(from, to, notificationFilterFields) {
+			from.Set(to)
+			report.Applied = append(report.Applied, key+" filters and templates")
+			continue
+		}
+		report.RestartRequired = append(report.RestartRequired, name)
+	}
+
+	sort.Strings(report.Applied)
+	sort.Strings(report.RestartRequired)
+	return report
+}
+
+// equalIgnoring reports whether a and b are deeply equal apart from struct fields named
+// in ignore, at any depth
+func equalIgnoring(a, b reflect.Value, ignore map[string]bool) bool {
+	switch a.Kind() {
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if ignore[a.Type().Field(i).Name] {
+				continue
+			}
+			if !equalIgnoring(a.Field(i), b.Field(i), ignore) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !equalIgnoring(a.Index(i), b.Index(i), ignore) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(a.Interface(), b.Interface())
+	}
+}
+
+// Reloader reloads the server configuration, from the configuration file and environment,
+// into the live configuration the server runs with. Components that copy settings at
+// startup register with OnReload to receive the new configuration.
+type Reloader struct {
+	mu    sync.Mutex
+	live  *Config
+	load  func() (*Config, error)
+	hooks []func(next *Config)
+}
+
+// NewReloader creates a reloader of live that reads the configuration with load, e.g.
+// LoadServerConfig
+func NewReloader(live *Config, load func() (*Config, error)) *Reloader {
+	return &Reloader{live: live, load: load}
+}
+
+// OnReload registers fn to be called with the new configuration after each reload
+func (r *Reloader) OnReload(fn func(next *Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = append(r.hooks, fn)
+}
+
+// Reload reads the configuration and applies the settings that can change while running.
+// Nothing is applied if the configuration is invalid.
+func (r *Reloader) Reload() (*ReloadReport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	report := r.live.ApplyReload(next)
+	for _, hook := range r.hooks {
+		hook(next)
+	}
+	return &report, nil
+}
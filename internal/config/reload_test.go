@@ -0,0 +1,108 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConfig_ApplyReload(t *testing.T) {
+	live := &Config{
+		ServerPort:        "8080",
+		UpdateInterval:    time.Hour,
+		CacheTTL:          5 * time.Minute,
+		NotificationSlack: SlackNotificationConfig{WebhookURL: "https://hooks.slack.test/a"},
+	}
+	next := *live
+	next.ServerPort = "9090"
+	next.UpdateInterval = 30 * time.Minute
+	next.DHLDailyAPIBudget = 100
+	next.DisableRateLimit = true
+	next.NotificationSlack.Statuses = []string{"delivered"}
+	next.NotificationDiscord.WebhookURL = "https://discord.test/hook"
+
+	report := live.ApplyReload(&next)
+
+	wantApplied := []string{
+		"carriers.dhl.daily_api_budget",
+		"notifications.slack filters and templates",
+		"rate_limit.disabled",
+		"update.interval",
+	}
+	if !reflect.DeepEqual(report.Applied, wantApplied) {
+		t.Errorf("Expected applied %v, got %v", wantApplied, report.Applied)
+	}
+	wantRestart := []string{"NotificationDiscord", "ServerPort"}
+	if !reflect.DeepEqual(report.RestartRequired, wantRestart) {
+		t.Errorf("Expected restart required %v, got %v", wantRestart, report.RestartRequired)
+	}
+
+	if live.UpdateInterval != 30*time.Minute || live.DHLDailyAPIBudget != 100 || !live.DisableRateLimit {
+		t.Errorf("Expected reloadable settings to be applied, got %+v", live)
+	}
+	if len(live.NotificationSlack.Statuses) != 1 {
+		t.Errorf("Expected the Slack filter to be applied, got %+v", live.NotificationSlack)
+	}
+	if live.ServerPort != "8080" || live.NotificationDiscord.WebhookURL != "" {
+		t.Errorf("Expected settings needing a restart to be kept, got %+v", live)
+	}
+}
+
+func TestReloader(t *testing.T) {
+	live := &Config{UpdateInterval: time.Hour}
+	next := &Config{UpdateInterval: time.Minute}
+	var loadErr error
+	reloader := NewReloader(live, func() (*Config, error) { return next, loadErr })
+
+	var hooked *Config
+	reloader.OnReload(func(cfg *Config) { hooked = cfg })
+
+	report, err := reloader.Reload()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(report.Applied) != 1 || live.UpdateInterval != time.Minute || hooked != next {
+		t.Errorf("Expected the interval to be applied and hooks called, got %+v", report)
+	}
+
+	// An invalid configuration applies nothing
+	hooked = nil
+	loadErr = errors.New("invalid update interval")
+	next = &Config{UpdateInterval: time.Second}
+	if _, err := reloader.Reload(); err == nil {
+		t.Error("Expected an error for an invalid configuration")
+	}
+	if live.UpdateInterval != time.Minute || hooked != nil {
+		t.Errorf("Expected nothing to be applied, got interval %v", live.UpdateInterval)
+	}
+}
+
+func TestConfigFileEnv(t *testing.T) {
+	clearEnvVars()
+	configFile := filepath.Join(t.TempDir(), "shared.yaml")
+	if err := os.WriteFile(configFile, []byte("server:\n  port: \"9191\"\nadmin:\n  auth_disabled: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ConfigFileEnv, configFile)
+
+	cfg, err := LoadServerConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.ServerPort != "9191" {
+		t.Errorf("Expected the port from %s, got %q", ConfigFileEnv, cfg.ServerPort)
+	}
+
+	// Environment variables override the file
+	t.Setenv("PKG_TRACKER_SERVER_PORT", "9292")
+	cfg, err = LoadServerConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.ServerPort != "9292" {
+		t.Errorf("Expected the environment to override the file, got %q", cfg.ServerPort)
+	}
+}
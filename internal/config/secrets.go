@@ -0,0 +1,231 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// secretSetting is a setting holding a credential. Like Docker secrets, each of its
+// environment variables has a _FILE variant naming a file to read the value from.
+type secretSetting struct {
+	key    string // Configuration key
+	newEnv string // New format variable, without the PKG_TRACKER_ prefix
+	oldEnv string // Old format variable; empty if there is none
+}
+
+// serverSecrets are the credentials of the server configuration
+var serverSecrets = []secretSetting{
+	{"carriers.usps.api_key", "CARRIERS_USPS_API_KEY", "USPS_API_KEY"},
+	{"carriers.ups.api_key", "CARRIERS_UPS_API_KEY", "UPS_API_KEY"},
+	{"carriers.ups.client_id", "CARRIERS_UPS_CLIENT_ID", "UPS_CLIENT_ID"},
+	{"carriers.ups.client_secret", "CARRIERS_UPS_CLIENT_SECRET", "UPS_CLIENT_SECRET"},
+	{"carriers.fedex.api_key", "CARRIERS_FEDEX_API_KEY", "FEDEX_API_KEY"},
+	{"carriers.fedex.secret_key", "CARRIERS_FEDEX_SECRET_KEY", "FEDEX_SECRET_KEY"},
+	{"carriers.dhl.api_key", "CARRIERS_DHL_API_KEY", "DHL_API_KEY"},
+	{"admin.api_key", "ADMIN_API_KEY", "ADMIN_API_KEY"},
+	{"mqtt.password", "MQTT_PASSWORD", "MQTT_PASSWORD"},
+	{"cache.redis_url", "CACHE_REDIS_URL", "CACHE_REDIS_URL"},
+	{"notifications.slack.webhook_url", "NOTIFICATIONS_SLACK_WEBHOOK_URL", "NOTIFICATIONS_SLACK_WEBHOOK_URL"},
+	{"notifications.slack.bot_token", "NOTIFICATIONS_SLACK_BOT_TOKEN", "NOTIFICATIONS_SLACK_BOT_TOKEN"},
+	{"notifications.discord.webhook_url", "NOTIFICATIONS_DISCORD_WEBHOOK_URL", "NOTIFICATIONS_DISCORD_WEBHOOK_URL"},
+	{"notifications.telegram.bot_token", "NOTIFICATIONS_TELEGRAM_BOT_TOKEN", "NOTIFICATIONS_TELEGRAM_BOT_TOKEN"},
+	{"notifications.ntfy.token", "NOTIFICATIONS_NTFY_TOKEN", "NOTIFICATIONS_NTFY_TOKEN"},
+	{"notifications.pushover.app_token", "NOTIFICATIONS_PUSHOVER_APP_TOKEN", "NOTIFICATIONS_PUSHOVER_APP_TOKEN"},
+	{"notifications.pushover.user_key", "NOTIFICATIONS_PUSHOVER_USER_KEY", "NOTIFICATIONS_PUSHOVER_USER_KEY"},
+	{"notifications.gotify.app_token", "NOTIFICATIONS_GOTIFY_APP_TOKEN", "NOTIFICATIONS_GOTIFY_APP_TOKEN"},
+	{"secrets.vault.token", "SECRETS_VAULT_TOKEN", "VAULT_TOKEN"},
+}
+
+// emailSecrets are the credentials of the email tracker configuration
+var emailSecrets = []secretSetting{
+	{"gmail.client_id", "EMAIL_GMAIL_CLIENT_ID", "GMAIL_CLIENT_ID"},
+	{"gmail.client_secret", "EMAIL_GMAIL_CLIENT_SECRET", "GMAIL_CLIENT_SECRET"},
+	{"gmail.refresh_token", "EMAIL_GMAIL_REFRESH_TOKEN", "GMAIL_REFRESH_TOKEN"},
+	{"gmail.access_token", "EMAIL_GMAIL_ACCESS_TOKEN", "GMAIL_ACCESS_TOKEN"},
+	{"gmail.app_password", "EMAIL_GMAIL_APP_PASSWORD", "GMAIL_APP_PASSWORD"},
+	{"llm.api_key", "LLM_API_KEY", "LLM_API_KEY"},
+	{"secrets.vault.token", "SECRETS_VAULT_TOKEN", "VAULT_TOKEN"},
+}
+
+// envNames returns the environment variables of a secret, new format first
+func (s secretSetting) envNames() []string {
+	names := []string{"PKG_TRACKER_" + s.newEnv}
+	if s.oldEnv != "" {
+		names = append(names, s.oldEnv)
+	}
+	return names
+}
+
+// bindSecretsEnv binds the settings of the external secret stores to environment
+// variables, including the variables Vault's own tools use
+func bindSecretsEnv(v *viper.Viper) {
+	v.BindEnv("secrets.vault.address", "PKG_TRACKER_SECRETS_VAULT_ADDRESS", "VAULT_ADDR")
+	v.BindEnv("secrets.vault.token", "PKG_TRACKER_SECRETS_VAULT_TOKEN", "VAULT_TOKEN")
+	v.BindEnv("secrets.vault.path", "PKG_TRACKER_SECRETS_VAULT_PATH")
+	v.BindEnv("secrets.sops_file", "PKG_TRACKER_SECRETS_SOPS_FILE")
+}
+
+// loadSecrets reads the credentials given as files, then merges in those from Vault and
+// SOPS. Values from files take the place of environment variables; values from Vault and
+// SOPS take the place of the configuration file, so the environment still overrides them.
+func loadSecrets(v *viper.Viper, secrets []secretSetting) error {
+	if err := loadSecretFiles(v, secrets); err != nil {
+		return err
+	}
+	if err := loadVaultSecrets(v); err != nil {
+		return err
+	}
+	return loadSOPSSecrets(v)
+}
+
+// loadSecretFiles sets each secret whose _FILE variable is set to the contents of the file
+func loadSecretFiles(v *viper.Viper, secrets []secretSetting) error {
+	for _, secret := range secrets {
+		for _, name := range secret.envNames() {
+			path := os.Getenv(name + "_FILE")
+			if path == "" {
+				continue
+			}
+			if os.Getenv(name) != "" {
+				return fmt.Errorf("both %s and %s_FILE are set", name, name)
+			}
+			value, err := readSecretFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read %s_FILE: %w", name, err)
+			}
+			v.Set(secret.key, value)
+			break
+		}
+	}
+	return nil
+}
+
+// readSecretFile returns the contents of a secret file without its trailing newline
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// getSecretEnv returns an environment variable, or the contents of the file its _FILE
+// variant names, for the configuration loaders that read the environment directly
+func getSecretEnv(key string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	path := os.Getenv(key + "_FILE")
+	if path == "" {
+		return ""
+	}
+	value, err := readSecretFile(path)
+	if err != nil {
+		slog.Warn("Failed to read secret file", "variable", key+"_FILE", "error", err)
+		return ""
+	}
+	return value
+}
+
+// vaultTimeout bounds the request for secrets from Vault
+const vaultTimeout = 10 * time.Second
+
+// loadVaultSecrets merges the secret at secrets.vault.path, if set, into the configuration.
+// The secret's keys are configuration keys such as carriers.ups.client_secret. Both
+// versions of the KV secrets engine are supported; with version 2 the path includes data/,
+// e.g. secret/data/package-tracker.
+func loadVaultSecrets(v *viper.Viper) error {
+	path := strings.Trim(v.GetString("secrets.vault.path"), "/")
+	if path == "" {
+		return nil
+	}
+	address := strings.TrimRight(v.GetString("secrets.vault.address"), "/")
+	if address == "" {
+		return fmt.Errorf("secrets.vault.address is required with secrets.vault.path")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, address+"/v1/"+path, nil)
+	if err != nil {
+		return fmt.Errorf("invalid Vault address: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", v.GetString("secrets.vault.token"))
+
+	client := &http.Client{Timeout: vaultTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to read Vault secret %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to read Vault secret %s: status %d", path, resp.StatusCode)
+	}
+
+	var secret struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return fmt.Errorf("invalid Vault response: %w", err)
+	}
+	values := secret.Data
+	// Version 2 nests the values under data, next to the version metadata
+	if nested, ok := values["data"].(map[string]any); ok {
+		if _, ok := values["metadata"]; ok {
+			values = nested
+		}
+	}
+	return v.MergeConfigMap(nestKeys(values))
+}
+
+// loadSOPSSecrets decrypts the file at secrets.sops_file, if set, with the sops command
+// and merges it into the configuration. It has the same layout as the configuration file.
+func loadSOPSSecrets(v *viper.Viper) error {
+	path := v.GetString("secrets.sops_file")
+	if path == "" {
+		return nil
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("sops", "--decrypt", path)
+	cmd.Stderr = &stderr
+	decrypted, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to decrypt %s with sops: %w: %s", path, err, strings.TrimSpace(stderr.String()))
+	}
+
+	secrets := viper.New()
+	secrets.SetConfigType(strings.TrimPrefix(filepath.Ext(path), "."))
+	if err := secrets.ReadConfig(bytes.NewReader(decrypted)); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return v.MergeConfigMap(secrets.AllSettings())
+}
+
+// nestKeys turns dotted keys such as carriers.ups.client_secret into nested maps, the
+// form configuration files take
+func nestKeys(values map[string]any) map[string]any {
+	nested := make(map[string]any)
+	for key, value := range values {
+		parts := strings.Split(strings.ToLower(key), ".")
+		current := nested
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := current[part].(map[string]any)
+			if !ok {
+				next = make(map[string]any)
+				current[part] = next
+			}
+			current = next
+		}
+		current[parts[len(parts)-1]] = value
+	}
+	return nested
+}
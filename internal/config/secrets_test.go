@@ -0,0 +1,135 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// unsetEnv clears environment variables for the rest of the test
+func unsetEnv(t *testing.T, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		t.Setenv(name, "")
+	}
+}
+
+// writeSecret writes a secret file in a temporary directory and returns its path
+func writeSecret(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadServerConfig_SecretFiles(t *testing.T) {
+	clearEnvVars()
+	unsetEnv(t, "UPS_CLIENT_SECRET", "PKG_TRACKER_CARRIERS_UPS_CLIENT_SECRET", "ADMIN_API_KEY", "PKG_TRACKER_ADMIN_API_KEY")
+	t.Setenv("PKG_TRACKER_CARRIERS_UPS_CLIENT_SECRET_FILE", writeSecret(t, "ups", "ups-secret\n"))
+	t.Setenv("ADMIN_API_KEY_FILE", writeSecret(t, "admin", "admin-key"))
+
+	config, err := LoadServerConfigWithViper(viper.New())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.UPSClientSecret != "ups-secret" {
+		t.Errorf("Expected the UPS client secret from its file, got %q", config.UPSClientSecret)
+	}
+	if config.AdminAPIKey != "admin-key" {
+		t.Errorf("Expected the admin key from its old format file, got %q", config.AdminAPIKey)
+	}
+
+	// A secret can't be given both ways
+	t.Setenv("PKG_TRACKER_CARRIERS_UPS_CLIENT_SECRET", "other-secret")
+	if _, err := LoadServerConfigWithViper(viper.New()); err == nil {
+		t.Error("Expected an error for a secret set both directly and as a file")
+	}
+}
+
+func TestLoadEmailConfig_SecretFiles(t *testing.T) {
+	clearEmailEnvVars()
+	unsetEnv(t, "GMAIL_APP_PASSWORD", "PKG_TRACKER_EMAIL_GMAIL_APP_PASSWORD")
+	t.Setenv("PKG_TRACKER_EMAIL_GMAIL_USERNAME", "test@gmail.com")
+	t.Setenv("GMAIL_APP_PASSWORD_FILE", writeSecret(t, "gmail", "app-password\n"))
+
+	config, err := LoadEmailConfigWithViper(viper.New())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.Gmail.AppPassword != "app-password" {
+		t.Errorf("Expected the app password from its file, got %q", config.Gmail.AppPassword)
+	}
+}
+
+func TestLoadServerConfig_VaultSecrets(t *testing.T) {
+	clearEnvVars()
+	unsetEnv(t, "DHL_API_KEY", "PKG_TRACKER_CARRIERS_DHL_API_KEY", "ADMIN_API_KEY")
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/secret/data/package-tracker" || r.Header.Get("X-Vault-Token") != "vault-token" {
+			http.Error(w, "permission denied", http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"data": {"data": {"carriers.dhl.api_key": "dhl-key", "admin.api_key": "vault-admin"}, "metadata": {"version": 3}}}`))
+	}))
+	defer vault.Close()
+
+	t.Setenv("VAULT_ADDR", vault.URL)
+	t.Setenv("VAULT_TOKEN_FILE", writeSecret(t, "token", "vault-token\n"))
+	t.Setenv("PKG_TRACKER_SECRETS_VAULT_PATH", "secret/data/package-tracker")
+	t.Setenv("PKG_TRACKER_ADMIN_API_KEY", "env-admin")
+
+	config, err := LoadServerConfigWithViper(viper.New())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.DHLAPIKey != "dhl-key" {
+		t.Errorf("Expected the DHL key from Vault, got %q", config.DHLAPIKey)
+	}
+	if config.AdminAPIKey != "env-admin" {
+		t.Errorf("Expected the environment to override Vault, got %q", config.AdminAPIKey)
+	}
+
+	t.Setenv("VAULT_TOKEN_FILE", writeSecret(t, "token", "wrong-token"))
+	if _, err := LoadServerConfigWithViper(viper.New()); err == nil {
+		t.Error("Expected an error when Vault denies access")
+	}
+}
+
+func TestLoadServerConfig_SOPSSecrets(t *testing.T) {
+	clearEnvVars()
+	unsetEnv(t, "FEDEX_API_KEY", "PKG_TRACKER_CARRIERS_FEDEX_API_KEY", "ADMIN_API_KEY", "PKG_TRACKER_ADMIN_API_KEY")
+	// Stand in for sops with a script that prints the file as if decrypted
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "sops"), []byte("#!/bin/sh\ncat \"$2\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("PKG_TRACKER_SECRETS_SOPS_FILE", writeSecret(t, "secrets.enc.yaml",
+		"carriers:\n  fedex:\n    api_key: fedex-key\nadmin:\n  api_key: sops-admin\n"))
+
+	config, err := LoadServerConfigWithViper(viper.New())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.FedExAPIKey != "fedex-key" || config.AdminAPIKey != "sops-admin" {
+		t.Errorf("Expected the keys from the SOPS file, got %q and %q", config.FedExAPIKey, config.AdminAPIKey)
+	}
+}
+
+func TestGetSecretEnv(t *testing.T) {
+	t.Setenv("TEST_SECRET_FILE", writeSecret(t, "secret", "from-file\n"))
+	if value := getSecretEnv("TEST_SECRET"); value != "from-file" {
+		t.Errorf("Expected the value from the file, got %q", value)
+	}
+
+	t.Setenv("TEST_SECRET", "from-env")
+	if value := getSecretEnv("TEST_SECRET"); value != "from-env" {
+		t.Errorf("Expected the variable to take precedence, got %q", value)
+	}
+}
@@ -158,7 +158,7 @@ func validateCLIConfig(config *cli.Config) error {
 	}
 
 	// Validate format
-	validFormats := []string{"table", "json"}
+	validFormats := []string{"table", "json", "csv", "yaml"}
 	isValidFormat := false
 	for _, format := range validFormats {
 		if config.Format == format {
@@ -167,7 +167,7 @@ func validateCLIConfig(config *cli.Config) error {
 		}
 	}
 	if !isValidFormat {
-		return fmt.Errorf("invalid format: %s (must be one of: table, json)", config.Format)
+		return fmt.Errorf("invalid format: %s (must be one of: table, json, csv, yaml)", config.Format)
 	}
 
 	// Validate timeout with reasonable ranges
@@ -238,7 +238,7 @@ func TestCLIViperConfig_ValidationErrors(t *testing.T) {
 			envVars: map[string]string{
 				"PKG_TRACKER_CLI_FORMAT": "invalid-format",
 			},
-			errorMsg: "invalid configuration: invalid format: invalid-format (must be one of: table, json)",
+			errorMsg: "invalid configuration: invalid format: invalid-format (must be one of: table, json, csv, yaml)",
 		},
 		{
 			name: "negative timeout",
@@ -3,6 +3,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -16,12 +17,18 @@ func LoadEmailConfigWithViper(v *viper.Viper) (*EmailConfig, error) {
 
 	// Set up environment variable binding
 	setupEmailEnvBinding(v)
+	bindSecretsEnv(v)
 
 	// Load configuration file if specified
 	if err := loadEmailConfigFile(v); err != nil {
 		return nil, fmt.Errorf("failed to load config file: %w", err)
 	}
 
+	// Load credentials from files, Vault and SOPS
+	if err := loadSecrets(v, emailSecrets); err != nil {
+		return nil, fmt.Errorf("failed to load secrets: %w", err)
+	}
+
 	// Unmarshal configuration
 	config := &EmailConfig{}
 	if err := unmarshalEmailConfig(v, config); err != nil {
@@ -240,7 +247,9 @@ func setupEmailEnvBinding(v *viper.Viper) {
 // loadEmailConfigFile loads configuration file if it exists
 func loadEmailConfigFile(v *viper.Viper) error {
 	// Check if a specific config file was set
-	if v.ConfigFileUsed() == "" {
+	if v.ConfigFileUsed() == "" && os.Getenv(ConfigFileEnv) != "" {
+		v.SetConfigFile(os.Getenv(ConfigFileEnv))
+	} else if v.ConfigFileUsed() == "" {
 		// Add configuration search paths
 		v.AddConfigPath(".")
 		v.AddConfigPath("./config")
@@ -3,6 +3,8 @@ package config
 import (
 	"errors"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -15,12 +17,18 @@ func LoadServerConfigWithViper(v *viper.Viper) (*Config, error) {
 
 	// Set up environment variable binding
 	setupServerEnvBinding(v)
+	bindSecretsEnv(v)
 
 	// Load configuration file if specified
 	if err := loadConfigFile(v); err != nil {
 		return nil, fmt.Errorf("failed to load config file: %w", err)
 	}
 
+	// Load credentials from files, Vault and SOPS
+	if err := loadSecrets(v, serverSecrets); err != nil {
+		return nil, fmt.Errorf("failed to load secrets: %w", err)
+	}
+
 	// Unmarshal configuration
 	config := &Config{}
 	if err := unmarshalServerConfig(v, config); err != nil {
@@ -40,6 +48,7 @@ func setServerDefaults(v *viper.Viper) {
 	// Server defaults
 	v.SetDefault("server.port", "8080")
 	v.SetDefault("server.host", "localhost")
+	v.SetDefault("server.reuse_port", false)
 
 	// Database defaults
 	v.SetDefault("database.path", "./database.db")
@@ -56,6 +65,55 @@ func setServerDefaults(v *viper.Viper) {
 	v.SetDefault("update.failure_threshold", 10)
 	v.SetDefault("update.batch_timeout", "60s")
 	v.SetDefault("update.individual_timeout", "30s")
+	v.SetDefault("update.catchup_enabled", true)
+	v.SetDefault("update.catchup_threshold", 3)
+	v.SetDefault("update.catchup_delay", "500ms")
+
+	// Email maintenance defaults
+	v.SetDefault("maintenance.email_cleanup_enabled", true)
+	v.SetDefault("maintenance.email_cleanup_interval", "24h")
+	v.SetDefault("maintenance.email_body_retention_days", 90)
+
+	// Stalled shipment detection defaults
+	v.SetDefault("stalled.enabled", true)
+	v.SetDefault("stalled.check_interval", "6h")
+	v.SetDefault("stalled.threshold_days", 7)
+	v.SetDefault("stalled.carrier_threshold_days", "")
+	v.SetDefault("stalled.webhook_url", "")
+
+	// Notification defaults
+	v.SetDefault("notifications.enabled", true)
+	v.SetDefault("notifications.queue_size", 100)
+	v.SetDefault("notifications.max_attempts", 3)
+	v.SetDefault("notifications.retry_backoff", "10s")
+	v.SetDefault("notifications.max_backoff", "5m")
+	v.SetDefault("notifications.base_url", "")
+	v.SetDefault("notifications.digest_time", "08:00")
+	v.SetDefault("notifications.digest_weekday", "monday")
+	v.SetDefault("notifications.today_alert_time", "07:00")
+	v.SetDefault("notifications.escalation.enabled", false)
+	v.SetDefault("notifications.escalation.interval", "15m")
+	v.SetDefault("notifications.escalation.repeat", "12h")
+	v.SetDefault("notifications.escalation.max_reminders", 3)
+
+	// MQTT defaults
+	v.SetDefault("mqtt.enabled", false)
+	v.SetDefault("mqtt.broker_url", "")
+	v.SetDefault("mqtt.username", "")
+	v.SetDefault("mqtt.password", "")
+	v.SetDefault("mqtt.client_id", "package-tracker")
+	v.SetDefault("mqtt.topic_prefix", "package_tracker")
+	v.SetDefault("mqtt.discovery_prefix", "homeassistant")
+	v.SetDefault("mqtt.sync_interval", "5m")
+	v.SetDefault("mqtt.delivered_retention", "48h")
+	setNotificationChannelDefaults(v, "notifications.log")
+	setNotificationChannelDefaults(v, "notifications.slack", "webhook_url", "bot_token", "channel")
+	setNotificationChannelDefaults(v, "notifications.discord", "webhook_url", "username")
+	setNotificationChannelDefaults(v, "notifications.telegram", "bot_token", "chat_ids")
+	v.SetDefault("notifications.telegram.commands_enabled", false)
+	setNotificationChannelDefaults(v, "notifications.ntfy", "server_url", "topic", "token", "priorities")
+	setNotificationChannelDefaults(v, "notifications.pushover", "app_token", "user_key", "device", "priorities")
+	setNotificationChannelDefaults(v, "notifications.gotify", "server_url", "app_token", "priorities")
 
 	// Per-carrier auto-update defaults
 	v.SetDefault("carriers.ups.auto_update_enabled", true)
@@ -63,9 +121,18 @@ func setServerDefaults(v *viper.Viper) {
 	v.SetDefault("carriers.dhl.auto_update_enabled", true)
 	v.SetDefault("carriers.dhl.auto_update_cutoff_days", 0)
 
+	// Per-carrier daily API budget defaults (0 means unlimited)
+	v.SetDefault("carriers.usps.daily_api_budget", 0)
+	v.SetDefault("carriers.ups.daily_api_budget", 0)
+	v.SetDefault("carriers.dhl.daily_api_budget", 250)
+
 	// Cache defaults
 	v.SetDefault("cache.ttl", "5m")
 	v.SetDefault("cache.disabled", false)
+	v.SetDefault("cache.memory_size", 1000)
+	v.SetDefault("cache.backend", "sqlite")
+	v.SetDefault("cache.redis_url", "redis://localhost:6379/0")
+	v.SetDefault("cache.redis_prefix", "package-tracking:")
 
 	// Development/testing defaults
 	v.SetDefault("rate_limit.disabled", false)
@@ -73,6 +140,10 @@ func setServerDefaults(v *viper.Viper) {
 	// Admin defaults
 	v.SetDefault("admin.auth_disabled", false)
 	v.SetDefault("admin.api_key", "")
+	v.SetDefault("admin.auth_mode", "api_key")
+	v.SetDefault("auth.proxy.trusted_proxies", []string{"127.0.0.1", "::1"})
+	v.SetDefault("auth.proxy.user_headers", []string{"Remote-User", "X-Forwarded-User"})
+	v.SetDefault("auth.proxy.users", []string{})
 
 	// FedEx defaults
 	v.SetDefault("carriers.fedex.api_url", "https://apis.fedex.com")
@@ -86,35 +157,85 @@ func setupServerEnvBinding(v *viper.Viper) {
 
 	// Bind new format environment variables
 	envBindings := map[string]string{
-		"server.port":                          "SERVER_PORT",
-		"server.host":                          "SERVER_HOST",
-		"database.path":                        "DATABASE_PATH",
-		"logging.level":                        "LOGGING_LEVEL",
-		"update.interval":                      "UPDATE_INTERVAL",
-		"update.auto_enabled":                  "UPDATE_AUTO_ENABLED",
-		"update.cutoff_days":                   "UPDATE_CUTOFF_DAYS",
-		"update.batch_size":                    "UPDATE_BATCH_SIZE",
-		"update.max_retries":                   "UPDATE_MAX_RETRIES",
-		"update.failure_threshold":             "UPDATE_FAILURE_THRESHOLD",
-		"update.batch_timeout":                 "UPDATE_BATCH_TIMEOUT",
-		"update.individual_timeout":            "UPDATE_INDIVIDUAL_TIMEOUT",
-		"carriers.usps.api_key":                "CARRIERS_USPS_API_KEY",
-		"carriers.ups.api_key":                 "CARRIERS_UPS_API_KEY",
-		"carriers.ups.client_id":               "CARRIERS_UPS_CLIENT_ID",
-		"carriers.ups.client_secret":           "CARRIERS_UPS_CLIENT_SECRET",
-		"carriers.ups.auto_update_enabled":     "CARRIERS_UPS_AUTO_UPDATE_ENABLED",
-		"carriers.ups.auto_update_cutoff_days": "CARRIERS_UPS_AUTO_UPDATE_CUTOFF_DAYS",
-		"carriers.fedex.api_key":               "CARRIERS_FEDEX_API_KEY",
-		"carriers.fedex.secret_key":            "CARRIERS_FEDEX_SECRET_KEY",
-		"carriers.fedex.api_url":               "CARRIERS_FEDEX_API_URL",
-		"carriers.dhl.api_key":                 "CARRIERS_DHL_API_KEY",
-		"carriers.dhl.auto_update_enabled":     "CARRIERS_DHL_AUTO_UPDATE_ENABLED",
-		"carriers.dhl.auto_update_cutoff_days": "CARRIERS_DHL_AUTO_UPDATE_CUTOFF_DAYS",
-		"cache.ttl":                            "CACHE_TTL",
-		"cache.disabled":                       "CACHE_DISABLED",
-		"rate_limit.disabled":                  "RATE_LIMIT_DISABLED",
-		"admin.api_key":                        "ADMIN_API_KEY",
-		"admin.auth_disabled":                  "ADMIN_AUTH_DISABLED",
+		"server.port":                           "SERVER_PORT",
+		"server.host":                           "SERVER_HOST",
+		"server.reuse_port":                     "SERVER_REUSE_PORT",
+		"database.path":                         "DATABASE_PATH",
+		"logging.level":                         "LOGGING_LEVEL",
+		"update.interval":                       "UPDATE_INTERVAL",
+		"update.auto_enabled":                   "UPDATE_AUTO_ENABLED",
+		"update.cutoff_days":                    "UPDATE_CUTOFF_DAYS",
+		"update.batch_size":                     "UPDATE_BATCH_SIZE",
+		"update.max_retries":                    "UPDATE_MAX_RETRIES",
+		"update.failure_threshold":              "UPDATE_FAILURE_THRESHOLD",
+		"update.batch_timeout":                  "UPDATE_BATCH_TIMEOUT",
+		"update.individual_timeout":             "UPDATE_INDIVIDUAL_TIMEOUT",
+		"update.catchup_enabled":                "UPDATE_CATCHUP_ENABLED",
+		"update.catchup_threshold":              "UPDATE_CATCHUP_THRESHOLD",
+		"update.catchup_delay":                  "UPDATE_CATCHUP_DELAY",
+		"maintenance.email_cleanup_enabled":     "MAINTENANCE_EMAIL_CLEANUP_ENABLED",
+		"maintenance.email_cleanup_interval":    "MAINTENANCE_EMAIL_CLEANUP_INTERVAL",
+		"maintenance.email_body_retention_days": "MAINTENANCE_EMAIL_BODY_RETENTION_DAYS",
+		"stalled.enabled":                       "STALLED_ENABLED",
+		"stalled.check_interval":                "STALLED_CHECK_INTERVAL",
+		"stalled.threshold_days":                "STALLED_THRESHOLD_DAYS",
+		"stalled.carrier_threshold_days":        "STALLED_CARRIER_THRESHOLD_DAYS",
+		"stalled.webhook_url":                   "STALLED_WEBHOOK_URL",
+		"notifications.enabled":                 "NOTIFICATIONS_ENABLED",
+		"notifications.queue_size":              "NOTIFICATIONS_QUEUE_SIZE",
+		"notifications.max_attempts":            "NOTIFICATIONS_MAX_ATTEMPTS",
+		"notifications.retry_backoff":           "NOTIFICATIONS_RETRY_BACKOFF",
+		"notifications.max_backoff":             "NOTIFICATIONS_MAX_BACKOFF",
+		"notifications.base_url":                "NOTIFICATIONS_BASE_URL",
+		"notifications.digest_time":             "NOTIFICATIONS_DIGEST_TIME",
+		"notifications.digest_weekday":          "NOTIFICATIONS_DIGEST_WEEKDAY",
+		"notifications.today_alert_time":        "NOTIFICATIONS_TODAY_ALERT_TIME",
+
+		"notifications.escalation.enabled":       "NOTIFICATIONS_ESCALATION_ENABLED",
+		"notifications.escalation.interval":      "NOTIFICATIONS_ESCALATION_INTERVAL",
+		"notifications.escalation.repeat":        "NOTIFICATIONS_ESCALATION_REPEAT",
+		"notifications.escalation.max_reminders": "NOTIFICATIONS_ESCALATION_MAX_REMINDERS",
+
+		"mqtt.enabled":                          "MQTT_ENABLED",
+		"mqtt.broker_url":                       "MQTT_BROKER_URL",
+		"mqtt.username":                         "MQTT_USERNAME",
+		"mqtt.password":                         "MQTT_PASSWORD",
+		"mqtt.client_id":                        "MQTT_CLIENT_ID",
+		"mqtt.topic_prefix":                     "MQTT_TOPIC_PREFIX",
+		"mqtt.discovery_prefix":                 "MQTT_DISCOVERY_PREFIX",
+		"mqtt.sync_interval":                    "MQTT_SYNC_INTERVAL",
+		"mqtt.delivered_retention":              "MQTT_DELIVERED_RETENTION",
+		"carriers.usps.api_key":                 "CARRIERS_USPS_API_KEY",
+		"carriers.ups.api_key":                  "CARRIERS_UPS_API_KEY",
+		"carriers.ups.client_id":                "CARRIERS_UPS_CLIENT_ID",
+		"carriers.ups.client_secret":            "CARRIERS_UPS_CLIENT_SECRET",
+		"carriers.ups.auto_update_enabled":      "CARRIERS_UPS_AUTO_UPDATE_ENABLED",
+		"carriers.ups.auto_update_cutoff_days":  "CARRIERS_UPS_AUTO_UPDATE_CUTOFF_DAYS",
+		"carriers.fedex.api_key":                "CARRIERS_FEDEX_API_KEY",
+		"carriers.fedex.secret_key":             "CARRIERS_FEDEX_SECRET_KEY",
+		"carriers.fedex.api_url":                "CARRIERS_FEDEX_API_URL",
+		"carriers.dhl.api_key":                  "CARRIERS_DHL_API_KEY",
+		"carriers.dhl.auto_update_enabled":      "CARRIERS_DHL_AUTO_UPDATE_ENABLED",
+		"carriers.dhl.auto_update_cutoff_days":  "CARRIERS_DHL_AUTO_UPDATE_CUTOFF_DAYS",
+		"carriers.usps.daily_api_budget":        "CARRIERS_USPS_DAILY_API_BUDGET",
+		"carriers.ups.daily_api_budget":         "CARRIERS_UPS_DAILY_API_BUDGET",
+		"carriers.dhl.daily_api_budget":         "CARRIERS_DHL_DAILY_API_BUDGET",
+		"cache.ttl":                             "CACHE_TTL",
+		"cache.memory_size":                     "CACHE_MEMORY_SIZE",
+		"cache.backend":                         "CACHE_BACKEND",
+		"cache.redis_url":                       "CACHE_REDIS_URL",
+		"cache.redis_prefix":                    "CACHE_REDIS_PREFIX",
+		"cache.disabled":                        "CACHE_DISABLED",
+		"rate_limit.disabled":                   "RATE_LIMIT_DISABLED",
+		"admin.api_key":                         "ADMIN_API_KEY",
+		"admin.auth_disabled":                   "ADMIN_AUTH_DISABLED",
+		"admin.auth_mode":                       "ADMIN_AUTH_MODE",
+		"auth.proxy.trusted_proxies":            "AUTH_PROXY_TRUSTED_PROXIES",
+		"auth.proxy.user_headers":               "AUTH_PROXY_USER_HEADERS",
+		"auth.proxy.users":                      "AUTH_PROXY_USERS",
+		"server.tls.cert_file":                  "SERVER_TLS_CERT_FILE",
+		"server.tls.key_file":                   "SERVER_TLS_KEY_FILE",
+		"server.tls.client_ca_file":             "SERVER_TLS_CLIENT_CA_FILE",
 	}
 
 	for configKey, envSuffix := range envBindings {
@@ -123,49 +244,114 @@ func setupServerEnvBinding(v *viper.Viper) {
 
 	// Bind old format environment variables for backward compatibility
 	oldEnvBindings := map[string]string{
-		"server.port":                          "SERVER_PORT",
-		"server.host":                          "SERVER_HOST",
-		"database.path":                        "DB_PATH",
-		"logging.level":                        "LOG_LEVEL",
-		"update.interval":                      "UPDATE_INTERVAL",
-		"update.auto_enabled":                  "AUTO_UPDATE_ENABLED",
-		"update.cutoff_days":                   "AUTO_UPDATE_CUTOFF_DAYS",
-		"update.batch_size":                    "AUTO_UPDATE_BATCH_SIZE",
-		"update.max_retries":                   "AUTO_UPDATE_MAX_RETRIES",
-		"update.failure_threshold":             "AUTO_UPDATE_FAILURE_THRESHOLD",
-		"update.batch_timeout":                 "AUTO_UPDATE_BATCH_TIMEOUT",
-		"update.individual_timeout":            "AUTO_UPDATE_INDIVIDUAL_TIMEOUT",
-		"carriers.usps.api_key":                "USPS_API_KEY",
-		"carriers.ups.api_key":                 "UPS_API_KEY",
-		"carriers.ups.client_id":               "UPS_CLIENT_ID",
-		"carriers.ups.client_secret":           "UPS_CLIENT_SECRET",
-		"carriers.ups.auto_update_enabled":     "UPS_AUTO_UPDATE_ENABLED",
-		"carriers.ups.auto_update_cutoff_days": "UPS_AUTO_UPDATE_CUTOFF_DAYS",
-		"carriers.fedex.api_key":               "FEDEX_API_KEY",
-		"carriers.fedex.secret_key":            "FEDEX_SECRET_KEY",
-		"carriers.fedex.api_url":               "FEDEX_API_URL",
-		"carriers.dhl.api_key":                 "DHL_API_KEY",
-		"carriers.dhl.auto_update_enabled":     "DHL_AUTO_UPDATE_ENABLED",
-		"carriers.dhl.auto_update_cutoff_days": "DHL_AUTO_UPDATE_CUTOFF_DAYS",
-		"cache.ttl":                            "CACHE_TTL",
-		"cache.disabled":                       "DISABLE_CACHE",
-		"rate_limit.disabled":                  "DISABLE_RATE_LIMIT",
-		"admin.api_key":                        "ADMIN_API_KEY",
-		"admin.auth_disabled":                  "DISABLE_ADMIN_AUTH",
+		"server.port":                           "SERVER_PORT",
+		"server.host":                           "SERVER_HOST",
+		"server.reuse_port":                     "SERVER_REUSE_PORT",
+		"database.path":                         "DB_PATH",
+		"logging.level":                         "LOG_LEVEL",
+		"update.interval":                       "UPDATE_INTERVAL",
+		"update.auto_enabled":                   "AUTO_UPDATE_ENABLED",
+		"update.cutoff_days":                    "AUTO_UPDATE_CUTOFF_DAYS",
+		"update.batch_size":                     "AUTO_UPDATE_BATCH_SIZE",
+		"update.max_retries":                    "AUTO_UPDATE_MAX_RETRIES",
+		"update.failure_threshold":              "AUTO_UPDATE_FAILURE_THRESHOLD",
+		"update.batch_timeout":                  "AUTO_UPDATE_BATCH_TIMEOUT",
+		"update.individual_timeout":             "AUTO_UPDATE_INDIVIDUAL_TIMEOUT",
+		"update.catchup_enabled":                "AUTO_UPDATE_CATCHUP_ENABLED",
+		"update.catchup_threshold":              "AUTO_UPDATE_CATCHUP_THRESHOLD",
+		"update.catchup_delay":                  "AUTO_UPDATE_CATCHUP_DELAY",
+		"maintenance.email_cleanup_enabled":     "EMAIL_CLEANUP_ENABLED",
+		"maintenance.email_cleanup_interval":    "EMAIL_CLEANUP_INTERVAL",
+		"maintenance.email_body_retention_days": "EMAIL_BODY_RETENTION_DAYS",
+		"stalled.enabled":                       "STALLED_DETECTION_ENABLED",
+		"stalled.check_interval":                "STALLED_CHECK_INTERVAL",
+		"stalled.threshold_days":                "STALLED_THRESHOLD_DAYS",
+		"stalled.carrier_threshold_days":        "STALLED_CARRIER_THRESHOLD_DAYS",
+		"stalled.webhook_url":                   "STALLED_WEBHOOK_URL",
+		"notifications.enabled":                 "NOTIFICATIONS_ENABLED",
+		"notifications.queue_size":              "NOTIFICATIONS_QUEUE_SIZE",
+		"notifications.max_attempts":            "NOTIFICATIONS_MAX_ATTEMPTS",
+		"notifications.retry_backoff":           "NOTIFICATIONS_RETRY_BACKOFF",
+		"notifications.max_backoff":             "NOTIFICATIONS_MAX_BACKOFF",
+		"notifications.base_url":                "NOTIFICATIONS_BASE_URL",
+		"notifications.digest_time":             "NOTIFICATIONS_DIGEST_TIME",
+		"notifications.digest_weekday":          "NOTIFICATIONS_DIGEST_WEEKDAY",
+		"notifications.today_alert_time":        "NOTIFICATIONS_TODAY_ALERT_TIME",
+
+		"notifications.escalation.enabled":       "NOTIFICATIONS_ESCALATION_ENABLED",
+		"notifications.escalation.interval":      "NOTIFICATIONS_ESCALATION_INTERVAL",
+		"notifications.escalation.repeat":        "NOTIFICATIONS_ESCALATION_REPEAT",
+		"notifications.escalation.max_reminders": "NOTIFICATIONS_ESCALATION_MAX_REMINDERS",
+
+		"mqtt.enabled":                          "MQTT_ENABLED",
+		"mqtt.broker_url":                       "MQTT_BROKER_URL",
+		"mqtt.username":                         "MQTT_USERNAME",
+		"mqtt.password":                         "MQTT_PASSWORD",
+		"mqtt.client_id":                        "MQTT_CLIENT_ID",
+		"mqtt.topic_prefix":                     "MQTT_TOPIC_PREFIX",
+		"mqtt.discovery_prefix":                 "MQTT_DISCOVERY_PREFIX",
+		"mqtt.sync_interval":                    "MQTT_SYNC_INTERVAL",
+		"mqtt.delivered_retention":              "MQTT_DELIVERED_RETENTION",
+		"carriers.usps.api_key":                 "USPS_API_KEY",
+		"carriers.ups.api_key":                  "UPS_API_KEY",
+		"carriers.ups.client_id":                "UPS_CLIENT_ID",
+		"carriers.ups.client_secret":            "UPS_CLIENT_SECRET",
+		"carriers.ups.auto_update_enabled":      "UPS_AUTO_UPDATE_ENABLED",
+		"carriers.ups.auto_update_cutoff_days":  "UPS_AUTO_UPDATE_CUTOFF_DAYS",
+		"carriers.fedex.api_key":                "FEDEX_API_KEY",
+		"carriers.fedex.secret_key":             "FEDEX_SECRET_KEY",
+		"carriers.fedex.api_url":                "FEDEX_API_URL",
+		"carriers.dhl.api_key":                  "DHL_API_KEY",
+		"carriers.dhl.auto_update_enabled":      "DHL_AUTO_UPDATE_ENABLED",
+		"carriers.dhl.auto_update_cutoff_days":  "DHL_AUTO_UPDATE_CUTOFF_DAYS",
+		"carriers.usps.daily_api_budget":        "USPS_DAILY_API_BUDGET",
+		"carriers.ups.daily_api_budget":         "UPS_DAILY_API_BUDGET",
+		"carriers.dhl.daily_api_budget":         "DHL_DAILY_API_BUDGET",
+		"cache.ttl":                             "CACHE_TTL",
+		"cache.memory_size":                     "CACHE_MEMORY_SIZE",
+		"cache.backend":                         "CACHE_BACKEND",
+		"cache.redis_url":                       "CACHE_REDIS_URL",
+		"cache.redis_prefix":                    "CACHE_REDIS_PREFIX",
+		"cache.disabled":                        "DISABLE_CACHE",
+		"rate_limit.disabled":                   "DISABLE_RATE_LIMIT",
+		"admin.api_key":                         "ADMIN_API_KEY",
+		"admin.auth_disabled":                   "DISABLE_ADMIN_AUTH",
+		"admin.auth_mode":                       "ADMIN_AUTH_MODE",
+		"auth.proxy.trusted_proxies":            "AUTH_PROXY_TRUSTED_PROXIES",
+		"auth.proxy.user_headers":               "AUTH_PROXY_USER_HEADERS",
+		"auth.proxy.users":                      "AUTH_PROXY_USERS",
+		"server.tls.cert_file":                  "TLS_CERT_FILE",
+		"server.tls.key_file":                   "TLS_KEY_FILE",
+		"server.tls.client_ca_file":             "TLS_CLIENT_CA_FILE",
 	}
 
 	for configKey, envVar := range oldEnvBindings {
 		v.BindEnv(configKey, envVar)
 	}
 
+	// Notification channels share their setting names, so they're bound per channel
+	bindNotificationChannelEnv(v, "notifications.log", "NOTIFICATIONS_LOG")
+	bindNotificationChannelEnv(v, "notifications.slack", "NOTIFICATIONS_SLACK", "webhook_url", "bot_token", "channel")
+	bindNotificationChannelEnv(v, "notifications.discord", "NOTIFICATIONS_DISCORD", "webhook_url", "username")
+	bindNotificationChannelEnv(v, "notifications.telegram", "NOTIFICATIONS_TELEGRAM", "bot_token", "chat_ids", "commands_enabled")
+	bindNotificationChannelEnv(v, "notifications.ntfy", "NOTIFICATIONS_NTFY", "server_url", "topic", "token", "priorities")
+	bindNotificationChannelEnv(v, "notifications.pushover", "NOTIFICATIONS_PUSHOVER", "app_token", "user_key", "device", "priorities")
+	bindNotificationChannelEnv(v, "notifications.gotify", "NOTIFICATIONS_GOTIFY", "server_url", "app_token", "priorities")
+
 	// Prioritize new format over old format by binding them separately
 	// New format values will override old format values due to AutomaticEnv()
 }
 
+// ConfigFileEnv names the environment variable that selects the configuration file when
+// none is given on the command line. The server and email tracker can share one file.
+const ConfigFileEnv = "PKG_TRACKER_CONFIG_FILE"
+
 // loadConfigFile loads configuration file if it exists
 func loadConfigFile(v *viper.Viper) error {
 	// Check if a specific config file was set
-	if v.ConfigFileUsed() == "" {
+	if v.ConfigFileUsed() == "" && os.Getenv(ConfigFileEnv) != "" {
+		v.SetConfigFile(os.Getenv(ConfigFileEnv))
+	} else if v.ConfigFileUsed() == "" {
 		// Add configuration search paths
 		v.AddConfigPath(".")
 		v.AddConfigPath("./config")
@@ -194,6 +380,13 @@ func unmarshalServerConfig(v *viper.Viper, config *Config) error {
 	config.ServerHost = v.GetString("server.host")
 	config.DBPath = v.GetString("database.path")
 	config.LogLevel = v.GetString("logging.level")
+	config.CacheBackend = v.GetString("cache.backend")
+	config.CacheRedisURL = v.GetString("cache.redis_url")
+	config.CacheRedisPrefix = v.GetString("cache.redis_prefix")
+	config.AdminAuthMode = v.GetString("admin.auth_mode")
+	config.TLSCertFile = v.GetString("server.tls.cert_file")
+	config.TLSKeyFile = v.GetString("server.tls.key_file")
+	config.TLSClientCAFile = v.GetString("server.tls.client_ca_file")
 
 	// Parse duration fields
 	var err error
@@ -217,6 +410,46 @@ func unmarshalServerConfig(v *viper.Viper, config *Config) error {
 		return fmt.Errorf("invalid individual timeout: %w", err)
 	}
 
+	config.AutoUpdateCatchUpDelay, err = time.ParseDuration(v.GetString("update.catchup_delay"))
+	if err != nil {
+		return fmt.Errorf("invalid catch-up delay: %w", err)
+	}
+
+	config.EmailCleanupInterval, err = time.ParseDuration(v.GetString("maintenance.email_cleanup_interval"))
+	if err != nil {
+		return fmt.Errorf("invalid email cleanup interval: %w", err)
+	}
+
+	config.StalledCheckInterval, err = time.ParseDuration(v.GetString("stalled.check_interval"))
+	if err != nil {
+		return fmt.Errorf("invalid stalled check interval: %w", err)
+	}
+
+	config.NotificationRetryBackoff, err = time.ParseDuration(v.GetString("notifications.retry_backoff"))
+	if err != nil {
+		return fmt.Errorf("invalid notification retry backoff: %w", err)
+	}
+
+	config.NotificationMaxBackoff, err = time.ParseDuration(v.GetString("notifications.max_backoff"))
+	if err != nil {
+		return fmt.Errorf("invalid notification max backoff: %w", err)
+	}
+
+	config.NotificationEscalationInterval, err = time.ParseDuration(v.GetString("notifications.escalation.interval"))
+	if err != nil {
+		return fmt.Errorf("invalid notification escalation interval: %w", err)
+	}
+
+	config.NotificationEscalationRepeat, err = time.ParseDuration(v.GetString("notifications.escalation.repeat"))
+	if err != nil {
+		return fmt.Errorf("invalid notification escalation repeat: %w", err)
+	}
+
+	config.StalledCarrierThresholdDays, err = parseCarrierDays(v.GetString("stalled.carrier_threshold_days"))
+	if err != nil {
+		return fmt.Errorf("invalid stalled carrier threshold days: %w", err)
+	}
+
 	// Carrier API keys
 	config.USPSAPIKey = v.GetString("carriers.usps.api_key")
 	config.UPSAPIKey = v.GetString("carriers.ups.api_key")
@@ -231,20 +464,124 @@ func unmarshalServerConfig(v *viper.Viper, config *Config) error {
 	config.AutoUpdateEnabled = v.GetBool("update.auto_enabled")
 	config.UPSAutoUpdateEnabled = v.GetBool("carriers.ups.auto_update_enabled")
 	config.DHLAutoUpdateEnabled = v.GetBool("carriers.dhl.auto_update_enabled")
+	config.MQTTSyncInterval, err = time.ParseDuration(v.GetString("mqtt.sync_interval"))
+	if err != nil {
+		return fmt.Errorf("invalid MQTT sync interval: %w", err)
+	}
+
+	config.MQTTDeliveredRetention, err = time.ParseDuration(v.GetString("mqtt.delivered_retention"))
+	if err != nil {
+		return fmt.Errorf("invalid MQTT delivered retention: %w", err)
+	}
+
+	config.AutoUpdateCatchUpEnabled = v.GetBool("update.catchup_enabled")
+	config.EmailCleanupEnabled = v.GetBool("maintenance.email_cleanup_enabled")
+	config.StalledDetectionEnabled = v.GetBool("stalled.enabled")
+	config.NotificationsEnabled = v.GetBool("notifications.enabled")
+	config.NotificationEscalationEnabled = v.GetBool("notifications.escalation.enabled")
+	config.MQTTEnabled = v.GetBool("mqtt.enabled")
 	config.DisableRateLimit = v.GetBool("rate_limit.disabled")
 	config.DisableCache = v.GetBool("cache.disabled")
 	config.DisableAdminAuth = v.GetBool("admin.auth_disabled")
+	config.ServerReusePort = v.GetBool("server.reuse_port")
 
 	// Integer values
 	config.AutoUpdateCutoffDays = v.GetInt("update.cutoff_days")
+	config.CacheMemorySize = v.GetInt("cache.memory_size")
 	config.AutoUpdateBatchSize = v.GetInt("update.batch_size")
 	config.AutoUpdateMaxRetries = v.GetInt("update.max_retries")
 	config.AutoUpdateFailureThreshold = v.GetInt("update.failure_threshold")
 	config.UPSAutoUpdateCutoffDays = v.GetInt("carriers.ups.auto_update_cutoff_days")
 	config.DHLAutoUpdateCutoffDays = v.GetInt("carriers.dhl.auto_update_cutoff_days")
+	config.USPSDailyAPIBudget = v.GetInt("carriers.usps.daily_api_budget")
+	config.UPSDailyAPIBudget = v.GetInt("carriers.ups.daily_api_budget")
+	config.DHLDailyAPIBudget = v.GetInt("carriers.dhl.daily_api_budget")
+	config.AutoUpdateCatchUpThreshold = v.GetInt("update.catchup_threshold")
+	config.EmailBodyRetentionDays = v.GetInt("maintenance.email_body_retention_days")
+	config.StalledThresholdDays = v.GetInt("stalled.threshold_days")
+	config.NotificationQueueSize = v.GetInt("notifications.queue_size")
+	config.NotificationMaxAttempts = v.GetInt("notifications.max_attempts")
+	config.NotificationEscalationMaxReminders = v.GetInt("notifications.escalation.max_reminders")
+
+	// Optional URLs
+	config.StalledWebhookURL = v.GetString("stalled.webhook_url")
+
+	// MQTT
+	config.MQTTBrokerURL = v.GetString("mqtt.broker_url")
+	config.MQTTUsername = v.GetString("mqtt.username")
+	config.MQTTPassword = v.GetString("mqtt.password")
+	config.MQTTClientID = v.GetString("mqtt.client_id")
+	config.MQTTTopicPrefix = v.GetString("mqtt.topic_prefix")
+	config.MQTTDiscoveryPrefix = v.GetString("mqtt.discovery_prefix")
+
+	// Notification channels
+	config.NotificationBaseURL = v.GetString("notifications.base_url")
+	config.NotificationDigestTime = v.GetString("notifications.digest_time")
+	config.NotificationDigestWeekday = v.GetString("notifications.digest_weekday")
+	config.NotificationTodayAlertTime = v.GetString("notifications.today_alert_time")
+	config.NotificationLog = notificationChannelFromViper(v, "notifications.log")
+	config.NotificationSlack = SlackNotificationConfig{
+		NotificationChannelConfig: notificationChannelFromViper(v, "notifications.slack"),
+		WebhookURL:                v.GetString("notifications.slack.webhook_url"),
+		BotToken:                  v.GetString("notifications.slack.bot_token"),
+		Channel:                   v.GetString("notifications.slack.channel"),
+	}
+	discordRoutes, err := discordRoutesFromViper(v, "notifications.discord.routes")
+	if err != nil {
+		return err
+	}
+	config.NotificationDiscord = DiscordNotificationConfig{
+		NotificationChannelConfig: notificationChannelFromViper(v, "notifications.discord"),
+		WebhookURL:                v.GetString("notifications.discord.webhook_url"),
+		Username:                  v.GetString("notifications.discord.username"),
+		Routes:                    discordRoutes,
+	}
+	telegramChats, err := parseChatIDs(strings.Join(v.GetStringSlice("notifications.telegram.chat_ids"), ","))
+	if err != nil {
+		return fmt.Errorf("invalid notifications.telegram.chat_ids: %w", err)
+	}
+	config.NotificationTelegram = TelegramNotificationConfig{
+		NotificationChannelConfig: notificationChannelFromViper(v, "notifications.telegram"),
+		BotToken:                  v.GetString("notifications.telegram.bot_token"),
+		ChatIDs:                   telegramChats,
+		CommandsEnabled:           v.GetBool("notifications.telegram.commands_enabled"),
+	}
+	config.NotificationNtfy = NtfyNotificationConfig{
+		NotificationChannelConfig: notificationChannelFromViper(v, "notifications.ntfy"),
+		ServerURL:                 v.GetString("notifications.ntfy.server_url"),
+		Topic:                     v.GetString("notifications.ntfy.topic"),
+		Token:                     v.GetString("notifications.ntfy.token"),
+	}
+	config.NotificationPushover = PushoverNotificationConfig{
+		NotificationChannelConfig: notificationChannelFromViper(v, "notifications.pushover"),
+		AppToken:                  v.GetString("notifications.pushover.app_token"),
+		UserKey:                   v.GetString("notifications.pushover.user_key"),
+		Device:                    v.GetString("notifications.pushover.device"),
+	}
+	config.NotificationGotify = GotifyNotificationConfig{
+		NotificationChannelConfig: notificationChannelFromViper(v, "notifications.gotify"),
+		ServerURL:                 v.GetString("notifications.gotify.server_url"),
+		AppToken:                  v.GetString("notifications.gotify.app_token"),
+	}
+	if config.NotificationWebhooks, err = webhooksFromViper(v, "notifications.webhooks"); err != nil {
+		return err
+	}
+	for channel, priorities := range map[string]*map[string]string{
+		"ntfy":     &config.NotificationNtfy.Priorities,
+		"pushover": &config.NotificationPushover.Priorities,
+		"gotify":   &config.NotificationGotify.Priorities,
+	} {
+		key := "notifications." + channel + ".priorities"
+		if *priorities, err = priorityMapFromViper(v, key); err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+	}
 
 	// Admin API key
 	config.AdminAPIKey = v.GetString("admin.api_key")
+	config.AuthProxyTrustedProxies = splitAndTrim(strings.Join(v.GetStringSlice("auth.proxy.trusted_proxies"), ","), ",")
+	config.AuthProxyUserHeaders = splitAndTrim(strings.Join(v.GetStringSlice("auth.proxy.user_headers"), ","), ",")
+	config.AuthProxyUsers = splitAndTrim(strings.Join(v.GetStringSlice("auth.proxy.users"), ","), ",")
 
 	return nil
 }
@@ -284,4 +621,4 @@ func LoadServerConfigWithEnvFile(envFile string) (*Config, error) {
 // Ensure backward compatibility by providing a new Load function that works with Viper
 func LoadWithViper() (*Config, error) {
 	return LoadServerConfigWithEnvFile("")
-}
\ No newline at end of file
+}
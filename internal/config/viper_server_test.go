@@ -59,6 +59,8 @@ func TestServerViperConfig_LoadFromEnvironment(t *testing.T) {
 		"PKG_TRACKER_ADMIN_AUTH_DISABLED":     "true",
 		"PKG_TRACKER_CACHE_TTL":               "10m",
 		"PKG_TRACKER_CACHE_DISABLED":          "true",
+		"PKG_TRACKER_CACHE_BACKEND":           "redis",
+		"PKG_TRACKER_CACHE_REDIS_URL":         "redis://cache.local:6379/1",
 	}
 
 	for key, value := range envVars {
@@ -104,6 +106,12 @@ func TestServerViperConfig_LoadFromEnvironment(t *testing.T) {
 	if config.DisableCache != true {
 		t.Errorf("Expected DisableCache to be true, got %v", config.DisableCache)
 	}
+	if config.CacheBackend != "redis" || config.CacheRedisURL != "redis://cache.local:6379/1" {
+		t.Errorf("Expected the redis cache backend, got %q at %q", config.CacheBackend, config.CacheRedisURL)
+	}
+	if config.CacheRedisPrefix != "package-tracking:" {
+		t.Errorf("Expected the default Redis key prefix, got %q", config.CacheRedisPrefix)
+	}
 }
 
 func TestServerViperConfig_LoadFromYAMLFile(t *testing.T) {
@@ -187,6 +195,201 @@ update:
 	}
 }
 
+func TestServerViperConfig_Notifications(t *testing.T) {
+	clearEnvVars()
+
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, "config.yaml")
+	configContent := `admin:
+  auth_disabled: true
+
+notifications:
+  max_attempts: 5
+  retry_backoff: "30s"
+  digest_time: "07:30"
+  escalation:
+    enabled: true
+    repeat: 6h
+  log:
+    enabled: true
+    statuses: [delivered, Exception]
+    subject_template: "{{.Name}}: {{.ToStatus}}"
+  slack:
+    enabled: true
+    statuses: delivered
+    bot_token: "xoxb-test"
+    digest: Weekly
+    today_alert: true
+  discord:
+    enabled: true
+    routes:
+      - name: problems
+        webhook_url: "https://discord.test/problems"
+        statuses: [Exception, returned]
+      - webhook_url: "https://discord.test/amazon"
+        carriers: amazon
+  telegram:
+    enabled: true
+    bot_token: "123:test"
+    chat_ids: [42, -100]
+  ntfy:
+    enabled: true
+    topic: packages
+    priorities:
+      in_transit: low
+  webhooks:
+    - name: chat
+      url: "https://chat.test/hooks/packages"
+      method: put
+      headers:
+        Authorization: "Bearer ${TEST_WEBHOOK_TOKEN}"
+      statuses: Delivered
+      payload_templates:
+        Delivered: '{"text": {{json .Subject}}}'
+`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	os.Setenv("NOTIFICATIONS_LOG_BODY_TEMPLATE", "{{.Shipment.TrackingNumber}}")
+	defer os.Unsetenv("NOTIFICATIONS_LOG_BODY_TEMPLATE")
+	os.Setenv("PKG_TRACKER_NOTIFICATIONS_SLACK_CHANNEL", "#deliveries")
+	defer os.Unsetenv("PKG_TRACKER_NOTIFICATIONS_SLACK_CHANNEL")
+	os.Setenv("NOTIFICATIONS_GOTIFY_PRIORITIES", "exception=urgent, delivered=low")
+	defer os.Unsetenv("NOTIFICATIONS_GOTIFY_PRIORITIES")
+	os.Setenv("TEST_WEBHOOK_TOKEN", "secret")
+	defer os.Unsetenv("TEST_WEBHOOK_TOKEN")
+
+	v := viper.New()
+	v.SetConfigFile(configFile)
+	config, err := LoadServerConfigWithViper(v)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !config.NotificationsEnabled || config.NotificationMaxAttempts != 5 || config.NotificationRetryBackoff != 30*time.Second {
+		t.Errorf("Unexpected notification settings: enabled %v, attempts %d, backoff %v",
+			config.NotificationsEnabled, config.NotificationMaxAttempts, config.NotificationRetryBackoff)
+	}
+	if config.NotificationQueueSize != 100 || config.NotificationMaxBackoff != 5*time.Minute {
+		t.Errorf("Expected default queue size and max backoff, got %d and %v", config.NotificationQueueSize, config.NotificationMaxBackoff)
+	}
+
+	if !config.NotificationEscalationEnabled || config.NotificationEscalationRepeat != 6*time.Hour ||
+		config.NotificationEscalationInterval != 15*time.Minute || config.NotificationEscalationMaxReminders != 3 {
+		t.Errorf("Unexpected escalation settings: enabled %v, interval %v, repeat %v, reminders %d",
+			config.NotificationEscalationEnabled, config.NotificationEscalationInterval,
+			config.NotificationEscalationRepeat, config.NotificationEscalationMaxReminders)
+	}
+
+	channel := config.NotificationLog
+	if !channel.Enabled {
+		t.Error("Expected the log channel to be enabled")
+	}
+	if len(channel.Statuses) != 2 || channel.Statuses[0] != "delivered" || channel.Statuses[1] != "exception" {
+		t.Errorf("Expected statuses [delivered exception], got %v", channel.Statuses)
+	}
+	if channel.SubjectTemplate != "{{.Name}}: {{.ToStatus}}" {
+		t.Errorf("Unexpected subject template %q", channel.SubjectTemplate)
+	}
+	if channel.BodyTemplate != "{{.Shipment.TrackingNumber}}" {
+		t.Errorf("Expected body template from the environment, got %q", channel.BodyTemplate)
+	}
+
+	slack := config.NotificationSlack
+	if !slack.Enabled || len(slack.Statuses) != 1 || slack.BotToken != "xoxb-test" || slack.Channel != "#deliveries" {
+		t.Errorf("Unexpected slack settings: %+v", slack)
+	}
+	if slack.Digest != "weekly" || !slack.TodayAlert || config.NotificationTodayAlertTime != "07:00" || config.NotificationDigestTime != "07:30" || config.NotificationDigestWeekday != "monday" {
+		t.Errorf("Unexpected digest settings: %q at %s on %s", slack.Digest, config.NotificationDigestTime, config.NotificationDigestWeekday)
+	}
+
+	routes := config.NotificationDiscord.Routes
+	if len(routes) != 2 {
+		t.Fatalf("Expected 2 discord routes, got %+v", routes)
+	}
+	if routes[0].Name != "problems" || len(routes[0].Statuses) != 2 || routes[0].Statuses[0] != "exception" {
+		t.Errorf("Unexpected first route: %+v", routes[0])
+	}
+	if len(routes[1].Carriers) != 1 || routes[1].Carriers[0] != "amazon" {
+		t.Errorf("Expected carriers from a comma-separated string, got %+v", routes[1])
+	}
+
+	telegram := config.NotificationTelegram
+	if !telegram.Enabled || len(telegram.ChatIDs) != 2 || telegram.ChatIDs[1] != -100 || telegram.CommandsEnabled {
+		t.Errorf("Unexpected telegram settings: %+v", telegram)
+	}
+
+	if config.NotificationNtfy.Priorities["in_transit"] != "low" {
+		t.Errorf("Expected ntfy priorities from the config file, got %v", config.NotificationNtfy.Priorities)
+	}
+	gotify := config.NotificationGotify.Priorities
+	if len(gotify) != 2 || gotify["exception"] != "urgent" || gotify["delivered"] != "low" {
+		t.Errorf("Expected gotify priorities from the environment, got %v", gotify)
+	}
+
+	if len(config.NotificationWebhooks) != 1 {
+		t.Fatalf("Expected 1 webhook, got %+v", config.NotificationWebhooks)
+	}
+	webhook := config.NotificationWebhooks[0]
+	if webhook.Method != "PUT" || webhook.Headers["authorization"] != "Bearer secret" {
+		t.Errorf("Expected the method uppercased and the header expanded, got %+v", webhook)
+	}
+	if len(webhook.Statuses) != 1 || webhook.Statuses[0] != "delivered" || webhook.PayloadTemplates["delivered"] != `{"text": {{json .Subject}}}` {
+		t.Errorf("Unexpected webhook filters or templates: %+v", webhook)
+	}
+}
+
+func TestServerViperConfig_MQTT(t *testing.T) {
+	clearEnvVars()
+
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, "config.yaml")
+	configContent := `admin:
+  auth_disabled: true
+
+mqtt:
+  enabled: true
+  broker_url: "tcp://homeassistant.local:1883"
+  username: tracker
+  sync_interval: "1m"
+`
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	os.Setenv("MQTT_PASSWORD", "secret")
+	defer os.Unsetenv("MQTT_PASSWORD")
+
+	v := viper.New()
+	v.SetConfigFile(configFile)
+	config, err := LoadServerConfigWithViper(v)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !config.MQTTEnabled || config.MQTTBrokerURL != "tcp://homeassistant.local:1883" ||
+		config.MQTTUsername != "tracker" || config.MQTTPassword != "secret" {
+		t.Errorf("Unexpected MQTT connection settings: %q %q %q", config.MQTTBrokerURL, config.MQTTUsername, config.MQTTPassword)
+	}
+	if config.MQTTSyncInterval != time.Minute || config.MQTTDeliveredRetention != 48*time.Hour {
+		t.Errorf("Unexpected MQTT intervals: sync %v, retention %v", config.MQTTSyncInterval, config.MQTTDeliveredRetention)
+	}
+	if config.MQTTTopicPrefix != "package_tracker" || config.MQTTDiscoveryPrefix != "homeassistant" {
+		t.Errorf("Expected default prefixes, got %q and %q", config.MQTTTopicPrefix, config.MQTTDiscoveryPrefix)
+	}
+
+	// The broker URL is required when MQTT is enabled
+	v = viper.New()
+	os.Setenv("PKG_TRACKER_ADMIN_AUTH_DISABLED", "true")
+	defer os.Unsetenv("PKG_TRACKER_ADMIN_AUTH_DISABLED")
+	os.Setenv("MQTT_ENABLED", "true")
+	defer os.Unsetenv("MQTT_ENABLED")
+	if _, err := LoadServerConfigWithViper(v); err == nil {
+		t.Error("Expected an error without a broker URL")
+	}
+}
+
 func TestServerViperConfig_BackwardCompatibility(t *testing.T) {
 	// Clear environment variables first
 	clearEnvVars()
@@ -395,6 +598,7 @@ func clearEnvVars() {
 		"PKG_TRACKER_CARRIERS_USPS_API_KEY", "PKG_TRACKER_CARRIERS_UPS_CLIENT_ID",
 		"PKG_TRACKER_CARRIERS_UPS_CLIENT_SECRET", "PKG_TRACKER_ADMIN_API_KEY",
 		"PKG_TRACKER_ADMIN_AUTH_DISABLED", "PKG_TRACKER_CACHE_TTL", "PKG_TRACKER_CACHE_DISABLED",
+		"PKG_TRACKER_CACHE_BACKEND", "PKG_TRACKER_CACHE_REDIS_URL",
 	}
 
 	// Clear old format variables
@@ -0,0 +1,143 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Kinds of alerts
+const (
+	AlertKindException = "exception" // The shipment is in exception
+	AlertKindDelay     = "delay"     // The expected delivery moved later
+)
+
+// Alert is a problem with a shipment that is escalated until it is acknowledged or
+// resolved. A shipment has at most one open alert of each kind.
+type Alert struct {
+	ID                int        `json:"id"`
+	ShipmentID        int        `json:"shipment_id"`
+	Kind              string     `json:"kind"`
+	Detail            string     `json:"detail"`
+	NotificationsSent int        `json:"notifications_sent"`
+	LastNotifiedAt    *time.Time `json:"last_notified_at,omitempty"`
+	AcknowledgedAt    *time.Time `json:"acknowledged_at,omitempty"`
+	ResolvedAt        *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// AlertStore handles alert operations
+type AlertStore struct {
+	db *sql.DB
+}
+
+// NewAlertStore creates a new alert store
+func NewAlertStore(db *sql.DB) *AlertStore {
+	return &AlertStore{db: db}
+}
+
+const alertColumns = `id, shipment_id, kind, detail, notifications_sent, last_notified_at,
+	acknowledged_at, resolved_at, created_at`
+
+// Open returns the shipment's open alert of a kind, creating it with detail if there is
+// none. created reports whether it was created.
+func (s *AlertStore) Open(shipmentID int, kind, detail string) (alert *Alert, created bool, err error) {
+	alerts, err := s.query(`SELECT `+alertColumns+` FROM alerts
+		WHERE shipment_id = ? AND kind = ? AND resolved_at IS NULL ORDER BY id LIMIT 1`, shipmentID, kind)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(alerts) > 0 {
+		return &alerts[0], false, nil
+	}
+
+	result, err := s.db.Exec(`INSERT INTO alerts (shipment_id, kind, detail) VALUES (?, ?, ?)`,
+		shipmentID, kind, detail)
+	if err != nil {
+		return nil, false, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, false, err
+	}
+	alert, err = s.GetByID(int(id))
+	return alert, err == nil, err
+}
+
+// GetOpen returns the unresolved alerts, oldest first
+func (s *AlertStore) GetOpen() ([]Alert, error) {
+	return s.query(`SELECT ` + alertColumns + ` FROM alerts WHERE resolved_at IS NULL ORDER BY id`)
+}
+
+// GetAll returns the newest alerts first, including resolved ones when includeResolved
+// is set
+func (s *AlertStore) GetAll(includeResolved bool) ([]Alert, error) {
+	if includeResolved {
+		return s.query(`SELECT ` + alertColumns + ` FROM alerts ORDER BY id DESC`)
+	}
+	return s.query(`SELECT ` + alertColumns + ` FROM alerts WHERE resolved_at IS NULL ORDER BY id DESC`)
+}
+
+// GetByID returns an alert, or sql.ErrNoRows if it doesn't exist
+func (s *AlertStore) GetByID(id int) (*Alert, error) {
+	alerts, err := s.query(`SELECT `+alertColumns+` FROM alerts WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(alerts) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &alerts[0], nil
+}
+
+// MarkNotified records that a notification about an alert was sent at the given time
+func (s *AlertStore) MarkNotified(id int, at time.Time) error {
+	return s.exec(`UPDATE alerts SET notifications_sent = notifications_sent + 1,
+		last_notified_at = ? WHERE id = ?`, at, id)
+}
+
+// Acknowledge stops further notifications about an alert, or returns sql.ErrNoRows if it
+// doesn't exist. Acknowledging an alert again keeps the first time.
+func (s *AlertStore) Acknowledge(id int, at time.Time) error {
+	return s.exec(`UPDATE alerts SET acknowledged_at = COALESCE(acknowledged_at, ?) WHERE id = ?`, at, id)
+}
+
+// Resolve closes an alert, so the problem opens a new one if it happens again
+func (s *AlertStore) Resolve(id int, at time.Time) error {
+	return s.exec(`UPDATE alerts SET resolved_at = ? WHERE id = ? AND resolved_at IS NULL`, at, id)
+}
+
+// exec runs an update of a single alert, returning sql.ErrNoRows if it matched none
+func (s *AlertStore) exec(query string, args ...any) error {
+	result, err := s.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (s *AlertStore) query(query string, args ...any) ([]Alert, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []Alert
+	for rows.Next() {
+		var alert Alert
+		if err := rows.Scan(&alert.ID, &alert.ShipmentID, &alert.Kind, &alert.Detail,
+			&alert.NotificationsSent, &alert.LastNotifiedAt, &alert.AcknowledgedAt,
+			&alert.ResolvedAt, &alert.CreatedAt); err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, rows.Err()
+}
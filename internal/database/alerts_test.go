@@ -0,0 +1,77 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestAlertStore(t *testing.T) {
+	db := setupTestDB(t)
+
+	shipment := &Shipment{TrackingNumber: "1Z999AA10123456784", Carrier: "ups", Description: "Headphones", Status: "exception"}
+	if err := db.Shipments.Create(shipment); err != nil {
+		t.Fatalf("Failed to create shipment: %v", err)
+	}
+
+	alert, created, err := db.Alerts.Open(shipment.ID, AlertKindException, "Delivery exception")
+	if err != nil || !created {
+		t.Fatalf("Open failed: created %v, error %v", created, err)
+	}
+	if alert.ShipmentID != shipment.ID || alert.NotificationsSent != 0 || alert.LastNotifiedAt != nil {
+		t.Errorf("Unexpected new alert: %+v", alert)
+	}
+
+	// The open alert is returned rather than a second one
+	again, created, err := db.Alerts.Open(shipment.ID, AlertKindException, "Another exception")
+	if err != nil || created || again.ID != alert.ID {
+		t.Fatalf("Expected the open alert, got %+v (created %v, error %v)", again, created, err)
+	}
+	if _, created, _ := db.Alerts.Open(shipment.ID, AlertKindDelay, "Late"); !created {
+		t.Error("Expected an alert of another kind to be opened")
+	}
+
+	now := time.Now().Truncate(time.Second)
+	if err := db.Alerts.MarkNotified(alert.ID, now); err != nil {
+		t.Fatalf("MarkNotified failed: %v", err)
+	}
+	if err := db.Alerts.Acknowledge(alert.ID, now); err != nil {
+		t.Fatalf("Acknowledge failed: %v", err)
+	}
+	if err := db.Alerts.Acknowledge(alert.ID, now.Add(time.Hour)); err != nil {
+		t.Fatalf("Acknowledge failed: %v", err)
+	}
+	got, err := db.Alerts.GetByID(alert.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if got.NotificationsSent != 1 || got.LastNotifiedAt == nil || got.AcknowledgedAt == nil || !got.AcknowledgedAt.Equal(now) {
+		t.Errorf("Unexpected alert after notifying and acknowledging: %+v", got)
+	}
+	if err := db.Alerts.Acknowledge(999, now); err != sql.ErrNoRows {
+		t.Errorf("Expected sql.ErrNoRows acknowledging a missing alert, got %v", err)
+	}
+
+	if err := db.Alerts.Resolve(alert.ID, now); err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	open, err := db.Alerts.GetOpen()
+	if err != nil {
+		t.Fatalf("GetOpen failed: %v", err)
+	}
+	if len(open) != 1 || open[0].Kind != AlertKindDelay {
+		t.Errorf("Expected only the delay alert open, got %+v", open)
+	}
+	all, err := db.Alerts.GetAll(true)
+	if err != nil {
+		t.Fatalf("GetAll failed: %v", err)
+	}
+	if len(all) != 2 || all[1].ResolvedAt == nil {
+		t.Errorf("Expected both alerts, newest first, got %+v", all)
+	}
+
+	// Once resolved, the problem opens a new alert
+	if reopened, created, err := db.Alerts.Open(shipment.ID, AlertKindException, "Again"); err != nil || !created || reopened.ID == alert.ID {
+		t.Errorf("Expected a new alert after resolving, got %+v (created %v, error %v)", reopened, created, err)
+	}
+}
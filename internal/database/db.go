@@ -29,6 +29,10 @@ type DB struct {
 	Carriers       *CarrierStore
 	RefreshCache   *RefreshCacheStore
 	Emails         *EmailStore
+	Quota          *QuotaStore
+	Rules          *NotificationRuleStore
+	Alerts         *AlertStore
+	Notifications  *NotificationHistoryStore
 }
 
 // Open opens a database connection and initializes stores
@@ -56,6 +60,10 @@ func Open(dbPath string) (*DB, error) {
 		Carriers:       NewCarrierStore(db),
 		RefreshCache:   NewRefreshCacheStore(db),
 		Emails:         NewEmailStore(db),
+		Quota:          NewQuotaStore(db),
+		Rules:          NewNotificationRuleStore(db),
+		Alerts:         NewAlertStore(db),
+		Notifications:  NewNotificationHistoryStore(db),
 	}
 
 	// Run migrations
@@ -110,12 +118,68 @@ func (db *DB) migrate() error {
 		FOREIGN KEY (shipment_id) REFERENCES shipments(id) ON DELETE CASCADE
 	);
 
+	CREATE TABLE IF NOT EXISTS carrier_api_usage (
+		carrier TEXT NOT NULL,
+		usage_date TEXT NOT NULL,
+		calls INTEGER NOT NULL DEFAULT 0,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (carrier, usage_date)
+	);
+
+	CREATE TABLE IF NOT EXISTS notification_rules (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		enabled BOOLEAN NOT NULL DEFAULT TRUE,
+		carriers TEXT NOT NULL DEFAULT '',
+		from_statuses TEXT NOT NULL DEFAULT '',
+		to_statuses TEXT NOT NULL DEFAULT '',
+		active_from TEXT NOT NULL DEFAULT '',
+		active_until TEXT NOT NULL DEFAULT '',
+		expected_within_days INTEGER,
+		channels TEXT NOT NULL DEFAULT '',
+		quiet_hours_start TEXT NOT NULL DEFAULT '',
+		quiet_hours_end TEXT NOT NULL DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS notification_history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		channel TEXT NOT NULL,
+		shipment_id INTEGER,
+		source TEXT NOT NULL DEFAULT '',
+		to_status TEXT NOT NULL DEFAULT '',
+		subject TEXT NOT NULL DEFAULT '',
+		body TEXT NOT NULL DEFAULT '',
+		payload TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		error TEXT NOT NULL DEFAULT '',
+		retry_of INTEGER,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS alerts (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		shipment_id INTEGER NOT NULL,
+		kind TEXT NOT NULL,
+		detail TEXT NOT NULL DEFAULT '',
+		notifications_sent INTEGER NOT NULL DEFAULT 0,
+		last_notified_at DATETIME,
+		acknowledged_at DATETIME,
+		resolved_at DATETIME,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (shipment_id) REFERENCES shipments(id) ON DELETE CASCADE
+	);
+
 	CREATE INDEX IF NOT EXISTS idx_shipments_status ON shipments(status);
 	CREATE INDEX IF NOT EXISTS idx_shipments_carrier ON shipments(carrier);
 	CREATE INDEX IF NOT EXISTS idx_shipments_carrier_delivered ON shipments(carrier, is_delivered);
 	CREATE INDEX IF NOT EXISTS idx_tracking_events_shipment ON tracking_events(shipment_id);
 	CREATE INDEX IF NOT EXISTS idx_tracking_events_dedup ON tracking_events(shipment_id, timestamp, description);
 	CREATE INDEX IF NOT EXISTS idx_refresh_cache_expires ON refresh_cache(expires_at);
+	CREATE INDEX IF NOT EXISTS idx_alerts_open ON alerts(shipment_id, kind, resolved_at);
+	CREATE INDEX IF NOT EXISTS idx_notification_history_shipment ON notification_history(shipment_id);
 	`
 
 	_, err := db.Exec(schema)
@@ -143,6 +207,11 @@ func (db *DB) migrate() error {
 		return err
 	}
 
+	// Run stalled shipment fields migration
+	if err := db.migrateStalledFields(); err != nil {
+		return err
+	}
+
 	// Run email tables migration
 	if err := db.migrateEmailTables(); err != nil {
 		return err
@@ -310,6 +379,37 @@ func (db *DB) migrateAmazonFields() error {
 	return nil
 }
 
+// migrateStalledFields adds stalled shipment detection fields to existing databases
+func (db *DB) migrateStalledFields() error {
+	// Check if stalled columns already exist
+	var columnExists int
+	err := db.QueryRow(`
+		SELECT COUNT(*) 
+		FROM pragma_table_info('shipments') 
+		WHERE name = 'is_stalled'
+	`).Scan(&columnExists)
+	if err != nil {
+		return fmt.Errorf("failed to check is_stalled column existence: %w", err)
+	}
+
+	// If columns don't exist, add them
+	if columnExists == 0 {
+		alterQueries := []string{
+			"ALTER TABLE shipments ADD COLUMN is_stalled BOOLEAN DEFAULT FALSE",
+			"ALTER TABLE shipments ADD COLUMN stalled_since DATETIME",
+			"CREATE INDEX IF NOT EXISTS idx_shipments_stalled ON shipments(is_stalled)",
+		}
+
+		for _, query := range alterQueries {
+			if _, err := db.Exec(query); err != nil {
+				return fmt.Errorf("failed to execute stalled migration query '%s': %w", query, err)
+			}
+		}
+	}
+
+	return nil
+}
+
 // migrateEmailTables creates email-related tables and modifies processed_emails for time-based scanning
 func (db *DB) migrateEmailTables() error {
 	// Check if email_threads table already exists
@@ -441,15 +441,7 @@ func (e *EmailStore) GetEmailsSince(since time.Time) ([]EmailBodyEntry, error) {
 
 // CleanupOldEmails removes email bodies older than the specified date
 func (e *EmailStore) CleanupOldEmails(olderThan time.Time) error {
-	query := `UPDATE processed_emails SET body_text = '', body_html = '', 
-			  body_compressed = NULL WHERE processed_at < ?`
-	
-	result, err := e.db.Exec(query, olderThan)
-	if err != nil {
-		return err
-	}
-	
-	rowsAffected, err := result.RowsAffected()
+	rowsAffected, _, err := e.ClearEmailBodies(olderThan)
 	if err != nil {
 		return err
 	}
@@ -462,6 +454,59 @@ func (e *EmailStore) CleanupOldEmails(olderThan time.Time) error {
 	return nil
 }
 
+// ClearEmailBodies removes stored bodies from emails processed before the specified date,
+// returning the number of emails cleared and the number of body bytes reclaimed
+func (e *EmailStore) ClearEmailBodies(olderThan time.Time) (int64, int64, error) {
+	tx, err := e.db.Begin()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback() // Will be ignored if tx.Commit() succeeds
+
+	// Only emails that still have a body contribute to the reclaimed space
+	const hasBody = `(COALESCE(body_text, '') != '' OR COALESCE(body_html, '') != '' OR body_compressed IS NOT NULL)`
+
+	var reclaimed int64
+	sizeQuery := `SELECT COALESCE(SUM(LENGTH(COALESCE(body_text, '')) + LENGTH(COALESCE(body_html, '')) + 
+				  LENGTH(COALESCE(body_compressed, ''))), 0) 
+				  FROM processed_emails WHERE processed_at < ? AND ` + hasBody
+	if err := tx.QueryRow(sizeQuery, olderThan).Scan(&reclaimed); err != nil {
+		return 0, 0, err
+	}
+
+	query := `UPDATE processed_emails SET body_text = '', body_html = '', 
+			  body_compressed = NULL WHERE processed_at < ? AND ` + hasBody
+	result, err := tx.Exec(query, olderThan)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, err
+	}
+
+	return rowsAffected, reclaimed, nil
+}
+
+// PruneOrphanedLinks removes email-shipment links whose email or shipment no longer exists
+func (e *EmailStore) PruneOrphanedLinks() (int64, error) {
+	query := `DELETE FROM email_shipments 
+			  WHERE email_id NOT IN (SELECT id FROM processed_emails) 
+			  OR shipment_id NOT IN (SELECT id FROM shipments)`
+
+	result, err := e.db.Exec(query)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
 // IsProcessed checks if an email has been processed (for backward compatibility)
 func (e *EmailStore) IsProcessed(gmailMessageID string) (bool, error) {
 	var count int
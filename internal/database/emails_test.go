@@ -574,6 +574,108 @@ func TestEmailStore_CleanupOldEmails(t *testing.T) {
 	}
 }
 
+func TestEmailStore_ClearEmailBodies(t *testing.T) {
+	db, cleanup := setupTestEmailDB(t)
+	defer cleanup()
+
+	store := NewEmailStore(db)
+
+	now := time.Now()
+	oldTime := now.Add(-48 * time.Hour)
+
+	emails := []*EmailBodyEntry{
+		{GmailMessageID: "old-with-body", BodyText: "12345", BodyHTML: "<p>1</p>", ProcessedAt: oldTime},
+		{GmailMessageID: "old-without-body", ProcessedAt: oldTime},
+		{GmailMessageID: "recent", BodyText: "recent body", ProcessedAt: now},
+	}
+	for _, email := range emails {
+		email.GmailThreadID = "thread-" + email.GmailMessageID
+		email.From = "test@example.com"
+		email.Subject = "Subject"
+		email.Date = email.ProcessedAt
+		email.InternalTimestamp = email.ProcessedAt
+		email.ScanMethod = "time-based"
+		email.Status = "processed"
+		email.ProcessingPhase = "legacy"
+		if err := store.CreateOrUpdate(email); err != nil {
+			t.Fatalf("Failed to create email: %v", err)
+		}
+	}
+
+	cleared, reclaimed, err := store.ClearEmailBodies(now.Add(-24 * time.Hour))
+	if err != nil {
+		t.Fatalf("ClearEmailBodies failed: %v", err)
+	}
+
+	if cleared != 1 {
+		t.Errorf("Expected 1 email body cleared, got %d", cleared)
+	}
+	if reclaimed != int64(len("12345")+len("<p>1</p>")) {
+		t.Errorf("Expected %d bytes reclaimed, got %d", len("12345")+len("<p>1</p>"), reclaimed)
+	}
+
+	recent, err := store.GetByGmailMessageID("recent")
+	if err != nil {
+		t.Fatalf("Failed to retrieve recent email: %v", err)
+	}
+	if recent.BodyText != "recent body" {
+		t.Error("Expected recent email body to be retained")
+	}
+}
+
+func TestEmailStore_PruneOrphanedLinks(t *testing.T) {
+	db, cleanup := setupTestEmailDB(t)
+	defer cleanup()
+
+	store := NewEmailStore(db)
+	shipmentStore := NewShipmentStore(db)
+
+	email := &EmailBodyEntry{
+		GmailMessageID:    "linked-email",
+		GmailThreadID:     "linked-thread",
+		From:              "test@example.com",
+		Subject:           "Shipped",
+		Date:              time.Now(),
+		InternalTimestamp: time.Now(),
+		ScanMethod:        "time-based",
+		ProcessedAt:       time.Now(),
+		Status:            "processed",
+		ProcessingPhase:   "legacy",
+	}
+	if err := store.CreateOrUpdate(email); err != nil {
+		t.Fatalf("Failed to create email: %v", err)
+	}
+
+	shipment := &Shipment{TrackingNumber: "1Z999AA1234567890", Carrier: "ups", Description: "Test", Status: "pending"}
+	if err := shipmentStore.Create(shipment); err != nil {
+		t.Fatalf("Failed to create shipment: %v", err)
+	}
+
+	if err := store.LinkEmailToShipment(email.ID, shipment.ID, "automatic", shipment.TrackingNumber, "system"); err != nil {
+		t.Fatalf("Failed to link email: %v", err)
+	}
+	// Link to an email that no longer exists
+	if err := store.LinkEmailToShipment(email.ID+100, shipment.ID, "automatic", shipment.TrackingNumber, "system"); err != nil {
+		t.Fatalf("Failed to create orphaned link: %v", err)
+	}
+
+	pruned, err := store.PruneOrphanedLinks()
+	if err != nil {
+		t.Fatalf("PruneOrphanedLinks failed: %v", err)
+	}
+	if pruned != 1 {
+		t.Errorf("Expected 1 orphaned link pruned, got %d", pruned)
+	}
+
+	linked, err := store.GetByShipmentID(shipment.ID)
+	if err != nil {
+		t.Fatalf("Failed to get linked emails: %v", err)
+	}
+	if len(linked) != 1 {
+		t.Errorf("Expected valid link to be kept, got %d linked emails", len(linked))
+	}
+}
+
 func TestEmailStore_IsProcessed(t *testing.T) {
 	db, cleanup := setupTestEmailDB(t)
 	defer cleanup()
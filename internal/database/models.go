@@ -27,6 +27,8 @@ type Shipment struct {
 	DelegatedCarrier        *string `json:"delegated_carrier,omitempty"`
 	DelegatedTrackingNumber *string `json:"delegated_tracking_number,omitempty"`
 	IsAmazonLogistics       bool    `json:"is_amazon_logistics"`
+	IsStalled               bool       `json:"is_stalled"`
+	StalledSince            *time.Time `json:"stalled_since,omitempty"`
 }
 
 type TrackingEvent struct {
@@ -63,7 +65,7 @@ func (s *ShipmentStore) GetByTrackingNumber(trackingNumber string) (*Shipment, e
 			  last_manual_refresh, manual_refresh_count, last_auto_refresh,
 			  auto_refresh_count, auto_refresh_enabled, auto_refresh_error,
 			  auto_refresh_fail_count, amazon_order_number, delegated_carrier,
-			  delegated_tracking_number, is_amazon_logistics 
+			  delegated_tracking_number, is_amazon_logistics, is_stalled, stalled_since 
 			  FROM shipments WHERE tracking_number = ?`
 	
 	var shipment Shipment
@@ -75,7 +77,7 @@ func (s *ShipmentStore) GetByTrackingNumber(trackingNumber string) (*Shipment, e
 		&shipment.AutoRefreshEnabled, &shipment.AutoRefreshError,
 		&shipment.AutoRefreshFailCount, &shipment.AmazonOrderNumber,
 		&shipment.DelegatedCarrier, &shipment.DelegatedTrackingNumber,
-		&shipment.IsAmazonLogistics)
+		&shipment.IsAmazonLogistics, &shipment.IsStalled, &shipment.StalledSince)
 	
 	if err != nil {
 		return nil, err
@@ -91,7 +93,7 @@ func (s *ShipmentStore) GetShipmentsWithPoorDescriptions(limit int) ([]Shipment,
 			  last_manual_refresh, manual_refresh_count, last_auto_refresh,
 			  auto_refresh_count, auto_refresh_enabled, auto_refresh_error,
 			  auto_refresh_fail_count, amazon_order_number, delegated_carrier,
-			  delegated_tracking_number, is_amazon_logistics 
+			  delegated_tracking_number, is_amazon_logistics, is_stalled, stalled_since 
 			  FROM shipments 
 			  WHERE description = '' OR description LIKE 'Package from %' OR description IS NULL
 			  ORDER BY created_at DESC`
@@ -119,7 +121,7 @@ func (s *ShipmentStore) GetShipmentsWithPoorDescriptions(limit int) ([]Shipment,
 			&shipment.AutoRefreshEnabled, &shipment.AutoRefreshError,
 			&shipment.AutoRefreshFailCount, &shipment.AmazonOrderNumber,
 			&shipment.DelegatedCarrier, &shipment.DelegatedTrackingNumber,
-			&shipment.IsAmazonLogistics)
+			&shipment.IsAmazonLogistics, &shipment.IsStalled, &shipment.StalledSince)
 		if err != nil {
 			return nil, err
 		}
@@ -157,7 +159,7 @@ func (s *ShipmentStore) GetAll() ([]Shipment, error) {
 			  last_manual_refresh, manual_refresh_count, last_auto_refresh,
 			  auto_refresh_count, auto_refresh_enabled, auto_refresh_error,
 			  auto_refresh_fail_count, amazon_order_number, delegated_carrier,
-			  delegated_tracking_number, is_amazon_logistics 
+			  delegated_tracking_number, is_amazon_logistics, is_stalled, stalled_since 
 			  FROM shipments ORDER BY created_at DESC`
 	
 	rows, err := s.db.Query(query)
@@ -177,7 +179,7 @@ func (s *ShipmentStore) GetAll() ([]Shipment, error) {
 			&shipment.AutoRefreshEnabled, &shipment.AutoRefreshError,
 			&shipment.AutoRefreshFailCount, &shipment.AmazonOrderNumber,
 			&shipment.DelegatedCarrier, &shipment.DelegatedTrackingNumber,
-			&shipment.IsAmazonLogistics)
+			&shipment.IsAmazonLogistics, &shipment.IsStalled, &shipment.StalledSince)
 		if err != nil {
 			return nil, err
 		}
@@ -194,7 +196,7 @@ func (s *ShipmentStore) GetActiveByCarrier(carrier string) ([]Shipment, error) {
 			  last_manual_refresh, manual_refresh_count, last_auto_refresh,
 			  auto_refresh_count, auto_refresh_enabled, auto_refresh_error,
 			  auto_refresh_fail_count, amazon_order_number, delegated_carrier,
-			  delegated_tracking_number, is_amazon_logistics 
+			  delegated_tracking_number, is_amazon_logistics, is_stalled, stalled_since 
 			  FROM shipments WHERE is_delivered = false AND carrier = ? ORDER BY created_at DESC`
 	
 	rows, err := s.db.Query(query, carrier)
@@ -214,7 +216,7 @@ func (s *ShipmentStore) GetActiveByCarrier(carrier string) ([]Shipment, error) {
 			&shipment.AutoRefreshEnabled, &shipment.AutoRefreshError,
 			&shipment.AutoRefreshFailCount, &shipment.AmazonOrderNumber,
 			&shipment.DelegatedCarrier, &shipment.DelegatedTrackingNumber,
-			&shipment.IsAmazonLogistics)
+			&shipment.IsAmazonLogistics, &shipment.IsStalled, &shipment.StalledSince)
 		if err != nil {
 			return nil, err
 		}
@@ -231,7 +233,7 @@ func (s *ShipmentStore) GetByID(id int) (*Shipment, error) {
 			  last_manual_refresh, manual_refresh_count, last_auto_refresh,
 			  auto_refresh_count, auto_refresh_enabled, auto_refresh_error,
 			  auto_refresh_fail_count, amazon_order_number, delegated_carrier,
-			  delegated_tracking_number, is_amazon_logistics 
+			  delegated_tracking_number, is_amazon_logistics, is_stalled, stalled_since 
 			  FROM shipments WHERE id = ?`
 	
 	var shipment Shipment
@@ -243,7 +245,7 @@ func (s *ShipmentStore) GetByID(id int) (*Shipment, error) {
 		&shipment.AutoRefreshEnabled, &shipment.AutoRefreshError,
 		&shipment.AutoRefreshFailCount, &shipment.AmazonOrderNumber,
 		&shipment.DelegatedCarrier, &shipment.DelegatedTrackingNumber,
-		&shipment.IsAmazonLogistics)
+		&shipment.IsAmazonLogistics, &shipment.IsStalled, &shipment.StalledSince)
 	
 	if err != nil {
 		return nil, err
@@ -362,6 +364,19 @@ func (s *ShipmentStore) Delete(id int) error {
 	return nil
 }
 
+// StalledCandidate is a shipment considered by stalled shipment detection
+type StalledCandidate struct {
+	ID             int
+	TrackingNumber string
+	Carrier        string
+	Description    string
+	Status         string
+	CreatedAt      time.Time
+	IsDelivered    bool
+	IsStalled      bool
+	LastEventAt    *time.Time
+}
+
 // DashboardStats represents aggregated statistics for the dashboard
 type DashboardStats struct {
 	TotalShipments      int `json:"total_shipments"`
@@ -369,6 +384,7 @@ type DashboardStats struct {
 	InTransit           int `json:"in_transit"`
 	Delivered           int `json:"delivered"`
 	RequiringAttention  int `json:"requiring_attention"`
+	Stalled             int `json:"stalled"`
 }
 
 // GetStats returns aggregated statistics for the dashboard
@@ -405,9 +421,97 @@ func (s *ShipmentStore) GetStats() (*DashboardStats, error) {
 		return nil, err
 	}
 	
+	// Get stalled shipments (no new tracking events for too long)
+	err = s.db.QueryRow("SELECT COUNT(*) FROM shipments WHERE is_stalled = 1 AND is_delivered = 0").Scan(&stats.Stalled)
+	if err != nil {
+		return nil, err
+	}
+	
 	return stats, nil
 }
 
+// ShipmentActivity is what happened to shipments during a period, for digests
+type ShipmentActivity struct {
+	Delivered  []Shipment // Delivered during the period
+	Created    []Shipment // Added during the period
+	Exceptions []Shipment // Currently in exception, whenever that happened
+}
+
+// GetActivity returns the shipments delivered and added in [since, until), and those
+// currently in exception. Shipments count as delivered when they were last updated.
+func (s *ShipmentStore) GetActivity(since, until time.Time) (*ShipmentActivity, error) {
+	from, to := since.UTC().Format("2006-01-02 15:04:05"), until.UTC().Format("2006-01-02 15:04:05")
+	activity := &ShipmentActivity{}
+
+	var err error
+	activity.Delivered, err = s.queryShipments(`WHERE is_delivered = 1
+		AND datetime(updated_at) >= datetime(?) AND datetime(updated_at) < datetime(?)
+		ORDER BY updated_at`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	activity.Created, err = s.queryShipments(`WHERE datetime(created_at) >= datetime(?)
+		AND datetime(created_at) < datetime(?) ORDER BY created_at`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	activity.Exceptions, err = s.GetExceptions()
+	if err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+// GetExceptions returns the undelivered shipments in exception, longest first
+func (s *ShipmentStore) GetExceptions() ([]Shipment, error) {
+	return s.queryShipments(`WHERE status = 'exception' AND is_delivered = 0 ORDER BY updated_at`)
+}
+
+// GetDueToday returns the undelivered shipments out for delivery or expected in [start,
+// end), the bounds of the day in the server's time zone
+func (s *ShipmentStore) GetDueToday(start, end time.Time) ([]Shipment, error) {
+	from, to := start.UTC().Format("2006-01-02 15:04:05"), end.UTC().Format("2006-01-02 15:04:05")
+	return s.queryShipments(`WHERE is_delivered = 0 AND (status = 'out_for_delivery'
+		OR (datetime(expected_delivery) >= datetime(?) AND datetime(expected_delivery) < datetime(?)))
+		ORDER BY expected_delivery, id`, from, to)
+}
+
+// queryShipments returns the shipments selected by the given WHERE and ORDER BY clauses
+func (s *ShipmentStore) queryShipments(clauses string, args ...any) ([]Shipment, error) {
+	query := `SELECT id, tracking_number, carrier, description, status,
+			  created_at, updated_at, expected_delivery, is_delivered,
+			  last_manual_refresh, manual_refresh_count, last_auto_refresh,
+			  auto_refresh_count, auto_refresh_enabled, auto_refresh_error,
+			  auto_refresh_fail_count, amazon_order_number, delegated_carrier,
+			  delegated_tracking_number, is_amazon_logistics, is_stalled, stalled_since
+			  FROM shipments ` + clauses
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shipments []Shipment
+	for rows.Next() {
+		var shipment Shipment
+		err := rows.Scan(&shipment.ID, &shipment.TrackingNumber, &shipment.Carrier,
+			&shipment.Description, &shipment.Status, &shipment.CreatedAt,
+			&shipment.UpdatedAt, &shipment.ExpectedDelivery, &shipment.IsDelivered,
+			&shipment.LastManualRefresh, &shipment.ManualRefreshCount,
+			&shipment.LastAutoRefresh, &shipment.AutoRefreshCount,
+			&shipment.AutoRefreshEnabled, &shipment.AutoRefreshError,
+			&shipment.AutoRefreshFailCount, &shipment.AmazonOrderNumber,
+			&shipment.DelegatedCarrier, &shipment.DelegatedTrackingNumber,
+			&shipment.IsAmazonLogistics, &shipment.IsStalled, &shipment.StalledSince)
+		if err != nil {
+			return nil, err
+		}
+		shipments = append(shipments, shipment)
+	}
+	return shipments, rows.Err()
+}
+
 // UpdateRefreshTracking updates the last_manual_refresh timestamp and increments the count
 func (s *ShipmentStore) UpdateRefreshTracking(id int) error {
 	query := `UPDATE shipments SET 
@@ -440,7 +544,7 @@ func (s *ShipmentStore) GetActiveForAutoUpdate(carrier string, cutoffDate time.T
 			  last_manual_refresh, manual_refresh_count, last_auto_refresh,
 			  auto_refresh_count, auto_refresh_enabled, auto_refresh_error,
 			  auto_refresh_fail_count, amazon_order_number, delegated_carrier,
-			  delegated_tracking_number, is_amazon_logistics 
+			  delegated_tracking_number, is_amazon_logistics, is_stalled, stalled_since 
 			  FROM shipments 
 			  WHERE is_delivered = false 
 			  AND carrier = ? 
@@ -466,7 +570,7 @@ func (s *ShipmentStore) GetActiveForAutoUpdate(carrier string, cutoffDate time.T
 			&shipment.AutoRefreshEnabled, &shipment.AutoRefreshError,
 			&shipment.AutoRefreshFailCount, &shipment.AmazonOrderNumber,
 			&shipment.DelegatedCarrier, &shipment.DelegatedTrackingNumber,
-			&shipment.IsAmazonLogistics)
+			&shipment.IsAmazonLogistics, &shipment.IsStalled, &shipment.StalledSince)
 		if err != nil {
 			return nil, err
 		}
@@ -476,6 +580,29 @@ func (s *ShipmentStore) GetActiveForAutoUpdate(carrier string, cutoffDate time.T
 	return shipments, rows.Err()
 }
 
+// GetLastAutoRefreshTime returns the most recent auto-refresh timestamp across all shipments,
+// or nil if no shipment has ever been auto-refreshed
+func (s *ShipmentStore) GetLastAutoRefreshTime() (*time.Time, error) {
+	query := `SELECT last_auto_refresh FROM shipments
+			  WHERE last_auto_refresh IS NOT NULL
+			  ORDER BY last_auto_refresh DESC LIMIT 1`
+
+	var lastRefresh sql.NullTime
+	err := s.db.QueryRow(query).Scan(&lastRefresh)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !lastRefresh.Valid {
+		return nil, nil
+	}
+
+	return &lastRefresh.Time, nil
+}
+
 // UpdateAutoRefreshTracking updates auto-refresh tracking fields
 func (s *ShipmentStore) UpdateAutoRefreshTracking(id int64, success bool, errorMsg string) error {
 	var query string
@@ -594,6 +721,186 @@ func (s *ShipmentStore) UpdateShipmentWithAutoRefresh(id int, shipment *Shipment
 	return nil
 }
 
+// AutoUpdateResult is the outcome of refreshing a single shipment during an auto-update run
+type AutoUpdateResult struct {
+	ShipmentID int
+	// Shipment carries the refreshed status fields, nil when only bookkeeping changed
+	Shipment *Shipment
+	// PreviousStatus is the shipment's status before the refresh, for reporting changes
+	PreviousStatus string
+	// PreviousExpectedDelivery is the expected delivery before the refresh, for reporting
+	// delays
+	PreviousExpectedDelivery *time.Time
+	Events   []TrackingEvent
+	Success  bool
+	Error    string
+}
+
+// ApplyAutoUpdateBatch writes the results of an auto-update batch in a single transaction.
+// Status changes, new tracking events, and auto-refresh bookkeeping are applied together so
+// the updater holds the write lock once per batch instead of once per row.
+func (s *ShipmentStore) ApplyAutoUpdateBatch(results []AutoUpdateResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Will be ignored if tx.Commit() succeeds
+
+	statusStmt, err := tx.Prepare(`UPDATE shipments SET status = ?, expected_delivery = ?, is_delivered = ?,
+			  updated_at = CURRENT_TIMESTAMP WHERE id = ?`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare status update: %w", err)
+	}
+	defer statusStmt.Close()
+
+	successStmt, err := tx.Prepare(`UPDATE shipments SET 
+			  last_auto_refresh = CURRENT_TIMESTAMP,
+			  auto_refresh_count = auto_refresh_count + 1,
+			  auto_refresh_fail_count = 0,
+			  auto_refresh_error = NULL,
+			  updated_at = CURRENT_TIMESTAMP 
+			  WHERE id = ?`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare auto-refresh tracking update: %w", err)
+	}
+	defer successStmt.Close()
+
+	failureStmt, err := tx.Prepare(`UPDATE shipments SET 
+			  auto_refresh_fail_count = auto_refresh_fail_count + 1,
+			  auto_refresh_error = ?,
+			  updated_at = CURRENT_TIMESTAMP 
+			  WHERE id = ?`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare auto-refresh failure update: %w", err)
+	}
+	defer failureStmt.Close()
+
+	eventStmt, err := tx.Prepare(`INSERT INTO tracking_events (shipment_id, timestamp, location, status, description, created_at) 
+			  SELECT ?, ?, ?, ?, ?, CURRENT_TIMESTAMP 
+			  WHERE NOT EXISTS (SELECT 1 FROM tracking_events WHERE shipment_id = ? AND timestamp = ? AND description = ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare event insert: %w", err)
+	}
+	defer eventStmt.Close()
+
+	for _, result := range results {
+		if result.Shipment != nil {
+			res, err := statusStmt.Exec(result.Shipment.Status, result.Shipment.ExpectedDelivery,
+				result.Shipment.IsDelivered, result.ShipmentID)
+			if err != nil {
+				return fmt.Errorf("failed to update shipment %d: %w", result.ShipmentID, err)
+			}
+
+			rowsAffected, err := res.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to get rows affected: %w", err)
+			}
+
+			// The shipment was deleted while its refresh was in flight, nothing left to record
+			if rowsAffected == 0 {
+				continue
+			}
+		}
+
+		for _, event := range result.Events {
+			_, err := eventStmt.Exec(result.ShipmentID, event.Timestamp, event.Location, event.Status,
+				event.Description, result.ShipmentID, event.Timestamp, event.Description)
+			if err != nil {
+				return fmt.Errorf("failed to insert event for shipment %d: %w", result.ShipmentID, err)
+			}
+		}
+
+		if result.Success {
+			_, err = successStmt.Exec(result.ShipmentID)
+		} else {
+			_, err = failureStmt.Exec(result.Error, result.ShipmentID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update auto-refresh tracking for shipment %d: %w", result.ShipmentID, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetStalledCandidates returns active shipments that may need to be flagged or cleared as
+// stalled, along with the time of their most recent tracking event (nil if they have none)
+func (s *ShipmentStore) GetStalledCandidates() ([]StalledCandidate, error) {
+	query := `SELECT id, tracking_number, carrier, description, status, created_at, is_delivered, is_stalled
+			  FROM shipments WHERE is_delivered = false OR is_stalled = true`
+
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []StalledCandidate
+	for rows.Next() {
+		var c StalledCandidate
+		err := rows.Scan(&c.ID, &c.TrackingNumber, &c.Carrier, &c.Description, &c.Status,
+			&c.CreatedAt, &c.IsDelivered, &c.IsStalled)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Look up the latest event per shipment separately, aggregate expressions lose the
+	// DATETIME column type needed to scan into time.Time
+	eventQuery := `SELECT timestamp FROM tracking_events WHERE shipment_id = ? ORDER BY timestamp DESC LIMIT 1`
+	for i := range candidates {
+		var lastEvent time.Time
+		err := s.db.QueryRow(eventQuery, candidates[i].ID).Scan(&lastEvent)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		candidates[i].LastEventAt = &lastEvent
+	}
+
+	return candidates, nil
+}
+
+// SetStalled flags or clears the stalled state of a shipment. stalledSince is ignored when
+// clearing the flag.
+func (s *ShipmentStore) SetStalled(id int, stalled bool, stalledSince time.Time) error {
+	var result sql.Result
+	var err error
+	if stalled {
+		result, err = s.db.Exec(`UPDATE shipments SET is_stalled = true, stalled_since = ? WHERE id = ?`, stalledSince, id)
+	} else {
+		result, err = s.db.Exec(`UPDATE shipments SET is_stalled = false, stalled_since = NULL WHERE id = ?`, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
 // ResetAutoRefreshFailCount resets the auto-refresh fail count for a shipment
 func (s *ShipmentStore) ResetAutoRefreshFailCount(id int64) error {
 	query := `UPDATE shipments SET 
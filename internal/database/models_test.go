@@ -1,6 +1,7 @@
 package database
 
 import (
+	"database/sql"
 	"fmt"
 	"os"
 	"sync"
@@ -460,6 +461,93 @@ func TestShipmentStore_UpdateShipmentWithAutoRefresh_Failure(t *testing.T) {
 	}
 }
 
+func TestShipmentStore_ApplyAutoUpdateBatch(t *testing.T) {
+	db := setupTestDB(t)
+
+	refreshed := Shipment{TrackingNumber: "BATCH1", Carrier: "usps", Description: "Refreshed", Status: "pending", AutoRefreshEnabled: true}
+	cached := Shipment{TrackingNumber: "BATCH2", Carrier: "ups", Description: "Cached", Status: "in_transit", AutoRefreshEnabled: true}
+	failed := Shipment{TrackingNumber: "BATCH3", Carrier: "dhl", Description: "Failed", Status: "in_transit", AutoRefreshEnabled: true}
+	for _, s := range []*Shipment{&refreshed, &cached, &failed} {
+		if err := db.Shipments.Create(s); err != nil {
+			t.Fatalf("Failed to create test shipment: %v", err)
+		}
+	}
+
+	eventTime := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	update := refreshed
+	update.Status = "delivered"
+	update.IsDelivered = true
+	update.Description = "Should not be written"
+
+	results := []AutoUpdateResult{
+		{
+			ShipmentID: refreshed.ID,
+			Shipment:   &update,
+			Events: []TrackingEvent{
+				{Timestamp: eventTime, Location: "Austin, TX", Status: "delivered", Description: "Delivered"},
+				{Timestamp: eventTime, Location: "Austin, TX", Status: "delivered", Description: "Delivered"},
+			},
+			Success: true,
+		},
+		{ShipmentID: cached.ID, Success: true},
+		{ShipmentID: failed.ID, Success: false, Error: "carrier unavailable"},
+	}
+
+	if err := db.Shipments.ApplyAutoUpdateBatch(results); err != nil {
+		t.Fatalf("ApplyAutoUpdateBatch failed: %v", err)
+	}
+
+	got, err := db.Shipments.GetByID(refreshed.ID)
+	if err != nil {
+		t.Fatalf("Failed to get refreshed shipment: %v", err)
+	}
+	if got.Status != "delivered" || !got.IsDelivered {
+		t.Errorf("Expected delivered status, got %s (delivered=%v)", got.Status, got.IsDelivered)
+	}
+	if got.Description != "Refreshed" {
+		t.Errorf("Expected description to be left alone, got %q", got.Description)
+	}
+	if got.AutoRefreshCount != 1 || got.LastAutoRefresh == nil {
+		t.Errorf("Expected auto refresh bookkeeping to be updated, got count %d", got.AutoRefreshCount)
+	}
+
+	events, err := db.TrackingEvents.GetByShipmentID(refreshed.ID)
+	if err != nil {
+		t.Fatalf("Failed to get events: %v", err)
+	}
+	if len(events) != 1 {
+		t.Errorf("Expected duplicate events to be collapsed to 1, got %d", len(events))
+	}
+
+	got, err = db.Shipments.GetByID(cached.ID)
+	if err != nil {
+		t.Fatalf("Failed to get cached shipment: %v", err)
+	}
+	if got.Status != "in_transit" || got.AutoRefreshCount != 1 {
+		t.Errorf("Expected only bookkeeping to change for cached shipment, got status %s count %d", got.Status, got.AutoRefreshCount)
+	}
+
+	got, err = db.Shipments.GetByID(failed.ID)
+	if err != nil {
+		t.Fatalf("Failed to get failed shipment: %v", err)
+	}
+	if got.AutoRefreshFailCount != 1 || got.AutoRefreshError == nil || *got.AutoRefreshError != "carrier unavailable" {
+		t.Errorf("Expected failure to be recorded, got fail count %d error %v", got.AutoRefreshFailCount, got.AutoRefreshError)
+	}
+
+	// Applying the same events again should not duplicate them
+	if err := db.Shipments.ApplyAutoUpdateBatch(results[:1]); err != nil {
+		t.Fatalf("ApplyAutoUpdateBatch failed on reapply: %v", err)
+	}
+	events, err = db.TrackingEvents.GetByShipmentID(refreshed.ID)
+	if err != nil {
+		t.Fatalf("Failed to get events: %v", err)
+	}
+	if len(events) != 1 {
+		t.Errorf("Expected 1 event after reapplying batch, got %d", len(events))
+	}
+}
+
 func TestShipmentStore_UpdateShipmentWithAutoRefresh_AtomicTransaction(t *testing.T) {
 	db := setupTestDB(t)
 
@@ -555,4 +643,166 @@ func TestShipmentStore_UpdateShipmentWithAutoRefresh_AtomicTransaction(t *testin
 	
 	t.Logf("Atomicity test: %d successful + 1 failed update resulted in success count %d, fail count %d", 
 		expectedCount, finalWithError.AutoRefreshCount, finalWithError.AutoRefreshFailCount)
-}
\ No newline at end of file
+}
+func TestShipmentStore_GetLastAutoRefreshTime(t *testing.T) {
+	db := setupTestDB(t)
+
+	// No shipments have been auto-refreshed yet
+	lastRefresh, err := db.Shipments.GetLastAutoRefreshTime()
+	if err != nil {
+		t.Fatalf("GetLastAutoRefreshTime failed: %v", err)
+	}
+	if lastRefresh != nil {
+		t.Errorf("Expected nil last auto refresh, got %v", lastRefresh)
+	}
+
+	older := time.Now().Add(-3 * time.Hour).UTC().Truncate(time.Second)
+	newer := time.Now().Add(-1 * time.Hour).UTC().Truncate(time.Second)
+
+	for i, refreshTime := range []time.Time{older, newer} {
+		shipment := Shipment{
+			TrackingNumber: fmt.Sprintf("LASTREFRESH%d", i),
+			Carrier:        "usps",
+			Description:    "Test Package",
+			Status:         "in_transit",
+		}
+		if err := db.Shipments.Create(&shipment); err != nil {
+			t.Fatalf("Failed to create shipment: %v", err)
+		}
+		refresh := refreshTime
+		shipment.LastAutoRefresh = &refresh
+		if err := db.Shipments.Update(shipment.ID, &shipment); err != nil {
+			t.Fatalf("Failed to update shipment: %v", err)
+		}
+	}
+
+	lastRefresh, err = db.Shipments.GetLastAutoRefreshTime()
+	if err != nil {
+		t.Fatalf("GetLastAutoRefreshTime failed: %v", err)
+	}
+	if lastRefresh == nil {
+		t.Fatal("Expected last auto refresh to be set")
+	}
+	if !lastRefresh.Equal(newer) {
+		t.Errorf("Expected last auto refresh %v, got %v", newer, *lastRefresh)
+	}
+}
+
+func TestShipmentStore_StalledTracking(t *testing.T) {
+	db := setupTestDB(t)
+
+	quiet := Shipment{TrackingNumber: "1Z999AA10000000001", Carrier: "ups", Description: "Quiet Package", Status: "in_transit"}
+	active := Shipment{TrackingNumber: "1Z999AA10000000002", Carrier: "ups", Description: "Active Package", Status: "pending"}
+	delivered := Shipment{TrackingNumber: "1Z999AA10000000003", Carrier: "ups", Description: "Delivered Package", Status: "delivered", IsDelivered: true}
+	for _, s := range []*Shipment{&quiet, &active, &delivered} {
+		if err := db.Shipments.Create(s); err != nil {
+			t.Fatalf("Failed to create test shipment: %v", err)
+		}
+	}
+
+	older := time.Now().Add(-72 * time.Hour).Truncate(time.Second)
+	newer := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	for _, ts := range []time.Time{older, newer} {
+		event := TrackingEvent{ShipmentID: quiet.ID, Timestamp: ts, Location: "Memphis, TN", Status: "in_transit", Description: "Package in transit at " + ts.String()}
+		if err := db.TrackingEvents.CreateEvent(&event); err != nil {
+			t.Fatalf("Failed to create tracking event: %v", err)
+		}
+	}
+
+	candidates, err := db.Shipments.GetStalledCandidates()
+	if err != nil {
+		t.Fatalf("GetStalledCandidates failed: %v", err)
+	}
+	if len(candidates) != 2 {
+		t.Fatalf("Expected 2 candidates (delivered excluded), got %d", len(candidates))
+	}
+	for _, c := range candidates {
+		switch c.ID {
+		case quiet.ID:
+			if c.LastEventAt == nil || !c.LastEventAt.Equal(newer) {
+				t.Errorf("Expected last event at %v, got %v", newer, c.LastEventAt)
+			}
+		case active.ID:
+			if c.LastEventAt != nil {
+				t.Errorf("Expected no last event for shipment without events, got %v", c.LastEventAt)
+			}
+		default:
+			t.Errorf("Unexpected candidate %d", c.ID)
+		}
+	}
+
+	stalledSince := time.Now().Truncate(time.Second)
+	if err := db.Shipments.SetStalled(quiet.ID, true, stalledSince); err != nil {
+		t.Fatalf("SetStalled failed: %v", err)
+	}
+
+	stored, err := db.Shipments.GetByID(quiet.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if !stored.IsStalled || stored.StalledSince == nil || !stored.StalledSince.Equal(stalledSince) {
+		t.Errorf("Expected shipment stalled since %v, got stalled=%v since=%v", stalledSince, stored.IsStalled, stored.StalledSince)
+	}
+
+	stats, err := db.Shipments.GetStats()
+	if err != nil {
+		t.Fatalf("GetStats failed: %v", err)
+	}
+	if stats.Stalled != 1 {
+		t.Errorf("Expected 1 stalled shipment in stats, got %d", stats.Stalled)
+	}
+
+	if err := db.Shipments.SetStalled(quiet.ID, false, time.Time{}); err != nil {
+		t.Fatalf("SetStalled clear failed: %v", err)
+	}
+	stored, err = db.Shipments.GetByID(quiet.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if stored.IsStalled || stored.StalledSince != nil {
+		t.Errorf("Expected stalled flag cleared, got stalled=%v since=%v", stored.IsStalled, stored.StalledSince)
+	}
+
+	if err := db.Shipments.SetStalled(99999, true, stalledSince); err != sql.ErrNoRows {
+		t.Errorf("Expected sql.ErrNoRows for missing shipment, got %v", err)
+	}
+}
+
+func TestShipmentStore_GetActivity(t *testing.T) {
+	db := setupTestDB(t)
+
+	delivered := Shipment{TrackingNumber: "1Z999AA10000000001", Carrier: "ups", Description: "Delivered Package", Status: "delivered", IsDelivered: true}
+	exception := Shipment{TrackingNumber: "1Z999AA10000000002", Carrier: "ups", Description: "Damaged Package", Status: "exception"}
+	old := Shipment{TrackingNumber: "1Z999AA10000000003", Carrier: "ups", Description: "Old Package", Status: "in_transit"}
+	for _, s := range []*Shipment{&delivered, &exception, &old} {
+		if err := db.Shipments.Create(s); err != nil {
+			t.Fatalf("Failed to create test shipment: %v", err)
+		}
+	}
+	if _, err := db.Exec(`UPDATE shipments SET created_at = datetime('now', '-3 days'), updated_at = datetime('now', '-3 days') WHERE id = ?`, old.ID); err != nil {
+		t.Fatalf("Failed to age shipment: %v", err)
+	}
+
+	activity, err := db.Shipments.GetActivity(time.Now().Add(-24*time.Hour), time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("GetActivity failed: %v", err)
+	}
+	if len(activity.Delivered) != 1 || activity.Delivered[0].ID != delivered.ID {
+		t.Errorf("Expected the delivered shipment, got %+v", activity.Delivered)
+	}
+	if len(activity.Created) != 2 {
+		t.Errorf("Expected 2 shipments created in the last day, got %+v", activity.Created)
+	}
+	if len(activity.Exceptions) != 1 || activity.Exceptions[0].ID != exception.ID {
+		t.Errorf("Expected the exception shipment, got %+v", activity.Exceptions)
+	}
+
+	// Exceptions are current, so they are reported for any period
+	activity, err = db.Shipments.GetActivity(time.Now().Add(-96*time.Hour), time.Now().Add(-48*time.Hour))
+	if err != nil {
+		t.Fatalf("GetActivity failed: %v", err)
+	}
+	if len(activity.Delivered) != 0 || len(activity.Created) != 1 || len(activity.Exceptions) != 1 {
+		t.Errorf("Unexpected activity for an earlier period: %+v", activity)
+	}
+}
@@ -0,0 +1,142 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// Outcomes of notifications
+const (
+	NotificationSent    = "sent"
+	NotificationFailed  = "failed"
+	NotificationDropped = "dropped" // The channel's queue was full
+)
+
+// NotificationRecord is a notification that was delivered to a channel, or failed to be
+type NotificationRecord struct {
+	ID         int    `json:"id"`
+	Channel    string `json:"channel"`
+	ShipmentID *int   `json:"shipment_id"` // Nil for digests
+	Source     string `json:"source"`      // What caused it, e.g. auto_update or digest
+	ToStatus   string `json:"to_status"`
+	Subject    string `json:"subject"`
+	Body       string `json:"body"`
+	Payload    string `json:"payload"` // The message as JSON, for redelivery
+	Status     string `json:"status"`
+	Attempts   int    `json:"attempts"`
+	Error      string `json:"error,omitempty"`
+	RetryOf    *int   `json:"retry_of,omitempty"` // The record a redelivery repeats
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NotificationHistoryFilter selects notification records; zero values match everything
+type NotificationHistoryFilter struct {
+	Channel    string
+	ShipmentID int
+	Status     string
+	Since      time.Time
+	Limit      int // Defaults to 100
+}
+
+// NotificationHistoryStore handles notification history operations
+type NotificationHistoryStore struct {
+	db *sql.DB
+}
+
+// NewNotificationHistoryStore creates a new notification history store
+func NewNotificationHistoryStore(db *sql.DB) *NotificationHistoryStore {
+	return &NotificationHistoryStore{db: db}
+}
+
+const notificationRecordColumns = `id, channel, shipment_id, source, to_status, subject, body,
+	payload, status, attempts, error, retry_of, created_at`
+
+// Create records a notification and sets its ID and creation time
+func (s *NotificationHistoryStore) Create(record *NotificationRecord) error {
+	query := `INSERT INTO notification_history (channel, shipment_id, source, to_status, subject,
+			  body, payload, status, attempts, error, retry_of)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := s.db.Exec(query, record.Channel, record.ShipmentID, record.Source, record.ToStatus,
+		record.Subject, record.Body, record.Payload, record.Status, record.Attempts, record.Error, record.RetryOf)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	created, err := s.GetByID(int(id))
+	if err != nil {
+		return err
+	}
+	*record = *created
+	return nil
+}
+
+// GetByID returns a record, or sql.ErrNoRows if it doesn't exist
+func (s *NotificationHistoryStore) GetByID(id int) (*NotificationRecord, error) {
+	records, err := s.query(`SELECT `+notificationRecordColumns+` FROM notification_history WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &records[0], nil
+}
+
+// List returns the records matching the filter, newest first
+func (s *NotificationHistoryStore) List(filter NotificationHistoryFilter) ([]NotificationRecord, error) {
+	var conditions []string
+	var args []any
+	if filter.Channel != "" {
+		conditions = append(conditions, "channel = ?")
+		args = append(args, filter.Channel)
+	}
+	if filter.ShipmentID != 0 {
+		conditions = append(conditions, "shipment_id = ?")
+		args = append(args, filter.ShipmentID)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "datetime(created_at) >= datetime(?)")
+		args = append(args, filter.Since.UTC().Format("2006-01-02 15:04:05"))
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	query := `SELECT ` + notificationRecordColumns + ` FROM notification_history`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY id DESC LIMIT ?`
+	return s.query(query, append(args, limit)...)
+}
+
+func (s *NotificationHistoryStore) query(query string, args ...any) ([]NotificationRecord, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []NotificationRecord
+	for rows.Next() {
+		var record NotificationRecord
+		if err := rows.Scan(&record.ID, &record.Channel, &record.ShipmentID, &record.Source,
+			&record.ToStatus, &record.Subject, &record.Body, &record.Payload, &record.Status,
+			&record.Attempts, &record.Error, &record.RetryOf, &record.CreatedAt); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestNotificationHistoryStore(t *testing.T) {
+	db := setupTestDB(t)
+
+	shipmentID := 3
+	records := []*NotificationRecord{
+		{Channel: "slack", ShipmentID: &shipmentID, Source: "auto_update", ToStatus: "delivered", Status: NotificationSent, Attempts: 1},
+		{Channel: "discord", ShipmentID: &shipmentID, Source: "auto_update", ToStatus: "delivered", Status: NotificationFailed, Attempts: 3, Error: "boom"},
+		{Channel: "slack", Source: "digest", Subject: "Daily package digest", Status: NotificationDropped},
+	}
+	for _, record := range records {
+		if err := db.Notifications.Create(record); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	if records[0].ID == 0 || records[0].CreatedAt.IsZero() {
+		t.Errorf("Expected the ID and creation time to be set, got %+v", records[0])
+	}
+
+	retryOf := records[1].ID
+	retry := &NotificationRecord{Channel: "discord", ShipmentID: &shipmentID, Status: NotificationSent, Attempts: 1, RetryOf: &retryOf}
+	if err := db.Notifications.Create(retry); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	got, err := db.Notifications.GetByID(retry.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if got.RetryOf == nil || *got.RetryOf != retryOf || *got.ShipmentID != shipmentID {
+		t.Errorf("Unexpected record %+v", got)
+	}
+	if _, err := db.Notifications.GetByID(99); err != sql.ErrNoRows {
+		t.Errorf("Expected sql.ErrNoRows for a missing record, got %v", err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		filter NotificationHistoryFilter
+		want   []int
+	}{
+		{"all, newest first", NotificationHistoryFilter{}, []int{retry.ID, records[2].ID, records[1].ID, records[0].ID}},
+		{"channel", NotificationHistoryFilter{Channel: "discord"}, []int{retry.ID, records[1].ID}},
+		{"shipment", NotificationHistoryFilter{ShipmentID: shipmentID}, []int{retry.ID, records[1].ID, records[0].ID}},
+		{"status", NotificationHistoryFilter{Status: NotificationFailed}, []int{records[1].ID}},
+		{"limit", NotificationHistoryFilter{Limit: 1}, []int{retry.ID}},
+		{"since", NotificationHistoryFilter{Since: time.Now().Add(time.Hour)}, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			list, err := db.Notifications.List(tc.filter)
+			if err != nil {
+				t.Fatalf("List failed: %v", err)
+			}
+			if len(list) != len(tc.want) {
+				t.Fatalf("Expected %d records, got %+v", len(tc.want), list)
+			}
+			for i, id := range tc.want {
+				if list[i].ID != id {
+					t.Errorf("Expected record %d at %d, got %d", id, i, list[i].ID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// NotificationRule decides which status changes are sent to which notification channels.
+// Every filter that is set must match; empty filters match everything. Once any rule is
+// enabled, transitions only reach the channels of the rules they match.
+type NotificationRule struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+
+	Carriers     []string `json:"carriers"`
+	FromStatuses []string `json:"from_statuses"`
+	ToStatuses   []string `json:"to_statuses"`
+
+	// ActiveFrom and ActiveUntil limit the rule to a time of day, as HH:MM in the server's
+	// time zone; the window may span midnight
+	ActiveFrom  string `json:"active_from"`
+	ActiveUntil string `json:"active_until"`
+
+	// ExpectedWithinDays limits the rule to shipments expected within this many days, 0
+	// being today
+	ExpectedWithinDays *int `json:"expected_within_days"`
+
+	// Channels are the channel names the rule sends to; empty means every channel
+	Channels []string `json:"channels"`
+
+	// Notifications matching the rule during quiet hours, HH:MM to HH:MM, are held until
+	// the quiet hours end
+	QuietHoursStart string `json:"quiet_hours_start"`
+	QuietHoursEnd   string `json:"quiet_hours_end"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NotificationRuleStore handles notification rule operations
+type NotificationRuleStore struct {
+	db *sql.DB
+}
+
+// NewNotificationRuleStore creates a new notification rule store
+func NewNotificationRuleStore(db *sql.DB) *NotificationRuleStore {
+	return &NotificationRuleStore{db: db}
+}
+
+const notificationRuleColumns = `id, name, enabled, carriers, from_statuses, to_statuses,
+	active_from, active_until, expected_within_days, channels, quiet_hours_start,
+	quiet_hours_end, created_at, updated_at`
+
+// GetAll returns every rule, oldest first
+func (s *NotificationRuleStore) GetAll() ([]NotificationRule, error) {
+	return s.query(`SELECT ` + notificationRuleColumns + ` FROM notification_rules ORDER BY id`)
+}
+
+// GetEnabled returns the enabled rules, oldest first
+func (s *NotificationRuleStore) GetEnabled() ([]NotificationRule, error) {
+	return s.query(`SELECT ` + notificationRuleColumns + ` FROM notification_rules WHERE enabled = TRUE ORDER BY id`)
+}
+
+// GetByID returns a rule, or sql.ErrNoRows if it doesn't exist
+func (s *NotificationRuleStore) GetByID(id int) (*NotificationRule, error) {
+	rules, err := s.query(`SELECT `+notificationRuleColumns+` FROM notification_rules WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &rules[0], nil
+}
+
+// Create creates a rule and sets its ID and timestamps
+func (s *NotificationRuleStore) Create(rule *NotificationRule) error {
+	query := `INSERT INTO notification_rules (name, enabled, carriers, from_statuses, to_statuses,
+			  active_from, active_until, expected_within_days, channels, quiet_hours_start, quiet_hours_end)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := s.db.Exec(query, rule.Name, rule.Enabled, joinList(rule.Carriers),
+		joinList(rule.FromStatuses), joinList(rule.ToStatuses), rule.ActiveFrom, rule.ActiveUntil,
+		rule.ExpectedWithinDays, joinList(rule.Channels), rule.QuietHoursStart, rule.QuietHoursEnd)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	created, err := s.GetByID(int(id))
+	if err != nil {
+		return err
+	}
+	*rule = *created
+	return nil
+}
+
+// Update replaces a rule, or returns sql.ErrNoRows if it doesn't exist
+func (s *NotificationRuleStore) Update(id int, rule *NotificationRule) error {
+	query := `UPDATE notification_rules SET name = ?, enabled = ?, carriers = ?, from_statuses = ?,
+			  to_statuses = ?, active_from = ?, active_until = ?, expected_within_days = ?,
+			  channels = ?, quiet_hours_start = ?, quiet_hours_end = ?, updated_at = CURRENT_TIMESTAMP
+			  WHERE id = ?`
+
+	result, err := s.db.Exec(query, rule.Name, rule.Enabled, joinList(rule.Carriers),
+		joinList(rule.FromStatuses), joinList(rule.ToStatuses), rule.ActiveFrom, rule.ActiveUntil,
+		rule.ExpectedWithinDays, joinList(rule.Channels), rule.QuietHoursStart, rule.QuietHoursEnd, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	updated, err := s.GetByID(id)
+	if err != nil {
+		return err
+	}
+	*rule = *updated
+	return nil
+}
+
+// Delete deletes a rule, or returns sql.ErrNoRows if it doesn't exist
+func (s *NotificationRuleStore) Delete(id int) error {
+	result, err := s.db.Exec(`DELETE FROM notification_rules WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (s *NotificationRuleStore) query(query string, args ...any) ([]NotificationRule, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []NotificationRule
+	for rows.Next() {
+		var rule NotificationRule
+		var carriers, fromStatuses, toStatuses, channels string
+		var expectedWithinDays sql.NullInt64
+		if err := rows.Scan(&rule.ID, &rule.Name, &rule.Enabled, &carriers, &fromStatuses,
+			&toStatuses, &rule.ActiveFrom, &rule.ActiveUntil, &expectedWithinDays, &channels,
+			&rule.QuietHoursStart, &rule.QuietHoursEnd, &rule.CreatedAt, &rule.UpdatedAt); err != nil {
+			return nil, err
+		}
+		rule.Carriers = splitList(carriers)
+		rule.FromStatuses = splitList(fromStatuses)
+		rule.ToStatuses = splitList(toStatuses)
+		rule.Channels = splitList(channels)
+		if expectedWithinDays.Valid {
+			days := int(expectedWithinDays.Int64)
+			rule.ExpectedWithinDays = &days
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+// joinList stores a list as comma-separated text
+func joinList(values []string) string {
+	return strings.Join(values, ",")
+}
+
+// splitList reads a list stored by joinList; an empty list reads as an empty slice, so
+// it is encoded as [] rather than null
+func splitList(value string) []string {
+	values := []string{}
+	for _, v := range strings.Split(value, ",") {
+		if v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestNotificationRuleStore(t *testing.T) {
+	db := setupTestDB(t)
+
+	days := 1
+	rule := &NotificationRule{
+		Name:               "Arriving soon",
+		Enabled:            true,
+		ToStatuses:         []string{"out_for_delivery", "delivered"},
+		ExpectedWithinDays: &days,
+		Channels:           []string{"discord:home"},
+		QuietHoursStart:    "22:00",
+		QuietHoursEnd:      "07:00",
+	}
+	if err := db.Rules.Create(rule); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if rule.ID == 0 || rule.CreatedAt.IsZero() {
+		t.Fatalf("Expected the ID and timestamps to be set, got %+v", rule)
+	}
+
+	got, err := db.Rules.GetByID(rule.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if len(got.ToStatuses) != 2 || got.ToStatuses[1] != "delivered" || len(got.Carriers) != 0 ||
+		got.ExpectedWithinDays == nil || *got.ExpectedWithinDays != 1 || got.Channels[0] != "discord:home" {
+		t.Errorf("Unexpected rule: %+v", got)
+	}
+
+	disabled := &NotificationRule{Name: "Paused"}
+	if err := db.Rules.Create(disabled); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	enabled, err := db.Rules.GetEnabled()
+	if err != nil {
+		t.Fatalf("GetEnabled failed: %v", err)
+	}
+	if len(enabled) != 1 || enabled[0].ID != rule.ID {
+		t.Errorf("Expected only the enabled rule, got %+v", enabled)
+	}
+
+	rule.ExpectedWithinDays = nil
+	rule.Carriers = []string{"ups"}
+	if err := db.Rules.Update(rule.ID, rule); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if rule.ExpectedWithinDays != nil || len(rule.Carriers) != 1 {
+		t.Errorf("Expected the update to be stored, got %+v", rule)
+	}
+
+	if err := db.Rules.Delete(rule.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := db.Rules.GetByID(rule.ID); err != sql.ErrNoRows {
+		t.Errorf("Expected sql.ErrNoRows after delete, got %v", err)
+	}
+	if err := db.Rules.Update(rule.ID, rule); err != sql.ErrNoRows {
+		t.Errorf("Expected sql.ErrNoRows updating a deleted rule, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// QuotaStore is a persistent ledger of carrier API calls per day, so daily carrier
+// budgets survive server restarts
+type QuotaStore struct {
+	db *sql.DB
+}
+
+// NewQuotaStore creates a new quota store
+func NewQuotaStore(db *sql.DB) *QuotaStore {
+	return &QuotaStore{db: db}
+}
+
+// quotaDay returns the ledger key for the day containing t. Carrier quotas reset at
+// midnight UTC, so days are keyed in UTC.
+func quotaDay(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// RecordCall records a single carrier API call made at the given time
+func (q *QuotaStore) RecordCall(carrier string, at time.Time) error {
+	query := `INSERT INTO carrier_api_usage (carrier, usage_date, calls, updated_at)
+			  VALUES (?, ?, 1, ?)
+			  ON CONFLICT(carrier, usage_date) DO UPDATE SET calls = calls + 1, updated_at = excluded.updated_at`
+
+	if _, err := q.db.Exec(query, carrier, quotaDay(at), at); err != nil {
+		return fmt.Errorf("failed to record API call for %s: %w", carrier, err)
+	}
+	return nil
+}
+
+// GetUsage returns the number of API calls recorded for a carrier on the day containing t
+func (q *QuotaStore) GetUsage(carrier string, t time.Time) (int, error) {
+	var calls int
+	err := q.db.QueryRow(`SELECT calls FROM carrier_api_usage WHERE carrier = ? AND usage_date = ?`,
+		carrier, quotaDay(t)).Scan(&calls)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get API usage for %s: %w", carrier, err)
+	}
+	return calls, nil
+}
+
+// PruneBefore deletes ledger entries for days before the day containing t
+func (q *QuotaStore) PruneBefore(t time.Time) (int64, error) {
+	result, err := q.db.Exec(`DELETE FROM carrier_api_usage WHERE usage_date < ?`, quotaDay(t))
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune API usage ledger: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuotaStore(t *testing.T) {
+	db := setupTestDB(t)
+
+	day := time.Date(2024, 6, 1, 15, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		if err := db.Quota.RecordCall("dhl", day.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("RecordCall failed: %v", err)
+		}
+	}
+	if err := db.Quota.RecordCall("ups", day); err != nil {
+		t.Fatalf("RecordCall failed: %v", err)
+	}
+	if err := db.Quota.RecordCall("dhl", day.AddDate(0, 0, 1)); err != nil {
+		t.Fatalf("RecordCall failed: %v", err)
+	}
+
+	tests := []struct {
+		carrier string
+		at      time.Time
+		want    int
+	}{
+		{"dhl", day, 3},
+		{"dhl", time.Date(2024, 6, 1, 23, 59, 0, 0, time.UTC), 3},
+		{"ups", day, 1},
+		{"usps", day, 0},
+		{"dhl", day.AddDate(0, 0, 1), 1},
+	}
+	for _, tt := range tests {
+		got, err := db.Quota.GetUsage(tt.carrier, tt.at)
+		if err != nil {
+			t.Fatalf("GetUsage failed: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("GetUsage(%s, %v) = %d, want %d", tt.carrier, tt.at, got, tt.want)
+		}
+	}
+
+	pruned, err := db.Quota.PruneBefore(day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("PruneBefore failed: %v", err)
+	}
+	if pruned != 2 {
+		t.Errorf("Expected 2 pruned ledger rows, got %d", pruned)
+	}
+	if got, _ := db.Quota.GetUsage("dhl", day.AddDate(0, 0, 1)); got != 1 {
+		t.Errorf("Expected next day's usage to survive pruning, got %d", got)
+	}
+}
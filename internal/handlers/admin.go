@@ -12,31 +12,28 @@ import (
 // AdminHandler handles administrative operations
 type AdminHandler struct {
 	trackingUpdater     *workers.TrackingUpdater
+	emailCleanup        *workers.EmailCleanupWorker
 	descriptionEnhancer *services.DescriptionEnhancer
 	logger              *slog.Logger
 }
 
 // NewAdminHandler creates a new admin handler
-func NewAdminHandler(trackingUpdater *workers.TrackingUpdater, descriptionEnhancer *services.DescriptionEnhancer, logger *slog.Logger) *AdminHandler {
+func NewAdminHandler(trackingUpdater *workers.TrackingUpdater, emailCleanup *workers.EmailCleanupWorker, descriptionEnhancer *services.DescriptionEnhancer, logger *slog.Logger) *AdminHandler {
 	return &AdminHandler{
 		trackingUpdater:     trackingUpdater,
+		emailCleanup:        emailCleanup,
 		descriptionEnhancer: descriptionEnhancer,
 		logger:              logger,
 	}
 }
 
-// TrackingUpdaterStatusResponse represents the status of the tracking updater
-type TrackingUpdaterStatusResponse struct {
-	Running bool `json:"running"`
-	Paused  bool `json:"paused"`
-}
+// TrackingUpdaterStatusResponse represents the status of the tracking updater,
+// including per-carrier run statistics and backoff state
+type TrackingUpdaterStatusResponse = workers.UpdaterStatus
 
 // GetTrackingUpdaterStatus handles GET /api/admin/tracking-updater/status
 func (h *AdminHandler) GetTrackingUpdaterStatus(w http.ResponseWriter, r *http.Request) {
-	status := TrackingUpdaterStatusResponse{
-		Running: h.trackingUpdater.IsRunning(),
-		Paused:  h.trackingUpdater.IsPaused(),
-	}
+	status := h.trackingUpdater.Status()
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -67,6 +64,32 @@ func (h *AdminHandler) ResumeTrackingUpdater(w http.ResponseWriter, r *http.Requ
 	})
 }
 
+// GetEmailCleanupStatus handles GET /api/admin/email-cleanup/status
+func (h *AdminHandler) GetEmailCleanupStatus(w http.ResponseWriter, r *http.Request) {
+	status := h.emailCleanup.Status()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(status)
+}
+
+// RunEmailCleanup handles POST /api/admin/email-cleanup/run
+func (h *AdminHandler) RunEmailCleanup(w http.ResponseWriter, r *http.Request) {
+	report, err := h.emailCleanup.RunNow()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		// The report carries the error along with whatever was cleaned before it failed
+		h.logger.Error("Manual email cleanup failed", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(report)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
+
 // EnhanceDescriptionsRequest represents the request body for description enhancement
 type EnhanceDescriptionsRequest struct {
 	ShipmentID *int `json:"shipment_id,omitempty"`
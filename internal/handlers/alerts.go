@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"package-tracking/internal/database"
+)
+
+// AlertHandler lists escalated exceptions and delays and acknowledges them
+type AlertHandler struct {
+	db *database.DB
+}
+
+// NewAlertHandler creates a new alert handler
+func NewAlertHandler(db *database.DB) *AlertHandler {
+	return &AlertHandler{db: db}
+}
+
+// GetAlerts handles GET /api/alerts. Only open alerts are listed unless ?all=true.
+func (h *AlertHandler) GetAlerts(w http.ResponseWriter, r *http.Request) {
+	alerts, err := h.db.Alerts.GetAll(r.URL.Query().Get("all") == "true")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get alerts: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if alerts == nil {
+		alerts = []database.Alert{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(alerts)
+}
+
+// AcknowledgeAlert handles POST /api/alerts/{id}/acknowledge, which stops reminders about
+// the alert
+func (h *AlertHandler) AcknowledgeAlert(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid alert ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.db.Alerts.Acknowledge(id, time.Now()); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Alert not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to acknowledge alert: %v", err), http.StatusInternalServerError)
+		return
+	}
+	alert, err := h.db.Alerts.GetByID(id)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get alert: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(alert)
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"package-tracking/internal/database"
+)
+
+func TestAlerts_Acknowledge(t *testing.T) {
+	db, err := database.Open(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	handler := NewAlertHandler(db)
+	router := chi.NewRouter()
+	router.Get("/api/alerts", handler.GetAlerts)
+	router.Post("/api/alerts/{id}/acknowledge", handler.AcknowledgeAlert)
+
+	shipment := &database.Shipment{TrackingNumber: "1Z999AA10123456784", Carrier: "ups", Status: "exception"}
+	if err := db.Shipments.Create(shipment); err != nil {
+		t.Fatalf("Failed to create shipment: %v", err)
+	}
+	alert, _, err := db.Alerts.Open(shipment.ID, database.AlertKindException, "Delivery exception")
+	if err != nil {
+		t.Fatalf("Failed to open alert: %v", err)
+	}
+	resolved, _, err := db.Alerts.Open(shipment.ID, database.AlertKindDelay, "Late")
+	if err != nil {
+		t.Fatalf("Failed to open alert: %v", err)
+	}
+	if err := db.Alerts.Resolve(resolved.ID, time.Now()); err != nil {
+		t.Fatalf("Failed to resolve alert: %v", err)
+	}
+
+	w := doJSON(router, "GET", "/api/alerts", "")
+	var alerts []database.Alert
+	json.NewDecoder(w.Body).Decode(&alerts)
+	if w.Code != http.StatusOK || len(alerts) != 1 || alerts[0].ID != alert.ID {
+		t.Fatalf("Expected only the open alert, got %d: %+v", w.Code, alerts)
+	}
+	w = doJSON(router, "GET", "/api/alerts?all=true", "")
+	json.NewDecoder(w.Body).Decode(&alerts)
+	if len(alerts) != 2 {
+		t.Errorf("Expected resolved alerts with all=true, got %+v", alerts)
+	}
+
+	w = doJSON(router, "POST", "/api/alerts/1/acknowledge", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var acknowledged database.Alert
+	json.NewDecoder(w.Body).Decode(&acknowledged)
+	if acknowledged.AcknowledgedAt == nil {
+		t.Errorf("Expected the alert to be acknowledged, got %+v", acknowledged)
+	}
+
+	if w := doJSON(router, "POST", "/api/alerts/99/acknowledge", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a missing alert, got %d", w.Code)
+	}
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"package-tracking/internal/config"
+)
+
+// ConfigHandler handles configuration reloads
+type ConfigHandler struct {
+	reloader *config.Reloader
+}
+
+// NewConfigHandler creates a new config handler
+func NewConfigHandler(reloader *config.Reloader) *ConfigHandler {
+	return &ConfigHandler{reloader: reloader}
+}
+
+// ReloadConfig handles POST /api/admin/config/reload. It rereads the configuration file
+// and environment, applies the settings that can change while running, and reports the
+// changed settings that need a restart.
+func (h *ConfigHandler) ReloadConfig(w http.ResponseWriter, r *http.Request) {
+	report, err := h.reloader.Reload()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to reload configuration: %v", err), http.StatusUnprocessableEntity)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"package-tracking/internal/config"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestConfigHandler_ReloadConfig(t *testing.T) {
+	live := &config.Config{UpdateInterval: time.Hour}
+	next := &config.Config{UpdateInterval: time.Minute}
+	var loadErr error
+	handler := NewConfigHandler(config.NewReloader(live, func() (*config.Config, error) { return next, loadErr }))
+	router := chi.NewRouter()
+	router.Post("/api/admin/config/reload", handler.ReloadConfig)
+
+	w := doJSON(router, "POST", "/api/admin/config/reload", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var report config.ReloadReport
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Applied) != 1 || report.Applied[0] != "update.interval" || live.UpdateInterval != time.Minute {
+		t.Errorf("Expected the update interval to be applied, got %+v", report)
+	}
+
+	loadErr = errors.New("invalid update interval")
+	if w := doJSON(router, "POST", "/api/admin/config/reload", ""); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422 for an invalid configuration, got %d", w.Code)
+	}
+}
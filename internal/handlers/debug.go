@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	runtimepprof "runtime/pprof"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// DebugHandler exposes runtime diagnostics for live instances: pprof profiles, expvar
+// variables and a snapshot of goroutines and the heap. It is mounted under the admin
+// routes, so it requires admin authentication.
+type DebugHandler struct {
+	started time.Time
+}
+
+// NewDebugHandler creates a new debug handler
+func NewDebugHandler() *DebugHandler {
+	return &DebugHandler{started: time.Now()}
+}
+
+// RuntimeSnapshot is a point-in-time view of the process's goroutines and memory
+type RuntimeSnapshot struct {
+	Time       time.Time `json:"time"`
+	Uptime     string    `json:"uptime"`
+	GoVersion  string    `json:"go_version"`
+	NumCPU     int       `json:"num_cpu"`
+	GOMAXPROCS int       `json:"gomaxprocs"`
+	Goroutines int       `json:"goroutines"`
+
+	HeapAlloc    uint64 `json:"heap_alloc_bytes"`
+	HeapInuse    uint64 `json:"heap_inuse_bytes"`
+	HeapIdle     uint64 `json:"heap_idle_bytes"`
+	HeapReleased uint64 `json:"heap_released_bytes"`
+	HeapObjects  uint64 `json:"heap_objects"`
+	StackInuse   uint64 `json:"stack_inuse_bytes"`
+	Sys          uint64 `json:"sys_bytes"`
+	NumGC        uint32 `json:"num_gc"`
+	PauseTotal   string `json:"gc_pause_total"`
+	LastGC       string `json:"last_gc,omitempty"`
+
+	// GoroutineStacks groups the goroutines by stack, with ?stacks=true
+	GoroutineStacks string `json:"goroutine_stacks,omitempty"`
+}
+
+// GetRuntime handles GET /api/admin/debug/runtime. ?gc=true collects garbage first, so
+// the heap figures show live memory; ?stacks=true adds the goroutines' stacks.
+func (h *DebugHandler) GetRuntime(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("gc") == "true" {
+		runtime.GC()
+	}
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	snapshot := RuntimeSnapshot{
+		Time:         time.Now(),
+		Uptime:       time.Since(h.started).Truncate(time.Second).String(),
+		GoVersion:    runtime.Version(),
+		NumCPU:       runtime.NumCPU(),
+		GOMAXPROCS:   runtime.GOMAXPROCS(0),
+		Goroutines:   runtime.NumGoroutine(),
+		HeapAlloc:    mem.HeapAlloc,
+		HeapInuse:    mem.HeapInuse,
+		HeapIdle:     mem.HeapIdle,
+		HeapReleased: mem.HeapReleased,
+		HeapObjects:  mem.HeapObjects,
+		StackInuse:   mem.StackInuse,
+		Sys:          mem.Sys,
+		NumGC:        mem.NumGC,
+		PauseTotal:   time.Duration(mem.PauseTotalNs).String(),
+	}
+	if mem.LastGC > 0 {
+		snapshot.LastGC = time.Unix(0, int64(mem.LastGC)).Format(time.RFC3339)
+	}
+	if r.URL.Query().Get("stacks") == "true" {
+		var stacks bytes.Buffer
+		if err := runtimepprof.Lookup("goroutine").WriteTo(&stacks, 1); err == nil {
+			snapshot.GoroutineStacks = stacks.String()
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(snapshot)
+}
+
+// GetVars handles GET /api/admin/debug/vars, the expvar variables including memstats
+func (h *DebugHandler) GetVars(w http.ResponseWriter, r *http.Request) {
+	expvar.Handler().ServeHTTP(w, r)
+}
+
+// Pprof handles GET /api/admin/debug/pprof/*: the profile index, and the named profiles
+// such as heap, goroutine, profile (CPU, ?seconds=N) and trace. CPU profiles and traces
+// must be shorter than the server's write timeout.
+func (h *DebugHandler) Pprof(w http.ResponseWriter, r *http.Request) {
+	// The pprof handlers expect to be served from /debug/pprof/, so they are dispatched by
+	// name rather than mounted
+	switch name := chi.URLParam(r, "*"); name {
+	case "":
+		pprof.Index(w, r)
+	case "cmdline":
+		pprof.Cmdline(w, r)
+	case "profile":
+		pprof.Profile(w, r)
+	case "symbol":
+		pprof.Symbol(w, r)
+	case "trace":
+		pprof.Trace(w, r)
+	default:
+		if runtimepprof.Lookup(name) == nil {
+			http.Error(w, "Unknown profile", http.StatusNotFound)
+			return
+		}
+		pprof.Handler(name).ServeHTTP(w, r)
+	}
+}
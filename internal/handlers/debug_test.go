@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestDebugHandler(t *testing.T) {
+	handler := NewDebugHandler()
+	router := chi.NewRouter()
+	router.Get("/api/admin/debug/runtime", handler.GetRuntime)
+	router.Get("/api/admin/debug/vars", handler.GetVars)
+	router.Get("/api/admin/debug/pprof/*", handler.Pprof)
+
+	w := doJSON(router, "GET", "/api/admin/debug/runtime?gc=true&stacks=true", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	var snapshot RuntimeSnapshot
+	if err := json.NewDecoder(w.Body).Decode(&snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Goroutines == 0 || snapshot.HeapAlloc == 0 || snapshot.NumGC == 0 || !strings.Contains(snapshot.GoroutineStacks, "goroutine profile") {
+		t.Errorf("Unexpected snapshot %+v", snapshot)
+	}
+
+	if w := doJSON(router, "GET", "/api/admin/debug/vars", ""); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "memstats") {
+		t.Errorf("Expected expvar variables, got %d", w.Code)
+	}
+
+	if w := doJSON(router, "GET", "/api/admin/debug/pprof/", ""); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "heap") {
+		t.Errorf("Expected the profile index, got %d", w.Code)
+	}
+	if w := doJSON(router, "GET", "/api/admin/debug/pprof/goroutine?debug=1", ""); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "goroutine profile") {
+		t.Errorf("Expected the goroutine profile, got %d", w.Code)
+	}
+	if w := doJSON(router, "GET", "/api/admin/debug/pprof/heap", ""); w.Code != http.StatusOK || w.Body.Len() == 0 {
+		t.Errorf("Expected the heap profile, got %d", w.Code)
+	}
+	if w := doJSON(router, "GET", "/api/admin/debug/pprof/nonsense", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown profile, got %d", w.Code)
+	}
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"package-tracking/internal/database"
+	"package-tracking/internal/notifications"
+)
+
+// NotificationHistoryHandler lists sent and failed notifications and redelivers them
+type NotificationHistoryHandler struct {
+	db         *database.DB
+	dispatcher *notifications.Dispatcher
+}
+
+// NewNotificationHistoryHandler creates a new notification history handler. dispatcher
+// may be nil when notifications are disabled.
+func NewNotificationHistoryHandler(db *database.DB, dispatcher *notifications.Dispatcher) *NotificationHistoryHandler {
+	return &NotificationHistoryHandler{db: db, dispatcher: dispatcher}
+}
+
+// GetNotifications handles GET /api/notifications, newest first. It can be filtered by
+// ?channel=, ?shipment_id=, ?status= (sent, failed or dropped) and ?since= (RFC 3339),
+// and returns at most ?limit= records, 100 by default.
+func (h *NotificationHistoryHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := database.NotificationHistoryFilter{
+		Channel: query.Get("channel"),
+		Status:  query.Get("status"),
+	}
+
+	if value := query.Get("shipment_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			http.Error(w, "Invalid shipment ID", http.StatusBadRequest)
+			return
+		}
+		filter.ShipmentID = id
+	}
+	if value := query.Get("since"); value != "" {
+		since, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w, "Invalid since time, expected RFC 3339", http.StatusBadRequest)
+			return
+		}
+		filter.Since = since
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		filter.Limit = limit
+	}
+	switch filter.Status {
+	case "", database.NotificationSent, database.NotificationFailed, database.NotificationDropped:
+	default:
+		http.Error(w, "Invalid status, expected sent, failed or dropped", http.StatusBadRequest)
+		return
+	}
+
+	records, err := h.db.Notifications.List(filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get notifications: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if records == nil {
+		records = []database.NotificationRecord{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(records)
+}
+
+// NotificationRetryResponse acknowledges a queued redelivery
+type NotificationRetryResponse struct {
+	RetryOf int    `json:"retry_of"`
+	Channel string `json:"channel"`
+}
+
+// RetryNotification handles POST /api/notifications/{id}/retry, which queues the
+// notification for its channel again. The outcome shows up as a new record.
+func (h *NotificationHistoryHandler) RetryNotification(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid notification ID", http.StatusBadRequest)
+		return
+	}
+
+	record, err := h.db.Notifications.GetByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Notification not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to get notification: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.dispatcher.Redeliver(*record); err != nil {
+		switch {
+		case errors.Is(err, notifications.ErrNotRunning):
+			http.Error(w, "Notifications are disabled", http.StatusConflict)
+		case errors.Is(err, notifications.ErrUnknownChannel):
+			http.Error(w, fmt.Sprintf("Failed to retry notification: %v", err), http.StatusBadRequest)
+		case errors.Is(err, notifications.ErrQueueFull):
+			http.Error(w, fmt.Sprintf("Failed to retry notification: %v", err), http.StatusServiceUnavailable)
+		default:
+			http.Error(w, fmt.Sprintf("Failed to retry notification: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(NotificationRetryResponse{RetryOf: record.ID, Channel: record.Channel})
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"package-tracking/internal/database"
+	"package-tracking/internal/notifications"
+)
+
+func TestNotificationHistory(t *testing.T) {
+	db, err := database.Open(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	dispatcher := notifications.NewDispatcher(notifications.RetryPolicy{MaxAttempts: 1}, 10, logger)
+	if err := dispatcher.AddChannel(notifications.NewLogNotifier(logger), notifications.ChannelConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	dispatcher.SetHistory(db.Notifications)
+	dispatcher.Start()
+	defer dispatcher.Stop()
+
+	handler := NewNotificationHistoryHandler(db, dispatcher)
+	router := chi.NewRouter()
+	router.Get("/api/notifications", handler.GetNotifications)
+	router.Post("/api/notifications/{id}/retry", handler.RetryNotification)
+
+	shipmentID := 1
+	for _, record := range []*database.NotificationRecord{
+		{Channel: "log", ShipmentID: &shipmentID, Status: database.NotificationFailed, Payload: `{"Subject": "Delivered"}`, Error: "timeout"},
+		{Channel: "log", Status: database.NotificationSent, Payload: `{}`},
+		{Channel: "gone", Status: database.NotificationFailed, Payload: `{}`},
+	} {
+		if err := db.Notifications.Create(record); err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+	}
+
+	var records []database.NotificationRecord
+	w := doJSON(router, "GET", "/api/notifications?status=failed&channel=log", "")
+	json.NewDecoder(w.Body).Decode(&records)
+	if w.Code != http.StatusOK || len(records) != 1 || records[0].Error != "timeout" {
+		t.Fatalf("Expected the failed record, got %d: %+v", w.Code, records)
+	}
+	w = doJSON(router, "GET", "/api/notifications?shipment_id=1&since=2000-01-01T00:00:00Z&limit=5", "")
+	json.NewDecoder(w.Body).Decode(&records)
+	if w.Code != http.StatusOK || len(records) != 1 || records[0].ID != 1 {
+		t.Errorf("Expected the shipment's record, got %d: %+v", w.Code, records)
+	}
+	for _, query := range []string{"shipment_id=x", "since=yesterday", "limit=0", "status=lost"} {
+		if w := doJSON(router, "GET", "/api/notifications?"+query, ""); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", query, w.Code)
+		}
+	}
+
+	w = doJSON(router, "POST", "/api/notifications/1/retry", "")
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d: %s", w.Code, w.Body.String())
+	}
+	var retry NotificationRetryResponse
+	json.NewDecoder(w.Body).Decode(&retry)
+	if retry.RetryOf != 1 || retry.Channel != "log" {
+		t.Errorf("Unexpected retry response %+v", retry)
+	}
+
+	if w := doJSON(router, "POST", "/api/notifications/3/retry", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a removed channel, got %d", w.Code)
+	}
+	if w := doJSON(router, "POST", "/api/notifications/99/retry", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a missing record, got %d", w.Code)
+	}
+
+	disabled := chi.NewRouter()
+	disabled.Post("/api/notifications/{id}/retry", NewNotificationHistoryHandler(db, nil).RetryNotification)
+	if w := doJSON(disabled, "POST", "/api/notifications/1/retry", ""); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 with notifications disabled, got %d", w.Code)
+	}
+}
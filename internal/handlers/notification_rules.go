@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"package-tracking/internal/database"
+	"package-tracking/internal/notifications"
+)
+
+// NotificationRuleHandler manages notification rules
+type NotificationRuleHandler struct {
+	db         *database.DB
+	dispatcher *notifications.Dispatcher
+}
+
+// NewNotificationRuleHandler creates a new notification rule handler. dispatcher may be
+// nil when notifications are disabled.
+func NewNotificationRuleHandler(db *database.DB, dispatcher *notifications.Dispatcher) *NotificationRuleHandler {
+	return &NotificationRuleHandler{db: db, dispatcher: dispatcher}
+}
+
+// GetRules handles GET /api/admin/notification-rules
+func (h *NotificationRuleHandler) GetRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.db.Rules.GetAll()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get notification rules: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if rules == nil {
+		rules = []database.NotificationRule{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(rules)
+}
+
+// CreateRule handles POST /api/admin/notification-rules
+func (h *NotificationRuleHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	rule, ok := h.decodeRule(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.db.Rules.Create(&rule); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to create notification rule: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rule)
+}
+
+// GetRule handles GET /api/admin/notification-rules/{id}
+func (h *NotificationRuleHandler) GetRule(w http.ResponseWriter, r *http.Request) {
+	rule, ok := h.getRule(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(rule)
+}
+
+// UpdateRule handles PUT /api/admin/notification-rules/{id}
+func (h *NotificationRuleHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid rule ID", http.StatusBadRequest)
+		return
+	}
+	rule, ok := h.decodeRule(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.db.Rules.Update(id, &rule); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Notification rule not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to update notification rule: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(rule)
+}
+
+// DeleteRule handles DELETE /api/admin/notification-rules/{id}
+func (h *NotificationRuleHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid rule ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.db.Rules.Delete(id); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Notification rule not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to delete notification rule: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RuleTestRequest is the body of a rule test. The transition defaults to the shipment's
+// current status, from no previous status.
+type RuleTestRequest struct {
+	ShipmentID int    `json:"shipment_id"`
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	Send       bool   `json:"send"` // Also deliver the messages, ignoring filters and quiet hours
+}
+
+// RuleTestResponse is what a rule would do with the test transition
+type RuleTestResponse struct {
+	RuleID   int                            `json:"rule_id"`
+	Result   notifications.RuleResult       `json:"result"`
+	Channels []notifications.ChannelPreview `json:"channels"`
+	SentTo   []string                       `json:"sent_to,omitempty"`
+}
+
+// TestRule handles POST /api/admin/notification-rules/{id}/test. It reports whether the
+// rule matches a transition of a shipment and previews the messages its channels would
+// be sent, optionally sending them.
+func (h *NotificationRuleHandler) TestRule(w http.ResponseWriter, r *http.Request) {
+	rule, ok := h.getRule(w, r)
+	if !ok {
+		return
+	}
+
+	var req RuleTestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if h.dispatcher == nil {
+		http.Error(w, "Notifications are disabled", http.StatusConflict)
+		return
+	}
+
+	shipment, err := h.db.Shipments.GetByID(req.ShipmentID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Shipment not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to get shipment: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	transition := notifications.Transition{
+		Shipment:   *shipment,
+		FromStatus: req.FromStatus,
+		ToStatus:   req.ToStatus,
+		Source:     "rule_test",
+		OccurredAt: time.Now(),
+	}
+	if transition.ToStatus == "" {
+		transition.ToStatus = shipment.Status
+	}
+	if events, err := h.db.TrackingEvents.GetByShipmentID(shipment.ID); err == nil {
+		transition.SetEvents(events)
+	} else {
+		log.Printf("WARN: Failed to get events for rule test of shipment %d: %v", shipment.ID, err)
+	}
+
+	response := RuleTestResponse{
+		RuleID:   rule.ID,
+		Result:   notifications.EvaluateRule(*rule, transition, time.Now()),
+		Channels: h.dispatcher.PreviewRule(*rule, transition),
+	}
+	if req.Send {
+		response.SentTo = h.dispatcher.SendRuleTest(*rule, transition)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// getRule loads the rule named by the URL, writing an error response if it can't
+func (h *NotificationRuleHandler) getRule(w http.ResponseWriter, r *http.Request) (*database.NotificationRule, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid rule ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	rule, err := h.db.Rules.GetByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Notification rule not found", http.StatusNotFound)
+			return nil, false
+		}
+		http.Error(w, fmt.Sprintf("Failed to get notification rule: %v", err), http.StatusInternalServerError)
+		return nil, false
+	}
+	return rule, true
+}
+
+// decodeRule decodes and validates a rule from the request body, writing an error
+// response if it is invalid. Rules are enabled unless the body says otherwise.
+func (h *NotificationRuleHandler) decodeRule(w http.ResponseWriter, r *http.Request) (database.NotificationRule, bool) {
+	rule := database.NotificationRule{Enabled: true}
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return rule, false
+	}
+
+	notifications.NormalizeRule(&rule)
+	if err := notifications.ValidateRule(rule, h.dispatcher.Channels()); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid notification rule: %v", err), http.StatusBadRequest)
+		return rule, false
+	}
+	return rule, true
+}
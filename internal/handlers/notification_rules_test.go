@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"package-tracking/internal/database"
+	"package-tracking/internal/notifications"
+)
+
+func newNotificationRuleRouter(t *testing.T) (http.Handler, *database.DB) {
+	db, err := database.Open(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	dispatcher := notifications.NewDispatcher(notifications.RetryPolicy{MaxAttempts: 1}, 10, logger)
+	if err := dispatcher.AddChannel(notifications.NewLogNotifier(logger), notifications.ChannelConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := NewNotificationRuleHandler(db, dispatcher)
+	r := chi.NewRouter()
+	r.Get("/api/admin/notification-rules", handler.GetRules)
+	r.Post("/api/admin/notification-rules", handler.CreateRule)
+	r.Put("/api/admin/notification-rules/{id}", handler.UpdateRule)
+	r.Post("/api/admin/notification-rules/{id}/test", handler.TestRule)
+	return r, db
+}
+
+func doJSON(router http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestNotificationRules_Create(t *testing.T) {
+	router, _ := newNotificationRuleRouter(t)
+
+	w := doJSON(router, "POST", "/api/admin/notification-rules",
+		`{"name": "Deliveries", "to_statuses": ["Delivered"], "channels": ["log"], "quiet_hours_start": "22:00", "quiet_hours_end": "07:00"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var rule database.NotificationRule
+	json.NewDecoder(w.Body).Decode(&rule)
+	if !rule.Enabled || rule.ToStatuses[0] != "delivered" || len(rule.Carriers) != 0 {
+		t.Errorf("Expected an enabled rule with normalized statuses, got %+v", rule)
+	}
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"unknown channel", `{"name": "x", "channels": ["slack"]}`},
+		{"bad time", `{"name": "x", "active_from": "9", "active_until": "17:00"}`},
+		{"no name", `{}`},
+	}
+	for _, tt := range tests {
+		if w := doJSON(router, "POST", "/api/admin/notification-rules", tt.body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", tt.name, w.Code)
+		}
+	}
+
+	if w := doJSON(router, "PUT", "/api/admin/notification-rules/99", `{"name": "x"}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 updating a missing rule, got %d", w.Code)
+	}
+}
+
+func TestNotificationRules_Test(t *testing.T) {
+	router, db := newNotificationRuleRouter(t)
+
+	shipment := &database.Shipment{TrackingNumber: "1Z999AA10123456784", Carrier: "ups", Description: "Headphones", Status: "in_transit"}
+	if err := db.Shipments.Create(shipment); err != nil {
+		t.Fatalf("Failed to create shipment: %v", err)
+	}
+	rule := &database.NotificationRule{Name: "Deliveries", Enabled: true, ToStatuses: []string{"delivered"}}
+	if err := db.Rules.Create(rule); err != nil {
+		t.Fatalf("Failed to create rule: %v", err)
+	}
+
+	w := doJSON(router, "POST", "/api/admin/notification-rules/1/test", `{"shipment_id": 1}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response RuleTestResponse
+	json.NewDecoder(w.Body).Decode(&response)
+	if response.Result.Matched || response.Result.Reason == "" {
+		t.Errorf("Expected the current status not to match, got %+v", response.Result)
+	}
+
+	w = doJSON(router, "POST", "/api/admin/notification-rules/1/test", `{"shipment_id": 1, "to_status": "delivered"}`)
+	json.NewDecoder(w.Body).Decode(&response)
+	if !response.Result.Matched {
+		t.Errorf("Expected the delivered transition to match, got %+v", response.Result)
+	}
+	if len(response.Channels) != 1 || response.Channels[0].Subject != "Headphones is delivered" {
+		t.Errorf("Unexpected previews: %+v", response.Channels)
+	}
+
+	if w := doJSON(router, "POST", "/api/admin/notification-rules/1/test", `{"shipment_id": 42}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a missing shipment, got %d", w.Code)
+	}
+}
@@ -15,6 +15,8 @@ import (
 	"package-tracking/internal/carriers"
 	"package-tracking/internal/ratelimit"
 	"package-tracking/internal/database"
+	"package-tracking/internal/notifications"
+	"package-tracking/internal/services"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -35,6 +37,9 @@ type ShipmentHandler struct {
 	factory *carriers.ClientFactory
 	config  Config
 	cache   *cache.Manager
+
+	// notifier receives status changes found by manual refreshes; nil drops them
+	notifier *notifications.Dispatcher
 }
 
 // NewShipmentHandler creates a new shipment handler
@@ -71,6 +76,11 @@ func NewShipmentHandlerWithFactory(db *database.DB, config Config, cacheManager
 	}
 }
 
+// SetNotifier sets the dispatcher that status changes found by refreshes are reported to
+func (h *ShipmentHandler) SetNotifier(notifier *notifications.Dispatcher) {
+	h.notifier = notifier
+}
+
 // GetShipments handles GET /api/shipments
 func (h *ShipmentHandler) GetShipments(w http.ResponseWriter, r *http.Request) {
 	shipments, err := h.db.Shipments.GetAll()
@@ -95,7 +105,7 @@ func (h *ShipmentHandler) CreateShipment(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Validate required fields
-	if err := validateShipment(&shipment); err != nil {
+	if err := services.ValidateShipment(&shipment); err != nil {
 		log.Printf("ERROR: Validation failed for shipment: %v", err)
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -164,7 +174,7 @@ func (h *ShipmentHandler) UpdateShipment(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Validate required fields
-	if err := validateShipment(&shipment); err != nil {
+	if err := services.ValidateShipment(&shipment); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -251,59 +261,6 @@ func (h *ShipmentHandler) GetShipmentEvents(w http.ResponseWriter, r *http.Reque
 	json.NewEncoder(w).Encode(events)
 }
 
-// validateShipment validates shipment data
-func validateShipment(shipment *database.Shipment) error {
-	if shipment.TrackingNumber == "" {
-		return fmt.Errorf("tracking number is required")
-	}
-	if shipment.Carrier == "" {
-		return fmt.Errorf("carrier is required")
-	}
-	if shipment.Description == "" {
-		return fmt.Errorf("description is required")
-	}
-
-	// Validate carrier
-	validCarriers := []string{"ups", "usps", "fedex", "dhl", "amazon"}
-	validCarrier := false
-	for _, c := range validCarriers {
-		if shipment.Carrier == c {
-			validCarrier = true
-			break
-		}
-	}
-	if !validCarrier {
-		return fmt.Errorf("invalid carrier: must be one of %v", validCarriers)
-	}
-
-	// Amazon-specific validation
-	if shipment.Carrier == "amazon" {
-		// Validate Amazon tracking number format
-		if err := validateAmazonTrackingNumber(shipment.TrackingNumber); err != nil {
-			return fmt.Errorf("invalid Amazon tracking number: %v", err)
-		}
-	}
-
-	return nil
-}
-
-// validateAmazonTrackingNumber validates Amazon tracking number formats
-func validateAmazonTrackingNumber(trackingNumber string) error {
-	// Create Amazon client to validate
-	factory := carriers.NewClientFactory()
-	client, _, err := factory.CreateClient("amazon")
-	if err != nil {
-		return fmt.Errorf("failed to create Amazon client for validation: %v", err)
-	}
-	
-	// Use the Amazon client's validation
-	if !client.ValidateTrackingNumber(trackingNumber) {
-		return fmt.Errorf("tracking number does not match Amazon format (17-digit order number or TBA+12 digits)")
-	}
-	
-	return nil
-}
-
 // RefreshResponse represents the response from a manual refresh request
 type RefreshResponse struct {
 	ShipmentID       int                      `json:"shipment_id"`
@@ -394,7 +351,8 @@ func (h *ShipmentHandler) RefreshShipment(w http.ResponseWriter, r *http.Request
 	}
 
 	// Check rate limiting using unified rate limiting logic
-	rateLimitResult := ratelimit.CheckRefreshRateLimit(h.config, shipment.LastManualRefresh, forceRefresh)
+	lastManualRefresh := h.cache.ManualRefreshTime(id, shipment.LastManualRefresh)
+	rateLimitResult := ratelimit.CheckRefreshRateLimit(h.config, lastManualRefresh, forceRefresh)
 	if rateLimitResult.ShouldBlock {
 		http.Error(w, fmt.Sprintf("Rate limit exceeded. Please wait %v before refreshing again", rateLimitResult.RemainingTime.Truncate(time.Second)), http.StatusTooManyRequests)
 		return
@@ -446,7 +404,21 @@ func (h *ShipmentHandler) RefreshShipment(w http.ResponseWriter, r *http.Request
 		Carrier:         shipment.Carrier,
 	}
 
-	resp, err := client.Track(ctx, req)
+	// A refresh of the same shipment already in flight, e.g. by the auto-updater, is
+	// shared rather than repeated
+	resp, shared, err := h.cache.Track(id, func() (*carriers.TrackingResponse, error) {
+		resp, err := client.Track(ctx, req)
+		if clientType == carriers.ClientTypeAPI {
+			// Manual refreshes share the carrier's daily API budget with auto-updates
+			if ledgerErr := h.db.Quota.RecordCall(shipment.Carrier, time.Now()); ledgerErr != nil {
+				log.Printf("WARN: Failed to record %s API call: %v", shipment.Carrier, ledgerErr)
+			}
+		}
+		return resp, err
+	})
+	if shared {
+		log.Printf("DEBUG: Shared an in-flight carrier lookup for shipment %d", id)
+	}
 	if err != nil {
 		// Handle carrier errors
 		if carrierErr, ok := err.(*carriers.CarrierError); ok {
@@ -475,6 +447,7 @@ func (h *ShipmentHandler) RefreshShipment(w http.ResponseWriter, r *http.Request
 
 	// Process results
 	eventsAdded := 0
+	previousStatus := shipment.Status
 	if len(resp.Results) > 0 {
 		trackingInfo := resp.Results[0]
 
@@ -490,6 +463,7 @@ func (h *ShipmentHandler) RefreshShipment(w http.ResponseWriter, r *http.Request
 		}
 
 		// Add new tracking events
+		var carrierEvents []database.TrackingEvent
 		for _, event := range trackingInfo.Events {
 			dbEvent := &database.TrackingEvent{
 				ShipmentID:  id,
@@ -498,6 +472,7 @@ func (h *ShipmentHandler) RefreshShipment(w http.ResponseWriter, r *http.Request
 				Status:      string(event.Status),
 				Description: event.Description,
 			}
+			carrierEvents = append(carrierEvents, *dbEvent)
 
 			// CreateEvent has deduplication logic
 			err := h.db.TrackingEvents.CreateEvent(dbEvent)
@@ -514,6 +489,11 @@ func (h *ShipmentHandler) RefreshShipment(w http.ResponseWriter, r *http.Request
 			http.Error(w, fmt.Sprintf("Failed to update shipment: %v", err), http.StatusInternalServerError)
 			return
 		}
+
+		if transition, changed := notifications.NewTransition(*shipment, previousStatus, notifications.SourceManualRefresh); changed {
+			transition.SetEvents(carrierEvents)
+			h.notifier.Notify(transition)
+		}
 	}
 
 	// Update refresh tracking
@@ -522,6 +502,7 @@ func (h *ShipmentHandler) RefreshShipment(w http.ResponseWriter, r *http.Request
 		http.Error(w, fmt.Sprintf("Failed to update refresh tracking: %v", err), http.StatusInternalServerError)
 		return
 	}
+	h.cache.RecordManualRefresh(id, time.Now())
 
 	// Get updated events
 	updatedEvents, err := h.db.TrackingEvents.GetByShipmentID(id)
@@ -81,7 +81,9 @@ func setupTestDB(t *testing.T) *database.DB {
 		amazon_order_number TEXT,
 		delegated_carrier TEXT,
 		delegated_tracking_number TEXT,
-		is_amazon_logistics BOOLEAN DEFAULT FALSE
+		is_amazon_logistics BOOLEAN DEFAULT FALSE,
+		is_stalled BOOLEAN DEFAULT FALSE,
+		stalled_since DATETIME
 	);
 
 	CREATE TABLE tracking_events (
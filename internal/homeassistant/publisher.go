@@ -0,0 +1,421 @@
+// Package homeassistant publishes shipments to MQTT in the shape Home Assistant expects:
+// every shipment becomes a sensor through MQTT discovery, with its status as the state and
+// its details as attributes, and status changes are published as events automations can
+// trigger on, e.g. to announce "package out for delivery" on a smart speaker.
+package homeassistant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"package-tracking/internal/config"
+	"package-tracking/internal/database"
+	"package-tracking/internal/mqtt"
+	"package-tracking/internal/notifications"
+)
+
+// Availability payloads
+const (
+	payloadOnline  = "online"
+	payloadOffline = "offline"
+)
+
+// mqttPublisher publishes MQTT messages; *mqtt.Client in production
+type mqttPublisher interface {
+	Publish(topic string, payload []byte, retain bool) error
+	Close() error
+}
+
+// Publisher keeps Home Assistant's view of the shipments up to date. It republishes every
+// shipment periodically, so sensors recover from a broker or Home Assistant restart, and
+// is also a notification channel that publishes status changes as they happen.
+type Publisher struct {
+	ctx           context.Context
+	cancel        context.CancelFunc
+	config        *config.Config
+	shipmentStore *database.ShipmentStore
+	eventStore    *database.TrackingEventStore
+	client        mqttPublisher
+	logger        *slog.Logger
+	loopDone      chan struct{}
+
+	// mu serializes syncs and guards published
+	mu        sync.Mutex
+	published map[int]bool // Shipments with a sensor
+	synced    bool
+}
+
+// NewPublisher creates a publisher for the configured broker. It doesn't connect until
+// there is something to publish.
+func NewPublisher(cfg *config.Config, db *database.DB, logger *slog.Logger) (*Publisher, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Publisher{
+		ctx:           ctx,
+		cancel:        cancel,
+		config:        cfg,
+		shipmentStore: db.Shipments,
+		eventStore:    db.TrackingEvents,
+		logger:        logger,
+		published:     make(map[int]bool),
+	}
+	if !cfg.MQTTEnabled {
+		return p, nil
+	}
+
+	client, err := mqtt.NewClient(mqtt.Options{
+		BrokerURL:    cfg.MQTTBrokerURL,
+		ClientID:     cfg.MQTTClientID,
+		Username:     cfg.MQTTUsername,
+		Password:     cfg.MQTTPassword,
+		WillTopic:    p.availabilityTopic(),
+		WillPayload:  []byte(payloadOffline),
+		BirthPayload: []byte(payloadOnline),
+	})
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid MQTT configuration: %w", err)
+	}
+	p.client = client
+	return p, nil
+}
+
+// Start begins republishing the shipments periodically
+func (p *Publisher) Start() {
+	if !p.config.MQTTEnabled {
+		p.logger.Info("MQTT publishing is disabled")
+		return
+	}
+
+	p.logger.Info("Starting Home Assistant MQTT publisher",
+		"broker", p.config.MQTTBrokerURL,
+		"topic_prefix", p.config.MQTTTopicPrefix,
+		"sync_interval", p.config.MQTTSyncInterval)
+
+	p.loopDone = make(chan struct{})
+	go p.syncLoop()
+}
+
+// Stop stops republishing, marks the integration offline and disconnects
+func (p *Publisher) Stop() {
+	if !p.config.MQTTEnabled {
+		return
+	}
+	p.logger.Info("Stopping Home Assistant MQTT publisher")
+	p.cancel()
+
+	if p.loopDone != nil && !waitForDone(p.loopDone, 10*time.Second) {
+		p.logger.Warn("Timed out waiting for MQTT sync to finish")
+	}
+
+	// A clean disconnect doesn't trigger the will, so publish it ourselves
+	if err := p.client.Publish(p.availabilityTopic(), []byte(payloadOffline), true); err != nil {
+		p.logger.Warn("Failed to publish MQTT availability", "error", err)
+	}
+	p.client.Close()
+}
+
+// syncLoop syncs once at startup and then on the configured interval
+func (p *Publisher) syncLoop() {
+	defer close(p.loopDone)
+
+	ticker := time.NewTicker(p.config.MQTTSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.Sync(time.Now()); err != nil {
+			p.logger.Error("MQTT sync failed", "error", err)
+		}
+
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync publishes every shipment that should have a sensor and removes the sensors of
+// shipments that were deleted or delivered longer ago than the retention
+func (p *Publisher) Sync(now time.Time) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	shipments, err := p.shipmentStore.GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to get shipments: %w", err)
+	}
+
+	seen := make(map[int]bool, len(shipments))
+	var published, removed int
+	for _, shipment := range shipments {
+		seen[shipment.ID] = true
+
+		if p.visible(shipment, now) {
+			var latest *database.TrackingEvent
+			if events, err := p.eventStore.GetByShipmentID(shipment.ID); err == nil && len(events) > 0 {
+				latest = &events[len(events)-1] // Oldest first
+			}
+			if err := p.publishShipment(shipment, latest, p.shipmentURL(shipment.ID)); err != nil {
+				return err
+			}
+			published++
+			continue
+		}
+
+		// After a restart we don't know which sensors exist, so remove them all once
+		if p.published[shipment.ID] || !p.synced {
+			if err := p.removeShipment(shipment.ID); err != nil {
+				return err
+			}
+			removed++
+		}
+	}
+
+	for id := range p.published {
+		if !seen[id] {
+			if err := p.removeShipment(id); err != nil {
+				return err
+			}
+			removed++
+		}
+	}
+
+	p.synced = true
+	p.logger.Debug("MQTT sync completed", "published", published, "removed", removed)
+	return nil
+}
+
+// visible reports whether a shipment should have a sensor
+func (p *Publisher) visible(shipment database.Shipment, now time.Time) bool {
+	return !shipment.IsDelivered || now.Sub(shipment.UpdatedAt) <= p.config.MQTTDeliveredRetention
+}
+
+// Name returns the notification channel name
+func (p *Publisher) Name() string {
+	return "mqtt"
+}
+
+// Send publishes a status change: the shipment's new state and an event on the events
+// topic
+func (p *Publisher) Send(ctx context.Context, msg notifications.Message) error {
+	t := msg.Transition
+
+	p.mu.Lock()
+	err := p.publishShipment(t.Shipment, t.LatestEvent, msg.URL)
+	p.mu.Unlock()
+	if err != nil {
+		return permanentIfRefused(err)
+	}
+
+	event := statusEvent{
+		ShipmentID:     t.Shipment.ID,
+		TrackingNumber: t.Shipment.TrackingNumber,
+		Carrier:        t.Shipment.Carrier,
+		Description:    t.Shipment.Description,
+		FromStatus:     t.FromStatus,
+		ToStatus:       t.ToStatus,
+		Source:         t.Source,
+		OccurredAt:     t.OccurredAt,
+		Message:        msg.Subject,
+		URL:            msg.URL,
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return notifications.Permanent(err)
+	}
+	return permanentIfRefused(p.client.Publish(p.eventsTopic(), payload, false))
+}
+
+// permanentIfRefused marks errors retrying won't fix, like rejected credentials
+func permanentIfRefused(err error) error {
+	var refused *mqtt.ConnectError
+	if errors.As(err, &refused) && (refused.Code == 4 || refused.Code == 5) {
+		return notifications.Permanent(err)
+	}
+	return err
+}
+
+// statusEvent is published to the events topic when a shipment's status changes
+type statusEvent struct {
+	ShipmentID     int       `json:"shipment_id"`
+	TrackingNumber string    `json:"tracking_number"`
+	Carrier        string    `json:"carrier"`
+	Description    string    `json:"description"`
+	FromStatus     string    `json:"from_status"`
+	ToStatus       string    `json:"to_status"`
+	Source         string    `json:"source"`
+	OccurredAt     time.Time `json:"occurred_at"`
+	Message        string    `json:"message"` // The rendered subject, ready to be announced
+	URL            string    `json:"url,omitempty"`
+}
+
+// shipmentAttributes are the sensor's attributes
+type shipmentAttributes struct {
+	ShipmentID       int        `json:"shipment_id"`
+	TrackingNumber   string     `json:"tracking_number"`
+	Carrier          string     `json:"carrier"`
+	Description      string     `json:"description"`
+	ExpectedDelivery *time.Time `json:"expected_delivery,omitempty"`
+	Delivered        bool       `json:"delivered"`
+	LatestEvent      string     `json:"latest_event,omitempty"`
+	LatestLocation   string     `json:"latest_location,omitempty"`
+	LatestEventAt    *time.Time `json:"latest_event_at,omitempty"`
+	URL              string     `json:"url,omitempty"`
+}
+
+// discoveryConfig is a sensor's MQTT discovery payload
+type discoveryConfig struct {
+	Name                string          `json:"name"`
+	UniqueID            string          `json:"unique_id"`
+	ObjectID            string          `json:"object_id"`
+	StateTopic          string          `json:"state_topic"`
+	JSONAttributesTopic string          `json:"json_attributes_topic"`
+	AvailabilityTopic   string          `json:"availability_topic"`
+	Icon                string          `json:"icon"`
+	Device              discoveryDevice `json:"device"`
+}
+
+type discoveryDevice struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer"`
+	Model        string   `json:"model"`
+}
+
+// publishShipment publishes a shipment's discovery config, state and attributes, all
+// retained. Callers hold mu.
+func (p *Publisher) publishShipment(shipment database.Shipment, latest *database.TrackingEvent, url string) error {
+	name := shipment.Description
+	if name == "" {
+		name = shipment.TrackingNumber
+	}
+	node := p.nodeID()
+	discovery := discoveryConfig{
+		Name:                name,
+		UniqueID:            fmt.Sprintf("%s_shipment_%d", node, shipment.ID),
+		ObjectID:            fmt.Sprintf("package_%d", shipment.ID),
+		StateTopic:          p.shipmentTopic(shipment.ID, "state"),
+		JSONAttributesTopic: p.shipmentTopic(shipment.ID, "attributes"),
+		AvailabilityTopic:   p.availabilityTopic(),
+		Icon:                "mdi:package-variant-closed",
+		Device: discoveryDevice{
+			Identifiers:  []string{node},
+			Name:         "Package Tracker",
+			Manufacturer: "package-tracking",
+			Model:        "Shipment tracker",
+		},
+	}
+	if shipment.IsDelivered {
+		discovery.Icon = "mdi:package-variant"
+	}
+
+	attributes := shipmentAttributes{
+		ShipmentID:       shipment.ID,
+		TrackingNumber:   shipment.TrackingNumber,
+		Carrier:          shipment.Carrier,
+		Description:      shipment.Description,
+		ExpectedDelivery: shipment.ExpectedDelivery,
+		Delivered:        shipment.IsDelivered,
+		URL:              url,
+	}
+	if latest != nil {
+		attributes.LatestEvent = latest.Description
+		attributes.LatestLocation = latest.Location
+		timestamp := latest.Timestamp
+		attributes.LatestEventAt = &timestamp
+	}
+
+	discoveryPayload, err := json.Marshal(discovery)
+	if err != nil {
+		return err
+	}
+	attributesPayload, err := json.Marshal(attributes)
+	if err != nil {
+		return err
+	}
+
+	// Discovery first, so Home Assistant is subscribed before the state arrives
+	for _, message := range []struct {
+		topic   string
+		payload []byte
+	}{
+		{p.discoveryTopic(shipment.ID), discoveryPayload},
+		{p.shipmentTopic(shipment.ID, "attributes"), attributesPayload},
+		{p.shipmentTopic(shipment.ID, "state"), []byte(shipment.Status)},
+	} {
+		if err := p.client.Publish(message.topic, message.payload, true); err != nil {
+			return err
+		}
+	}
+
+	p.published[shipment.ID] = true
+	return nil
+}
+
+// removeShipment removes a shipment's sensor and clears its retained messages. Callers
+// hold mu.
+func (p *Publisher) removeShipment(id int) error {
+	for _, topic := range []string{
+		p.discoveryTopic(id),
+		p.shipmentTopic(id, "attributes"),
+		p.shipmentTopic(id, "state"),
+	} {
+		// An empty retained message deletes the retained message, and the sensor
+		if err := p.client.Publish(topic, nil, true); err != nil {
+			return err
+		}
+	}
+	delete(p.published, id)
+	return nil
+}
+
+func (p *Publisher) availabilityTopic() string {
+	return p.config.MQTTTopicPrefix + "/status"
+}
+
+func (p *Publisher) eventsTopic() string {
+	return p.config.MQTTTopicPrefix + "/events"
+}
+
+func (p *Publisher) shipmentTopic(id int, suffix string) string {
+	return fmt.Sprintf("%s/shipment/%d/%s", p.config.MQTTTopicPrefix, id, suffix)
+}
+
+func (p *Publisher) discoveryTopic(id int) string {
+	return fmt.Sprintf("%s/sensor/%s/shipment_%d/config", p.config.MQTTDiscoveryPrefix, p.nodeID(), id)
+}
+
+// nodeID identifies this tracker in discovery topics and unique IDs, which only allow
+// letters, digits, underscores and hyphens
+func (p *Publisher) nodeID() string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' {
+			return r
+		}
+		return '_'
+	}, p.config.MQTTTopicPrefix)
+}
+
+// shipmentURL returns the web UI link to a shipment, or "" without a base URL
+func (p *Publisher) shipmentURL(id int) string {
+	if p.config.NotificationBaseURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/shipments/%d", strings.TrimRight(p.config.NotificationBaseURL, "/"), id)
+}
+
+// waitForDone waits up to timeout for done to be closed
+func waitForDone(done <-chan struct{}, timeout time.Duration) bool {
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
@@ -0,0 +1,161 @@
+package homeassistant
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strconv"
+	"testing"
+	"time"
+
+	"package-tracking/internal/config"
+	"package-tracking/internal/database"
+	"package-tracking/internal/notifications"
+)
+
+// recordingClient records published messages by topic, like a broker's retained messages
+type recordingClient struct {
+	retained map[string]string
+	events   []string
+}
+
+func (c *recordingClient) Publish(topic string, payload []byte, retain bool) error {
+	if !retain {
+		c.events = append(c.events, string(payload))
+		return nil
+	}
+	if len(payload) == 0 {
+		delete(c.retained, topic)
+	} else {
+		c.retained[topic] = string(payload)
+	}
+	return nil
+}
+
+func (c *recordingClient) Close() error {
+	return nil
+}
+
+func newTestPublisher(t *testing.T) (*Publisher, *recordingClient, *database.DB) {
+	db, err := database.Open(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	cfg := &config.Config{
+		NotificationBaseURL:    "http://tracker.local",
+		MQTTTopicPrefix:        "package_tracker",
+		MQTTDiscoveryPrefix:    "homeassistant",
+		MQTTDeliveredRetention: 48 * time.Hour,
+	}
+	publisher, err := NewPublisher(cfg, db, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewPublisher() error = %v", err)
+	}
+	client := &recordingClient{retained: make(map[string]string)}
+	publisher.client = client
+	return publisher, client, db
+}
+
+func createShipment(t *testing.T, db *database.DB, trackingNumber, status string) *database.Shipment {
+	shipment := &database.Shipment{
+		TrackingNumber: trackingNumber,
+		Carrier:        "ups",
+		Description:    "Headphones",
+		Status:         status,
+		IsDelivered:    status == "delivered",
+	}
+	if err := db.Shipments.Create(shipment); err != nil {
+		t.Fatalf("Failed to create shipment: %v", err)
+	}
+	return shipment
+}
+
+func TestPublisher_Sync(t *testing.T) {
+	publisher, client, db := newTestPublisher(t)
+
+	active := createShipment(t, db, "1Z999AA10123456784", "in_transit")
+	delivered := createShipment(t, db, "1Z999AA10123456785", "delivered")
+	old := createShipment(t, db, "1Z999AA10123456786", "delivered")
+	if _, err := db.Exec(`UPDATE shipments SET updated_at = ? WHERE id = ?`, time.Now().Add(-72*time.Hour), old.ID); err != nil {
+		t.Fatalf("Failed to age shipment: %v", err)
+	}
+	client.retained["homeassistant/sensor/package_tracker/shipment_"+strconv.Itoa(old.ID)+"/config"] = "{}"
+
+	if err := publisher.Sync(time.Now()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	if got := client.retained["package_tracker/shipment/"+strconv.Itoa(active.ID)+"/state"]; got != "in_transit" {
+		t.Errorf("active state = %q, want in_transit", got)
+	}
+	if got := client.retained["package_tracker/shipment/"+strconv.Itoa(delivered.ID)+"/state"]; got != "delivered" {
+		t.Errorf("recently delivered state = %q, want delivered", got)
+	}
+	if _, ok := client.retained["homeassistant/sensor/package_tracker/shipment_"+strconv.Itoa(old.ID)+"/config"]; ok {
+		t.Error("Expected the sensor of a shipment delivered before the retention to be removed")
+	}
+
+	var discovery discoveryConfig
+	if err := json.Unmarshal([]byte(client.retained["homeassistant/sensor/package_tracker/shipment_"+strconv.Itoa(active.ID)+"/config"]), &discovery); err != nil {
+		t.Fatalf("Invalid discovery payload: %v", err)
+	}
+	if discovery.Name != "Headphones" || discovery.StateTopic != "package_tracker/shipment/"+strconv.Itoa(active.ID)+"/state" ||
+		discovery.AvailabilityTopic != "package_tracker/status" || discovery.UniqueID != "package_tracker_shipment_"+strconv.Itoa(active.ID) {
+		t.Errorf("Unexpected discovery payload: %+v", discovery)
+	}
+
+	var attributes shipmentAttributes
+	json.Unmarshal([]byte(client.retained["package_tracker/shipment/"+strconv.Itoa(active.ID)+"/attributes"]), &attributes)
+	if attributes.TrackingNumber != "1Z999AA10123456784" || attributes.URL != "http://tracker.local/shipments/"+strconv.Itoa(active.ID) {
+		t.Errorf("Unexpected attributes: %+v", attributes)
+	}
+
+	// Deleted shipments lose their sensor on the next sync
+	if err := db.Shipments.Delete(active.ID); err != nil {
+		t.Fatalf("Failed to delete shipment: %v", err)
+	}
+	if err := publisher.Sync(time.Now()); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if _, ok := client.retained["package_tracker/shipment/"+strconv.Itoa(active.ID)+"/state"]; ok {
+		t.Error("Expected the deleted shipment's state to be cleared")
+	}
+}
+
+func TestPublisher_Send(t *testing.T) {
+	publisher, client, db := newTestPublisher(t)
+	shipment := createShipment(t, db, "1Z999AA10123456784", "out_for_delivery")
+
+	msg := notifications.Message{
+		Subject: "Headphones is out for delivery",
+		URL:     "http://tracker.local/shipments/1",
+		Transition: notifications.Transition{
+			Shipment:   *shipment,
+			FromStatus: "in_transit",
+			ToStatus:   "out_for_delivery",
+			Source:     "auto_update",
+			LatestEvent: &database.TrackingEvent{
+				Description: "Out for delivery",
+				Location:    "Austin, TX",
+			},
+		},
+	}
+	if err := publisher.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if got := client.retained["package_tracker/shipment/"+strconv.Itoa(shipment.ID)+"/state"]; got != "out_for_delivery" {
+		t.Errorf("state = %q, want out_for_delivery", got)
+	}
+	if len(client.events) != 1 {
+		t.Fatalf("events = %v, want one event", client.events)
+	}
+	var event statusEvent
+	json.Unmarshal([]byte(client.events[0]), &event)
+	if event.ToStatus != "out_for_delivery" || event.FromStatus != "in_transit" || event.Message != msg.Subject {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+}
@@ -0,0 +1,334 @@
+// Package mqtt is a minimal MQTT 3.1.1 client that publishes messages, which is all the
+// Home Assistant integration needs. It connects lazily, waits for the broker to acknowledge
+// each QoS 1 message and reconnects after a failure.
+package mqtt
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Packet types
+const (
+	packetConnect    = 1
+	packetConnack    = 2
+	packetPublish    = 3
+	packetPuback     = 4
+	packetPingresp   = 13
+	packetDisconnect = 14
+)
+
+// defaultTimeout bounds connecting and waiting for acknowledgements
+const defaultTimeout = 10 * time.Second
+
+// Options configures a client
+type Options struct {
+	BrokerURL string // tcp://host:1883, or ssl:// or tls:// for TLS; the port defaults to 1883 or 8883
+	ClientID  string
+	Username  string
+	Password  string
+
+	// Will is published retained by the broker if the connection is lost, and Birth to the
+	// same topic after every connect, e.g. "offline" and "online" for availability
+	WillTopic    string
+	WillPayload  []byte
+	BirthPayload []byte
+
+	Timeout time.Duration // Defaults to 10s
+}
+
+// Client publishes messages to a broker. It is safe for concurrent use. Keep alive is
+// disabled, so an idle connection stays open and the will is only published when the
+// connection is actually lost.
+type Client struct {
+	opts    Options
+	address string
+	useTLS  bool
+
+	mu       sync.Mutex
+	conn     net.Conn
+	reader   *bufio.Reader
+	packetID uint16
+}
+
+// NewClient creates a client for the broker in opts. It doesn't connect until the first
+// Publish.
+func NewClient(opts Options) (*Client, error) {
+	broker, err := url.Parse(opts.BrokerURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid broker URL: %w", err)
+	}
+
+	var useTLS bool
+	port := "1883"
+	switch broker.Scheme {
+	case "tcp", "mqtt":
+	case "ssl", "tls", "mqtts":
+		useTLS = true
+		port = "8883"
+	default:
+		return nil, fmt.Errorf("unsupported broker URL scheme %q", broker.Scheme)
+	}
+	if broker.Hostname() == "" {
+		return nil, errors.New("broker URL has no host")
+	}
+	if broker.Port() != "" {
+		port = broker.Port()
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultTimeout
+	}
+
+	return &Client{
+		opts:    opts,
+		address: net.JoinHostPort(broker.Hostname(), port),
+		useTLS:  useTLS,
+	}, nil
+}
+
+// Publish publishes payload to topic with QoS 1, waiting for the broker's acknowledgement.
+// Retained messages are kept by the broker and sent to new subscribers.
+func (c *Client) Publish(topic string, payload []byte, retain bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.connect(); err != nil {
+		return err
+	}
+	if err := c.publish(topic, payload, retain); err != nil {
+		// The connection may have gone stale; retry once on a new one
+		c.closeConn()
+		if err := c.connect(); err != nil {
+			return err
+		}
+		if err := c.publish(topic, payload, retain); err != nil {
+			c.closeConn()
+			return err
+		}
+	}
+	return nil
+}
+
+// Close disconnects cleanly, so the broker doesn't publish the will
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+	c.conn.SetWriteDeadline(time.Now().Add(c.opts.Timeout))
+	_, err := c.conn.Write([]byte{packetDisconnect << 4, 0})
+	c.closeConn()
+	return err
+}
+
+// connect opens a connection if there isn't one and publishes the birth message
+func (c *Client) connect() error {
+	if c.conn != nil {
+		return nil
+	}
+
+	dialer := &net.Dialer{Timeout: c.opts.Timeout}
+	var conn net.Conn
+	var err error
+	if c.useTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", c.address, &tls.Config{MinVersion: tls.VersionTLS12})
+	} else {
+		conn, err = dialer.Dial("tcp", c.address)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to broker: %w", err)
+	}
+
+	c.conn = conn
+	c.reader = bufio.NewReader(conn)
+	conn.SetDeadline(time.Now().Add(c.opts.Timeout))
+	defer conn.SetDeadline(time.Time{})
+
+	if _, err := conn.Write(c.connectPacket()); err != nil {
+		c.closeConn()
+		return fmt.Errorf("failed to send connect: %w", err)
+	}
+
+	packetType, body, err := c.readPacket()
+	if err != nil {
+		c.closeConn()
+		return fmt.Errorf("failed to read connack: %w", err)
+	}
+	if packetType != packetConnack || len(body) != 2 {
+		c.closeConn()
+		return fmt.Errorf("unexpected packet %d instead of connack", packetType)
+	}
+	if code := body[1]; code != 0 {
+		c.closeConn()
+		return &ConnectError{Code: code}
+	}
+
+	if c.opts.WillTopic != "" && c.opts.BirthPayload != nil {
+		if err := c.publish(c.opts.WillTopic, c.opts.BirthPayload, true); err != nil {
+			c.closeConn()
+			return err
+		}
+	}
+	return nil
+}
+
+// ConnectError is a connection refused by the broker
+type ConnectError struct {
+	Code byte
+}
+
+func (e *ConnectError) Error() string {
+	reasons := map[byte]string{
+		1: "unacceptable protocol version",
+		2: "client identifier rejected",
+		3: "server unavailable",
+		4: "bad user name or password",
+		5: "not authorized",
+	}
+	if reason, ok := reasons[e.Code]; ok {
+		return "broker refused connection: " + reason
+	}
+	return fmt.Sprintf("broker refused connection: code %d", e.Code)
+}
+
+// connectPacket encodes the CONNECT packet
+func (c *Client) connectPacket() []byte {
+	flags := byte(0x02) // Clean session
+	var payload []byte
+	payload = appendString(payload, c.opts.ClientID)
+	if c.opts.WillTopic != "" {
+		flags |= 0x04 | 0x08 | 0x20 // Will, will QoS 1, will retain
+		payload = appendString(payload, c.opts.WillTopic)
+		payload = appendBytes(payload, c.opts.WillPayload)
+	}
+	if c.opts.Username != "" {
+		flags |= 0x80
+		payload = appendString(payload, c.opts.Username)
+		if c.opts.Password != "" {
+			flags |= 0x40
+			payload = appendString(payload, c.opts.Password)
+		}
+	}
+
+	var body []byte
+	body = appendString(body, "MQTT")
+	body = append(body, 4, flags) // Protocol level 3.1.1
+	body = append(body, 0, 0)     // Keep alive disabled
+	body = append(body, payload...)
+	return packet(packetConnect<<4, body)
+}
+
+// publish sends a QoS 1 PUBLISH and waits for its PUBACK
+func (c *Client) publish(topic string, payload []byte, retain bool) error {
+	c.packetID++
+	if c.packetID == 0 {
+		c.packetID = 1
+	}
+
+	header := byte(packetPublish<<4 | 0x02) // QoS 1
+	if retain {
+		header |= 0x01
+	}
+	var body []byte
+	body = appendString(body, topic)
+	body = binary.BigEndian.AppendUint16(body, c.packetID)
+	body = append(body, payload...)
+
+	c.conn.SetDeadline(time.Now().Add(c.opts.Timeout))
+	defer c.conn.SetDeadline(time.Time{})
+
+	if _, err := c.conn.Write(packet(header, body)); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", topic, err)
+	}
+
+	for {
+		packetType, ack, err := c.readPacket()
+		if err != nil {
+			return fmt.Errorf("failed to read puback for %s: %w", topic, err)
+		}
+		if packetType == packetPuback && len(ack) == 2 && binary.BigEndian.Uint16(ack) == c.packetID {
+			return nil
+		}
+		if packetType != packetPuback && packetType != packetPingresp {
+			return fmt.Errorf("unexpected packet %d while waiting for puback", packetType)
+		}
+	}
+}
+
+// readPacket reads a packet and returns its type and body
+func (c *Client) readPacket() (byte, []byte, error) {
+	header, err := c.reader.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// Remaining length is a variable length integer of up to four bytes
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		b, err := c.reader.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(b&0x7f) * multiplier
+		if b&0x80 == 0 {
+			break
+		}
+		if i == 3 {
+			return 0, nil, errors.New("malformed remaining length")
+		}
+		multiplier *= 128
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, body); err != nil {
+		return 0, nil, err
+	}
+	return header >> 4, body, nil
+}
+
+func (c *Client) closeConn() {
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+		c.reader = nil
+	}
+}
+
+// packet prefixes body with the fixed header and remaining length
+func packet(header byte, body []byte) []byte {
+	out := []byte{header}
+	length := len(body)
+	for {
+		b := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			b |= 0x80
+		}
+		out = append(out, b)
+		if length == 0 {
+			break
+		}
+	}
+	return append(out, body...)
+}
+
+// appendString appends a length-prefixed UTF-8 string
+func appendString(b []byte, s string) []byte {
+	return appendBytes(b, []byte(s))
+}
+
+// appendBytes appends length-prefixed binary data
+func appendBytes(b []byte, data []byte) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(data)))
+	return append(b, data...)
+}
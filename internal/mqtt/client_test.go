@@ -0,0 +1,205 @@
+package mqtt
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// received is a message published to the fake broker
+type received struct {
+	topic   string
+	payload string
+}
+
+// fakeBroker accepts connections, acknowledges CONNECT and PUBLISH packets and records
+// what it was sent
+type fakeBroker struct {
+	listener   net.Listener
+	returnCode byte
+
+	mu          sync.Mutex
+	connects    int
+	username    string
+	willTopic   string
+	messages    []received
+	disconnects int
+}
+
+func newFakeBroker(t *testing.T, returnCode byte) *fakeBroker {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	b := &fakeBroker{listener: listener, returnCode: returnCode}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(conn)
+		}
+	}()
+	return b
+}
+
+func (b *fakeBroker) url() string {
+	return "tcp://" + b.listener.Addr().String()
+}
+
+func (b *fakeBroker) serve(conn net.Conn) {
+	defer conn.Close()
+	c := &Client{reader: bufio.NewReader(conn)}
+
+	for {
+		packetType, body, err := c.readPacket()
+		if err != nil {
+			return
+		}
+		switch packetType {
+		case packetConnect:
+			b.recordConnect(body)
+			conn.Write([]byte{packetConnack << 4, 2, 0, b.returnCode})
+		case packetPublish:
+			topicLength := int(binary.BigEndian.Uint16(body))
+			topic := string(body[2 : 2+topicLength])
+			id := body[2+topicLength : 4+topicLength]
+			b.mu.Lock()
+			b.messages = append(b.messages, received{topic: topic, payload: string(body[4+topicLength:])})
+			b.mu.Unlock()
+			conn.Write([]byte{packetPuback << 4, 2, id[0], id[1]})
+		case packetDisconnect:
+			b.mu.Lock()
+			b.disconnects++
+			b.mu.Unlock()
+			return
+		}
+	}
+}
+
+// recordConnect extracts the will topic and user name from a CONNECT body
+func (b *fakeBroker) recordConnect(body []byte) {
+	flags := body[7]
+	rest := body[10:]
+	next := func() string {
+		length := int(binary.BigEndian.Uint16(rest))
+		value := string(rest[2 : 2+length])
+		rest = rest[2+length:]
+		return value
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.connects++
+	next() // Client ID
+	if flags&0x04 != 0 {
+		b.willTopic = next()
+		next() // Will payload
+	}
+	if flags&0x80 != 0 {
+		b.username = next()
+	}
+}
+
+func (b *fakeBroker) snapshot() (int, []received) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.connects, append([]received(nil), b.messages...)
+}
+
+func TestNewClient_BrokerURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		address string
+		tls     bool
+		wantErr bool
+	}{
+		{"tcp://broker.local", "broker.local:1883", false, false},
+		{"mqtt://broker.local:1884", "broker.local:1884", false, false},
+		{"ssl://broker.local", "broker.local:8883", true, false},
+		{"http://broker.local", "", false, true},
+		{"tcp://", "", false, true},
+	}
+	for _, tt := range tests {
+		client, err := NewClient(Options{BrokerURL: tt.url})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NewClient(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (client.address != tt.address || client.useTLS != tt.tls) {
+			t.Errorf("NewClient(%q) address = %q, tls = %v", tt.url, client.address, client.useTLS)
+		}
+	}
+}
+
+func TestClient_Publish(t *testing.T) {
+	broker := newFakeBroker(t, 0)
+	client, err := NewClient(Options{
+		BrokerURL:    broker.url(),
+		ClientID:     "test",
+		Username:     "user",
+		Password:     "secret",
+		WillTopic:    "tracker/status",
+		WillPayload:  []byte("offline"),
+		BirthPayload: []byte("online"),
+		Timeout:      time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	if err := client.Publish("tracker/shipment/1/state", []byte("in_transit"), true); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if err := client.Publish("tracker/events", []byte("{}"), false); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	connects, messages := broker.snapshot()
+	if connects != 1 {
+		t.Errorf("connects = %d, want the connection to be reused", connects)
+	}
+	want := []received{
+		{topic: "tracker/status", payload: "online"},
+		{topic: "tracker/shipment/1/state", payload: "in_transit"},
+		{topic: "tracker/events", payload: "{}"},
+	}
+	if len(messages) != len(want) {
+		t.Fatalf("messages = %+v, want %+v", messages, want)
+	}
+	for i := range want {
+		if messages[i] != want[i] {
+			t.Errorf("message %d = %+v, want %+v", i, messages[i], want[i])
+		}
+	}
+	broker.mu.Lock()
+	if broker.username != "user" || broker.willTopic != "tracker/status" {
+		t.Errorf("username = %q, will topic = %q", broker.username, broker.willTopic)
+	}
+	broker.mu.Unlock()
+
+	if err := client.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
+
+func TestClient_ConnectionRefused(t *testing.T) {
+	broker := newFakeBroker(t, 5)
+	client, _ := NewClient(Options{BrokerURL: broker.url(), Timeout: time.Second})
+
+	err := client.Publish("tracker/events", []byte("{}"), false)
+	var refused *ConnectError
+	if !errors.As(err, &refused) || refused.Code != 5 {
+		t.Fatalf("Publish() error = %v, want a refused connection", err)
+	}
+	if refused.Error() != "broker refused connection: not authorized" {
+		t.Errorf("Error() = %q", refused.Error())
+	}
+}
@@ -0,0 +1,184 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"package-tracking/internal/database"
+)
+
+// Digest frequencies, set per channel. DigestToday is the morning list of today's
+// deliveries, sent to channels with TodayAlert set.
+const (
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+	DigestToday  = "today"
+)
+
+// maxDigestItems is how many shipments a digest lists per section
+const maxDigestItems = 10
+
+// Digest summarizes shipment activity over a period
+type Digest struct {
+	Frequency  string                  `json:"frequency"`
+	Since      time.Time               `json:"since"`
+	Until      time.Time               `json:"until"`
+	Stats      database.DashboardStats `json:"stats"` // Totals at the end of the period
+	Delivered  []database.Shipment     `json:"delivered"`
+	Created    []database.Shipment     `json:"created"`
+	Exceptions []database.Shipment     `json:"exceptions"`
+
+	// Today lists the shipments out for delivery or expected today, for DigestToday
+	Today []database.Shipment `json:"today,omitempty"`
+}
+
+// Empty reports whether nothing worth summarizing happened
+func (d Digest) Empty() bool {
+	if d.Frequency == DigestToday {
+		return len(d.Today) == 0
+	}
+	return len(d.Delivered) == 0 && len(d.Created) == 0 && len(d.Exceptions) == 0
+}
+
+// RenderDigest renders a digest's subject and body
+func RenderDigest(d Digest) (subject, body string) {
+	if d.Frequency == DigestToday {
+		return renderToday(d)
+	}
+
+	var counts []string
+	if n := len(d.Delivered); n > 0 {
+		counts = append(counts, fmt.Sprintf("%d delivered", n))
+	}
+	if n := len(d.Created); n > 0 {
+		counts = append(counts, fmt.Sprintf("%d new", n))
+	}
+	if n := len(d.Exceptions); n > 0 {
+		counts = append(counts, plural(n, "exception"))
+	}
+	if len(counts) == 0 {
+		counts = append(counts, "no activity")
+	}
+	title := "Daily"
+	if d.Frequency == DigestWeekly {
+		title = "Weekly"
+	}
+	subject = fmt.Sprintf("%s package digest: %s", title, strings.Join(counts, ", "))
+
+	var sections []string
+	for _, section := range []struct {
+		title     string
+		shipments []database.Shipment
+	}{
+		{"Delivered", d.Delivered},
+		{"New shipments", d.Created},
+		{"Exceptions", d.Exceptions},
+	} {
+		if len(section.shipments) == 0 {
+			continue
+		}
+		lines := []string{fmt.Sprintf("%s (%d):", section.title, len(section.shipments))}
+		for i, shipment := range section.shipments {
+			if i == maxDigestItems {
+				lines = append(lines, fmt.Sprintf("• and %d more", len(section.shipments)-maxDigestItems))
+				break
+			}
+			lines = append(lines, fmt.Sprintf("• %s (%s %s)", digestName(shipment), strings.ToUpper(shipment.Carrier), shipment.TrackingNumber))
+		}
+		sections = append(sections, strings.Join(lines, "\n"))
+	}
+	sections = append(sections, fmt.Sprintf("%s, %d in transit.",
+		plural(d.Stats.ActiveShipments, "active shipment"), d.Stats.InTransit))
+	return subject, strings.Join(sections, "\n\n")
+}
+
+// renderToday renders the list of today's deliveries, out for delivery first
+func renderToday(d Digest) (subject, body string) {
+	var outForDelivery, expected []string
+	for _, shipment := range d.Today {
+		line := fmt.Sprintf("• %s (%s %s)", digestName(shipment), strings.ToUpper(shipment.Carrier), shipment.TrackingNumber)
+		if strings.EqualFold(shipment.Status, "out_for_delivery") {
+			outForDelivery = append(outForDelivery, line)
+		} else {
+			expected = append(expected, line)
+		}
+	}
+
+	subject = fmt.Sprintf("Arriving today: %s", plural(len(d.Today), "package"))
+	var sections []string
+	if len(outForDelivery) > 0 {
+		sections = append(sections, "Out for delivery:\n"+strings.Join(outForDelivery, "\n"))
+	}
+	if len(expected) > 0 {
+		sections = append(sections, "Expected today:\n"+strings.Join(expected, "\n"))
+	}
+	return subject, strings.Join(sections, "\n\n")
+}
+
+// digestName returns a shipment's description, or its tracking number if it has none
+func digestName(shipment database.Shipment) string {
+	if shipment.Description != "" {
+		return shipment.Description
+	}
+	return shipment.TrackingNumber
+}
+
+// plural formats a count with a noun, adding an s unless the count is one
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// DigestFrequencies returns the digest frequencies channels are configured for
+func (d *Dispatcher) DigestFrequencies() map[string]bool {
+	frequencies := make(map[string]bool)
+	if d == nil {
+		return frequencies
+	}
+	for _, ch := range d.channels {
+		if ch.digest != "" {
+			frequencies[ch.digest] = true
+		}
+		if ch.today {
+			frequencies[DigestToday] = true
+		}
+	}
+	return frequencies
+}
+
+// SendDigest queues a digest for every channel configured for its frequency and returns
+// their names
+func (d *Dispatcher) SendDigest(digest Digest) []string {
+	if d == nil {
+		return nil
+	}
+	subject, body := RenderDigest(digest)
+	msg := Message{Subject: subject, Body: body, URL: d.baseURL, Digest: &digest}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stopped {
+		return nil
+	}
+
+	var sent []string
+	for _, ch := range d.channels {
+		if !ch.wantsDigest(digest.Frequency) {
+			continue
+		}
+		d.enqueue(ch, msg)
+		sent = append(sent, ch.notifier.Name())
+	}
+	return sent
+}
+
+// wantsDigest reports whether the channel receives digests of a frequency
+func (c *channel) wantsDigest(frequency string) bool {
+	if frequency == DigestToday {
+		return c.today
+	}
+	return c.digest == frequency
+}
@@ -0,0 +1,181 @@
+package notifications
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"package-tracking/internal/config"
+	"package-tracking/internal/database"
+)
+
+func testDigest() Digest {
+	var created []database.Shipment
+	for i := 1; i <= 12; i++ {
+		created = append(created, database.Shipment{ID: i, TrackingNumber: fmt.Sprintf("TRACK%02d", i), Carrier: "usps"})
+	}
+	return Digest{
+		Frequency:  DigestDaily,
+		Since:      time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC),
+		Until:      time.Date(2024, 6, 2, 8, 0, 0, 0, time.UTC),
+		Stats:      database.DashboardStats{ActiveShipments: 13, InTransit: 4},
+		Delivered:  []database.Shipment{{ID: 20, TrackingNumber: "1Z999AA10123456784", Carrier: "ups", Description: "Headphones"}},
+		Created:    created,
+		Exceptions: []database.Shipment{{ID: 21, TrackingNumber: "123456789012", Carrier: "fedex"}},
+	}
+}
+
+func TestRenderDigest(t *testing.T) {
+	subject, body := RenderDigest(testDigest())
+
+	if subject != "Daily package digest: 1 delivered, 12 new, 1 exception" {
+		t.Errorf("Unexpected subject %q", subject)
+	}
+	for _, want := range []string{
+		"Delivered (1):\n• Headphones (UPS 1Z999AA10123456784)",
+		"New shipments (12):",
+		"• TRACK10 (USPS TRACK10)\n• and 2 more",
+		"Exceptions (1):\n• 123456789012 (FEDEX 123456789012)",
+		"13 active shipments, 4 in transit.",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected body to contain %q, got:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "TRACK11") {
+		t.Errorf("Expected sections to be truncated, got:\n%s", body)
+	}
+
+	subject, _ = RenderDigest(Digest{Frequency: DigestWeekly})
+	if subject != "Weekly package digest: no activity" {
+		t.Errorf("Unexpected subject %q", subject)
+	}
+}
+
+func TestRenderDigest_Today(t *testing.T) {
+	digest := Digest{Frequency: DigestToday, Today: []database.Shipment{
+		{TrackingNumber: "1Z999AA10123456784", Carrier: "ups", Description: "Headphones", Status: "in_transit"},
+		{TrackingNumber: "123456789012", Carrier: "fedex", Status: "out_for_delivery"},
+	}}
+	subject, body := RenderDigest(digest)
+
+	if subject != "Arriving today: 2 packages" {
+		t.Errorf("Unexpected subject %q", subject)
+	}
+	want := "Out for delivery:\n• 123456789012 (FEDEX 123456789012)\n\nExpected today:\n• Headphones (UPS 1Z999AA10123456784)"
+	if body != want {
+		t.Errorf("Expected body:\n%s\ngot:\n%s", want, body)
+	}
+	if digest.Empty() || !(Digest{Frequency: DigestToday, Delivered: digest.Today}).Empty() {
+		t.Error("Expected the today list to be empty only without shipments due today")
+	}
+}
+
+func TestDispatcher_SendDigest(t *testing.T) {
+	d, _ := newTestDispatcher(RetryPolicy{MaxAttempts: 1})
+	daily := &recordingNotifier{name: "daily"}
+	weekly := &recordingNotifier{name: "weekly"}
+	none := &recordingNotifier{name: "none"}
+	for _, ch := range []struct {
+		notifier *recordingNotifier
+		digest   string
+		today    bool
+	}{{daily, DigestDaily, false}, {weekly, DigestWeekly, true}, {none, "", false}} {
+		if err := d.AddChannel(ch.notifier, ChannelConfig{Digest: ch.digest, TodayAlert: ch.today}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	d.SetBaseURL("https://tracker.example.com")
+
+	frequencies := d.DigestFrequencies()
+	if len(frequencies) != 3 || !frequencies[DigestDaily] || !frequencies[DigestWeekly] || !frequencies[DigestToday] {
+		t.Errorf("Unexpected frequencies %v", frequencies)
+	}
+
+	d.Start()
+	sent := d.SendDigest(testDigest())
+	today := d.SendDigest(Digest{Frequency: DigestToday, Today: testDigest().Created})
+	d.Stop()
+
+	if len(sent) != 1 || sent[0] != "daily" {
+		t.Errorf("Expected the digest to be queued for the daily channel, got %v", sent)
+	}
+	if len(today) != 1 || today[0] != "weekly" {
+		t.Errorf("Expected the today list to be queued for the channel with the alert, got %v", today)
+	}
+	messages, _ := daily.sent()
+	if len(messages) != 1 || messages[0].Digest == nil {
+		t.Fatalf("Expected one digest, got %+v", messages)
+	}
+	if messages[0].URL != "https://tracker.example.com" || !strings.HasPrefix(messages[0].Subject, "Daily package digest") {
+		t.Errorf("Unexpected digest message %+v", messages[0])
+	}
+	if messages, _ := weekly.sent(); len(messages) != 1 || messages[0].Digest.Frequency != DigestToday {
+		t.Errorf("Expected only the today list on the weekly channel, got %+v", messages)
+	}
+	if messages, _ := none.sent(); len(messages) != 0 {
+		t.Errorf("Expected no digest on the channel without digests, got %d", len(messages))
+	}
+
+	var nilDispatcher *Dispatcher
+	if sent := nilDispatcher.SendDigest(testDigest()); sent != nil {
+		t.Errorf("Expected nil dispatcher to send nothing, got %v", sent)
+	}
+}
+
+func TestDigestPayloads(t *testing.T) {
+	digest := testDigest()
+	subject, body := RenderDigest(digest)
+	msg := Message{Subject: subject, Body: body, URL: "https://tracker.example.com", Digest: &digest}
+
+	slack := slackPayload(msg)
+	if slack.Text != subject || len(slack.Blocks) != 3 || slack.Blocks[0].Text.Text != subject {
+		t.Errorf("Unexpected Slack digest %+v", slack)
+	}
+
+	discord := discordPayload(msg)
+	if discord.Title != subject || discord.Description != body || discord.Timestamp != "2024-06-02T08:00:00Z" {
+		t.Errorf("Unexpected Discord digest %+v", discord)
+	}
+
+	webhook, err := NewWebhookNotifier("webhook:test", config.WebhookNotificationConfig{
+		URL:             "http://example.invalid",
+		PayloadTemplate: `{"text": {{json .Subject}}}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := webhook.render(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Event     string              `json:"event"`
+		Frequency string              `json:"frequency"`
+		Delivered []database.Shipment `json:"delivered"`
+	}
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatalf("Invalid payload %s: %v", payload, err)
+	}
+	if decoded.Event != "digest" || decoded.Frequency != DigestDaily || len(decoded.Delivered) != 1 {
+		t.Errorf("Unexpected webhook digest %s", payload)
+	}
+
+	// A digest payload template replaces the default
+	webhook, err = NewWebhookNotifier("webhook:test", config.WebhookNotificationConfig{
+		URL:              "http://example.invalid",
+		PayloadTemplates: map[string]string{"digest": `{"text": {{json .Subject}}, "new": {{len .Digest.Created}}}`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err = webhook.render(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(payload) != `{"text": "Daily package digest: 1 delivered, 12 new, 1 exception", "new": 12}` {
+		t.Errorf("Unexpected templated webhook digest %s", payload)
+	}
+}
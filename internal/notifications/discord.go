@@ -0,0 +1,218 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"package-tracking/internal/config"
+	"package-tracking/internal/database"
+)
+
+// Discord limits for embed text
+const (
+	discordTitleLimit       = 256
+	discordDescriptionLimit = 4096
+	discordFieldLimit       = 1024
+)
+
+// discordStatusColors color-codes embeds by the shipment's new status
+var discordStatusColors = map[string]int{
+	"pre_ship":         0x95A5A6, // Grey
+	"in_transit":       0x3498DB, // Blue
+	"out_for_delivery": 0xF1C40F, // Yellow
+	"delivered":        0x2ECC71, // Green
+	"exception":        0xE74C3C, // Red
+	"returned":         0xE67E22, // Orange
+}
+
+// discordDefaultColor is used for statuses without a color of their own
+const discordDefaultColor = 0x7F8C8D
+
+// carrierDomains are the carriers' websites, used for the embed's carrier icon
+var carrierDomains = map[string]string{
+	"usps":   "usps.com",
+	"ups":    "ups.com",
+	"fedex":  "fedex.com",
+	"dhl":    "dhl.com",
+	"amazon": "amazon.com",
+}
+
+// DiscordNotifier posts rich embeds to a Discord webhook
+type DiscordNotifier struct {
+	name       string
+	webhookURL string
+	username   string
+	client     *http.Client
+}
+
+// NewDiscordNotifier creates a notifier that posts to webhookURL. name identifies the
+// channel, e.g. "discord" or "discord:deliveries" for a route. username overrides the
+// webhook's name when set.
+func NewDiscordNotifier(name, webhookURL, username string) *DiscordNotifier {
+	return &DiscordNotifier{
+		name:       name,
+		webhookURL: webhookURL,
+		username:   username,
+		client:     newHTTPClient(),
+	}
+}
+
+// Name returns the channel name
+func (n *DiscordNotifier) Name() string {
+	return n.name
+}
+
+// discordMessage is the body of a webhook request
+type discordMessage struct {
+	Username string         `json:"username,omitempty"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	URL         string         `json:"url,omitempty"`
+	Color       int            `json:"color"`
+	Timestamp   string         `json:"timestamp,omitempty"`
+	Author      *discordAuthor `json:"author,omitempty"`
+	Fields      []discordField `json:"fields,omitempty"`
+}
+
+type discordAuthor struct {
+	Name    string `json:"name"`
+	IconURL string `json:"icon_url,omitempty"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+// Send posts the message
+func (n *DiscordNotifier) Send(ctx context.Context, msg Message) error {
+	payload := discordMessage{
+		Username: n.username,
+		Embeds:   []discordEmbed{discordPayload(msg)},
+	}
+	_, err := postJSON(ctx, n.client, n.webhookURL, nil, payload)
+	return err
+}
+
+// discordPayload formats a message as an embed colored by status, with the carrier as its
+// author, the shipment's details and a snippet of its event history
+func discordPayload(msg Message) discordEmbed {
+	if msg.Digest != nil {
+		return discordEmbed{
+			Title:       truncate(msg.Subject, discordTitleLimit),
+			Description: truncate(msg.Body, discordDescriptionLimit),
+			URL:         msg.URL,
+			Color:       discordDefaultColor,
+			Timestamp:   msg.Digest.Until.UTC().Format(time.RFC3339),
+		}
+	}
+	t := msg.Transition
+
+	status := humanizeStatus(t.ToStatus)
+	if t.FromStatus != "" {
+		status = humanizeStatus(t.FromStatus) + " → " + status
+	}
+	fields := []discordField{
+		{Name: "Tracking number", Value: "`" + t.Shipment.TrackingNumber + "`", Inline: true},
+		{Name: "Status", Value: status, Inline: true},
+	}
+	if t.Shipment.ExpectedDelivery != nil {
+		fields = append(fields, discordField{
+			Name:   "Expected delivery",
+			Value:  t.Shipment.ExpectedDelivery.Format("Mon, Jan 2"),
+			Inline: true,
+		})
+	}
+	if history := discordEventHistory(t.RecentEvents); history != "" {
+		fields = append(fields, discordField{Name: "Recent events", Value: history})
+	}
+
+	color, ok := discordStatusColors[strings.ToLower(t.ToStatus)]
+	if !ok {
+		color = discordDefaultColor
+	}
+
+	embed := discordEmbed{
+		Title:       truncate(msg.Subject, discordTitleLimit),
+		Description: truncate(msg.Body, discordDescriptionLimit),
+		URL:         msg.URL,
+		Color:       color,
+		Author:      discordCarrierAuthor(t.Shipment.Carrier),
+		Fields:      fields,
+	}
+	if !t.OccurredAt.IsZero() {
+		embed.Timestamp = t.OccurredAt.UTC().Format(time.RFC3339)
+	}
+	return embed
+}
+
+// discordCarrierAuthor shows the carrier, with its icon if it is a known carrier
+func discordCarrierAuthor(carrier string) *discordAuthor {
+	if carrier == "" {
+		return nil
+	}
+	author := &discordAuthor{Name: strings.ToUpper(carrier)}
+	if domain, ok := carrierDomains[strings.ToLower(carrier)]; ok {
+		author.IconURL = fmt.Sprintf("https://www.google.com/s2/favicons?domain=%s&sz=64", domain)
+	}
+	return author
+}
+
+// discordEventHistory lists events, newest first, one per line
+func discordEventHistory(events []database.TrackingEvent) string {
+	lines := make([]string, 0, len(events))
+	for _, event := range events {
+		line := "• " + event.Timestamp.Format("Jan 2 3:04 PM") + " — " + event.Description
+		if event.Location != "" {
+			line += " (" + event.Location + ")"
+		}
+		lines = append(lines, line)
+	}
+	return truncate(strings.Join(lines, "\n"), discordFieldLimit)
+}
+
+// truncate shortens text to at most limit characters, ending it with an ellipsis if cut
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+// newDiscordNotifiersFromConfig creates a channel for the Discord webhook and one for each
+// route, with the channel configuration each should be added with
+func newDiscordNotifiersFromConfig(cfg config.DiscordNotificationConfig) ([]Notifier, []ChannelConfig) {
+	var notifiers []Notifier
+	var configs []ChannelConfig
+
+	base := channelConfig(cfg.NotificationChannelConfig)
+	if cfg.WebhookURL != "" {
+		notifiers = append(notifiers, NewDiscordNotifier("discord", cfg.WebhookURL, cfg.Username))
+		configs = append(configs, base)
+	}
+
+	for i, route := range cfg.Routes {
+		name := route.Name
+		if name == "" {
+			name = fmt.Sprint(i + 1)
+		}
+		routeConfig := base
+		if len(route.Statuses) > 0 {
+			routeConfig.Statuses = route.Statuses
+		}
+		routeConfig.Carriers = route.Carriers
+
+		notifiers = append(notifiers, NewDiscordNotifier("discord:"+name, route.WebhookURL, cfg.Username))
+		configs = append(configs, routeConfig)
+	}
+	return notifiers, configs
+}
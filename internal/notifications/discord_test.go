@@ -0,0 +1,122 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"package-tracking/internal/config"
+	"package-tracking/internal/database"
+)
+
+func TestDiscordNotifier_Send(t *testing.T) {
+	var payload discordMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	transition := testTransition("delivered")
+	transition.OccurredAt = time.Date(2025, 3, 14, 15, 0, 0, 0, time.UTC)
+	transition.SetEvents([]database.TrackingEvent{
+		{Timestamp: time.Date(2025, 3, 13, 9, 0, 0, 0, time.UTC), Description: "Departed facility", Location: "Dallas, TX"},
+		{Timestamp: time.Date(2025, 3, 14, 14, 30, 0, 0, time.UTC), Description: "Delivered, front door"},
+	})
+	msg := Message{Subject: "Headphones is delivered", URL: "http://tracker.local/shipments/7", Transition: transition}
+
+	notifier := NewDiscordNotifier("discord", server.URL, "Package Tracker")
+	if err := notifier.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if payload.Username != "Package Tracker" || len(payload.Embeds) != 1 {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+	embed := payload.Embeds[0]
+	if embed.Color != discordStatusColors["delivered"] {
+		t.Errorf("color = %#x, want the delivered color", embed.Color)
+	}
+	if embed.Author == nil || embed.Author.Name != "UPS" || !strings.Contains(embed.Author.IconURL, "ups.com") {
+		t.Errorf("author = %+v, want UPS with its icon", embed.Author)
+	}
+	if embed.URL != msg.URL || embed.Timestamp != "2025-03-14T15:00:00Z" {
+		t.Errorf("url = %q, timestamp = %q", embed.URL, embed.Timestamp)
+	}
+
+	history := embed.Fields[len(embed.Fields)-1]
+	want := "• Mar 14 2:30 PM — Delivered, front door\n• Mar 13 9:00 AM — Departed facility (Dallas, TX)"
+	if history.Name != "Recent events" || history.Value != want {
+		t.Errorf("history = %q, want %q", history.Value, want)
+	}
+}
+
+func TestDiscordPayload_UnknownStatusAndCarrier(t *testing.T) {
+	transition := testTransition("held_at_customs")
+	transition.Shipment.Carrier = "ontrac"
+
+	embed := discordPayload(Message{Subject: strings.Repeat("x", 300), Transition: transition})
+	if embed.Color != discordDefaultColor {
+		t.Errorf("color = %#x, want the default color", embed.Color)
+	}
+	if embed.Author.IconURL != "" {
+		t.Errorf("icon = %q, want none for an unknown carrier", embed.Author.IconURL)
+	}
+	if n := len([]rune(embed.Title)); n != discordTitleLimit {
+		t.Errorf("title has %d characters, want %d", n, discordTitleLimit)
+	}
+}
+
+func TestNewDiscordNotifiersFromConfig(t *testing.T) {
+	notifiers, configs := newDiscordNotifiersFromConfig(config.DiscordNotificationConfig{
+		NotificationChannelConfig: config.NotificationChannelConfig{
+			Enabled:  true,
+			Statuses: []string{"delivered"},
+		},
+		WebhookURL: "https://discord.test/all",
+		Routes: []config.DiscordRoute{
+			{Name: "problems", WebhookURL: "https://discord.test/problems", Statuses: []string{"exception"}},
+			{WebhookURL: "https://discord.test/amazon", Carriers: []string{"amazon"}},
+		},
+	})
+
+	var names []string
+	for _, notifier := range notifiers {
+		names = append(names, notifier.Name())
+	}
+	if strings.Join(names, ",") != "discord,discord:problems,discord:2" {
+		t.Fatalf("channels = %v", names)
+	}
+	if configs[1].Statuses[0] != "exception" {
+		t.Errorf("route statuses = %v, want its own", configs[1].Statuses)
+	}
+	if configs[2].Statuses[0] != "delivered" || configs[2].Carriers[0] != "amazon" {
+		t.Errorf("route config = %+v, want the channel's statuses and its carriers", configs[2])
+	}
+}
+
+func TestDispatcher_CarrierFilter(t *testing.T) {
+	d, _ := newTestDispatcher(RetryPolicy{MaxAttempts: 1})
+	notifier := &recordingNotifier{name: "amazon"}
+	if err := d.AddChannel(notifier, ChannelConfig{Carriers: []string{"Amazon"}}); err != nil {
+		t.Fatalf("AddChannel() error = %v", err)
+	}
+	d.Start()
+
+	d.Notify(testTransition("delivered"))
+	amazon := testTransition("delivered")
+	amazon.Shipment.Carrier = "amazon"
+	d.Notify(amazon)
+	d.Stop()
+
+	messages, _ := notifier.sent()
+	if len(messages) != 1 || messages[0].Transition.Shipment.Carrier != "amazon" {
+		t.Errorf("got %d messages, want only the amazon one", len(messages))
+	}
+}
@@ -0,0 +1,522 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"package-tracking/internal/database"
+)
+
+// defaultStopTimeout is how long Stop waits for queued notifications to be delivered
+const defaultStopTimeout = 10 * time.Second
+
+// RetryPolicy controls how failed deliveries are retried
+type RetryPolicy struct {
+	MaxAttempts    int           // Attempts per message, including the first
+	InitialBackoff time.Duration // Wait before the first retry; doubled for each further retry
+	MaxBackoff     time.Duration
+}
+
+// backoff returns the wait before the given retry, counting from 1
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	wait := p.InitialBackoff
+	for i := 1; i < retry && wait < p.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	return wait
+}
+
+// ChannelConfig configures a channel added to the dispatcher
+type ChannelConfig struct {
+	Statuses        []string // Statuses to notify about; empty means every status
+	Carriers        []string // Carriers to notify about; empty means every carrier
+	SubjectTemplate string
+	BodyTemplate    string
+	Digest          string // DigestDaily or DigestWeekly to also receive digests; empty for none
+	TodayAlert      bool   // Also receive the morning list of today's deliveries
+}
+
+// channel is a notifier with its filter, templates and delivery queue. Each channel delivers
+// from its own goroutine, so a slow or failing service doesn't hold up the others.
+type channel struct {
+	notifier  Notifier
+	statuses  map[string]bool
+	carriers  map[string]bool
+	templates *Templates
+	digest    string
+	today     bool
+	queue     chan Message
+}
+
+// accepts reports whether the channel wants to be notified about a transition
+func (c *channel) accepts(transition Transition) bool {
+	if len(c.statuses) > 0 && !c.statuses[strings.ToLower(transition.ToStatus)] {
+		return false
+	}
+	return len(c.carriers) == 0 || c.carriers[strings.ToLower(transition.Shipment.Carrier)]
+}
+
+// Dispatcher fans shipment status transitions out to the configured channels. A nil
+// *Dispatcher is valid and drops every transition, so callers don't need to check whether
+// notifications are configured.
+type Dispatcher struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	logger    *slog.Logger
+	retry     RetryPolicy
+	queueSize int
+	baseURL   string
+	channels  []*channel
+	rules     RuleSource      // nil delivers by channel filters alone
+	history   HistoryRecorder // nil keeps no history
+
+	// sleep waits between retries and now tells the time for rules; replaced in tests
+	sleep func(ctx context.Context, d time.Duration) error
+	now   func() time.Time
+
+	mu       sync.RWMutex // Guards started, stopped, held and channel filters against Notify
+	started  bool
+	stopped  bool
+	held     map[*time.Timer]bool // Messages held for quiet hours
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewDispatcher creates a dispatcher with no channels. queueSize is the number of messages
+// each channel buffers; transitions reported while a channel's queue is full are dropped
+// for that channel.
+func NewDispatcher(retry RetryPolicy, queueSize int, logger *slog.Logger) *Dispatcher {
+	if retry.MaxAttempts < 1 {
+		retry.MaxAttempts = 1
+	}
+	if queueSize < 1 {
+		queueSize = 1
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Dispatcher{
+		ctx:       ctx,
+		cancel:    cancel,
+		logger:    logger,
+		retry:     retry,
+		queueSize: queueSize,
+		sleep:     sleepContext,
+		now:       time.Now,
+		held:      make(map[*time.Timer]bool),
+	}
+}
+
+// AddChannel registers a notifier. Channels must be added before Start.
+func (d *Dispatcher) AddChannel(notifier Notifier, config ChannelConfig) error {
+	templates, err := ParseTemplates(config.SubjectTemplate, config.BodyTemplate)
+	if err != nil {
+		return fmt.Errorf("%s notifications: %w", notifier.Name(), err)
+	}
+
+	d.channels = append(d.channels, &channel{
+		notifier:  notifier,
+		statuses:  filterSet(config.Statuses),
+		carriers:  filterSet(config.Carriers),
+		templates: templates,
+		digest:    config.Digest,
+		today:     config.TodayAlert,
+		queue:     make(chan Message, d.queueSize),
+	})
+	return nil
+}
+
+// SetBaseURL sets the web UI address used to link messages to their shipment, e.g.
+// http://tracker.local:8080. Messages have no link without one.
+func (d *Dispatcher) SetBaseURL(baseURL string) {
+	d.baseURL = strings.TrimRight(baseURL, "/")
+}
+
+// shipmentURL returns the web UI link to a shipment, or "" without a base URL
+func (d *Dispatcher) shipmentURL(shipmentID int) string {
+	if d.baseURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/shipments/%d", d.baseURL, shipmentID)
+}
+
+// SetRules makes the dispatcher route transitions by the source's enabled rules. While
+// there are none, every channel gets the transitions its filters accept.
+func (d *Dispatcher) SetRules(rules RuleSource) {
+	d.rules = rules
+}
+
+// SetHistory makes the dispatcher record the outcome of every notification
+func (d *Dispatcher) SetHistory(history HistoryRecorder) {
+	d.history = history
+}
+
+// filterSet returns the lowercased values of a channel filter as a set
+func filterSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[strings.ToLower(strings.TrimSpace(value))] = true
+	}
+	return set
+}
+
+// Channels returns the names of the registered channels
+func (d *Dispatcher) Channels() []string {
+	if d == nil {
+		return nil
+	}
+	names := make([]string, len(d.channels))
+	for i, ch := range d.channels {
+		names[i] = ch.notifier.Name()
+	}
+	return names
+}
+
+// ReloadChannels replaces the filters and templates of each channel with those of the
+// channel of the same name in next, a dispatcher built from the reloaded configuration.
+// Channels only one of them has are left alone; adding or removing them needs a restart.
+func (d *Dispatcher) ReloadChannels(next *Dispatcher) []string {
+	if d == nil || next == nil {
+		return nil
+	}
+	reloaded := make(map[string]*channel, len(next.channels))
+	for _, ch := range next.channels {
+		reloaded[ch.notifier.Name()] = ch
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var names []string
+	for _, ch := range d.channels {
+		if updated, ok := reloaded[ch.notifier.Name()]; ok {
+			ch.statuses = updated.statuses
+			ch.carriers = updated.carriers
+			ch.templates = updated.templates
+			names = append(names, ch.notifier.Name())
+		}
+	}
+	return names
+}
+
+// Start begins delivering notifications
+func (d *Dispatcher) Start() {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.started || d.stopped {
+		return
+	}
+	d.started = true
+
+	d.logger.Info("Starting notification dispatcher", "channels", d.Channels())
+	for _, ch := range d.channels {
+		d.wg.Add(1)
+		go d.deliverLoop(ch)
+	}
+}
+
+// Stop stops accepting transitions and waits for queued notifications to be delivered
+func (d *Dispatcher) Stop() {
+	d.StopWithTimeout(defaultStopTimeout)
+}
+
+// StopWithTimeout is like Stop, but gives up on undelivered notifications after timeout
+func (d *Dispatcher) StopWithTimeout(timeout time.Duration) {
+	if d == nil {
+		return
+	}
+	d.stopOnce.Do(func() {
+		d.mu.Lock()
+		d.stopped = true
+		for _, ch := range d.channels {
+			close(ch.queue)
+		}
+		for timer := range d.held {
+			timer.Stop()
+		}
+		if len(d.held) > 0 {
+			d.logger.Warn("Dropping notifications held for quiet hours", "count", len(d.held))
+		}
+		d.mu.Unlock()
+
+		done := make(chan struct{})
+		go func() {
+			d.wg.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(timeout):
+			d.logger.Warn("Notification dispatcher stopped before all notifications were delivered",
+				"timeout", timeout)
+			d.cancel()
+			<-done
+		}
+		d.cancel()
+	})
+}
+
+// Notify queues a transition for every channel that wants it. It never blocks; if a
+// channel's queue is full the transition is dropped for that channel.
+func (d *Dispatcher) Notify(transition Transition) {
+	d.dispatch(transition, func(ch *channel, url string) (Message, error) {
+		return ch.templates.Render(transition, url)
+	})
+}
+
+// Escalate queues a message about a shipment, such as a reminder about an unresolved
+// exception, for every channel that wants its transition, like Notify but with a fixed
+// subject and body rather than the channels' templates. It returns the channels the
+// message was queued or held for.
+func (d *Dispatcher) Escalate(transition Transition, subject, body string) []string {
+	return d.dispatch(transition, func(ch *channel, url string) (Message, error) {
+		return Message{Subject: subject, Body: body, URL: url, Transition: transition}, nil
+	})
+}
+
+// dispatch queues the message render returns for every channel that wants a transition,
+// routing by channel filters and rules, and returns the channels it was queued for
+func (d *Dispatcher) dispatch(transition Transition, render func(ch *channel, url string) (Message, error)) []string {
+	if d == nil || len(d.channels) == 0 {
+		return nil
+	}
+	if transition.OccurredAt.IsZero() {
+		transition.OccurredAt = time.Now()
+	}
+	rules := d.enabledRules()
+	now := d.now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stopped {
+		return nil
+	}
+
+	var sent []string
+	url := d.shipmentURL(transition.Shipment.ID)
+	for _, ch := range d.channels {
+		if !ch.accepts(transition) {
+			continue
+		}
+		var holdUntil time.Time
+		if rules != nil {
+			var send bool
+			if send, holdUntil = routeByRules(rules, ch.notifier.Name(), transition, now); !send {
+				continue
+			}
+		}
+
+		msg, err := render(ch, url)
+		if err != nil {
+			d.logger.Error("Failed to render notification",
+				"channel", ch.notifier.Name(),
+				"shipment_id", transition.Shipment.ID,
+				"error", err)
+			continue
+		}
+
+		sent = append(sent, ch.notifier.Name())
+		if !holdUntil.IsZero() {
+			d.hold(ch, msg, holdUntil.Sub(now))
+			continue
+		}
+		d.enqueue(ch, msg)
+	}
+	return sent
+}
+
+// enabledRules returns the enabled rules, or nil when transitions are routed by channel
+// filters alone. If the rules can't be read, notifications are delivered by channel
+// filters rather than lost.
+func (d *Dispatcher) enabledRules() []database.NotificationRule {
+	if d.rules == nil {
+		return nil
+	}
+	rules, err := d.rules.GetEnabled()
+	if err != nil {
+		d.logger.Error("Failed to load notification rules, ignoring them", "error", err)
+		return nil
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	return rules
+}
+
+// enqueue queues a message for a channel without blocking, and reports whether it was
+// queued. Callers hold mu and have checked that the dispatcher isn't stopped.
+func (d *Dispatcher) enqueue(ch *channel, msg Message) bool {
+	select {
+	case ch.queue <- msg:
+		return true
+	default:
+		d.logger.Warn("Notification queue full, dropping notification",
+			"channel", ch.notifier.Name(),
+			"shipment_id", msg.Transition.Shipment.ID,
+			"status", msg.Transition.ToStatus)
+		d.record(ch, msg, database.NotificationDropped, 0, errQueueFull)
+		return false
+	}
+}
+
+// hold queues a message for a channel once wait has passed, at the end of quiet hours.
+// Held messages are dropped if the dispatcher stops first. Callers hold mu.
+func (d *Dispatcher) hold(ch *channel, msg Message, wait time.Duration) {
+	d.logger.Info("Holding notification for quiet hours",
+		"channel", ch.notifier.Name(),
+		"shipment_id", msg.Transition.Shipment.ID,
+		"until", d.now().Add(wait).Format(time.RFC3339))
+
+	var timer *time.Timer
+	timer = time.AfterFunc(wait, func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		delete(d.held, timer)
+		if !d.stopped {
+			d.enqueue(ch, msg)
+		}
+	})
+	d.held[timer] = true
+}
+
+// ChannelPreview is what a channel would be sent for a transition
+type ChannelPreview struct {
+	Channel  string `json:"channel"`
+	Accepted bool   `json:"accepted"` // False when the channel's own filters reject the transition
+	Subject  string `json:"subject,omitempty"`
+	Body     string `json:"body,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// PreviewRule renders the messages the channels a rule targets would be sent for a
+// transition, without sending them
+func (d *Dispatcher) PreviewRule(rule database.NotificationRule, transition Transition) []ChannelPreview {
+	if d == nil {
+		return nil
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	previews := []ChannelPreview{}
+	url := d.shipmentURL(transition.Shipment.ID)
+	for _, ch := range d.channels {
+		if !targets(rule, ch.notifier.Name()) {
+			continue
+		}
+		preview := ChannelPreview{Channel: ch.notifier.Name(), Accepted: ch.accepts(transition)}
+		if msg, err := ch.templates.Render(transition, url); err != nil {
+			preview.Error = err.Error()
+		} else {
+			preview.Subject = msg.Subject
+			preview.Body = msg.Body
+		}
+		previews = append(previews, preview)
+	}
+	return previews
+}
+
+// SendRuleTest queues a transition for every channel a rule targets, ignoring filters and
+// quiet hours, and returns the channels it was queued for
+func (d *Dispatcher) SendRuleTest(rule database.NotificationRule, transition Transition) []string {
+	if d == nil {
+		return nil
+	}
+	if transition.OccurredAt.IsZero() {
+		transition.OccurredAt = time.Now()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stopped {
+		return nil
+	}
+
+	sent := []string{}
+	url := d.shipmentURL(transition.Shipment.ID)
+	for _, ch := range d.channels {
+		if !targets(rule, ch.notifier.Name()) {
+			continue
+		}
+		msg, err := ch.templates.Render(transition, url)
+		if err != nil {
+			continue
+		}
+		d.enqueue(ch, msg)
+		sent = append(sent, ch.notifier.Name())
+	}
+	return sent
+}
+
+// deliverLoop delivers a channel's messages until its queue is closed
+func (d *Dispatcher) deliverLoop(ch *channel) {
+	defer d.wg.Done()
+	for msg := range ch.queue {
+		attempts, err := d.deliver(ch, msg)
+		status := database.NotificationSent
+		if err != nil {
+			status = database.NotificationFailed
+		}
+		d.record(ch, msg, status, attempts, err)
+	}
+}
+
+// deliver sends a message, retrying with exponential backoff until it succeeds, fails
+// permanently, runs out of attempts or the dispatcher is stopped. It returns the number
+// of attempts made.
+func (d *Dispatcher) deliver(ch *channel, msg Message) (attempts int, err error) {
+	for attempt := 1; attempt <= d.retry.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			if sleepErr := d.sleep(d.ctx, d.retry.backoff(attempt-1)); sleepErr != nil {
+				break
+			}
+		}
+
+		attempts = attempt
+		err = ch.notifier.Send(d.ctx, msg)
+		if err == nil {
+			d.logger.Debug("Delivered notification",
+				"channel", ch.notifier.Name(),
+				"shipment_id", msg.Transition.Shipment.ID,
+				"attempt", attempt)
+			return attempts, nil
+		}
+		if IsPermanent(err) || d.ctx.Err() != nil {
+			break
+		}
+
+		d.logger.Warn("Notification delivery failed",
+			"channel", ch.notifier.Name(),
+			"shipment_id", msg.Transition.Shipment.ID,
+			"attempt", attempt,
+			"max_attempts", d.retry.MaxAttempts,
+			"error", err)
+	}
+
+	d.logger.Error("Giving up on notification",
+		"channel", ch.notifier.Name(),
+		"shipment_id", msg.Transition.Shipment.ID,
+		"status", msg.Transition.ToStatus,
+		"error", err)
+	return attempts, err
+}
+
+// sleepContext waits for d, returning early with ctx's error if it is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,265 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"package-tracking/internal/database"
+)
+
+// recordingNotifier records the messages it is sent, failing the first failures sends
+type recordingNotifier struct {
+	name     string
+	mu       sync.Mutex
+	messages []Message
+	attempts int
+	failures int
+	err      error
+}
+
+func (n *recordingNotifier) Name() string { return n.name }
+
+func (n *recordingNotifier) Send(ctx context.Context, msg Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.attempts++
+	if n.attempts <= n.failures {
+		if n.err != nil {
+			return n.err
+		}
+		return errors.New("service unavailable")
+	}
+	n.messages = append(n.messages, msg)
+	return nil
+}
+
+func (n *recordingNotifier) sent() ([]Message, int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]Message(nil), n.messages...), n.attempts
+}
+
+func newTestDispatcher(retry RetryPolicy) (*Dispatcher, *[]time.Duration) {
+	d := NewDispatcher(retry, 10, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	var waits []time.Duration
+	var mu sync.Mutex
+	d.sleep = func(ctx context.Context, wait time.Duration) error {
+		mu.Lock()
+		defer mu.Unlock()
+		waits = append(waits, wait)
+		return ctx.Err()
+	}
+	return d, &waits
+}
+
+func testTransition(to string) Transition {
+	return Transition{
+		Shipment:   database.Shipment{ID: 7, TrackingNumber: "1Z999AA10123456784", Carrier: "ups", Description: "Headphones", Status: to},
+		FromStatus: "in_transit",
+		ToStatus:   to,
+		Source:     SourceAutoUpdate,
+	}
+}
+
+func TestDispatcher_DeliversToMatchingChannels(t *testing.T) {
+	d, _ := newTestDispatcher(RetryPolicy{MaxAttempts: 1})
+	all := &recordingNotifier{name: "all"}
+	delivered := &recordingNotifier{name: "delivered"}
+	if err := d.AddChannel(all, ChannelConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.AddChannel(delivered, ChannelConfig{Statuses: []string{"Delivered"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	d.Start()
+	d.Notify(testTransition("out_for_delivery"))
+	d.Notify(testTransition("delivered"))
+	d.Stop()
+
+	if messages, _ := all.sent(); len(messages) != 2 {
+		t.Errorf("Expected both transitions on the unfiltered channel, got %d", len(messages))
+	}
+	messages, _ := delivered.sent()
+	if len(messages) != 1 || messages[0].Transition.ToStatus != "delivered" {
+		t.Fatalf("Expected only the delivered transition, got %+v", messages)
+	}
+	if messages[0].Subject != "Headphones is delivered" {
+		t.Errorf("Unexpected subject %q", messages[0].Subject)
+	}
+}
+
+func TestDispatcher_RetriesWithBackoff(t *testing.T) {
+	d, waits := newTestDispatcher(RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Second, MaxBackoff: 3 * time.Second})
+	notifier := &recordingNotifier{name: "flaky", failures: 3}
+	if err := d.AddChannel(notifier, ChannelConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	d.Start()
+	d.Notify(testTransition("delivered"))
+	d.Stop()
+
+	messages, attempts := notifier.sent()
+	if len(messages) != 1 || attempts != 4 {
+		t.Errorf("Expected delivery on the 4th attempt, got %d messages after %d attempts", len(messages), attempts)
+	}
+	expected := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}
+	if len(*waits) != len(expected) {
+		t.Fatalf("Expected waits %v, got %v", expected, *waits)
+	}
+	for i := range expected {
+		if (*waits)[i] != expected[i] {
+			t.Errorf("Expected waits %v, got %v", expected, *waits)
+			break
+		}
+	}
+}
+
+func TestDispatcher_GivesUp(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"after max attempts", errors.New("timeout"), 3},
+		{"on permanent errors", Permanent(errors.New("invalid token")), 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, _ := newTestDispatcher(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second, MaxBackoff: time.Second})
+			notifier := &recordingNotifier{name: "broken", failures: 10, err: tt.err}
+			if err := d.AddChannel(notifier, ChannelConfig{}); err != nil {
+				t.Fatal(err)
+			}
+
+			d.Start()
+			d.Notify(testTransition("delivered"))
+			d.Stop()
+
+			if _, attempts := notifier.sent(); attempts != tt.expected {
+				t.Errorf("Expected %d attempts, got %d", tt.expected, attempts)
+			}
+		})
+	}
+}
+
+func TestDispatcher_NotifyAfterStopAndNil(t *testing.T) {
+	d, _ := newTestDispatcher(RetryPolicy{MaxAttempts: 1})
+	notifier := &recordingNotifier{name: "log"}
+	if err := d.AddChannel(notifier, ChannelConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	d.Start()
+	d.Stop()
+	d.Notify(testTransition("delivered"))
+
+	if messages, _ := notifier.sent(); len(messages) != 0 {
+		t.Errorf("Expected no delivery after Stop, got %d", len(messages))
+	}
+
+	// A nil dispatcher drops everything
+	var none *Dispatcher
+	none.Start()
+	none.Notify(testTransition("delivered"))
+	none.Stop()
+}
+
+func TestDispatcher_Escalate(t *testing.T) {
+	d, _ := newTestDispatcher(RetryPolicy{MaxAttempts: 1})
+	exceptions := &recordingNotifier{name: "exceptions"}
+	delivered := &recordingNotifier{name: "delivered"}
+	if err := d.AddChannel(exceptions, ChannelConfig{Statuses: []string{"exception"}, SubjectTemplate: "ignored"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.AddChannel(delivered, ChannelConfig{Statuses: []string{"delivered"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	d.Start()
+	transition := testTransition("exception")
+	transition.FromStatus = ""
+	transition.Source = SourceEscalation
+	sent := d.Escalate(transition, "Delivery exception: Headphones", "Still stuck.")
+	d.Stop()
+
+	if len(sent) != 1 || sent[0] != "exceptions" {
+		t.Errorf("Expected the escalation to go to the exceptions channel, got %v", sent)
+	}
+	messages, _ := exceptions.sent()
+	if len(messages) != 1 || messages[0].Subject != "Delivery exception: Headphones" || messages[0].Body != "Still stuck." {
+		t.Errorf("Expected the fixed subject and body, got %+v", messages)
+	}
+	if messages, _ := delivered.sent(); len(messages) != 0 {
+		t.Errorf("Expected the delivered channel's filter to apply, got %d", len(messages))
+	}
+}
+
+func TestDispatcher_InvalidTemplate(t *testing.T) {
+	d, _ := newTestDispatcher(RetryPolicy{MaxAttempts: 1})
+	if err := d.AddChannel(&recordingNotifier{name: "log"}, ChannelConfig{SubjectTemplate: "{{.Nope"}); err == nil {
+		t.Error("Expected an error for an invalid template")
+	}
+}
+
+func TestDispatcher_ReloadChannels(t *testing.T) {
+	d, _ := newTestDispatcher(RetryPolicy{MaxAttempts: 1})
+	slack := &recordingNotifier{name: "slack"}
+	if err := d.AddChannel(slack, ChannelConfig{Statuses: []string{"delivered"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	next, _ := newTestDispatcher(RetryPolicy{MaxAttempts: 1})
+	for _, ch := range []struct {
+		name   string
+		config ChannelConfig
+	}{
+		{"slack", ChannelConfig{Statuses: []string{"exception"}, SubjectTemplate: "Problem with {{.Name}}"}},
+		{"discord", ChannelConfig{}},
+	} {
+		if err := next.AddChannel(&recordingNotifier{name: ch.name}, ch.config); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if reloaded := d.ReloadChannels(next); len(reloaded) != 1 || reloaded[0] != "slack" {
+		t.Errorf("Expected only the existing channel to be reloaded, got %v", reloaded)
+	}
+	if channels := d.Channels(); len(channels) != 1 {
+		t.Errorf("Expected channels to be kept, got %v", channels)
+	}
+
+	d.Start()
+	d.Notify(testTransition("delivered"))
+	d.Notify(testTransition("exception"))
+	d.Stop()
+
+	messages, _ := slack.sent()
+	if len(messages) != 1 || messages[0].Subject != "Problem with Headphones" {
+		t.Errorf("Expected the reloaded filter and template to apply, got %+v", messages)
+	}
+
+	var nilDispatcher *Dispatcher
+	if reloaded := nilDispatcher.ReloadChannels(next); reloaded != nil {
+		t.Errorf("Expected nil dispatcher to reload nothing, got %v", reloaded)
+	}
+}
+
+func TestNewTransition(t *testing.T) {
+	shipment := database.Shipment{ID: 1, Status: "delivered"}
+
+	transition, changed := NewTransition(shipment, "out_for_delivery", SourceManualRefresh)
+	if !changed || transition.FromStatus != "out_for_delivery" || transition.ToStatus != "delivered" || transition.Source != SourceManualRefresh {
+		t.Errorf("Unexpected transition %+v (changed %v)", transition, changed)
+	}
+
+	if _, changed := NewTransition(shipment, "delivered", SourceManualRefresh); changed {
+		t.Error("Expected no transition when the status is unchanged")
+	}
+}
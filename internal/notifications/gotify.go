@@ -0,0 +1,68 @@
+package notifications
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// gotifyPriorities maps priorities onto Gotify's 0 to 10 scale. Gotify's Android app
+// alerts with sound from 4 and pops up from 8.
+var gotifyPriorities = map[Priority]int{
+	PriorityMin:    0,
+	PriorityLow:    2,
+	PriorityNormal: 5,
+	PriorityHigh:   8,
+	PriorityUrgent: 10,
+}
+
+// GotifyNotifier sends notifications to a Gotify server
+type GotifyNotifier struct {
+	serverURL  string
+	appToken   string
+	priorities PriorityMap
+	client     *http.Client
+}
+
+// NewGotifyNotifier creates a notifier that sends to serverURL as the application appToken
+func NewGotifyNotifier(serverURL, appToken string, priorities PriorityMap) *GotifyNotifier {
+	return &GotifyNotifier{
+		serverURL:  strings.TrimRight(serverURL, "/"),
+		appToken:   appToken,
+		priorities: priorities,
+		client:     newHTTPClient(),
+	}
+}
+
+// Name returns the channel name
+func (n *GotifyNotifier) Name() string {
+	return "gotify"
+}
+
+// gotifyMessage is the body of a message API request
+type gotifyMessage struct {
+	Title    string         `json:"title"`
+	Message  string         `json:"message"`
+	Priority int            `json:"priority"`
+	Extras   map[string]any `json:"extras,omitempty"`
+}
+
+// Send sends the message
+func (n *GotifyNotifier) Send(ctx context.Context, msg Message) error {
+	payload := gotifyMessage{
+		Title:    msg.Subject,
+		Message:  pushText(msg),
+		Priority: gotifyPriorities[n.priorities.For(msg.Transition.ToStatus)],
+	}
+	if msg.URL != "" {
+		// Opens the shipment when the notification is tapped
+		payload.Extras = map[string]any{
+			"client::notification": map[string]any{"click": map[string]string{"url": msg.URL}},
+		}
+	}
+
+	_, err := postJSON(ctx, n.client, n.serverURL+"/message", map[string]string{
+		"X-Gotify-Key": n.appToken,
+	}, payload)
+	return err
+}
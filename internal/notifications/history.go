@@ -0,0 +1,105 @@
+package notifications
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"package-tracking/internal/database"
+)
+
+// Source of digests and the today list in the notification history
+const historySourceDigest = "digest"
+
+var (
+	// ErrNotRunning is returned when redelivering while the dispatcher is stopped or
+	// notifications are disabled
+	ErrNotRunning = errors.New("notifications are not running")
+	// ErrUnknownChannel is returned when redelivering to a channel that is no longer
+	// configured
+	ErrUnknownChannel = errors.New("notification channel is not configured")
+	// ErrQueueFull is returned when redelivering to a channel whose queue is full
+	ErrQueueFull = errors.New("notification queue is full")
+
+	errQueueFull = errors.New("queue full")
+)
+
+// HistoryRecorder keeps the outcome of every notification;
+// *database.NotificationHistoryStore in production
+type HistoryRecorder interface {
+	Create(record *database.NotificationRecord) error
+}
+
+// record adds a notification's outcome to the history, if one is kept
+func (d *Dispatcher) record(ch *channel, msg Message, status string, attempts int, err error) {
+	if d.history == nil {
+		return
+	}
+
+	payload, marshalErr := json.Marshal(msg)
+	if marshalErr != nil {
+		d.logger.Error("Failed to encode notification for history", "channel", ch.notifier.Name(), "error", marshalErr)
+		return
+	}
+	record := &database.NotificationRecord{
+		Channel:  ch.notifier.Name(),
+		Source:   msg.Transition.Source,
+		ToStatus: msg.Transition.ToStatus,
+		Subject:  msg.Subject,
+		Body:     msg.Body,
+		Payload:  string(payload),
+		Status:   status,
+		Attempts: attempts,
+	}
+	if msg.Digest != nil {
+		record.Source = historySourceDigest
+	} else {
+		shipmentID := msg.Transition.Shipment.ID
+		record.ShipmentID = &shipmentID
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	if msg.RetryOf != 0 {
+		retryOf := msg.RetryOf
+		record.RetryOf = &retryOf
+	}
+
+	if err := d.history.Create(record); err != nil {
+		d.logger.Error("Failed to record notification history",
+			"channel", record.Channel,
+			"shipment_id", record.ShipmentID,
+			"error", err)
+	}
+}
+
+// Redeliver queues the message of a history record for its channel again, ignoring
+// filters, rules and quiet hours. The outcome is recorded as a new record that refers to
+// the original.
+func (d *Dispatcher) Redeliver(record database.NotificationRecord) error {
+	if d == nil {
+		return ErrNotRunning
+	}
+
+	var msg Message
+	if err := json.Unmarshal([]byte(record.Payload), &msg); err != nil {
+		return fmt.Errorf("invalid notification payload: %w", err)
+	}
+	msg.RetryOf = record.ID
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.started || d.stopped {
+		return ErrNotRunning
+	}
+	for _, ch := range d.channels {
+		if ch.notifier.Name() != record.Channel {
+			continue
+		}
+		if !d.enqueue(ch, msg) {
+			return ErrQueueFull
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownChannel, record.Channel)
+}
@@ -0,0 +1,88 @@
+package notifications
+
+import (
+	"errors"
+	"testing"
+
+	"package-tracking/internal/database"
+)
+
+func TestDispatcher_History(t *testing.T) {
+	db, err := database.Open(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.Close()
+
+	d, _ := newTestDispatcher(RetryPolicy{MaxAttempts: 2})
+	ok := &recordingNotifier{name: "ok"}
+	failing := &recordingNotifier{name: "failing", failures: 2}
+	for _, n := range []*recordingNotifier{ok, failing} {
+		if err := d.AddChannel(n, ChannelConfig{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	d.SetHistory(db.Notifications)
+
+	if err := d.Redeliver(database.NotificationRecord{Channel: "ok", Payload: "{}"}); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("Expected redelivery before Start to fail, got %v", err)
+	}
+
+	d.Start()
+	d.Notify(Transition{Shipment: database.Shipment{ID: 7, Carrier: "ups"}, ToStatus: "delivered", Source: SourceAutoUpdate})
+	d.SendDigest(Digest{Frequency: DigestDaily})
+	d.StopWithTimeout(defaultStopTimeout)
+
+	records, err := db.Notifications.List(database.NotificationHistoryFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected one record per channel, got %+v", records)
+	}
+	byChannel := map[string]database.NotificationRecord{}
+	for _, record := range records {
+		byChannel[record.Channel] = record
+	}
+	sent := byChannel["ok"]
+	if sent.Status != database.NotificationSent || sent.Attempts != 1 || sent.ShipmentID == nil || *sent.ShipmentID != 7 ||
+		sent.Source != SourceAutoUpdate || sent.ToStatus != "delivered" || sent.Subject == "" {
+		t.Errorf("Unexpected record of the delivered notification %+v", sent)
+	}
+	failed := byChannel["failing"]
+	if failed.Status != database.NotificationFailed || failed.Attempts != 2 || failed.Error != "service unavailable" {
+		t.Errorf("Unexpected record of the failed notification %+v", failed)
+	}
+
+	// The failing channel now succeeds, so a redelivery goes through
+	d, _ = newTestDispatcher(RetryPolicy{MaxAttempts: 1})
+	if err := d.AddChannel(failing, ChannelConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	d.SetHistory(db.Notifications)
+	d.Start()
+	if err := d.Redeliver(failed); err != nil {
+		t.Fatalf("Failed to redeliver: %v", err)
+	}
+	if err := d.Redeliver(database.NotificationRecord{Channel: "gone", Payload: failed.Payload}); !errors.Is(err, ErrUnknownChannel) {
+		t.Errorf("Expected redelivery to a missing channel to fail, got %v", err)
+	}
+	d.StopWithTimeout(defaultStopTimeout)
+
+	messages, _ := failing.sent()
+	if len(messages) != 1 || messages[0].Subject != failed.Subject || messages[0].Transition.Shipment.ID != 7 {
+		t.Errorf("Expected the original message to be redelivered, got %+v", messages)
+	}
+	retries, err := db.Notifications.List(database.NotificationHistoryFilter{Channel: "failing", Status: database.NotificationSent})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(retries) != 1 || retries[0].RetryOf == nil || *retries[0].RetryOf != failed.ID {
+		t.Errorf("Expected the redelivery to refer to the original, got %+v", retries)
+	}
+
+	var nilDispatcher *Dispatcher
+	if err := nilDispatcher.Redeliver(failed); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("Expected nil dispatcher to refuse redelivery, got %v", err)
+	}
+}
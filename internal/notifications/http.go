@@ -0,0 +1,71 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultHTTPTimeout bounds a single delivery request to a notification service
+const defaultHTTPTimeout = 15 * time.Second
+
+// maxResponseBody is how much of a service's response is read, for errors and API replies
+const maxResponseBody = 64 * 1024
+
+// newHTTPClient returns the client notifiers use unless one is configured
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: defaultHTTPTimeout}
+}
+
+// postJSON posts payload as JSON to url and returns the response body. Responses other than
+// 2xx are errors; client errors other than 429 Too Many Requests are marked Permanent,
+// since retrying the same request won't fix them.
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, payload any) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, Permanent(fmt.Errorf("failed to encode payload: %w", err))
+	}
+
+	all := map[string]string{"Content-Type": "application/json; charset=utf-8"}
+	for name, value := range headers {
+		all[name] = value
+	}
+	return sendRequest(ctx, client, http.MethodPost, url, all, data)
+}
+
+// sendRequest sends body to url and returns the response body, treating responses like
+// postJSON does
+func sendRequest(ctx context.Context, client *http.Client, method, url string, headers map[string]string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, Permanent(fmt.Errorf("failed to create request: %w", err))
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		err := fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return nil, Permanent(err)
+		}
+		return nil, err
+	}
+	return respBody, nil
+}
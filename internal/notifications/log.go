@@ -0,0 +1,32 @@
+package notifications
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogNotifier writes notifications to the server log. It is useful for checking templates
+// and filters before connecting a real service.
+type LogNotifier struct {
+	logger *slog.Logger
+}
+
+// NewLogNotifier creates a notifier that logs to logger
+func NewLogNotifier(logger *slog.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+// Name returns the channel name
+func (n *LogNotifier) Name() string {
+	return "log"
+}
+
+// Send logs the message
+func (n *LogNotifier) Send(ctx context.Context, msg Message) error {
+	n.logger.Info("Notification",
+		"subject", msg.Subject,
+		"body", msg.Body,
+		"shipment_id", msg.Transition.Shipment.ID,
+		"source", msg.Transition.Source)
+	return nil
+}
@@ -0,0 +1,115 @@
+// Package notifications delivers shipment status changes to notification channels such as
+// chat services and push providers. Handlers and workers report status transitions to a
+// Dispatcher, which renders a message for every channel that wants the transition and
+// delivers it in the background, retrying failed deliveries with exponential backoff.
+package notifications
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"package-tracking/internal/database"
+)
+
+// Sources of status transitions
+const (
+	SourceManualRefresh = "manual_refresh"
+	SourceAutoUpdate    = "auto_update"
+	SourceEscalation    = "escalation" // Reminders about unresolved exceptions and delays
+)
+
+// Transition is a change in a shipment's status
+type Transition struct {
+	Shipment   database.Shipment // The shipment after the change
+	FromStatus string
+	ToStatus   string
+	Source     string // Where the change was detected, e.g. SourceAutoUpdate
+	OccurredAt time.Time
+
+	// LatestEvent is the newest tracking event known when the change was detected, if any,
+	// and RecentEvents the newest few, newest first. Both are set by SetEvents.
+	LatestEvent  *database.TrackingEvent
+	RecentEvents []database.TrackingEvent
+}
+
+// NewTransition returns the transition of shipment from its previous status to its current
+// one, and false if the status didn't change
+func NewTransition(shipment database.Shipment, previousStatus, source string) (Transition, bool) {
+	if shipment.Status == "" || shipment.Status == previousStatus {
+		return Transition{}, false
+	}
+	return Transition{
+		Shipment:   shipment,
+		FromStatus: previousStatus,
+		ToStatus:   shipment.Status,
+		Source:     source,
+		OccurredAt: time.Now(),
+	}, true
+}
+
+// maxRecentEvents is how many events a transition keeps for event history snippets
+const maxRecentEvents = 5
+
+// SetEvents records the shipment's newest events, known when the change was detected
+func (t *Transition) SetEvents(events []database.TrackingEvent) {
+	recent := append([]database.TrackingEvent(nil), events...)
+	sort.SliceStable(recent, func(i, j int) bool {
+		return recent[i].Timestamp.After(recent[j].Timestamp)
+	})
+	if len(recent) > maxRecentEvents {
+		recent = recent[:maxRecentEvents]
+	}
+
+	t.RecentEvents = recent
+	t.LatestEvent = nil
+	if len(recent) > 0 {
+		t.LatestEvent = &recent[0]
+	}
+}
+
+// Message is a rendered notification for a single channel
+type Message struct {
+	Subject    string
+	Body       string
+	URL        string     // Link to the shipment in the web UI; empty without a base URL
+	Transition Transition // For notifiers that format their own payloads
+
+	// Digest is set, and Transition empty, for digests; URL then links to the web UI
+	Digest *Digest
+
+	// RetryOf is the history record a redelivered message repeats
+	RetryOf int `json:"-"`
+}
+
+// Notifier delivers messages to a notification service. Send is called from the channel's
+// own goroutine and should respect ctx cancellation.
+type Notifier interface {
+	// Name identifies the channel in logs and configuration, e.g. "slack"
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
+
+// permanentError marks a delivery failure that retrying won't fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so the dispatcher doesn't retry the delivery, e.g. when a service
+// rejects the request as invalid or unauthorized
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
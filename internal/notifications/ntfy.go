@@ -0,0 +1,96 @@
+package notifications
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// defaultNtfyServer is the public ntfy server, used when no self-hosted server is configured
+const defaultNtfyServer = "https://ntfy.sh"
+
+// ntfyTags are emoji shortcodes shown before the title, by status
+var ntfyTags = map[string]string{
+	"in_transit":       "truck",
+	"out_for_delivery": "package",
+	"delivered":        "white_check_mark",
+	"exception":        "warning",
+	"returned":         "leftwards_arrow_with_hook",
+}
+
+// NtfyNotifier publishes notifications to an ntfy topic
+type NtfyNotifier struct {
+	serverURL  string
+	topic      string
+	token      string
+	priorities PriorityMap
+	client     *http.Client
+}
+
+// NewNtfyNotifier creates a notifier that publishes to topic on serverURL, or on ntfy.sh if
+// serverURL is empty. token is an access token for protected topics and may be empty.
+func NewNtfyNotifier(serverURL, topic, token string, priorities PriorityMap) *NtfyNotifier {
+	if serverURL == "" {
+		serverURL = defaultNtfyServer
+	}
+	return &NtfyNotifier{
+		serverURL:  strings.TrimRight(serverURL, "/"),
+		topic:      topic,
+		token:      token,
+		priorities: priorities,
+		client:     newHTTPClient(),
+	}
+}
+
+// Name returns the channel name
+func (n *NtfyNotifier) Name() string {
+	return "ntfy"
+}
+
+// ntfyMessage is a message published as JSON
+type ntfyMessage struct {
+	Topic    string   `json:"topic"`
+	Title    string   `json:"title"`
+	Message  string   `json:"message"`
+	Priority int      `json:"priority"`
+	Tags     []string `json:"tags,omitempty"`
+	Click    string   `json:"click,omitempty"`
+}
+
+// Send publishes the message
+func (n *NtfyNotifier) Send(ctx context.Context, msg Message) error {
+	payload := ntfyMessage{
+		Topic:    n.topic,
+		Title:    msg.Subject,
+		Message:  pushText(msg),
+		Priority: int(n.priorities.For(msg.Transition.ToStatus)),
+		Click:    msg.URL,
+	}
+	if tag, ok := ntfyTags[strings.ToLower(msg.Transition.ToStatus)]; ok {
+		payload.Tags = []string{tag}
+	}
+
+	var headers map[string]string
+	if n.token != "" {
+		headers = map[string]string{"Authorization": "Bearer " + n.token}
+	}
+	_, err := postJSON(ctx, n.client, n.serverURL, headers, payload)
+	return err
+}
+
+// pushText is the text of a push notification: the body and the latest event, which is
+// often the most useful detail on a lock screen
+func pushText(msg Message) string {
+	text := msg.Body
+	if event := msg.Transition.LatestEvent; event != nil && event.Description != "" {
+		latest := event.Description
+		if event.Location != "" {
+			latest += " (" + event.Location + ")"
+		}
+		if text != "" {
+			text += "\n"
+		}
+		text += latest
+	}
+	return text
+}
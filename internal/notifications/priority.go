@@ -0,0 +1,73 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Priority is how urgently a push notification should get the user's attention. Push
+// channels map it onto their own scale.
+type Priority int
+
+// Priorities, from least to most urgent. The values match ntfy's scale.
+const (
+	PriorityMin Priority = iota + 1
+	PriorityLow
+	PriorityNormal
+	PriorityHigh
+	PriorityUrgent
+)
+
+// priorityNames are the names priorities are configured with
+var priorityNames = map[string]Priority{
+	"min":    PriorityMin,
+	"low":    PriorityLow,
+	"normal": PriorityNormal,
+	"high":   PriorityHigh,
+	"urgent": PriorityUrgent,
+}
+
+// defaultPriorities are used for statuses a channel doesn't map itself. Statuses not listed
+// here are PriorityNormal.
+var defaultPriorities = map[string]Priority{
+	"exception": PriorityHigh,
+	"returned":  PriorityHigh,
+	"delivered": PriorityNormal,
+}
+
+// ParsePriority parses a priority name such as "high"
+func ParsePriority(name string) (Priority, error) {
+	priority, ok := priorityNames[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return 0, fmt.Errorf("invalid priority %q: must be min, low, normal, high or urgent", name)
+	}
+	return priority, nil
+}
+
+// PriorityMap maps statuses to priorities for a channel
+type PriorityMap map[string]Priority
+
+// NewPriorityMap creates a channel's mapping from status to priority name overrides, e.g.
+// {"in_transit": "low"}. Statuses without an override use the defaults.
+func NewPriorityMap(overrides map[string]string) (PriorityMap, error) {
+	priorities := make(PriorityMap, len(defaultPriorities)+len(overrides))
+	for status, priority := range defaultPriorities {
+		priorities[status] = priority
+	}
+	for status, name := range overrides {
+		priority, err := ParsePriority(name)
+		if err != nil {
+			return nil, fmt.Errorf("status %s: %w", status, err)
+		}
+		priorities[strings.ToLower(strings.TrimSpace(status))] = priority
+	}
+	return priorities, nil
+}
+
+// For returns the priority of a transition to status
+func (m PriorityMap) For(status string) Priority {
+	if priority, ok := m[strings.ToLower(status)]; ok {
+		return priority
+	}
+	return PriorityNormal
+}
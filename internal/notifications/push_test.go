@@ -0,0 +1,140 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"package-tracking/internal/database"
+)
+
+// pushServer records the last request's path, headers and JSON body
+type pushServer struct {
+	*httptest.Server
+	path    string
+	headers http.Header
+	body    map[string]any
+}
+
+func newPushServer(t *testing.T) *pushServer {
+	s := &pushServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.path = r.URL.Path
+		s.headers = r.Header.Clone()
+		s.body = nil
+		if err := json.NewDecoder(r.Body).Decode(&s.body); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		w.Write([]byte(`{"status":1}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func testPushMessage(status string) Message {
+	transition := testTransition(status)
+	transition.LatestEvent = &database.TrackingEvent{Description: "Delivery attempted", Location: "Austin, TX"}
+	return Message{
+		Subject:    "Headphones is " + humanizeStatus(status),
+		Body:       "UPS 1Z999AA10123456784",
+		URL:        "http://tracker.local/shipments/7",
+		Transition: transition,
+	}
+}
+
+func TestNewPriorityMap(t *testing.T) {
+	priorities, err := NewPriorityMap(map[string]string{"In_Transit": "low", "exception": "urgent"})
+	if err != nil {
+		t.Fatalf("NewPriorityMap() error = %v", err)
+	}
+
+	tests := map[string]Priority{
+		"in_transit":       PriorityLow,
+		"exception":        PriorityUrgent,
+		"returned":         PriorityHigh,
+		"delivered":        PriorityNormal,
+		"out_for_delivery": PriorityNormal,
+	}
+	for status, want := range tests {
+		if got := priorities.For(status); got != want {
+			t.Errorf("For(%q) = %d, want %d", status, got, want)
+		}
+	}
+
+	if _, err := NewPriorityMap(map[string]string{"exception": "loud"}); err == nil {
+		t.Error("Expected an error for an unknown priority")
+	}
+}
+
+func TestNtfyNotifier_Send(t *testing.T) {
+	server := newPushServer(t)
+	priorities, _ := NewPriorityMap(nil)
+
+	notifier := NewNtfyNotifier(server.URL+"/", "packages", "tk_secret", priorities)
+	if err := notifier.Send(context.Background(), testPushMessage("exception")); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if server.path != "/" || server.headers.Get("Authorization") != "Bearer tk_secret" {
+		t.Errorf("path = %q, authorization = %q", server.path, server.headers.Get("Authorization"))
+	}
+	body := server.body
+	if body["topic"] != "packages" || body["priority"] != float64(PriorityHigh) || body["click"] != "http://tracker.local/shipments/7" {
+		t.Errorf("unexpected payload: %v", body)
+	}
+	if body["message"] != "UPS 1Z999AA10123456784\nDelivery attempted (Austin, TX)" {
+		t.Errorf("message = %q", body["message"])
+	}
+	if tags, _ := body["tags"].([]any); len(tags) != 1 || tags[0] != "warning" {
+		t.Errorf("tags = %v, want [warning]", body["tags"])
+	}
+}
+
+func TestPushoverNotifier_Send(t *testing.T) {
+	server := newPushServer(t)
+	priorities, _ := NewPriorityMap(map[string]string{"exception": "urgent"})
+
+	notifier := NewPushoverNotifier("app-token", "user-key", "", priorities)
+	notifier.apiURL = server.URL
+
+	if err := notifier.Send(context.Background(), testPushMessage("delivered")); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if server.body["token"] != "app-token" || server.body["user"] != "user-key" || server.body["priority"] != float64(0) {
+		t.Errorf("unexpected payload: %v", server.body)
+	}
+	if _, ok := server.body["retry"]; ok {
+		t.Error("Expected no retry for a normal priority message")
+	}
+
+	// Emergency messages need retry and expire
+	if err := notifier.Send(context.Background(), testPushMessage("exception")); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if server.body["priority"] != float64(2) || server.body["retry"] != float64(pushoverEmergencyRetry) {
+		t.Errorf("unexpected emergency payload: %v", server.body)
+	}
+}
+
+func TestGotifyNotifier_Send(t *testing.T) {
+	server := newPushServer(t)
+	priorities, _ := NewPriorityMap(nil)
+
+	notifier := NewGotifyNotifier(server.URL, "app-token", priorities)
+	if err := notifier.Send(context.Background(), testPushMessage("exception")); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if server.path != "/message" || server.headers.Get("X-Gotify-Key") != "app-token" {
+		t.Errorf("path = %q, key = %q", server.path, server.headers.Get("X-Gotify-Key"))
+	}
+	if server.body["priority"] != float64(8) {
+		t.Errorf("priority = %v, want 8", server.body["priority"])
+	}
+	extras, _ := json.Marshal(server.body["extras"])
+	if string(extras) != `{"client::notification":{"click":{"url":"http://tracker.local/shipments/7"}}}` {
+		t.Errorf("extras = %s", extras)
+	}
+}
@@ -0,0 +1,90 @@
+package notifications
+
+import (
+	"context"
+	"net/http"
+)
+
+// pushoverMessagesURL is the Pushover message API
+const pushoverMessagesURL = "https://api.pushover.net/1/messages.json"
+
+// Pushover requires emergency priority messages to be repeated until acknowledged
+const (
+	pushoverEmergencyRetry  = 300  // Seconds between repeats
+	pushoverEmergencyExpire = 3600 // Seconds until repeating stops
+)
+
+// PushoverNotifier sends notifications through Pushover
+type PushoverNotifier struct {
+	appToken   string
+	userKey    string
+	device     string
+	priorities PriorityMap
+	apiURL     string // Replaced in tests
+	client     *http.Client
+}
+
+// NewPushoverNotifier creates a notifier that sends to the user or group userKey as the
+// application appToken. device limits delivery to one of the user's devices and may be
+// empty.
+func NewPushoverNotifier(appToken, userKey, device string, priorities PriorityMap) *PushoverNotifier {
+	return &PushoverNotifier{
+		appToken:   appToken,
+		userKey:    userKey,
+		device:     device,
+		priorities: priorities,
+		apiURL:     pushoverMessagesURL,
+		client:     newHTTPClient(),
+	}
+}
+
+// Name returns the channel name
+func (n *PushoverNotifier) Name() string {
+	return "pushover"
+}
+
+// pushoverMessage is the body of a message API request
+type pushoverMessage struct {
+	Token    string `json:"token"`
+	User     string `json:"user"`
+	Device   string `json:"device,omitempty"`
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+	Priority int    `json:"priority"`
+	Retry    int    `json:"retry,omitempty"`
+	Expire   int    `json:"expire,omitempty"`
+	URL      string `json:"url,omitempty"`
+	URLTitle string `json:"url_title,omitempty"`
+}
+
+// Send sends the message
+func (n *PushoverNotifier) Send(ctx context.Context, msg Message) error {
+	payload := pushoverMessage{
+		Token:    n.appToken,
+		User:     n.userKey,
+		Device:   n.device,
+		Title:    msg.Subject,
+		Message:  pushText(msg),
+		Priority: pushoverPriority(n.priorities.For(msg.Transition.ToStatus)),
+		URL:      msg.URL,
+	}
+	if payload.Message == "" {
+		// Pushover rejects empty messages
+		payload.Message = msg.Subject
+	}
+	if msg.URL != "" {
+		payload.URLTitle = "View shipment"
+	}
+	if payload.Priority == 2 {
+		payload.Retry = pushoverEmergencyRetry
+		payload.Expire = pushoverEmergencyExpire
+	}
+
+	_, err := postJSON(ctx, n.client, n.apiURL, nil, payload)
+	return err
+}
+
+// pushoverPriority maps a priority onto Pushover's -2 (lowest) to 2 (emergency) scale
+func pushoverPriority(priority Priority) int {
+	return int(priority - PriorityNormal)
+}
@@ -0,0 +1,203 @@
+package notifications
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"package-tracking/internal/database"
+)
+
+// RuleSource provides the enabled notification rules; *database.NotificationRuleStore in
+// production
+type RuleSource interface {
+	GetEnabled() ([]database.NotificationRule, error)
+}
+
+// RuleResult is how a rule treats a transition
+type RuleResult struct {
+	Matched    bool       `json:"matched"`
+	Reason     string     `json:"reason,omitempty"`      // Why the rule didn't match
+	QuietUntil *time.Time `json:"quiet_until,omitempty"` // Set when matching during quiet hours
+}
+
+// EvaluateRule checks a transition against a rule at the given time
+func EvaluateRule(rule database.NotificationRule, transition Transition, now time.Time) RuleResult {
+	if !listMatches(rule.Carriers, transition.Shipment.Carrier) {
+		return RuleResult{Reason: fmt.Sprintf("carrier %s is not one of %s", transition.Shipment.Carrier, strings.Join(rule.Carriers, ", "))}
+	}
+	if !listMatches(rule.FromStatuses, transition.FromStatus) {
+		return RuleResult{Reason: fmt.Sprintf("previous status %q is not one of %s", transition.FromStatus, strings.Join(rule.FromStatuses, ", "))}
+	}
+	if !listMatches(rule.ToStatuses, transition.ToStatus) {
+		return RuleResult{Reason: fmt.Sprintf("status %s is not one of %s", transition.ToStatus, strings.Join(rule.ToStatuses, ", "))}
+	}
+	if rule.ActiveFrom != "" && rule.ActiveUntil != "" && !inClockWindow(now, rule.ActiveFrom, rule.ActiveUntil) {
+		return RuleResult{Reason: fmt.Sprintf("%s is outside %s-%s", now.Format("15:04"), rule.ActiveFrom, rule.ActiveUntil)}
+	}
+	if rule.ExpectedWithinDays != nil {
+		expected := transition.Shipment.ExpectedDelivery
+		if expected == nil {
+			return RuleResult{Reason: "the shipment has no expected delivery date"}
+		}
+		if days := daysBetween(now, *expected); days > *rule.ExpectedWithinDays {
+			return RuleResult{Reason: fmt.Sprintf("delivery is expected in %d days, more than %d", days, *rule.ExpectedWithinDays)}
+		}
+	}
+
+	result := RuleResult{Matched: true}
+	if rule.QuietHoursStart != "" && rule.QuietHoursEnd != "" && inClockWindow(now, rule.QuietHoursStart, rule.QuietHoursEnd) {
+		until := nextClock(now, rule.QuietHoursEnd)
+		result.QuietUntil = &until
+	}
+	return result
+}
+
+// ValidateRule checks a rule's settings. channels are the configured channel names, which
+// the rule's channels must be among.
+func ValidateRule(rule database.NotificationRule, channels []string) error {
+	if strings.TrimSpace(rule.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	for _, setting := range []struct {
+		name  string
+		value string
+	}{
+		{"active_from", rule.ActiveFrom},
+		{"active_until", rule.ActiveUntil},
+		{"quiet_hours_start", rule.QuietHoursStart},
+		{"quiet_hours_end", rule.QuietHoursEnd},
+	} {
+		if setting.value == "" {
+			continue
+		}
+		if _, err := parseClock(setting.value); err != nil {
+			return fmt.Errorf("%s: %w", setting.name, err)
+		}
+	}
+	if (rule.ActiveFrom == "") != (rule.ActiveUntil == "") {
+		return fmt.Errorf("active_from and active_until must be set together")
+	}
+	if (rule.QuietHoursStart == "") != (rule.QuietHoursEnd == "") {
+		return fmt.Errorf("quiet_hours_start and quiet_hours_end must be set together")
+	}
+	if rule.ExpectedWithinDays != nil && *rule.ExpectedWithinDays < 0 {
+		return fmt.Errorf("expected_within_days must not be negative")
+	}
+	for _, name := range rule.Channels {
+		if !slices.Contains(channels, name) {
+			return fmt.Errorf("unknown channel %q (configured: %s)", name, strings.Join(channels, ", "))
+		}
+	}
+	return nil
+}
+
+// NormalizeRule lowercases and trims a rule's filters, as transitions are matched
+// case-insensitively
+func NormalizeRule(rule *database.NotificationRule) {
+	for _, list := range []*[]string{&rule.Carriers, &rule.FromStatuses, &rule.ToStatuses} {
+		normalized := []string{}
+		for _, value := range *list {
+			if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+				normalized = append(normalized, value)
+			}
+		}
+		*list = normalized
+	}
+	channels := []string{}
+	for _, name := range rule.Channels {
+		if name = strings.TrimSpace(name); name != "" {
+			channels = append(channels, name)
+		}
+	}
+	rule.Channels = channels
+}
+
+// targets reports whether a rule sends to the named channel
+func targets(rule database.NotificationRule, channel string) bool {
+	return len(rule.Channels) == 0 || slices.Contains(rule.Channels, channel)
+}
+
+// routeByRules decides whether a channel gets a transition under the rules: it is sent if
+// any matching rule targets the channel, and held until the earliest end of quiet hours if
+// every such rule is in quiet hours
+func routeByRules(rules []database.NotificationRule, channel string, transition Transition, now time.Time) (send bool, holdUntil time.Time) {
+	for _, rule := range rules {
+		if !targets(rule, channel) {
+			continue
+		}
+		result := EvaluateRule(rule, transition, now)
+		if !result.Matched {
+			continue
+		}
+		if result.QuietUntil == nil {
+			return true, time.Time{}
+		}
+		if !send || result.QuietUntil.Before(holdUntil) {
+			holdUntil = *result.QuietUntil
+		}
+		send = true
+	}
+	return send, holdUntil
+}
+
+// listMatches reports whether value is in a filter list; empty lists match everything
+func listMatches(list []string, value string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	value = strings.ToLower(value)
+	for _, v := range list {
+		if strings.ToLower(v) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClock parses HH:MM into minutes after midnight
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// inClockWindow reports whether now's time of day is in [from, until), which spans
+// midnight when until is earlier than from. Unparseable windows never match.
+func inClockWindow(now time.Time, from, until string) bool {
+	start, err := parseClock(from)
+	if err != nil {
+		return false
+	}
+	end, err := parseClock(until)
+	if err != nil {
+		return false
+	}
+	minute := now.Hour()*60 + now.Minute()
+	if start <= end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+// nextClock returns the next time after now that the clock shows value
+func nextClock(now time.Time, value string) time.Time {
+	minutes, _ := parseClock(value)
+	next := time.Date(now.Year(), now.Month(), now.Day(), minutes/60, minutes%60, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// daysBetween returns the number of calendar days from now until t, in now's time zone;
+// negative when t is in the past
+func daysBetween(now, t time.Time) int {
+	t = t.In(now.Location())
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	to := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
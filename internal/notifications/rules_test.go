@@ -0,0 +1,145 @@
+package notifications
+
+import (
+	"testing"
+	"time"
+
+	"package-tracking/internal/database"
+)
+
+// staticRules is a RuleSource with fixed rules
+type staticRules []database.NotificationRule
+
+func (r staticRules) GetEnabled() ([]database.NotificationRule, error) {
+	return r, nil
+}
+
+func TestEvaluateRule(t *testing.T) {
+	evening := time.Date(2024, 6, 3, 21, 30, 0, 0, time.UTC)
+	tomorrow := time.Date(2024, 6, 4, 12, 0, 0, 0, time.UTC)
+	nextWeek := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	one := 1
+
+	transition := testTransition("out_for_delivery")
+	transition.Shipment.ExpectedDelivery = &tomorrow
+	late := testTransition("out_for_delivery")
+	late.Shipment.ExpectedDelivery = &nextWeek
+
+	tests := []struct {
+		name       string
+		rule       database.NotificationRule
+		transition Transition
+		matched    bool
+		quiet      bool
+	}{
+		{"empty rule", database.NotificationRule{}, transition, true, false},
+		{"carrier", database.NotificationRule{Carriers: []string{"UPS"}}, transition, true, false},
+		{"other carrier", database.NotificationRule{Carriers: []string{"fedex"}}, transition, false, false},
+		{"transition", database.NotificationRule{FromStatuses: []string{"in_transit"}, ToStatuses: []string{"out_for_delivery"}}, transition, true, false},
+		{"other transition", database.NotificationRule{FromStatuses: []string{"pre_ship"}}, transition, false, false},
+		{"time of day", database.NotificationRule{ActiveFrom: "08:00", ActiveUntil: "22:00"}, transition, true, false},
+		{"outside time of day", database.NotificationRule{ActiveFrom: "08:00", ActiveUntil: "18:00"}, transition, false, false},
+		{"overnight time of day", database.NotificationRule{ActiveFrom: "21:00", ActiveUntil: "06:00"}, transition, true, false},
+		{"expected soon", database.NotificationRule{ExpectedWithinDays: &one}, transition, true, false},
+		{"expected later", database.NotificationRule{ExpectedWithinDays: &one}, late, false, false},
+		{"quiet hours", database.NotificationRule{QuietHoursStart: "21:00", QuietHoursEnd: "07:00"}, transition, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := EvaluateRule(tt.rule, tt.transition, evening)
+			if result.Matched != tt.matched {
+				t.Errorf("Matched = %v, want %v (%s)", result.Matched, tt.matched, result.Reason)
+			}
+			if !tt.matched && result.Reason == "" {
+				t.Error("Expected a reason for the mismatch")
+			}
+			if (result.QuietUntil != nil) != tt.quiet {
+				t.Errorf("QuietUntil = %v, want quiet %v", result.QuietUntil, tt.quiet)
+			}
+		})
+	}
+
+	result := EvaluateRule(database.NotificationRule{QuietHoursStart: "21:00", QuietHoursEnd: "07:00"}, transition, evening)
+	if want := time.Date(2024, 6, 4, 7, 0, 0, 0, time.UTC); !result.QuietUntil.Equal(want) {
+		t.Errorf("QuietUntil = %v, want %v", result.QuietUntil, want)
+	}
+}
+
+func TestValidateRule(t *testing.T) {
+	channels := []string{"log", "discord:home"}
+	tests := []struct {
+		name    string
+		rule    database.NotificationRule
+		wantErr bool
+	}{
+		{"valid", database.NotificationRule{Name: "Deliveries", Channels: []string{"discord:home"}, QuietHoursStart: "22:00", QuietHoursEnd: "07:00"}, false},
+		{"no name", database.NotificationRule{}, true},
+		{"bad time", database.NotificationRule{Name: "x", ActiveFrom: "8am", ActiveUntil: "18:00"}, true},
+		{"half a window", database.NotificationRule{Name: "x", QuietHoursStart: "22:00"}, true},
+		{"unknown channel", database.NotificationRule{Name: "x", Channels: []string{"slack"}}, true},
+	}
+	for _, tt := range tests {
+		if err := ValidateRule(tt.rule, channels); (err != nil) != tt.wantErr {
+			t.Errorf("%s: ValidateRule() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestDispatcher_Rules(t *testing.T) {
+	d, _ := newTestDispatcher(RetryPolicy{MaxAttempts: 1})
+	d.now = func() time.Time { return time.Date(2024, 6, 3, 23, 0, 0, 0, time.Local) }
+	phone := &recordingNotifier{name: "phone"}
+	chat := &recordingNotifier{name: "chat"}
+	quiet := &recordingNotifier{name: "quiet"}
+	for _, n := range []*recordingNotifier{phone, chat, quiet} {
+		if err := d.AddChannel(n, ChannelConfig{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	d.SetRules(staticRules{
+		{Name: "Problems to my phone", ToStatuses: []string{"exception"}, Channels: []string{"phone"}},
+		{Name: "Everything to chat", Channels: []string{"chat"}},
+		{Name: "Overnight", Channels: []string{"quiet"}, QuietHoursStart: "22:00", QuietHoursEnd: "07:00"},
+	})
+
+	d.Start()
+	d.Notify(testTransition("delivered"))
+	d.Notify(testTransition("exception"))
+	d.mu.Lock()
+	held := len(d.held)
+	d.mu.Unlock()
+	d.Stop()
+
+	if messages, _ := phone.sent(); len(messages) != 1 || messages[0].Transition.ToStatus != "exception" {
+		t.Errorf("Expected only the exception on the phone channel, got %+v", messages)
+	}
+	if messages, _ := chat.sent(); len(messages) != 2 {
+		t.Errorf("Expected both transitions on the chat channel, got %d", len(messages))
+	}
+	if messages, _ := quiet.sent(); len(messages) != 0 || held != 2 {
+		t.Errorf("Expected both transitions held for quiet hours, got %d sent and %d held", len(messages), held)
+	}
+}
+
+func TestDispatcher_PreviewRule(t *testing.T) {
+	d, _ := newTestDispatcher(RetryPolicy{MaxAttempts: 1})
+	phone := &recordingNotifier{name: "phone"}
+	chat := &recordingNotifier{name: "chat"}
+	d.AddChannel(phone, ChannelConfig{Statuses: []string{"exception"}})
+	d.AddChannel(chat, ChannelConfig{})
+
+	previews := d.PreviewRule(database.NotificationRule{Channels: []string{"phone"}}, testTransition("delivered"))
+	if len(previews) != 1 || previews[0].Channel != "phone" || previews[0].Accepted || previews[0].Subject != "Headphones is delivered" {
+		t.Errorf("Unexpected previews: %+v", previews)
+	}
+
+	d.Start()
+	sent := d.SendRuleTest(database.NotificationRule{}, testTransition("delivered"))
+	d.Stop()
+	if len(sent) != 2 {
+		t.Errorf("Expected the test to be sent to both channels, got %v", sent)
+	}
+	if messages, _ := phone.sent(); len(messages) != 1 {
+		t.Errorf("Expected the test to ignore channel filters, got %d messages", len(messages))
+	}
+}
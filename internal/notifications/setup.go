@@ -0,0 +1,129 @@
+package notifications
+
+import (
+	"fmt"
+	"log/slog"
+
+	"package-tracking/internal/config"
+)
+
+// NewDispatcherFromConfig creates a dispatcher with every channel enabled in the server
+// configuration, plus the extra channels, which are integrations configured elsewhere and
+// receive every transition even when notifications are disabled. It returns nil, which
+// drops every transition, when there is no channel.
+func NewDispatcherFromConfig(cfg *config.Config, logger *slog.Logger, extra ...Notifier) (*Dispatcher, error) {
+	if !cfg.NotificationsEnabled && len(extra) == 0 {
+		logger.Info("Notifications are disabled")
+		return nil, nil
+	}
+
+	dispatcher := NewDispatcher(RetryPolicy{
+		MaxAttempts:    cfg.NotificationMaxAttempts,
+		InitialBackoff: cfg.NotificationRetryBackoff,
+		MaxBackoff:     cfg.NotificationMaxBackoff,
+	}, cfg.NotificationQueueSize, logger)
+	dispatcher.SetBaseURL(cfg.NotificationBaseURL)
+
+	for _, notifier := range extra {
+		if err := dispatcher.AddChannel(notifier, ChannelConfig{}); err != nil {
+			return nil, err
+		}
+	}
+	if !cfg.NotificationsEnabled {
+		logger.Info("Notifications are disabled")
+		return dispatcher, nil
+	}
+
+	if cfg.NotificationLog.Enabled {
+		if err := dispatcher.AddChannel(NewLogNotifier(logger), channelConfig(cfg.NotificationLog)); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.NotificationSlack.Enabled {
+		slack, err := newSlackNotifierFromConfig(cfg.NotificationSlack)
+		if err != nil {
+			return nil, err
+		}
+		if err := dispatcher.AddChannel(slack, channelConfig(cfg.NotificationSlack.NotificationChannelConfig)); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.NotificationDiscord.Enabled {
+		notifiers, configs := newDiscordNotifiersFromConfig(cfg.NotificationDiscord)
+		for i, notifier := range notifiers {
+			if err := dispatcher.AddChannel(notifier, configs[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if cfg.NotificationTelegram.Enabled {
+		for _, notifier := range newTelegramNotifiersFromConfig(cfg.NotificationTelegram) {
+			if err := dispatcher.AddChannel(notifier, channelConfig(cfg.NotificationTelegram.NotificationChannelConfig)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if cfg.NotificationNtfy.Enabled {
+		priorities, err := NewPriorityMap(cfg.NotificationNtfy.Priorities)
+		if err != nil {
+			return nil, fmt.Errorf("ntfy notifications: %w", err)
+		}
+		ntfy := NewNtfyNotifier(cfg.NotificationNtfy.ServerURL, cfg.NotificationNtfy.Topic, cfg.NotificationNtfy.Token, priorities)
+		if err := dispatcher.AddChannel(ntfy, channelConfig(cfg.NotificationNtfy.NotificationChannelConfig)); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.NotificationPushover.Enabled {
+		priorities, err := NewPriorityMap(cfg.NotificationPushover.Priorities)
+		if err != nil {
+			return nil, fmt.Errorf("pushover notifications: %w", err)
+		}
+		pushover := NewPushoverNotifier(cfg.NotificationPushover.AppToken, cfg.NotificationPushover.UserKey, cfg.NotificationPushover.Device, priorities)
+		if err := dispatcher.AddChannel(pushover, channelConfig(cfg.NotificationPushover.NotificationChannelConfig)); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.NotificationGotify.Enabled {
+		priorities, err := NewPriorityMap(cfg.NotificationGotify.Priorities)
+		if err != nil {
+			return nil, fmt.Errorf("gotify notifications: %w", err)
+		}
+		gotify := NewGotifyNotifier(cfg.NotificationGotify.ServerURL, cfg.NotificationGotify.AppToken, priorities)
+		if err := dispatcher.AddChannel(gotify, channelConfig(cfg.NotificationGotify.NotificationChannelConfig)); err != nil {
+			return nil, err
+		}
+	}
+
+	webhooks, configs, err := newWebhookNotifiersFromConfig(cfg.NotificationWebhooks)
+	if err != nil {
+		return nil, err
+	}
+	for i, notifier := range webhooks {
+		if err := dispatcher.AddChannel(notifier, configs[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(dispatcher.channels) == 0 {
+		logger.Info("No notification channels are enabled")
+		return nil, nil
+	}
+	return dispatcher, nil
+}
+
+// channelConfig converts a channel's server configuration
+func channelConfig(cfg config.NotificationChannelConfig) ChannelConfig {
+	return ChannelConfig{
+		Statuses:        cfg.Statuses,
+		SubjectTemplate: cfg.SubjectTemplate,
+		BodyTemplate:    cfg.BodyTemplate,
+		Digest:          cfg.Digest,
+		TodayAlert:      cfg.TodayAlert,
+	}
+}
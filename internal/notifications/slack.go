@@ -0,0 +1,203 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"package-tracking/internal/config"
+)
+
+// slackPostMessageURL is the Web API method used with a bot token
+const slackPostMessageURL = "https://slack.com/api/chat.postMessage"
+
+// SlackNotifier posts Block Kit messages to Slack, either through an incoming webhook or,
+// with a bot token, to a channel with chat.postMessage
+type SlackNotifier struct {
+	webhookURL string
+	botToken   string
+	channel    string
+	apiURL     string // chat.postMessage endpoint; replaced in tests
+	client     *http.Client
+}
+
+// newSlackNotifierFromConfig creates the notifier for the Slack server configuration,
+// preferring the webhook when both a webhook and a bot token are configured
+func newSlackNotifierFromConfig(cfg config.SlackNotificationConfig) (*SlackNotifier, error) {
+	switch {
+	case cfg.WebhookURL != "":
+		return NewSlackWebhookNotifier(cfg.WebhookURL), nil
+	case cfg.BotToken != "" && cfg.Channel != "":
+		return NewSlackBotNotifier(cfg.BotToken, cfg.Channel), nil
+	default:
+		return nil, errors.New("slack notifications need a webhook URL or a bot token and channel")
+	}
+}
+
+// NewSlackWebhookNotifier creates a notifier that posts to an incoming webhook
+func NewSlackWebhookNotifier(webhookURL string) *SlackNotifier {
+	return &SlackNotifier{webhookURL: webhookURL, client: newHTTPClient()}
+}
+
+// NewSlackBotNotifier creates a notifier that posts to channel as the bot owning token
+func NewSlackBotNotifier(token, channel string) *SlackNotifier {
+	return &SlackNotifier{
+		botToken: token,
+		channel:  channel,
+		apiURL:   slackPostMessageURL,
+		client:   newHTTPClient(),
+	}
+}
+
+// Name returns the channel name
+func (n *SlackNotifier) Name() string {
+	return "slack"
+}
+
+// slackMessage is the body of a webhook or chat.postMessage request
+type slackMessage struct {
+	Channel string       `json:"channel,omitempty"`
+	Text    string       `json:"text"` // Fallback for notifications and clients without blocks
+	Blocks  []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string       `json:"type"`
+	Text     *slackText   `json:"text,omitempty"`
+	Fields   []slackText  `json:"fields,omitempty"`
+	Elements []slackBlock `json:"elements,omitempty"`
+	URL      string       `json:"url,omitempty"` // For button elements
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// slackAPIResponse is the envelope of every Web API response
+type slackAPIResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+// Send posts the message
+func (n *SlackNotifier) Send(ctx context.Context, msg Message) error {
+	payload := slackPayload(msg)
+
+	if n.webhookURL != "" {
+		_, err := postJSON(ctx, n.client, n.webhookURL, nil, payload)
+		return err
+	}
+
+	payload.Channel = n.channel
+	body, err := postJSON(ctx, n.client, n.apiURL, map[string]string{
+		"Authorization": "Bearer " + n.botToken,
+	}, payload)
+	if err != nil {
+		return err
+	}
+
+	// The Web API reports failures in the body of a 200 response
+	var resp slackAPIResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("invalid chat.postMessage response: %w", err)
+	}
+	if !resp.OK {
+		err := fmt.Errorf("chat.postMessage failed: %s", resp.Error)
+		if resp.Error == "ratelimited" || resp.Error == "internal_error" || resp.Error == "service_unavailable" {
+			return err
+		}
+		return Permanent(err)
+	}
+	return nil
+}
+
+// slackPayload formats a message as Block Kit: the subject as a header, the body, the
+// shipment's details, its latest event and a button linking to the shipment
+func slackPayload(msg Message) slackMessage {
+	if msg.Digest != nil {
+		return slackDigestPayload(msg)
+	}
+	t := msg.Transition
+
+	status := humanizeStatus(t.ToStatus)
+	if t.FromStatus != "" {
+		status = humanizeStatus(t.FromStatus) + " → " + status
+	}
+	fields := []slackText{
+		slackMarkdown("*Tracking number*\n`" + slackEscape(t.Shipment.TrackingNumber) + "`"),
+		slackMarkdown("*Carrier*\n" + slackEscape(strings.ToUpper(t.Shipment.Carrier))),
+		slackMarkdown("*Status*\n" + slackEscape(status)),
+	}
+	if t.Shipment.ExpectedDelivery != nil {
+		fields = append(fields, slackMarkdown("*Expected delivery*\n"+t.Shipment.ExpectedDelivery.Format("Mon, Jan 2")))
+	}
+
+	blocks := []slackBlock{
+		{Type: "header", Text: &slackText{Type: "plain_text", Text: msg.Subject}},
+	}
+	if msg.Body != "" {
+		blocks = append(blocks, slackBlock{Type: "section", Text: ptr(slackMarkdown(slackEscape(msg.Body)))})
+	}
+	blocks = append(blocks, slackBlock{Type: "section", Fields: fields})
+
+	if event := t.LatestEvent; event != nil {
+		text := "*Latest event*\n" + slackEscape(event.Description)
+		if event.Location != "" {
+			text += " — " + slackEscape(event.Location)
+		}
+		if !event.Timestamp.IsZero() {
+			text += "\n" + event.Timestamp.Format("Jan 2, 3:04 PM")
+		}
+		blocks = append(blocks, slackBlock{Type: "section", Text: ptr(slackMarkdown(text))})
+	}
+
+	if msg.URL != "" {
+		blocks = append(blocks, slackBlock{
+			Type: "actions",
+			Elements: []slackBlock{{
+				Type: "button",
+				Text: &slackText{Type: "plain_text", Text: "View shipment"},
+				URL:  msg.URL,
+			}},
+		})
+	}
+
+	return slackMessage{Text: msg.Subject, Blocks: blocks}
+}
+
+// slackDigestPayload formats a digest as its subject and body, with a button linking to
+// the web UI
+func slackDigestPayload(msg Message) slackMessage {
+	blocks := []slackBlock{
+		{Type: "header", Text: &slackText{Type: "plain_text", Text: msg.Subject}},
+		{Type: "section", Text: ptr(slackMarkdown(slackEscape(msg.Body)))},
+	}
+	if msg.URL != "" {
+		blocks = append(blocks, slackBlock{
+			Type: "actions",
+			Elements: []slackBlock{{
+				Type: "button",
+				Text: &slackText{Type: "plain_text", Text: "Open tracker"},
+				URL:  msg.URL,
+			}},
+		})
+	}
+	return slackMessage{Text: msg.Subject, Blocks: blocks}
+}
+
+func slackMarkdown(text string) slackText {
+	return slackText{Type: "mrkdwn", Text: text}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// slackEscape escapes the characters Slack treats as control sequences in mrkdwn text
+func slackEscape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
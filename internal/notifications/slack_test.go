@@ -0,0 +1,164 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"package-tracking/internal/database"
+)
+
+func testSlackMessage() Message {
+	transition := testTransition("out_for_delivery")
+	transition.FromStatus = "in_transit"
+	transition.LatestEvent = &database.TrackingEvent{
+		Timestamp:   time.Date(2025, 3, 14, 8, 5, 0, 0, time.UTC),
+		Location:    "Austin, TX",
+		Description: "Out for delivery <today>",
+	}
+	return Message{
+		Subject:    "Headphones is out for delivery",
+		Body:       "UPS 1Z999AA10123456784: in transit → out for delivery",
+		URL:        "http://tracker.local/shipments/7",
+		Transition: transition,
+	}
+}
+
+func TestSlackNotifier_Webhook(t *testing.T) {
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	notifier := NewSlackWebhookNotifier(server.URL)
+	if err := notifier.Send(context.Background(), testSlackMessage()); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if payload["text"] != "Headphones is out for delivery" {
+		t.Errorf("text = %v", payload["text"])
+	}
+	if _, ok := payload["channel"]; ok {
+		t.Error("webhook payload should not set a channel")
+	}
+
+	var encoded strings.Builder
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(payload["blocks"])
+	blocks := encoded.String()
+	for _, want := range []string{
+		`"type":"header"`,
+		"1Z999AA10123456784",
+		`in transit → out for delivery`,
+		"Out for delivery &lt;today&gt; — Austin, TX",
+		`"url":"http://tracker.local/shipments/7"`,
+	} {
+		if !strings.Contains(blocks, want) {
+			t.Errorf("blocks missing %q:\n%s", want, blocks)
+		}
+	}
+}
+
+func TestSlackNotifier_WebhookErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		permanent bool
+	}{
+		{"invalid payload", http.StatusBadRequest, true},
+		{"revoked webhook", http.StatusNotFound, true},
+		{"rate limited", http.StatusTooManyRequests, false},
+		{"server error", http.StatusInternalServerError, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			err := NewSlackWebhookNotifier(server.URL).Send(context.Background(), testSlackMessage())
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if IsPermanent(err) != tt.permanent {
+				t.Errorf("IsPermanent() = %v, want %v", IsPermanent(err), tt.permanent)
+			}
+		})
+	}
+}
+
+func TestSlackNotifier_Bot(t *testing.T) {
+	tests := []struct {
+		name      string
+		response  string
+		wantErr   bool
+		permanent bool
+	}{
+		{"posted", `{"ok":true}`, false, false},
+		{"unknown channel", `{"ok":false,"error":"channel_not_found"}`, true, true},
+		{"rate limited", `{"ok":false,"error":"ratelimited"}`, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var auth, channel string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				auth = r.Header.Get("Authorization")
+				var payload slackMessage
+				json.NewDecoder(r.Body).Decode(&payload)
+				channel = payload.Channel
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			notifier := NewSlackBotNotifier("xoxb-test", "C0123")
+			notifier.apiURL = server.URL
+
+			err := notifier.Send(context.Background(), testSlackMessage())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && IsPermanent(err) != tt.permanent {
+				t.Errorf("IsPermanent() = %v, want %v", IsPermanent(err), tt.permanent)
+			}
+			if auth != "Bearer xoxb-test" {
+				t.Errorf("Authorization = %q", auth)
+			}
+			if channel != "C0123" {
+				t.Errorf("channel = %q, want C0123", channel)
+			}
+		})
+	}
+}
+
+func TestDispatcher_ShipmentURL(t *testing.T) {
+	d, _ := newTestDispatcher(RetryPolicy{MaxAttempts: 1})
+	notifier := &recordingNotifier{name: "test"}
+	if err := d.AddChannel(notifier, ChannelConfig{BodyTemplate: "{{.URL}}"}); err != nil {
+		t.Fatalf("AddChannel() error = %v", err)
+	}
+	d.SetBaseURL("http://tracker.local/")
+	d.Start()
+	d.Notify(testTransition("delivered"))
+	d.Stop()
+
+	messages, _ := notifier.sent()
+	if len(messages) != 1 {
+		t.Fatalf("got %d messages, want 1", len(messages))
+	}
+	want := "http://tracker.local/shipments/7"
+	if messages[0].URL != want || messages[0].Body != want {
+		t.Errorf("URL = %q, body = %q, want %q", messages[0].URL, messages[0].Body, want)
+	}
+}
@@ -0,0 +1,70 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"package-tracking/internal/config"
+	"package-tracking/internal/telegram"
+)
+
+// TelegramNotifier sends notifications to a Telegram chat as the configured bot
+type TelegramNotifier struct {
+	client *telegram.Client
+	chatID int64
+}
+
+// NewTelegramNotifier creates a notifier that sends to chatID through client
+func NewTelegramNotifier(client *telegram.Client, chatID int64) *TelegramNotifier {
+	return &TelegramNotifier{client: client, chatID: chatID}
+}
+
+// Name returns the channel name, which includes the chat so each chat's deliveries are
+// retried and logged on their own
+func (n *TelegramNotifier) Name() string {
+	return fmt.Sprintf("telegram:%d", n.chatID)
+}
+
+// Send sends the message
+func (n *TelegramNotifier) Send(ctx context.Context, msg Message) error {
+	err := n.client.SendMessage(ctx, n.chatID, telegramText(msg))
+
+	var apiErr *telegram.APIError
+	if errors.As(err, &apiErr) && !apiErr.Temporary() {
+		// E.g. the bot was blocked or removed from the chat
+		return Permanent(err)
+	}
+	return err
+}
+
+// telegramText formats a message as plain text: the subject, the body, the latest event
+// and the link to the shipment
+func telegramText(msg Message) string {
+	lines := []string{msg.Subject}
+	if msg.Body != "" {
+		lines = append(lines, msg.Body)
+	}
+	if event := msg.Transition.LatestEvent; event != nil {
+		latest := "Latest: " + event.Description
+		if event.Location != "" {
+			latest += " (" + event.Location + ")"
+		}
+		lines = append(lines, latest)
+	}
+	if msg.URL != "" {
+		lines = append(lines, msg.URL)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// newTelegramNotifiersFromConfig creates a channel for each configured chat
+func newTelegramNotifiersFromConfig(cfg config.TelegramNotificationConfig) []Notifier {
+	client := telegram.NewClient(cfg.BotToken)
+	notifiers := make([]Notifier, len(cfg.ChatIDs))
+	for i, chatID := range cfg.ChatIDs {
+		notifiers[i] = NewTelegramNotifier(client, chatID)
+	}
+	return notifiers
+}
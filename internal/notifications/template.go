@@ -0,0 +1,96 @@
+package notifications
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Default templates, used when a channel doesn't configure its own
+const (
+	DefaultSubjectTemplate = `{{.Name}} is {{humanize .ToStatus}}`
+	DefaultBodyTemplate    = `{{.Shipment.Carrier | upper}} {{.Shipment.TrackingNumber}}: {{if .FromStatus}}{{humanize .FromStatus}} → {{end}}{{humanize .ToStatus}}` +
+		`{{with .Shipment.ExpectedDelivery}} (expected {{.Format "Jan 2"}}){{end}}`
+)
+
+// templateFuncs are the functions available to notification templates
+var templateFuncs = template.FuncMap{
+	"humanize": humanizeStatus,
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+	"date": func(layout string, t time.Time) string {
+		return t.Format(layout)
+	},
+	"json": func(value any) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+}
+
+// TemplateData is what notification templates are executed with. Templates can use the
+// Transition fields, e.g. {{.Shipment.TrackingNumber}} or {{.ToStatus}}, Name and URL.
+type TemplateData struct {
+	Transition
+	URL string // Link to the shipment in the web UI; empty without a base URL
+}
+
+// Name returns the shipment's description, or its tracking number if it has none
+func (d TemplateData) Name() string {
+	if d.Shipment.Description != "" {
+		return d.Shipment.Description
+	}
+	return d.Shipment.TrackingNumber
+}
+
+// humanizeStatus turns a status like "out_for_delivery" into "out for delivery"
+func humanizeStatus(status string) string {
+	return strings.ReplaceAll(status, "_", " ")
+}
+
+// Templates renders the subject and body of a channel's messages
+type Templates struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+// ParseTemplates parses a channel's subject and body templates. Empty templates use the
+// defaults.
+func ParseTemplates(subject, body string) (*Templates, error) {
+	if subject == "" {
+		subject = DefaultSubjectTemplate
+	}
+	if body == "" {
+		body = DefaultBodyTemplate
+	}
+
+	subjectTemplate, err := template.New("subject").Funcs(templateFuncs).Parse(subject)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subject template: %w", err)
+	}
+	bodyTemplate, err := template.New("body").Funcs(templateFuncs).Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid body template: %w", err)
+	}
+	return &Templates{subject: subjectTemplate, body: bodyTemplate}, nil
+}
+
+// Render renders the message for a transition. url links to the shipment and may be empty.
+func (t *Templates) Render(transition Transition, url string) (Message, error) {
+	data := TemplateData{Transition: transition, URL: url}
+
+	var subject, body strings.Builder
+	if err := t.subject.Execute(&subject, data); err != nil {
+		return Message{}, fmt.Errorf("failed to render subject: %w", err)
+	}
+	if err := t.body.Execute(&body, data); err != nil {
+		return Message{}, fmt.Errorf("failed to render body: %w", err)
+	}
+	return Message{
+		Subject:    strings.TrimSpace(subject.String()),
+		Body:       strings.TrimSpace(body.String()),
+		URL:        url,
+		Transition: transition,
+	}, nil
+}
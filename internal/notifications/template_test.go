@@ -0,0 +1,73 @@
+package notifications
+
+import (
+	"testing"
+	"time"
+
+	"package-tracking/internal/database"
+)
+
+func TestTemplates_Render(t *testing.T) {
+	expected := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	transition := Transition{
+		Shipment: database.Shipment{
+			ID:               3,
+			TrackingNumber:   "9400111899223197428490",
+			Carrier:          "usps",
+			Status:           "out_for_delivery",
+			ExpectedDelivery: &expected,
+		},
+		FromStatus: "in_transit",
+		ToStatus:   "out_for_delivery",
+	}
+
+	tests := []struct {
+		name            string
+		subject         string
+		body            string
+		expectedSubject string
+		expectedBody    string
+	}{
+		{
+			name:            "defaults",
+			expectedSubject: "9400111899223197428490 is out for delivery",
+			expectedBody:    "USPS 9400111899223197428490: in transit → out for delivery (expected Mar 14)",
+		},
+		{
+			name:            "custom",
+			subject:         "[{{.Shipment.Carrier | upper}}] {{.ToStatus}}",
+			body:            "Shipment {{.Shipment.ID}} was {{humanize .FromStatus}}",
+			expectedSubject: "[USPS] out_for_delivery",
+			expectedBody:    "Shipment 3 was in transit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templates, err := ParseTemplates(tt.subject, tt.body)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			msg, err := templates.Render(transition, "")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if msg.Subject != tt.expectedSubject {
+				t.Errorf("Expected subject %q, got %q", tt.expectedSubject, msg.Subject)
+			}
+			if msg.Body != tt.expectedBody {
+				t.Errorf("Expected body %q, got %q", tt.expectedBody, msg.Body)
+			}
+		})
+	}
+}
+
+func TestTemplates_RenderError(t *testing.T) {
+	templates, err := ParseTemplates("{{.Shipment.Missing}}", "")
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	if _, err := templates.Render(Transition{}, ""); err == nil {
+		t.Error("Expected an error for an unknown field")
+	}
+}
@@ -0,0 +1,193 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"package-tracking/internal/config"
+	"package-tracking/internal/database"
+)
+
+// WebhookNotifier sends each notification as an HTTP request whose body is rendered from
+// a Go template, for services without a channel of their own
+type WebhookNotifier struct {
+	name        string
+	url         string
+	method      string
+	headers     map[string]string
+	payload     *template.Template            // nil sends the default JSON payload
+	payloads    map[string]*template.Template // By new status
+	contentType string
+	client      *http.Client
+}
+
+// WebhookPayloadData is what payload templates are executed with: everything message
+// templates get, plus the rendered Subject and Body. Use the json function to embed
+// values in JSON, e.g. {"text": {{json .Subject}}}. For digests, rendered with the
+// "digest" payload template, Digest is set instead of the transition.
+type WebhookPayloadData struct {
+	TemplateData
+	Subject string
+	Body    string
+	Digest  *Digest
+}
+
+// NewWebhookNotifier creates a notifier from a webhook's configuration. name identifies
+// the channel, e.g. "webhook:home-assistant".
+func NewWebhookNotifier(name string, cfg config.WebhookNotificationConfig) (*WebhookNotifier, error) {
+	n := &WebhookNotifier{
+		name:        name,
+		url:         cfg.URL,
+		method:      cfg.Method,
+		headers:     cfg.Headers,
+		payloads:    make(map[string]*template.Template, len(cfg.PayloadTemplates)),
+		contentType: cfg.ContentType,
+		client:      newHTTPClient(),
+	}
+	if n.method == "" {
+		n.method = http.MethodPost
+	}
+	if n.contentType == "" {
+		n.contentType = "application/json; charset=utf-8"
+	}
+
+	if cfg.PayloadTemplate != "" {
+		payload, err := template.New("payload").Funcs(templateFuncs).Parse(cfg.PayloadTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid payload template: %w", err)
+		}
+		n.payload = payload
+	}
+	for status, text := range cfg.PayloadTemplates {
+		payload, err := template.New("payload:" + status).Funcs(templateFuncs).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid payload template for %s: %w", status, err)
+		}
+		n.payloads[strings.ToLower(status)] = payload
+	}
+	return n, nil
+}
+
+// Name returns the channel name
+func (n *WebhookNotifier) Name() string {
+	return n.name
+}
+
+// webhookEvent is the default payload
+type webhookEvent struct {
+	Event       string                  `json:"event"`
+	Shipment    database.Shipment       `json:"shipment"`
+	FromStatus  string                  `json:"from_status"`
+	ToStatus    string                  `json:"to_status"`
+	Source      string                  `json:"source"`
+	OccurredAt  time.Time               `json:"occurred_at"`
+	Subject     string                  `json:"subject"`
+	Body        string                  `json:"body"`
+	URL         string                  `json:"url,omitempty"`
+	LatestEvent *database.TrackingEvent `json:"latest_event,omitempty"`
+}
+
+// Send renders the payload for the message's new status and sends it
+func (n *WebhookNotifier) Send(ctx context.Context, msg Message) error {
+	body, err := n.render(msg)
+	if err != nil {
+		// The template fails the same way every time
+		return Permanent(err)
+	}
+
+	headers := map[string]string{"Content-Type": n.contentType}
+	for name, value := range n.headers {
+		headers[name] = value
+	}
+	_, err = sendRequest(ctx, n.client, n.method, n.url, headers, body)
+	return err
+}
+
+// webhookDigest is the default payload of digests
+type webhookDigest struct {
+	Event   string `json:"event"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+	URL     string `json:"url,omitempty"`
+	Digest
+}
+
+// render renders the request body for a message
+func (n *WebhookNotifier) render(msg Message) ([]byte, error) {
+	t := msg.Transition
+	payload, ok := n.payloads[strings.ToLower(t.ToStatus)]
+	if !ok {
+		payload = n.payload
+	}
+	if msg.Digest != nil {
+		// Transition templates don't suit digests
+		payload = n.payloads["digest"]
+		if payload == nil {
+			return json.Marshal(webhookDigest{
+				Event:   "digest",
+				Subject: msg.Subject,
+				Body:    msg.Body,
+				URL:     msg.URL,
+				Digest:  *msg.Digest,
+			})
+		}
+	}
+	if payload == nil {
+		return json.Marshal(webhookEvent{
+			Event:       "status_changed",
+			Shipment:    t.Shipment,
+			FromStatus:  t.FromStatus,
+			ToStatus:    t.ToStatus,
+			Source:      t.Source,
+			OccurredAt:  t.OccurredAt,
+			Subject:     msg.Subject,
+			Body:        msg.Body,
+			URL:         msg.URL,
+			LatestEvent: t.LatestEvent,
+		})
+	}
+
+	var body strings.Builder
+	data := WebhookPayloadData{
+		TemplateData: TemplateData{Transition: t, URL: msg.URL},
+		Subject:      msg.Subject,
+		Body:         msg.Body,
+		Digest:       msg.Digest,
+	}
+	if err := payload.Execute(&body, data); err != nil {
+		return nil, fmt.Errorf("failed to render payload: %w", err)
+	}
+	return []byte(body.String()), nil
+}
+
+// newWebhookNotifiersFromConfig creates a channel for every configured webhook, named
+// webhook:<name or position>
+func newWebhookNotifiersFromConfig(webhooks []config.WebhookNotificationConfig) ([]Notifier, []ChannelConfig, error) {
+	var notifiers []Notifier
+	var configs []ChannelConfig
+	for i, webhook := range webhooks {
+		name := webhook.Name
+		if name == "" {
+			name = fmt.Sprint(i + 1)
+		}
+		notifier, err := NewWebhookNotifier("webhook:"+name, webhook)
+		if err != nil {
+			return nil, nil, fmt.Errorf("webhook:%s notifications: %w", name, err)
+		}
+		notifiers = append(notifiers, notifier)
+		configs = append(configs, ChannelConfig{
+			Statuses:        webhook.Statuses,
+			Carriers:        webhook.Carriers,
+			SubjectTemplate: webhook.SubjectTemplate,
+			BodyTemplate:    webhook.BodyTemplate,
+			Digest:          webhook.Digest,
+			TodayAlert:      webhook.TodayAlert,
+		})
+	}
+	return notifiers, configs, nil
+}
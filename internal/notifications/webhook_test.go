@@ -0,0 +1,112 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"package-tracking/internal/config"
+)
+
+// webhookServer records the last request
+type webhookServer struct {
+	*httptest.Server
+	method  string
+	headers http.Header
+	body    string
+	status  int
+}
+
+func newWebhookServer(t *testing.T) *webhookServer {
+	s := &webhookServer{status: http.StatusOK}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		s.method = r.Method
+		s.headers = r.Header.Clone()
+		s.body = string(data)
+		w.WriteHeader(s.status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestWebhookNotifier_PayloadTemplates(t *testing.T) {
+	server := newWebhookServer(t)
+	notifier, err := NewWebhookNotifier("webhook:chat", config.WebhookNotificationConfig{
+		URL:             server.URL,
+		Method:          "PUT",
+		Headers:         map[string]string{"Authorization": "Bearer secret"},
+		PayloadTemplate: `{"text": {{json .Subject}}, "tracking": "{{.Shipment.TrackingNumber}}"}`,
+		PayloadTemplates: map[string]string{
+			"Delivered": `{"text": {{json (printf "%s arrived!" .Name)}}}`,
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier() error = %v", err)
+	}
+
+	msg := testPushMessage("exception")
+	msg.Subject = `Headphones is "stuck"`
+	if err := notifier.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if server.method != http.MethodPut || server.headers.Get("Authorization") != "Bearer secret" {
+		t.Errorf("method = %s, authorization = %q", server.method, server.headers.Get("Authorization"))
+	}
+	if server.headers.Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Errorf("Content-Type = %q", server.headers.Get("Content-Type"))
+	}
+	if server.body != `{"text": "Headphones is \"stuck\"", "tracking": "1Z999AA10123456784"}` {
+		t.Errorf("body = %s", server.body)
+	}
+
+	// Statuses with a template of their own use it
+	if err := notifier.Send(context.Background(), testPushMessage("delivered")); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if server.body != `{"text": "Headphones arrived!"}` {
+		t.Errorf("body = %s", server.body)
+	}
+}
+
+func TestWebhookNotifier_DefaultPayload(t *testing.T) {
+	server := newWebhookServer(t)
+	notifier, err := NewWebhookNotifier("webhook:1", config.WebhookNotificationConfig{URL: server.URL})
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier() error = %v", err)
+	}
+
+	if err := notifier.Send(context.Background(), testPushMessage("delivered")); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if server.method != http.MethodPost {
+		t.Errorf("method = %s, want POST", server.method)
+	}
+
+	var event webhookEvent
+	if err := json.Unmarshal([]byte(server.body), &event); err != nil {
+		t.Fatalf("Invalid default payload: %v", err)
+	}
+	if event.Event != "status_changed" || event.ToStatus != "delivered" || event.Shipment.ID != 7 ||
+		event.URL != "http://tracker.local/shipments/7" || event.LatestEvent == nil {
+		t.Errorf("Unexpected default payload: %s", server.body)
+	}
+}
+
+func TestWebhookNotifier_Errors(t *testing.T) {
+	if _, err := NewWebhookNotifier("webhook:bad", config.WebhookNotificationConfig{PayloadTemplate: "{{.Nope"}); err == nil {
+		t.Error("Expected an error for an invalid payload template")
+	}
+
+	server := newWebhookServer(t)
+	server.status = http.StatusBadRequest
+	notifier, _ := NewWebhookNotifier("webhook:1", config.WebhookNotificationConfig{URL: server.URL})
+
+	err := notifier.Send(context.Background(), testPushMessage("delivered"))
+	if !IsPermanent(err) {
+		t.Errorf("Send() error = %v, want a permanent error for a 400", err)
+	}
+}
@@ -46,7 +46,9 @@ func setupTestServer(t *testing.T) *httptest.Server {
 		amazon_order_number TEXT,
 		delegated_carrier TEXT,
 		delegated_tracking_number TEXT,
-		is_amazon_logistics BOOLEAN DEFAULT FALSE
+		is_amazon_logistics BOOLEAN DEFAULT FALSE,
+		is_stalled BOOLEAN DEFAULT FALSE,
+		stalled_since DATETIME
 	);
 
 	CREATE TABLE tracking_events (
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+)
+
+// listenFDsStart is the first file descriptor systemd passes to a socket-activated service
+const listenFDsStart = 3
+
+// Listen returns the listener the server accepts connections on: the socket systemd
+// passed through socket activation, if any, otherwise a new socket bound to addr. systemd
+// keeps its socket open across restarts, so connections made while the server restarts
+// wait for the new process instead of being refused.
+//
+// With reusePort the new socket is bound with SO_REUSEPORT, so an upgraded server can
+// start on the same address while the old one finishes its in-flight requests.
+func Listen(addr string, reusePort bool) (net.Listener, error) {
+	listener, err := activationListener()
+	if err != nil || listener != nil {
+		return listener, err
+	}
+
+	config := net.ListenConfig{}
+	if reusePort {
+		config.Control = reusePortControl
+	}
+	return config.Listen(context.Background(), "tcp", addr)
+}
+
+// activationListener returns the first socket passed by systemd socket activation, or nil
+// if the process wasn't socket activated. The activation variables are cleared so child
+// processes don't take the socket for theirs.
+func activationListener() (net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+	fds, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || fds < 1 {
+		return nil, nil
+	}
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	file := os.NewFile(uintptr(listenFDsStart), "LISTEN_FD_3")
+	defer file.Close()
+	listener, err := net.FileListener(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to use socket from systemd: %w", err)
+	}
+	return listener, nil
+}
+
+// NotifySystemd sends a state such as "READY=1" or "STOPPING=1" to systemd when the server
+// runs as a Type=notify service, and does nothing otherwise
+func NotifySystemd(state string) error {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("failed to notify systemd: %w", err)
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte(state))
+	return err
+}
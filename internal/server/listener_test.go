@@ -0,0 +1,64 @@
+package server
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestListen_ReusePort(t *testing.T) {
+	first, err := Listen("127.0.0.1:0", true)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer first.Close()
+
+	// An upgraded server can bind the same address while the old one is running
+	second, err := Listen(first.Addr().String(), true)
+	if err != nil {
+		t.Fatalf("Expected a second listener on %s, got: %v", first.Addr(), err)
+	}
+	second.Close()
+
+	if third, err := Listen(first.Addr().String(), false); err == nil {
+		third.Close()
+		t.Error("Expected an error binding the address without SO_REUSEPORT")
+	}
+}
+
+func TestActivationListener_OtherProcess(t *testing.T) {
+	// The activation variables are for another process, e.g. inherited from a parent
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()+1))
+	t.Setenv("LISTEN_FDS", "1")
+
+	listener, err := activationListener()
+	if listener != nil || err != nil {
+		t.Errorf("Expected no activation listener, got %v: %v", listener, err)
+	}
+}
+
+func TestNotifySystemd(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	if err := NotifySystemd("READY=1"); err != nil {
+		t.Errorf("Expected no error outside systemd, got: %v", err)
+	}
+
+	socket := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	t.Setenv("NOTIFY_SOCKET", socket)
+
+	if err := NotifySystemd("READY=1"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	buf := make([]byte, 64)
+	n, err := conn.Read(buf)
+	if err != nil || string(buf[:n]) != "READY=1" {
+		t.Errorf("Expected READY=1, got %q: %v", buf[:n], err)
+	}
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// ProxyAuthConfig configures ProxyAuthMiddleware
+type ProxyAuthConfig struct {
+	TrustedProxies []*net.IPNet // Proxies whose user headers are trusted
+	UserHeaders    []string     // Headers naming the user, in order of preference
+	Users          []string     // Users allowed through; empty allows any authenticated user
+	ClientCerts    bool         // Take the user from the common name of verified client certificates
+	APIKey         string       // Also accept this bearer token, e.g. for scripts; empty for none
+}
+
+type contextKey string
+
+const userContextKey contextKey = "user"
+
+// UserFromContext returns the user ProxyAuthMiddleware authenticated the request as, or ""
+func UserFromContext(ctx context.Context) string {
+	user, _ := ctx.Value(userContextKey).(string)
+	return user
+}
+
+// ProxyAuthMiddleware authenticates users a reverse proxy such as Authelia or Traefik has
+// already logged in, from the user headers of requests sent by trusted proxies, or users
+// presenting a client certificate the server verified. The user is added to the request
+// context. Requests with a bearer token are checked against the API key instead.
+func ProxyAuthMiddleware(cfg ProxyAuthConfig) func(http.Handler) http.Handler {
+	allowed := make(map[string]bool, len(cfg.Users))
+	for _, user := range cfg.Users {
+		allowed[user] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		apiKeyAuth := AuthMiddleware(cfg.APIKey)(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := cfg.userOf(r)
+			if user == "" {
+				if cfg.APIKey != "" && r.Header.Get("Authorization") != "" {
+					apiKeyAuth.ServeHTTP(w, r)
+					return
+				}
+				log.Printf("WARN: Unauthorized access attempt to %s %s from %s: no authenticated user",
+					r.Method, r.URL.Path, r.RemoteAddr)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			if len(allowed) > 0 && !allowed[user] {
+				log.Printf("WARN: Forbidden access attempt to %s %s by user %q", r.Method, r.URL.Path, user)
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userContextKey, user)))
+		})
+	}
+}
+
+// userOf returns the user a request is authenticated as, or "" if it isn't. The user
+// headers are ignored unless the request comes straight from a trusted proxy, since anyone
+// else could set them.
+func (cfg ProxyAuthConfig) userOf(r *http.Request) string {
+	if cfg.ClientCerts && r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		if name := r.TLS.VerifiedChains[0][0].Subject.CommonName; name != "" {
+			return name
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !cfg.trusts(ip) {
+		return ""
+	}
+	for _, header := range cfg.UserHeaders {
+		if user := strings.TrimSpace(r.Header.Get(header)); user != "" {
+			return user
+		}
+	}
+	return ""
+}
+
+// trusts reports whether ip is a trusted proxy
+func (cfg ProxyAuthConfig) trusts(ip net.IP) bool {
+	for _, proxy := range cfg.TrustedProxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewTLSConfig returns the TLS configuration of the server. With a client CA file, every
+// client must present a certificate signed by one of its CAs (mutual TLS).
+func NewTLSConfig(clientCAFile string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if clientCAFile == "" {
+		return tlsConfig, nil
+	}
+
+	pem, err := os.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in client CA file %s", clientCAFile)
+	}
+	tlsConfig.ClientCAs = pool
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	return tlsConfig, nil
+}
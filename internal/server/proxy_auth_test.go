@@ -0,0 +1,113 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestProxyAuthMiddleware(t *testing.T) {
+	_, trusted, _ := net.ParseCIDR("10.0.0.0/8")
+	handler := ProxyAuthMiddleware(ProxyAuthConfig{
+		TrustedProxies: []*net.IPNet{trusted},
+		UserHeaders:    []string{"Remote-User", "X-Forwarded-User"},
+		Users:          []string{"alice", "admin-client"},
+		ClientCerts:    true,
+		APIKey:         "test-key",
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(UserFromContext(r.Context())))
+	}))
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		clientCN   string
+		wantCode   int
+		wantUser   string
+	}{
+		{"Trusted proxy", "10.1.2.3:4567", map[string]string{"X-Forwarded-User": "alice"}, "", http.StatusOK, "alice"},
+		{"Header order", "10.1.2.3:4567", map[string]string{"Remote-User": "alice", "X-Forwarded-User": "bob"}, "", http.StatusOK, "alice"},
+		{"Untrusted client", "192.168.1.5:4567", map[string]string{"Remote-User": "alice"}, "", http.StatusUnauthorized, ""},
+		{"User not allowed", "10.1.2.3:4567", map[string]string{"Remote-User": "bob"}, "", http.StatusForbidden, ""},
+		{"No user", "10.1.2.3:4567", nil, "", http.StatusUnauthorized, ""},
+		{"Client certificate", "192.168.1.5:4567", nil, "admin-client", http.StatusOK, "admin-client"},
+		{"API key", "192.168.1.5:4567", map[string]string{"Authorization": "Bearer test-key"}, "", http.StatusOK, ""},
+		{"Wrong API key", "192.168.1.5:4567", map[string]string{"Authorization": "Bearer wrong"}, "", http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/admin/tracking-updater/status", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			if tt.clientCN != "" {
+				cert := &x509.Certificate{Subject: pkix.Name{CommonName: tt.clientCN}}
+				req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("Expected status %d, got %d", tt.wantCode, w.Code)
+			}
+			if tt.wantCode == http.StatusOK && w.Body.String() != tt.wantUser {
+				t.Errorf("Expected user %q, got %q", tt.wantUser, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	config, err := NewTLSConfig("")
+	if err != nil || config.ClientAuth != tls.NoClientCert {
+		t.Errorf("Expected no client certificates without a CA, got %v: %v", config, err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err = NewTLSConfig(caFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if config.ClientAuth != tls.RequireAndVerifyClientCert || config.ClientCAs == nil {
+		t.Errorf("Expected client certificates to be required, got %v", config.ClientAuth)
+	}
+
+	if _, err := NewTLSConfig(filepath.Join(t.TempDir(), "missing.pem")); err == nil {
+		t.Error("Expected an error for a missing CA file")
+	}
+}
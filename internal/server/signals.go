@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -38,6 +39,7 @@ func (sh *SignalHandler) WaitForShutdown() {
 	sig := <-quit
 	log.Printf("Received signal: %v", sig)
 	log.Println("Initiating graceful shutdown...")
+	NotifySystemd("STOPPING=1")
 
 	// Create context with timeout for graceful shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), sh.shutdownTimeout)
@@ -53,10 +55,30 @@ func (sh *SignalHandler) WaitForShutdown() {
 
 // HandleSignals is a convenience function that combines server start and signal handling
 func HandleSignals(server *http.Server, shutdownTimeout time.Duration) error {
+	return serveUntilSignal(server, server.ListenAndServe, shutdownTimeout)
+}
+
+// HandleSignalsOn is HandleSignals for a server accepting connections on listener, such
+// as one from Listen, over HTTPS when certFile and keyFile are set. systemd is told when
+// the server is ready and when it is stopping.
+func HandleSignalsOn(server *http.Server, listener net.Listener, certFile, keyFile string, shutdownTimeout time.Duration) error {
+	return serveUntilSignal(server, func() error {
+		if err := NotifySystemd("READY=1"); err != nil {
+			log.Printf("WARN: %v", err)
+		}
+		if certFile != "" {
+			return server.ServeTLS(listener, certFile, keyFile)
+		}
+		return server.Serve(listener)
+	}, shutdownTimeout)
+}
+
+// serveUntilSignal starts the server with listen and shuts it down on a shutdown signal
+func serveUntilSignal(server *http.Server, listen func() error, shutdownTimeout time.Duration) error {
 	// Start server in a goroutine
 	go func() {
 		log.Printf("Starting server on %s", server.Addr)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := listen(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Server failed to start: %v", err)
 		}
 	}()
@@ -68,6 +90,31 @@ func HandleSignals(server *http.Server, shutdownTimeout time.Duration) error {
 	return nil
 }
 
+// HandleReloadSignal calls reload each time the process receives SIGHUP, until the
+// returned stop function is called
+func HandleReloadSignal(reload func()) (stop func()) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-hup:
+				log.Println("Received SIGHUP, reloading configuration")
+				reload()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(hup)
+		close(done)
+	}
+}
+
 // Notes about signal handling:
 //
 // Catchable signals (can be handled gracefully):
@@ -81,5 +128,6 @@ func HandleSignals(server *http.Server, shutdownTimeout time.Duration) error {
 // - SIGKILL (9): Kill signal, immediately terminates the process
 // - SIGSTOP (19): Stop signal, suspends the process (cannot be caught or ignored)
 //
-// Our server handles SIGINT and SIGTERM gracefully, but SIGKILL will
+// Our server handles SIGINT and SIGTERM gracefully and reloads its configuration on
+// SIGHUP, but SIGKILL will
 // immediately terminate the process without any cleanup.
\ No newline at end of file
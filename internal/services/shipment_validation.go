@@ -0,0 +1,62 @@
+package services
+
+import (
+	"fmt"
+
+	"package-tracking/internal/carriers"
+	"package-tracking/internal/database"
+)
+
+// ValidateShipment checks the fields every new or updated shipment needs, whether it comes
+// from the API or another client such as the Telegram bot
+func ValidateShipment(shipment *database.Shipment) error {
+	if shipment.TrackingNumber == "" {
+		return fmt.Errorf("tracking number is required")
+	}
+	if shipment.Carrier == "" {
+		return fmt.Errorf("carrier is required")
+	}
+	if shipment.Description == "" {
+		return fmt.Errorf("description is required")
+	}
+
+	// Validate carrier
+	validCarriers := []string{"ups", "usps", "fedex", "dhl", "amazon"}
+	validCarrier := false
+	for _, c := range validCarriers {
+		if shipment.Carrier == c {
+			validCarrier = true
+			break
+		}
+	}
+	if !validCarrier {
+		return fmt.Errorf("invalid carrier: must be one of %v", validCarriers)
+	}
+
+	// Amazon-specific validation
+	if shipment.Carrier == "amazon" {
+		// Validate Amazon tracking number format
+		if err := validateAmazonTrackingNumber(shipment.TrackingNumber); err != nil {
+			return fmt.Errorf("invalid Amazon tracking number: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// validateAmazonTrackingNumber validates Amazon tracking number formats
+func validateAmazonTrackingNumber(trackingNumber string) error {
+	// Create Amazon client to validate
+	factory := carriers.NewClientFactory()
+	client, _, err := factory.CreateClient("amazon")
+	if err != nil {
+		return fmt.Errorf("failed to create Amazon client for validation: %v", err)
+	}
+
+	// Use the Amazon client's validation
+	if !client.ValidateTrackingNumber(trackingNumber) {
+		return fmt.Errorf("tracking number does not match Amazon format (17-digit order number or TBA+12 digits)")
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+// Package telegram is a minimal Telegram Bot API client, covering what the notification
+// channel and the command bot need: sending messages and long polling for updates.
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"time"
+)
+
+// defaultBaseURL is the Bot API server
+const defaultBaseURL = "https://api.telegram.org"
+
+// requestTimeout bounds a call, on top of the time a long poll waits for updates
+const requestTimeout = 15 * time.Second
+
+// Client calls the Bot API as the bot owning a token
+type Client struct {
+	token   string
+	baseURL string // Replaced in tests
+	client  *http.Client
+}
+
+// NewClient creates a client for the bot owning token
+func NewClient(token string) *Client {
+	return &Client{
+		token:   token,
+		baseURL: defaultBaseURL,
+		client:  &http.Client{},
+	}
+}
+
+// SetBaseURL points the client at another Bot API server, such as a local one or a test
+// server
+func (c *Client) SetBaseURL(baseURL string) {
+	c.baseURL = baseURL
+}
+
+// Update is an incoming update. Only messages are requested.
+type Update struct {
+	UpdateID int64    `json:"update_id"`
+	Message  *Message `json:"message"`
+}
+
+// Message is a message sent to the bot
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	From      *User  `json:"from"`
+	Chat      Chat   `json:"chat"`
+	Text      string `json:"text"`
+}
+
+// User is the sender of a message
+type User struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+// Chat is the private chat, group or channel a message was sent in
+type Chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
+}
+
+// APIError is a failed Bot API call
+type APIError struct {
+	Code        int    // HTTP-like error code, e.g. 403 when the bot was blocked
+	Description string // Telegram's explanation
+	RetryAfter  int    // Seconds to wait before retrying, set when rate limited
+}
+
+func (e *APIError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("telegram API error %d: %s (retry after %ds)", e.Code, e.Description, e.RetryAfter)
+	}
+	return fmt.Sprintf("telegram API error %d: %s", e.Code, e.Description)
+}
+
+// Temporary reports whether retrying the call later might succeed
+func (e *APIError) Temporary() bool {
+	return e.Code == http.StatusTooManyRequests || e.Code >= 500
+}
+
+// apiResponse is the envelope of every Bot API response
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
+}
+
+// SendMessage sends plain text to a chat
+func (c *Client) SendMessage(ctx context.Context, chatID int64, text string) error {
+	return c.call(ctx, "sendMessage", map[string]any{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"disable_web_page_preview": true,
+	}, nil, requestTimeout)
+}
+
+// GetUpdates returns the messages after offset, waiting up to timeout for one to arrive
+func (c *Client) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error) {
+	var updates []Update
+	err := c.call(ctx, "getUpdates", map[string]any{
+		"offset":          offset,
+		"timeout":         int(timeout.Seconds()),
+		"allowed_updates": []string{"message"},
+	}, &updates, timeout+requestTimeout)
+	return updates, err
+}
+
+// call invokes a Bot API method and decodes its result into result, if not nil. The call
+// is abandoned after timeout.
+func (c *Client) call(ctx context.Context, method string, params any, result any, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	body, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s request: %w", method, err)
+	}
+
+	url := fmt.Sprintf("%s/bot%s/%s", c.baseURL, c.token, method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// Drop the URL, which contains the token, so it doesn't end up in logs
+		var urlErr *neturl.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("%s request failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var envelope apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("invalid %s response (status %d): %w", method, resp.StatusCode, err)
+	}
+	if !envelope.OK {
+		code := envelope.ErrorCode
+		if code == 0 {
+			code = resp.StatusCode
+		}
+		return &APIError{Code: code, Description: envelope.Description, RetryAfter: envelope.Parameters.RetryAfter}
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(envelope.Result, result); err != nil {
+			return fmt.Errorf("invalid %s result: %w", method, err)
+		}
+	}
+	return nil
+}
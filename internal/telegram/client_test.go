@@ -0,0 +1,97 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_SendMessage(t *testing.T) {
+	var path string
+	var params map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&params)
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("123:secret")
+	client.SetBaseURL(server.URL)
+	if err := client.SendMessage(context.Background(), -100, "Delivered"); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+
+	if path != "/bot123:secret/sendMessage" {
+		t.Errorf("path = %q", path)
+	}
+	if params["chat_id"] != float64(-100) || params["text"] != "Delivered" {
+		t.Errorf("params = %v", params)
+	}
+}
+
+func TestClient_GetUpdates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":true,"result":[{"update_id":9,"message":{"message_id":1,"chat":{"id":42,"type":"private"},"text":"/list"}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("123:secret")
+	client.SetBaseURL(server.URL)
+	updates, err := client.GetUpdates(context.Background(), 0, time.Second)
+	if err != nil {
+		t.Fatalf("GetUpdates() error = %v", err)
+	}
+	if len(updates) != 1 || updates[0].UpdateID != 9 || updates[0].Message.Chat.ID != 42 || updates[0].Message.Text != "/list" {
+		t.Errorf("updates = %+v", updates)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		temporary bool
+	}{
+		{"blocked", http.StatusForbidden, `{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`, false},
+		{"rate limited", http.StatusTooManyRequests, `{"ok":false,"error_code":429,"description":"Too Many Requests","parameters":{"retry_after":3}}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient("123:secret")
+			client.SetBaseURL(server.URL)
+			err := client.SendMessage(context.Background(), 42, "hi")
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %v, want an APIError", err)
+			}
+			if apiErr.Code != tt.status || apiErr.Temporary() != tt.temporary {
+				t.Errorf("error = %+v, temporary = %v", apiErr, apiErr.Temporary())
+			}
+		})
+	}
+}
+
+func TestClient_ErrorHidesToken(t *testing.T) {
+	client := NewClient("123:secret")
+	client.SetBaseURL("http://127.0.0.1:1")
+
+	err := client.SendMessage(context.Background(), 42, "hi")
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("error = %v, want a failure without the token", err)
+	}
+}
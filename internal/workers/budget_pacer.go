@@ -0,0 +1,70 @@
+package workers
+
+import (
+	"time"
+)
+
+// cycleAllowance returns how many API calls a carrier may make in the update cycle starting
+// at now, spreading what is left of its daily budget evenly across the cycles remaining
+// before the budget resets at midnight UTC. This keeps a carrier like DHL (250 calls/day)
+// from exhausting its budget in the first few cycles of the day.
+func cycleAllowance(budget, used int, now time.Time, interval time.Duration) int {
+	remaining := budget - used
+	if remaining <= 0 {
+		return 0
+	}
+	if interval <= 0 {
+		return remaining
+	}
+
+	now = now.UTC()
+	resetAt := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	cyclesLeft := int((resetAt.Sub(now) + interval - 1) / interval)
+	if cyclesLeft < 1 {
+		cyclesLeft = 1
+	}
+
+	// Round up so a small leftover budget is still used rather than stranded
+	return (remaining + cyclesLeft - 1) / cyclesLeft
+}
+
+// budgetAllowances computes the per-cycle API call allowance for every carrier with a daily
+// budget. Carriers without a budget are absent from the result and are not paced.
+func (u *TrackingUpdater) budgetAllowances(now time.Time) map[string]int {
+	allowances := make(map[string]int)
+
+	for _, carrier := range u.enabledCarriers() {
+		budget := u.config.DailyAPIBudgetFor(carrier)
+		if budget <= 0 {
+			continue
+		}
+
+		used, err := u.quotaStore.GetUsage(carrier, now)
+		if err != nil {
+			// Without the ledger we can't tell how much budget is left, so hold off this cycle
+			u.logger.Error("Failed to read API usage ledger, skipping carrier this cycle",
+				"carrier", carrier,
+				"error", err)
+			allowances[carrier] = 0
+			continue
+		}
+
+		allowance := cycleAllowance(budget, used, now, u.config.UpdateInterval)
+		allowances[carrier] = allowance
+
+		u.logger.Debug("Computed carrier API budget allowance",
+			"carrier", carrier,
+			"daily_budget", budget,
+			"used_today", used,
+			"cycle_allowance", allowance)
+	}
+
+	return allowances
+}
+
+// recordAPICall adds a carrier API call to the persistent usage ledger
+func (u *TrackingUpdater) recordAPICall(carrier string, at time.Time) {
+	if err := u.quotaStore.RecordCall(carrier, at); err != nil {
+		u.logger.Error("Failed to record carrier API call", "carrier", carrier, "error", err)
+	}
+}
@@ -0,0 +1,62 @@
+package workers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCycleAllowance(t *testing.T) {
+	interval := 1 * time.Hour
+	midnight := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		budget int
+		used   int
+		now    time.Time
+		want   int
+	}{
+		{"start of day spreads over 24 cycles", 240, 0, midnight, 10},
+		{"rounds up leftover budget", 250, 0, midnight, 11},
+		{"nine am keeps most of the budget", 250, 30, midnight.Add(9 * time.Hour), 15},
+		{"last cycle gets everything left", 250, 200, midnight.Add(23*time.Hour + 30*time.Minute), 50},
+		{"exhausted budget", 250, 250, midnight.Add(12 * time.Hour), 0},
+		{"overspent budget", 250, 260, midnight.Add(12 * time.Hour), 0},
+		{"non-UTC clock uses UTC day", 240, 0, midnight.In(time.FixedZone("EST", -5*3600)), 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cycleAllowance(tt.budget, tt.used, tt.now, interval); got != tt.want {
+				t.Errorf("cycleAllowance(%d, %d) = %d, want %d", tt.budget, tt.used, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrackingUpdater_BudgetAllowances(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	cfg := getTestConfig()
+	cfg.UpdateInterval = 1 * time.Hour
+	cfg.DHLDailyAPIBudget = 48
+	updater := setupTestTrackingUpdater(t, cfg, db)
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 24; i++ {
+		if err := db.Quota.RecordCall("dhl", now.Add(-time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("RecordCall failed: %v", err)
+		}
+	}
+
+	allowances := updater.budgetAllowances(now)
+
+	// 24 calls left over the 12 remaining hourly cycles
+	if got, ok := allowances["dhl"]; !ok || got != 2 {
+		t.Errorf("Expected DHL allowance 2, got %d (present=%v)", got, ok)
+	}
+	if _, ok := allowances["usps"]; ok {
+		t.Error("Expected carriers without a budget to be unpaced")
+	}
+}
@@ -0,0 +1,221 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"package-tracking/internal/config"
+	"package-tracking/internal/database"
+	"package-tracking/internal/notifications"
+)
+
+// DigestWorker sends daily and weekly digests of shipment activity, and the morning list
+// of today's deliveries, to the notification channels configured for them
+type DigestWorker struct {
+	ctx           context.Context
+	cancel        context.CancelFunc
+	config        *config.Config
+	shipmentStore *database.ShipmentStore
+	notifier      *notifications.Dispatcher
+	logger        *slog.Logger
+	loopDone      chan struct{}
+
+	// runMu serializes runs so a manual run can't overlap a scheduled one
+	runMu sync.Mutex
+}
+
+// NewDigestWorker creates a new digest worker. notifier may be nil when notifications
+// are disabled.
+func NewDigestWorker(cfg *config.Config, shipmentStore *database.ShipmentStore, notifier *notifications.Dispatcher, logger *slog.Logger) *DigestWorker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &DigestWorker{
+		ctx:           ctx,
+		cancel:        cancel,
+		config:        cfg,
+		shipmentStore: shipmentStore,
+		notifier:      notifier,
+		logger:        logger,
+	}
+}
+
+// Start schedules digests if any channel is configured for them
+func (w *DigestWorker) Start() {
+	frequencies := w.notifier.DigestFrequencies()
+	if len(frequencies) == 0 {
+		w.logger.Info("Digest notifications are disabled")
+		return
+	}
+
+	w.logger.Info("Starting digest worker",
+		"time", w.config.NotificationDigestTime,
+		"weekday", w.config.NotificationDigestWeekday,
+		"daily", frequencies[notifications.DigestDaily],
+		"weekly", frequencies[notifications.DigestWeekly],
+		"today_alert", frequencies[notifications.DigestToday],
+		"today_alert_time", w.config.NotificationTodayAlertTime)
+
+	w.loopDone = make(chan struct{})
+	go w.digestLoop()
+}
+
+// Stop stops the schedule, waiting for a running digest to be queued
+func (w *DigestWorker) Stop() {
+	w.logger.Info("Stopping digest worker")
+	w.cancel()
+
+	if w.loopDone != nil && !waitForDrain(w.loopDone, defaultDrainTimeout) {
+		w.logger.Warn("Timed out waiting for digests to be queued")
+	}
+}
+
+// digestLoop sends digests and the today alert at their configured times of day until
+// stopped
+func (w *DigestWorker) digestLoop() {
+	defer close(w.loopDone)
+
+	frequencies := w.notifier.DigestFrequencies()
+	digests := frequencies[notifications.DigestDaily] || frequencies[notifications.DigestWeekly]
+	today := frequencies[notifications.DigestToday]
+
+	for {
+		now := time.Now()
+		nextDigest := nextRun(now, w.config.NotificationDigestTime)
+		nextToday := nextRun(now, w.config.NotificationTodayAlertTime)
+		next := nextDigest
+		if !digests || (today && nextToday.Before(nextDigest)) {
+			next = nextToday
+		}
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-w.ctx.Done():
+			timer.Stop()
+			w.logger.Info("Digest worker stopped")
+			return
+
+		case <-timer.C:
+			if digests && next.Equal(nextDigest) {
+				if _, err := w.RunDigests(next); err != nil {
+					w.logger.Error("Failed to send digests", "error", err)
+				}
+			}
+			if today && next.Equal(nextToday) {
+				if _, err := w.RunTodayAlert(next); err != nil {
+					w.logger.Error("Failed to send today's deliveries", "error", err)
+				}
+			}
+		}
+	}
+}
+
+// nextRun returns the next time after now that the clock shows value, HH:MM
+func nextRun(now time.Time, value string) time.Time {
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		// Validated with the configuration; fall back to midnight
+		clock = time.Time{}
+	}
+	next := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// RunDigests sends the daily digest covering the day before now and, on the configured
+// weekday, the weekly digest covering the week before. Digests without any activity are
+// skipped. It returns the channels the digests were queued for.
+func (w *DigestWorker) RunDigests(now time.Time) ([]string, error) {
+	w.runMu.Lock()
+	defer w.runMu.Unlock()
+
+	frequencies := w.notifier.DigestFrequencies()
+	weekday, err := config.ParseWeekday(w.config.NotificationDigestWeekday)
+	if err != nil {
+		weekday = time.Monday
+	}
+
+	var sent []string
+	for _, frequency := range []string{notifications.DigestDaily, notifications.DigestWeekly} {
+		if !frequencies[frequency] {
+			continue
+		}
+		since := now.AddDate(0, 0, -1)
+		if frequency == notifications.DigestWeekly {
+			if now.Weekday() != weekday {
+				continue
+			}
+			since = now.AddDate(0, 0, -7)
+		}
+
+		digest, err := w.buildDigest(frequency, since, now)
+		if err != nil {
+			return sent, err
+		}
+		if digest.Empty() {
+			w.logger.Info("Skipping digest without activity", "frequency", frequency)
+			continue
+		}
+
+		channels := w.notifier.SendDigest(*digest)
+		w.logger.Info("Digest queued",
+			"frequency", frequency,
+			"delivered", len(digest.Delivered),
+			"created", len(digest.Created),
+			"exceptions", len(digest.Exceptions),
+			"channels", channels)
+		sent = append(sent, channels...)
+	}
+	return sent, nil
+}
+
+// RunTodayAlert sends the shipments out for delivery or expected on now's day, unless
+// there are none. It returns the channels the list was queued for.
+func (w *DigestWorker) RunTodayAlert(now time.Time) ([]string, error) {
+	w.runMu.Lock()
+	defer w.runMu.Unlock()
+
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	shipments, err := w.shipmentStore.GetDueToday(start, start.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get today's deliveries: %w", err)
+	}
+	if len(shipments) == 0 {
+		w.logger.Info("No deliveries expected today")
+		return nil, nil
+	}
+
+	channels := w.notifier.SendDigest(notifications.Digest{
+		Frequency: notifications.DigestToday,
+		Since:     start,
+		Until:     now,
+		Today:     shipments,
+	})
+	w.logger.Info("Today's deliveries queued", "shipments", len(shipments), "channels", channels)
+	return channels, nil
+}
+
+// buildDigest summarizes the activity in [since, until)
+func (w *DigestWorker) buildDigest(frequency string, since, until time.Time) (*notifications.Digest, error) {
+	activity, err := w.shipmentStore.GetActivity(since, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shipment activity: %w", err)
+	}
+	stats, err := w.shipmentStore.GetStats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shipment stats: %w", err)
+	}
+
+	return &notifications.Digest{
+		Frequency:  frequency,
+		Since:      since,
+		Until:      until,
+		Stats:      *stats,
+		Delivered:  activity.Delivered,
+		Created:    activity.Created,
+		Exceptions: activity.Exceptions,
+	}, nil
+}
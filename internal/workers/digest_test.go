@@ -0,0 +1,114 @@
+package workers
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"package-tracking/internal/config"
+	"package-tracking/internal/database"
+	"package-tracking/internal/notifications"
+)
+
+func TestDigestWorker_RunDigests(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	daily := &recordingNotifier{}
+	dispatcher := notifications.NewDispatcher(notifications.RetryPolicy{MaxAttempts: 1}, 10, logger)
+	if err := dispatcher.AddChannel(daily, notifications.ChannelConfig{Digest: notifications.DigestDaily}); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{NotificationDigestTime: "08:00", NotificationDigestWeekday: "monday"}
+	worker := NewDigestWorker(cfg, db.Shipments, dispatcher, logger)
+
+	// Nothing happened yet, so no digest is sent
+	sent, err := worker.RunDigests(time.Now())
+	if err != nil {
+		t.Fatalf("RunDigests failed: %v", err)
+	}
+	if len(sent) != 0 {
+		t.Errorf("Expected empty digests to be skipped, got %v", sent)
+	}
+
+	shipment := &database.Shipment{TrackingNumber: "1Z999AA10123456784", Carrier: "ups", Description: "Headphones", Status: "delivered", IsDelivered: true}
+	if err := db.Shipments.Create(shipment); err != nil {
+		t.Fatalf("Failed to create shipment: %v", err)
+	}
+
+	dispatcher.Start()
+	sent, err = worker.RunDigests(time.Now().Add(time.Minute))
+	dispatcher.Stop()
+	if err != nil {
+		t.Fatalf("RunDigests failed: %v", err)
+	}
+	if len(sent) != 1 || sent[0] != "recording" {
+		t.Errorf("Expected the daily digest to be queued, got %v", sent)
+	}
+	if len(daily.digests) != 1 {
+		t.Fatalf("Expected one digest, got %d", len(daily.digests))
+	}
+	digest := daily.digests[0]
+	if digest.Frequency != notifications.DigestDaily || len(digest.Delivered) != 1 || len(digest.Created) != 1 || digest.Stats.TotalShipments != 1 {
+		t.Errorf("Unexpected digest %+v", digest)
+	}
+}
+
+func TestNextRun(t *testing.T) {
+	now := time.Date(2024, 6, 3, 7, 0, 0, 0, time.UTC)
+	if next := nextRun(now, "08:00"); !next.Equal(time.Date(2024, 6, 3, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected a run later today, got %v", next)
+	}
+	now = time.Date(2024, 6, 3, 8, 0, 0, 0, time.UTC)
+	if next := nextRun(now, "08:00"); !next.Equal(time.Date(2024, 6, 4, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected a run tomorrow, got %v", next)
+	}
+}
+
+func TestDigestWorker_RunTodayAlert(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	morning := &recordingNotifier{}
+	dispatcher := notifications.NewDispatcher(notifications.RetryPolicy{MaxAttempts: 1}, 10, logger)
+	if err := dispatcher.AddChannel(morning, notifications.ChannelConfig{TodayAlert: true}); err != nil {
+		t.Fatal(err)
+	}
+	worker := NewDigestWorker(&config.Config{NotificationTodayAlertTime: "07:00"}, db.Shipments, dispatcher, logger)
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, now.Location())
+	tomorrow := today.AddDate(0, 0, 1)
+	for _, s := range []*database.Shipment{
+		{TrackingNumber: "1Z999AA10000000001", Carrier: "ups", Description: "On the truck", Status: "out_for_delivery"},
+		{TrackingNumber: "1Z999AA10000000002", Carrier: "ups", Description: "Due today", Status: "in_transit", ExpectedDelivery: &today},
+		{TrackingNumber: "1Z999AA10000000003", Carrier: "ups", Description: "Due tomorrow", Status: "in_transit", ExpectedDelivery: &tomorrow},
+		{TrackingNumber: "1Z999AA10000000004", Carrier: "ups", Description: "Already here", Status: "delivered", IsDelivered: true, ExpectedDelivery: &today},
+	} {
+		if err := db.Shipments.Create(s); err != nil {
+			t.Fatalf("Failed to create shipment: %v", err)
+		}
+	}
+
+	dispatcher.Start()
+	sent, err := worker.RunTodayAlert(now)
+	dispatcher.Stop()
+	if err != nil {
+		t.Fatalf("RunTodayAlert failed: %v", err)
+	}
+	if len(sent) != 1 || len(morning.digests) != 1 {
+		t.Fatalf("Expected the list to be sent once, got %v and %d digests", sent, len(morning.digests))
+	}
+	digest := morning.digests[0]
+	if digest.Frequency != notifications.DigestToday || len(digest.Today) != 2 {
+		t.Fatalf("Expected today's two deliveries, got %+v", digest.Today)
+	}
+	for _, s := range digest.Today {
+		if s.Description != "On the truck" && s.Description != "Due today" {
+			t.Errorf("Unexpected shipment %q", s.Description)
+		}
+	}
+}
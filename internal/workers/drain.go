@@ -0,0 +1,88 @@
+package workers
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultDrainTimeout bounds how long Stop waits for in-flight work to finish
+	defaultDrainTimeout = 30 * time.Second
+	// drainFlushGrace is how long to wait for partial results to be persisted after
+	// in-flight work has been abandoned
+	drainFlushGrace = 5 * time.Second
+)
+
+// DrainReport summarizes what happened to outstanding work when a worker was stopped
+type DrainReport struct {
+	// Completed is the number of in-flight items that finished after the stop was requested
+	Completed int `json:"completed"`
+	// Abandoned is the number of in-flight items cut off by the drain timeout
+	Abandoned int `json:"abandoned"`
+	// Requeued is the number of queued items that were never started and will be picked up on the next run
+	Requeued int           `json:"requeued"`
+	Duration time.Duration `json:"duration"`
+	TimedOut bool          `json:"timed_out"`
+}
+
+// drainTracker accumulates drain counters while a worker is shutting down
+type drainTracker struct {
+	mu     sync.Mutex
+	report DrainReport
+}
+
+func (d *drainTracker) completed(n int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.report.Completed += n
+}
+
+func (d *drainTracker) abandoned(n int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.report.Abandoned += n
+}
+
+func (d *drainTracker) requeued(n int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.report.Requeued += n
+}
+
+// snapshot returns the current counters
+func (d *drainTracker) snapshot() DrainReport {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.report
+}
+
+// waitForDrain waits for done to close, returning false if the timeout elapsed first
+func waitForDrain(done <-chan struct{}, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// logDrainReport logs the drain summary for a worker
+func logDrainReport(logger *slog.Logger, worker string, report DrainReport) {
+	logArgs := []interface{}{
+		"worker", worker,
+		"completed", report.Completed,
+		"abandoned", report.Abandoned,
+		"requeued", report.Requeued,
+		"duration", report.Duration,
+	}
+
+	if report.TimedOut {
+		logger.Warn("Worker drain timed out, abandoning in-flight work", logArgs...)
+		return
+	}
+	logger.Info("Worker drained", logArgs...)
+}
@@ -0,0 +1,198 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"package-tracking/internal/config"
+	"package-tracking/internal/database"
+)
+
+// EmailCleanupReport describes the outcome of a single email maintenance run
+type EmailCleanupReport struct {
+	RanAt               time.Time     `json:"ran_at"`
+	RetentionCutoff     time.Time     `json:"retention_cutoff"`
+	BodiesCleared       int64         `json:"bodies_cleared"`
+	BytesReclaimed      int64         `json:"bytes_reclaimed"`
+	OrphanedLinksPruned int64         `json:"orphaned_links_pruned"`
+	Duration            time.Duration `json:"duration"`
+	Error               string        `json:"error,omitempty"`
+}
+
+// EmailCleanupStatus is a snapshot of the email cleanup worker state
+type EmailCleanupStatus struct {
+	Enabled             bool                `json:"enabled"`
+	Interval            string              `json:"interval"`
+	RetentionDays       int                 `json:"retention_days"`
+	NextRun             *time.Time          `json:"next_run,omitempty"`
+	LastRun             *EmailCleanupReport `json:"last_run,omitempty"`
+	TotalBytesReclaimed int64               `json:"total_bytes_reclaimed"`
+}
+
+// EmailCleanupWorker periodically applies email body retention and prunes orphaned
+// email-shipment links
+type EmailCleanupWorker struct {
+	ctx        context.Context
+	cancel     context.CancelFunc
+	config     *config.Config
+	emailStore *database.EmailStore
+	logger     *slog.Logger
+	loopDone   chan struct{}
+
+	// runMu serializes cleanup runs between the scheduler and the admin API
+	runMu sync.Mutex
+
+	statusMu       sync.Mutex
+	lastReport     *EmailCleanupReport
+	nextRun        *time.Time
+	totalReclaimed int64
+}
+
+// NewEmailCleanupWorker creates a new email cleanup worker
+func NewEmailCleanupWorker(cfg *config.Config, emailStore *database.EmailStore, logger *slog.Logger) *EmailCleanupWorker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &EmailCleanupWorker{
+		ctx:        ctx,
+		cancel:     cancel,
+		config:     cfg,
+		emailStore: emailStore,
+		logger:     logger,
+	}
+}
+
+// Start begins the scheduled cleanup process
+func (w *EmailCleanupWorker) Start() {
+	if !w.config.EmailCleanupEnabled {
+		w.logger.Info("Email cleanup is disabled, skipping scheduled maintenance")
+		return
+	}
+
+	w.logger.Info("Starting email cleanup worker",
+		"interval", w.config.EmailCleanupInterval,
+		"retention_days", w.config.EmailBodyRetentionDays)
+
+	w.loopDone = make(chan struct{})
+	go w.cleanupLoop()
+}
+
+// Stop stops the scheduled cleanup process, waiting for a running cleanup to finish
+func (w *EmailCleanupWorker) Stop() {
+	w.logger.Info("Stopping email cleanup worker")
+	w.cancel()
+
+	if w.loopDone != nil && !waitForDrain(w.loopDone, defaultDrainTimeout) {
+		w.logger.Warn("Timed out waiting for email cleanup to finish")
+	}
+}
+
+// cleanupLoop runs cleanup shortly after startup and then on the configured interval
+func (w *EmailCleanupWorker) cleanupLoop() {
+	defer close(w.loopDone)
+
+	// Run the first cleanup after a short delay so it doesn't compete with startup
+	initialDelay := 1 * time.Minute
+	timer := time.NewTimer(initialDelay)
+	defer timer.Stop()
+	w.setNextRun(time.Now().Add(initialDelay))
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			w.logger.Info("Email cleanup worker stopped")
+			return
+
+		case <-timer.C:
+			if _, err := w.RunNow(); err != nil {
+				w.logger.Error("Scheduled email cleanup failed", "error", err)
+			}
+			timer.Reset(w.config.EmailCleanupInterval)
+			w.setNextRun(time.Now().Add(w.config.EmailCleanupInterval))
+		}
+	}
+}
+
+// RunNow performs a cleanup run immediately and returns its report
+func (w *EmailCleanupWorker) RunNow() (*EmailCleanupReport, error) {
+	w.runMu.Lock()
+	defer w.runMu.Unlock()
+
+	startTime := time.Now()
+	report := &EmailCleanupReport{
+		RanAt:           startTime,
+		RetentionCutoff: startTime.AddDate(0, 0, -w.config.EmailBodyRetentionDays),
+	}
+
+	err := w.runCleanup(report)
+	report.Duration = time.Since(startTime)
+	if err != nil {
+		report.Error = err.Error()
+	}
+
+	w.statusMu.Lock()
+	w.lastReport = report
+	w.totalReclaimed += report.BytesReclaimed
+	w.statusMu.Unlock()
+
+	if err != nil {
+		return report, err
+	}
+
+	w.logger.Info("Email cleanup completed",
+		"bodies_cleared", report.BodiesCleared,
+		"bytes_reclaimed", report.BytesReclaimed,
+		"orphaned_links_pruned", report.OrphanedLinksPruned,
+		"retention_cutoff", report.RetentionCutoff,
+		"duration", report.Duration)
+
+	return report, nil
+}
+
+// runCleanup applies body retention and prunes orphaned links, filling in the report
+func (w *EmailCleanupWorker) runCleanup(report *EmailCleanupReport) error {
+	cleared, reclaimed, err := w.emailStore.ClearEmailBodies(report.RetentionCutoff)
+	if err != nil {
+		return fmt.Errorf("failed to apply email body retention: %w", err)
+	}
+	report.BodiesCleared = cleared
+	report.BytesReclaimed = reclaimed
+
+	pruned, err := w.emailStore.PruneOrphanedLinks()
+	if err != nil {
+		return fmt.Errorf("failed to prune orphaned email links: %w", err)
+	}
+	report.OrphanedLinksPruned = pruned
+
+	return nil
+}
+
+// Status returns a snapshot of the cleanup worker state
+func (w *EmailCleanupWorker) Status() EmailCleanupStatus {
+	w.statusMu.Lock()
+	defer w.statusMu.Unlock()
+
+	status := EmailCleanupStatus{
+		Enabled:             w.config.EmailCleanupEnabled,
+		Interval:            w.config.EmailCleanupInterval.String(),
+		RetentionDays:       w.config.EmailBodyRetentionDays,
+		NextRun:             copyTime(w.nextRun),
+		TotalBytesReclaimed: w.totalReclaimed,
+	}
+
+	if w.lastReport != nil {
+		report := *w.lastReport
+		status.LastRun = &report
+	}
+
+	return status
+}
+
+// setNextRun records when the next cleanup is scheduled
+func (w *EmailCleanupWorker) setNextRun(next time.Time) {
+	w.statusMu.Lock()
+	defer w.statusMu.Unlock()
+
+	w.nextRun = &next
+}
@@ -0,0 +1,68 @@
+package workers
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"package-tracking/internal/config"
+	"package-tracking/internal/database"
+)
+
+func TestEmailCleanupWorker_RunNow(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	cfg := &config.Config{
+		EmailCleanupEnabled:    true,
+		EmailCleanupInterval:   24 * time.Hour,
+		EmailBodyRetentionDays: 30,
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	worker := NewEmailCleanupWorker(cfg, db.Emails, logger)
+
+	oldTime := time.Now().AddDate(0, 0, -60)
+	email := &database.EmailBodyEntry{
+		GmailMessageID:    "old-message",
+		GmailThreadID:     "old-thread",
+		From:              "test@example.com",
+		Subject:           "Old shipping notice",
+		Date:              oldTime,
+		BodyText:          "body",
+		InternalTimestamp: oldTime,
+		ScanMethod:        "time-based",
+		ProcessedAt:       oldTime,
+		Status:            "processed",
+		ProcessingPhase:   "legacy",
+	}
+	if err := db.Emails.CreateOrUpdate(email); err != nil {
+		t.Fatalf("Failed to create email: %v", err)
+	}
+
+	report, err := worker.RunNow()
+	if err != nil {
+		t.Fatalf("RunNow failed: %v", err)
+	}
+
+	if report.BodiesCleared != 1 || report.BytesReclaimed != int64(len("body")) {
+		t.Errorf("Unexpected cleanup report: %+v", report)
+	}
+
+	status := worker.Status()
+	if status.LastRun == nil || status.LastRun.BodiesCleared != 1 {
+		t.Errorf("Expected status to include the last run, got %+v", status.LastRun)
+	}
+	if status.TotalBytesReclaimed != int64(len("body")) {
+		t.Errorf("Expected total bytes reclaimed %d, got %d", len("body"), status.TotalBytesReclaimed)
+	}
+
+	// A second run has nothing left to reclaim
+	report, err = worker.RunNow()
+	if err != nil {
+		t.Fatalf("RunNow failed: %v", err)
+	}
+	if report.BodiesCleared != 0 || report.BytesReclaimed != 0 {
+		t.Errorf("Expected nothing to clean on second run, got %+v", report)
+	}
+}
@@ -32,6 +32,15 @@ type TimeBasedEmailProcessor struct {
 	factory       CarrierFactory // For validation
 	cacheManager  CacheManager   // For validation caching
 	rateLimiter   RateLimiter    // For validation rate limiting
+
+	// Shutdown coordination: scanning holds the lock while a scan is running so Stop
+	// can wait for the in-flight email to finish
+	stopCtx     context.Context
+	stopCancel  context.CancelFunc
+	scanning    sync.Mutex
+	stopOnce    sync.Once
+	drain       drainTracker
+	drainReport DrainReport
 }
 
 // CacheManager interface for caching validation results
@@ -118,6 +127,7 @@ func NewTimeBasedEmailProcessor(
 	apiClient APIClient,
 	logger *slog.Logger,
 ) *TimeBasedEmailProcessor {
+	stopCtx, stopCancel := context.WithCancel(context.Background())
 	return &TimeBasedEmailProcessor{
 		config:        config,
 		emailClient:   emailClient,
@@ -131,7 +141,67 @@ func NewTimeBasedEmailProcessor(
 		factory:       nil, // Will be set separately if validation is needed
 		cacheManager:  nil, // Will be set separately if caching is needed
 		rateLimiter:   nil, // Will be set separately if rate limiting is needed
+		stopCtx:       stopCtx,
+		stopCancel:    stopCancel,
+	}
+}
+
+// Done returns a channel that is closed once Stop has been called
+func (p *TimeBasedEmailProcessor) Done() <-chan struct{} {
+	if p.stopCtx == nil {
+		return nil
+	}
+	return p.stopCtx.Done()
+}
+
+// stopping reports whether Stop has been called
+func (p *TimeBasedEmailProcessor) stopping() bool {
+	return p.stopCtx != nil && p.stopCtx.Err() != nil
+}
+
+// Stop stops accepting new work and waits up to timeout for the email currently being
+// processed to finish. Emails that were fetched but not yet processed remain unprocessed
+// and are picked up by the next scan. Subsequent calls return the report from the first stop.
+func (p *TimeBasedEmailProcessor) Stop(timeout time.Duration) DrainReport {
+	p.stopOnce.Do(func() {
+		p.logger.Info("Stopping time-based email processor", "drain_timeout", timeout)
+		startTime := time.Now()
+		if p.stopCancel != nil {
+			p.stopCancel()
+		}
+
+		// Acquiring the scan lock means no scan is in progress
+		idle := make(chan struct{})
+		go func() {
+			p.scanning.Lock()
+			close(idle)
+		}()
+
+		timedOut := !waitForDrain(idle, timeout)
+		if timedOut {
+			p.drain.abandoned(1)
+		}
+
+		p.drainReport = p.drain.snapshot()
+		p.drainReport.Duration = time.Since(startTime)
+		p.drainReport.TimedOut = timedOut
+		logDrainReport(p.logger, "email_processor", p.drainReport)
+	})
+	return p.drainReport
+}
+
+// beginScan marks a scan as running, returning false if the processor is stopping
+func (p *TimeBasedEmailProcessor) beginScan() bool {
+	if p.stopping() {
+		return false
+	}
+	p.scanning.Lock()
+	// Stop may have been called while waiting for a previous scan to finish
+	if p.stopping() {
+		p.scanning.Unlock()
+		return false
 	}
+	return true
 }
 
 // validateTracking validates a tracking number by performing a carrier lookup
@@ -285,6 +355,12 @@ func truncateForLogging(text string, maxLength int) string {
 
 // ProcessEmailsSince processes all emails since the specified time using time-based scanning
 func (p *TimeBasedEmailProcessor) ProcessEmailsSince(since time.Time) error {
+	if !p.beginScan() {
+		p.logger.Debug("Email processor is stopping, skipping scan")
+		return nil
+	}
+	defer p.scanning.Unlock()
+
 	startTime := time.Now()
 	p.metrics.incrementTotalScans()
 
@@ -318,6 +394,16 @@ func (p *TimeBasedEmailProcessor) ProcessEmailsSince(since time.Time) error {
 			break
 		}
 
+		// Stop picking up new emails once shutdown has started
+		if p.stopping() {
+			remaining := len(messages) - i
+			if p.config.MaxEmailsPerScan > 0 && p.config.MaxEmailsPerScan-i < remaining {
+				remaining = p.config.MaxEmailsPerScan - i
+			}
+			p.drain.requeued(remaining)
+			break
+		}
+
 		// Check if already processed
 		alreadyProcessed, err := p.stateManager.IsProcessed(msg.ID)
 		if err != nil {
@@ -332,7 +418,11 @@ func (p *TimeBasedEmailProcessor) ProcessEmailsSince(since time.Time) error {
 		}
 
 		// Process the individual email
-		if err := p.processIndividualEmail(&msg); err != nil {
+		err = p.processIndividualEmail(&msg)
+		if p.stopping() {
+			p.drain.completed(1)
+		}
+		if err != nil {
 			p.logger.Error("Failed to process individual email",
 				"email_id", msg.ID,
 				"from", msg.From,
@@ -372,6 +462,12 @@ func (p *TimeBasedEmailProcessor) ProcessEmailsSince(since time.Time) error {
 
 // PerformRetroactiveScan performs a full retroactive scan for the configured number of days
 func (p *TimeBasedEmailProcessor) PerformRetroactiveScan() error {
+	if !p.beginScan() {
+		p.logger.Debug("Email processor is stopping, skipping retroactive scan")
+		return nil
+	}
+	defer p.scanning.Unlock()
+
 	p.logger.Info("Starting retroactive scan", "days", p.config.ScanDays)
 
 	messages, err := p.emailClient.PerformRetroactiveScan(p.config.ScanDays)
@@ -385,7 +481,13 @@ func (p *TimeBasedEmailProcessor) PerformRetroactiveScan() error {
 	p.metrics.addEmailsScanned(int64(len(messages)))
 
 	// Process all retrieved messages
-	for _, msg := range messages {
+	for i, msg := range messages {
+		// Stop picking up new emails once shutdown has started
+		if p.stopping() {
+			p.drain.requeued(len(messages) - i)
+			break
+		}
+
 		// Check if already processed
 		alreadyProcessed, err := p.stateManager.IsProcessed(msg.ID)
 		if err != nil {
@@ -399,7 +501,11 @@ func (p *TimeBasedEmailProcessor) PerformRetroactiveScan() error {
 		}
 
 		// Process the email
-		if err := p.processIndividualEmail(&msg); err != nil {
+		err = p.processIndividualEmail(&msg)
+		if p.stopping() {
+			p.drain.completed(1)
+		}
+		if err != nil {
 			p.logger.Error("Failed to process email during retroactive scan",
 				"email_id", msg.ID, "error", err)
 			continue
@@ -619,3 +619,30 @@ func countOccurrences(slice []string, item string) int {
 }
 
 
+
+func TestTimeBasedEmailProcessor_StopSkipsNewScans(t *testing.T) {
+	processor, client, db, _ := setupTimeBasedProcessor(t)
+	defer db.Close()
+
+	client.messages = []email.EmailMessage{
+		{ID: "msg-1", ThreadID: "thread-1", From: "test@example.com", Subject: "Package shipped", Date: time.Now()},
+	}
+
+	report := processor.Stop(time.Second)
+	if report.TimedOut || report.Abandoned != 0 {
+		t.Errorf("Expected idle processor to drain cleanly, got %+v", report)
+	}
+
+	select {
+	case <-processor.Done():
+	default:
+		t.Error("Expected Done channel to be closed after Stop")
+	}
+
+	if err := processor.ProcessEmailsSince(time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("ProcessEmailsSince failed: %v", err)
+	}
+	if contains(client.callLog, "GetMessagesSince") {
+		t.Error("Expected no scan to start after Stop")
+	}
+}
@@ -0,0 +1,265 @@
+package workers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"package-tracking/internal/config"
+	"package-tracking/internal/database"
+	"package-tracking/internal/notifications"
+)
+
+// EscalationReport describes the outcome of a single escalation check
+type EscalationReport struct {
+	RanAt    time.Time `json:"ran_at"`
+	Opened   int       `json:"opened"`
+	Notified int       `json:"notified"`
+	Resolved int       `json:"resolved"`
+}
+
+// EscalationWorker follows up on delivery exceptions and expected delivery slips. Each
+// problem is an alert that is notified when it is detected and again every repeat
+// interval, up to a number of reminders, until it is acknowledged through the API or
+// resolved by the shipment moving on.
+type EscalationWorker struct {
+	ctx           context.Context
+	cancel        context.CancelFunc
+	config        *config.Config
+	shipmentStore *database.ShipmentStore
+	alertStore    *database.AlertStore
+	notifier      *notifications.Dispatcher
+	logger        *slog.Logger
+	loopDone      chan struct{}
+
+	// runMu serializes checks with delays reported by the tracking updater
+	runMu sync.Mutex
+}
+
+// NewEscalationWorker creates a new escalation worker. notifier may be nil when
+// notifications are disabled.
+func NewEscalationWorker(cfg *config.Config, db *database.DB, notifier *notifications.Dispatcher, logger *slog.Logger) *EscalationWorker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &EscalationWorker{
+		ctx:           ctx,
+		cancel:        cancel,
+		config:        cfg,
+		shipmentStore: db.Shipments,
+		alertStore:    db.Alerts,
+		notifier:      notifier,
+		logger:        logger,
+	}
+}
+
+// enabled reports whether escalation is configured and has somewhere to notify
+func (w *EscalationWorker) enabled() bool {
+	return w != nil && w.config.NotificationEscalationEnabled && w.notifier != nil
+}
+
+// Start begins the periodic escalation check
+func (w *EscalationWorker) Start() {
+	if !w.enabled() {
+		w.logger.Info("Exception and delay escalation is disabled")
+		return
+	}
+
+	w.logger.Info("Starting escalation worker",
+		"interval", w.config.NotificationEscalationInterval,
+		"repeat", w.config.NotificationEscalationRepeat,
+		"max_reminders", w.config.NotificationEscalationMaxReminders)
+
+	w.loopDone = make(chan struct{})
+	go w.escalationLoop()
+}
+
+// Stop stops the periodic check, waiting for a running check to finish
+func (w *EscalationWorker) Stop() {
+	w.logger.Info("Stopping escalation worker")
+	w.cancel()
+
+	if w.loopDone != nil && !waitForDrain(w.loopDone, defaultDrainTimeout) {
+		w.logger.Warn("Timed out waiting for escalation check to finish")
+	}
+}
+
+// escalationLoop checks once at startup and then on the configured interval
+func (w *EscalationWorker) escalationLoop() {
+	defer close(w.loopDone)
+
+	ticker := time.NewTicker(w.config.NotificationEscalationInterval)
+	defer ticker.Stop()
+
+	if _, err := w.RunOnce(time.Now()); err != nil {
+		w.logger.Error("Escalation check failed", "error", err)
+	}
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			w.logger.Info("Escalation worker stopped")
+			return
+
+		case <-ticker.C:
+			if _, err := w.RunOnce(time.Now()); err != nil {
+				w.logger.Error("Escalation check failed", "error", err)
+			}
+		}
+	}
+}
+
+// ReportETAChange opens a delay alert, and notifies it right away, when a shipment's
+// expected delivery moves to a later day. A nil worker ignores the change.
+func (w *EscalationWorker) ReportETAChange(shipment database.Shipment, previous *time.Time) {
+	if !w.enabled() || previous == nil || shipment.ExpectedDelivery == nil || shipment.IsDelivered {
+		return
+	}
+	if daysBetween(*previous, *shipment.ExpectedDelivery) <= 0 {
+		return
+	}
+
+	w.runMu.Lock()
+	defer w.runMu.Unlock()
+
+	detail := fmt.Sprintf("Expected delivery moved from %s to %s",
+		previous.Format("Mon, Jan 2"), shipment.ExpectedDelivery.Format("Mon, Jan 2"))
+	alert, created, err := w.alertStore.Open(shipment.ID, database.AlertKindDelay, detail)
+	if err != nil {
+		w.logger.Error("Failed to open delay alert", "shipment_id", shipment.ID, "error", err)
+		return
+	}
+	if created {
+		w.logger.Info("Delivery delayed", "shipment_id", shipment.ID, "detail", detail)
+		w.escalate(*alert, shipment, time.Now())
+	}
+}
+
+// RunOnce opens alerts for shipments in exception, resolves alerts whose shipment has
+// moved on, and sends the notifications that are due
+func (w *EscalationWorker) RunOnce(now time.Time) (*EscalationReport, error) {
+	w.runMu.Lock()
+	defer w.runMu.Unlock()
+
+	report := &EscalationReport{RanAt: now}
+
+	exceptions, err := w.shipmentStore.GetExceptions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shipments in exception: %w", err)
+	}
+	for _, shipment := range exceptions {
+		detail := "The carrier reported a delivery exception"
+		if _, created, err := w.alertStore.Open(shipment.ID, database.AlertKindException, detail); err != nil {
+			w.logger.Error("Failed to open exception alert", "shipment_id", shipment.ID, "error", err)
+		} else if created {
+			report.Opened++
+		}
+	}
+
+	alerts, err := w.alertStore.GetOpen()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open alerts: %w", err)
+	}
+	for _, alert := range alerts {
+		shipment, err := w.shipmentStore.GetByID(alert.ShipmentID)
+		if err != nil && err != sql.ErrNoRows {
+			w.logger.Error("Failed to get shipment of alert", "alert_id", alert.ID, "error", err)
+			continue
+		}
+
+		if shipment == nil || resolved(alert, *shipment) {
+			if err := w.alertStore.Resolve(alert.ID, now); err != nil {
+				w.logger.Error("Failed to resolve alert", "alert_id", alert.ID, "error", err)
+				continue
+			}
+			report.Resolved++
+			continue
+		}
+
+		if w.due(alert, now) {
+			w.escalate(alert, *shipment, now)
+			report.Notified++
+		}
+	}
+
+	if report.Opened > 0 || report.Notified > 0 || report.Resolved > 0 {
+		w.logger.Info("Escalation check completed",
+			"opened", report.Opened,
+			"notified", report.Notified,
+			"resolved", report.Resolved)
+	}
+	return report, nil
+}
+
+// resolved reports whether the problem an alert is about is over
+func resolved(alert database.Alert, shipment database.Shipment) bool {
+	if shipment.IsDelivered {
+		return true
+	}
+	return alert.Kind == database.AlertKindException && !strings.EqualFold(shipment.Status, "exception")
+}
+
+// due reports whether an alert should be notified now: when it is new, and then every
+// repeat interval until it is acknowledged or runs out of reminders
+func (w *EscalationWorker) due(alert database.Alert, now time.Time) bool {
+	if alert.AcknowledgedAt != nil {
+		return false
+	}
+	if alert.NotificationsSent == 0 || alert.LastNotifiedAt == nil {
+		return true
+	}
+	if alert.NotificationsSent > w.config.NotificationEscalationMaxReminders {
+		return false
+	}
+	return now.Sub(*alert.LastNotifiedAt) >= w.config.NotificationEscalationRepeat
+}
+
+// escalate notifies an alert: informationally the first time, as a reminder after that.
+// Callers hold runMu.
+func (w *EscalationWorker) escalate(alert database.Alert, shipment database.Shipment, now time.Time) {
+	name := shipment.Description
+	if name == "" {
+		name = shipment.TrackingNumber
+	}
+	problem := "Delivery exception"
+	if alert.Kind == database.AlertKindDelay {
+		problem = "Delivery delayed"
+	}
+
+	subject := fmt.Sprintf("%s: %s", problem, name)
+	body := alert.Detail + "."
+	if reminder := alert.NotificationsSent; reminder > 0 {
+		subject = fmt.Sprintf("%s (reminder %d): %s", problem, reminder, name)
+		body += fmt.Sprintf(" First reported %s.", alert.CreatedAt.Local().Format("Mon, Jan 2 3:04 PM"))
+	}
+	if alert.NotificationsSent < w.config.NotificationEscalationMaxReminders {
+		body += fmt.Sprintf(" Acknowledge alert %d to stop reminders.", alert.ID)
+	}
+
+	transition := notifications.Transition{
+		Shipment:   shipment,
+		ToStatus:   shipment.Status,
+		Source:     notifications.SourceEscalation,
+		OccurredAt: now,
+	}
+	channels := w.notifier.Escalate(transition, subject, body)
+	if err := w.alertStore.MarkNotified(alert.ID, now); err != nil {
+		w.logger.Error("Failed to record alert notification", "alert_id", alert.ID, "error", err)
+	}
+	w.logger.Info("Alert escalated",
+		"alert_id", alert.ID,
+		"shipment_id", shipment.ID,
+		"kind", alert.Kind,
+		"notifications_sent", alert.NotificationsSent+1,
+		"channels", channels)
+}
+
+// daysBetween returns the number of calendar days from a to b, in a's time zone
+func daysBetween(a, b time.Time) int {
+	b = b.In(a.Location())
+	from := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	to := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
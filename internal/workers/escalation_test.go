@@ -0,0 +1,126 @@
+package workers
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"package-tracking/internal/config"
+	"package-tracking/internal/database"
+	"package-tracking/internal/notifications"
+)
+
+func newTestEscalationWorker(t *testing.T, db *database.DB) (*EscalationWorker, *notifications.Dispatcher, *recordingNotifier) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	notifier := &recordingNotifier{}
+	dispatcher := notifications.NewDispatcher(notifications.RetryPolicy{MaxAttempts: 1}, 10, logger)
+	if err := dispatcher.AddChannel(notifier, notifications.ChannelConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{
+		NotificationEscalationEnabled:      true,
+		NotificationEscalationInterval:     15 * time.Minute,
+		NotificationEscalationRepeat:       12 * time.Hour,
+		NotificationEscalationMaxReminders: 1,
+	}
+	return NewEscalationWorker(cfg, db, dispatcher, logger), dispatcher, notifier
+}
+
+func TestEscalationWorker_Exceptions(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	worker, dispatcher, notifier := newTestEscalationWorker(t, db)
+
+	shipment := &database.Shipment{TrackingNumber: "1Z999AA10123456784", Carrier: "ups", Description: "Headphones", Status: "exception"}
+	if err := db.Shipments.Create(shipment); err != nil {
+		t.Fatalf("Failed to create shipment: %v", err)
+	}
+
+	dispatcher.Start()
+	now := time.Now()
+	steps := []struct {
+		at                         time.Time
+		opened, notified, resolved int
+	}{
+		{now, 1, 1, 0},                     // Informational notification
+		{now.Add(time.Hour), 0, 0, 0},      // Not yet due again
+		{now.Add(13 * time.Hour), 0, 1, 0}, // First reminder
+		{now.Add(26 * time.Hour), 0, 0, 0}, // Out of reminders
+	}
+	for i, step := range steps {
+		report, err := worker.RunOnce(step.at)
+		if err != nil {
+			t.Fatalf("RunOnce failed: %v", err)
+		}
+		if report.Opened != step.opened || report.Notified != step.notified || report.Resolved != step.resolved {
+			t.Errorf("Step %d: expected %d opened, %d notified, %d resolved, got %+v",
+				i, step.opened, step.notified, step.resolved, report)
+		}
+	}
+
+	// The exception clearing resolves the alert
+	shipment.Status = "in_transit"
+	if err := db.Shipments.Update(shipment.ID, shipment); err != nil {
+		t.Fatalf("Failed to update shipment: %v", err)
+	}
+	if report, _ := worker.RunOnce(now.Add(27 * time.Hour)); report.Resolved != 1 {
+		t.Errorf("Expected the alert to be resolved, got %+v", report)
+	}
+	dispatcher.Stop()
+
+	if len(notifier.subjects) != 2 {
+		t.Fatalf("Expected 2 notifications, got %v", notifier.subjects)
+	}
+	if notifier.subjects[0] != "Delivery exception: Headphones" || notifier.subjects[1] != "Delivery exception (reminder 1): Headphones" {
+		t.Errorf("Unexpected subjects %q", notifier.subjects)
+	}
+	if notifier.transitions[0].Source != notifications.SourceEscalation {
+		t.Errorf("Expected escalation source, got %q", notifier.transitions[0].Source)
+	}
+}
+
+func TestEscalationWorker_AcknowledgedDelay(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	worker, dispatcher, notifier := newTestEscalationWorker(t, db)
+
+	previous := time.Now().AddDate(0, 0, 1)
+	expected := previous.AddDate(0, 0, 2)
+	shipment := &database.Shipment{TrackingNumber: "9400111899223197428490", Carrier: "usps", Description: "Books", Status: "in_transit", ExpectedDelivery: &expected}
+	if err := db.Shipments.Create(shipment); err != nil {
+		t.Fatalf("Failed to create shipment: %v", err)
+	}
+
+	dispatcher.Start()
+	// Moving earlier or within the same day isn't a delay
+	earlier := expected.AddDate(0, 0, 1)
+	worker.ReportETAChange(*shipment, &earlier)
+	worker.ReportETAChange(*shipment, &previous)
+	worker.ReportETAChange(*shipment, &previous)
+
+	alerts, err := db.Alerts.GetOpen()
+	if err != nil || len(alerts) != 1 || alerts[0].Kind != database.AlertKindDelay || alerts[0].NotificationsSent != 1 {
+		t.Fatalf("Expected one notified delay alert, got %+v (%v)", alerts, err)
+	}
+	if !strings.HasPrefix(alerts[0].Detail, "Expected delivery moved from") {
+		t.Errorf("Unexpected detail %q", alerts[0].Detail)
+	}
+
+	// Acknowledged alerts get no reminders
+	if err := db.Alerts.Acknowledge(alerts[0].ID, time.Now()); err != nil {
+		t.Fatalf("Acknowledge failed: %v", err)
+	}
+	if report, _ := worker.RunOnce(time.Now().Add(13 * time.Hour)); report.Notified != 0 {
+		t.Errorf("Expected no reminder after acknowledging, got %+v", report)
+	}
+	dispatcher.Stop()
+
+	if len(notifier.subjects) != 1 || notifier.subjects[0] != "Delivery delayed: Books" {
+		t.Errorf("Expected a single delay notification, got %q", notifier.subjects)
+	}
+
+	var disabled *EscalationWorker
+	disabled.ReportETAChange(*shipment, &previous)
+}
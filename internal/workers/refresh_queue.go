@@ -0,0 +1,118 @@
+package workers
+
+import (
+	"container/heap"
+	"time"
+
+	"package-tracking/internal/carriers"
+	"package-tracking/internal/database"
+)
+
+// Refresh priority tiers, lower values are refreshed first
+const (
+	priorityOutForDelivery = iota
+	priorityDeliveryToday
+	priorityStaleInTransit
+	priorityDefault
+)
+
+// refreshPriority returns the scheduling tier for a shipment. A shipment is considered
+// stale when it has not been auto-refreshed within staleAfter.
+func refreshPriority(shipment *database.Shipment, now time.Time, staleAfter time.Duration) int {
+	if shipment.Status == string(carriers.StatusOutForDelivery) {
+		return priorityOutForDelivery
+	}
+
+	if shipment.ExpectedDelivery != nil && sameDay(*shipment.ExpectedDelivery, now) {
+		return priorityDeliveryToday
+	}
+
+	if shipment.Status == string(carriers.StatusInTransit) {
+		if shipment.LastAutoRefresh == nil || now.Sub(*shipment.LastAutoRefresh) >= staleAfter {
+			return priorityStaleInTransit
+		}
+	}
+
+	return priorityDefault
+}
+
+// sameDay reports whether two times fall on the same calendar day in the local time zone
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Local().Date()
+	by, bm, bd := b.Local().Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// refreshPriorityLess orders shipments by priority tier, then by least recently refreshed
+func refreshPriorityLess(now time.Time, staleAfter time.Duration) func(a, b *database.Shipment) bool {
+	return func(a, b *database.Shipment) bool {
+		pa := refreshPriority(a, now, staleAfter)
+		pb := refreshPriority(b, now, staleAfter)
+		if pa != pb {
+			return pa < pb
+		}
+
+		// Shipments that were never auto-refreshed go first
+		switch {
+		case a.LastAutoRefresh == nil && b.LastAutoRefresh != nil:
+			return true
+		case a.LastAutoRefresh != nil && b.LastAutoRefresh == nil:
+			return false
+		case a.LastAutoRefresh != nil && b.LastAutoRefresh != nil && !a.LastAutoRefresh.Equal(*b.LastAutoRefresh):
+			return a.LastAutoRefresh.Before(*b.LastAutoRefresh)
+		}
+
+		return a.ID < b.ID
+	}
+}
+
+// refreshQueue is a priority queue of shipments waiting to be refreshed
+type refreshQueue struct {
+	items []*database.Shipment
+	less  func(a, b *database.Shipment) bool
+}
+
+// newRefreshQueue creates an empty refresh queue ordered by the given comparison function
+func newRefreshQueue(less func(a, b *database.Shipment) bool) *refreshQueue {
+	return &refreshQueue{less: less}
+}
+
+// Len implements heap.Interface
+func (q *refreshQueue) Len() int { return len(q.items) }
+
+// Less implements heap.Interface
+func (q *refreshQueue) Less(i, j int) bool { return q.less(q.items[i], q.items[j]) }
+
+// Swap implements heap.Interface
+func (q *refreshQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+// Push implements heap.Interface, use Enqueue instead
+func (q *refreshQueue) Push(x interface{}) {
+	q.items = append(q.items, x.(*database.Shipment))
+}
+
+// Pop implements heap.Interface, use Next instead
+func (q *refreshQueue) Pop() interface{} {
+	old := q.items
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	q.items = old[:n-1]
+	return item
+}
+
+// Enqueue adds shipments to the queue
+func (q *refreshQueue) Enqueue(shipments ...database.Shipment) {
+	for i := range shipments {
+		shipment := shipments[i]
+		heap.Push(q, &shipment)
+	}
+}
+
+// Next removes and returns the highest priority shipment, or false if the queue is empty
+func (q *refreshQueue) Next() (*database.Shipment, bool) {
+	if q.Len() == 0 {
+		return nil, false
+	}
+	return heap.Pop(q).(*database.Shipment), true
+}
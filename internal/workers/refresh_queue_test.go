@@ -0,0 +1,98 @@
+package workers
+
+import (
+	"testing"
+	"time"
+
+	"package-tracking/internal/database"
+)
+
+func TestRefreshPriority(t *testing.T) {
+	now := time.Now()
+	laterToday := now.Add(time.Minute)
+	nextWeek := now.AddDate(0, 0, 7)
+	recentRefresh := now.Add(-10 * time.Minute)
+	oldRefresh := now.Add(-2 * time.Hour)
+
+	tests := []struct {
+		name     string
+		shipment database.Shipment
+		expected int
+	}{
+		{
+			name:     "out for delivery",
+			shipment: database.Shipment{Status: "out_for_delivery", ExpectedDelivery: &nextWeek},
+			expected: priorityOutForDelivery,
+		},
+		{
+			name:     "expected delivery today",
+			shipment: database.Shipment{Status: "in_transit", ExpectedDelivery: &laterToday, LastAutoRefresh: &recentRefresh},
+			expected: priorityDeliveryToday,
+		},
+		{
+			name:     "stale in transit",
+			shipment: database.Shipment{Status: "in_transit", LastAutoRefresh: &oldRefresh},
+			expected: priorityStaleInTransit,
+		},
+		{
+			name:     "never refreshed in transit",
+			shipment: database.Shipment{Status: "in_transit"},
+			expected: priorityStaleInTransit,
+		},
+		{
+			name:     "recently refreshed in transit",
+			shipment: database.Shipment{Status: "in_transit", LastAutoRefresh: &recentRefresh},
+			expected: priorityDefault,
+		},
+		{
+			name:     "pending",
+			shipment: database.Shipment{Status: "pending"},
+			expected: priorityDefault,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := refreshPriority(&tt.shipment, now, time.Hour)
+			if got != tt.expected {
+				t.Errorf("Expected priority %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRefreshQueue_Order(t *testing.T) {
+	now := time.Now()
+	laterToday := now.Add(time.Minute)
+	oldRefresh := now.Add(-3 * time.Hour)
+	olderRefresh := now.Add(-5 * time.Hour)
+	recentRefresh := now.Add(-10 * time.Minute)
+
+	queue := newRefreshQueue(refreshPriorityLess(now, time.Hour))
+	queue.Enqueue(
+		database.Shipment{ID: 1, Status: "pending"},
+		database.Shipment{ID: 2, Status: "in_transit", LastAutoRefresh: &oldRefresh},
+		database.Shipment{ID: 3, Status: "in_transit", ExpectedDelivery: &laterToday, LastAutoRefresh: &recentRefresh},
+		database.Shipment{ID: 4, Status: "out_for_delivery", LastAutoRefresh: &recentRefresh},
+		database.Shipment{ID: 5, Status: "in_transit", LastAutoRefresh: &olderRefresh},
+	)
+
+	if queue.Len() != 5 {
+		t.Fatalf("Expected 5 queued shipments, got %d", queue.Len())
+	}
+
+	expectedOrder := []int{4, 3, 5, 2, 1}
+	for i, id := range expectedOrder {
+		next, ok := queue.Next()
+		if !ok {
+			t.Fatalf("Queue exhausted at position %d", i)
+		}
+		if next.ID != id {
+			t.Errorf("Position %d: expected shipment %d, got %d", i, id, next.ID)
+		}
+	}
+
+	if _, ok := queue.Next(); ok {
+		t.Error("Expected queue to be empty")
+	}
+}
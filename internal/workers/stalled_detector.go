@@ -0,0 +1,221 @@
+package workers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"package-tracking/internal/config"
+	"package-tracking/internal/database"
+)
+
+// StalledShipment describes a shipment that has just been flagged as stalled
+type StalledShipment struct {
+	ID             int        `json:"id"`
+	TrackingNumber string     `json:"tracking_number"`
+	Carrier        string     `json:"carrier"`
+	Description    string     `json:"description"`
+	Status         string     `json:"status"`
+	LastEventAt    *time.Time `json:"last_event_at,omitempty"`
+	StalledSince   time.Time  `json:"stalled_since"`
+	ThresholdDays  int        `json:"threshold_days"`
+}
+
+// StalledDetectionReport describes the outcome of a single stalled shipment scan
+type StalledDetectionReport struct {
+	RanAt        time.Time         `json:"ran_at"`
+	Checked      int               `json:"checked"`
+	NewlyStalled []StalledShipment `json:"newly_stalled"`
+	Cleared      int               `json:"cleared"`
+}
+
+// StalledShipmentDetector periodically flags shipments that have gone too long without a
+// new tracking event, so lost packages are noticed while a claim is still possible
+type StalledShipmentDetector struct {
+	ctx           context.Context
+	cancel        context.CancelFunc
+	config        *config.Config
+	shipmentStore *database.ShipmentStore
+	logger        *slog.Logger
+	client        *http.Client
+	loopDone      chan struct{}
+
+	// runMu serializes scans so a slow webhook can't overlap the next run
+	runMu sync.Mutex
+}
+
+// NewStalledShipmentDetector creates a new stalled shipment detector
+func NewStalledShipmentDetector(cfg *config.Config, shipmentStore *database.ShipmentStore, logger *slog.Logger) *StalledShipmentDetector {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &StalledShipmentDetector{
+		ctx:           ctx,
+		cancel:        cancel,
+		config:        cfg,
+		shipmentStore: shipmentStore,
+		logger:        logger,
+		client:        &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Start begins the periodic stalled shipment scan
+func (d *StalledShipmentDetector) Start() {
+	if !d.config.StalledDetectionEnabled {
+		d.logger.Info("Stalled shipment detection is disabled")
+		return
+	}
+
+	d.logger.Info("Starting stalled shipment detector",
+		"interval", d.config.StalledCheckInterval,
+		"threshold_days", d.config.StalledThresholdDays,
+		"carrier_overrides", d.config.StalledCarrierThresholdDays,
+		"webhook_configured", d.config.StalledWebhookURL != "")
+
+	d.loopDone = make(chan struct{})
+	go d.detectLoop()
+}
+
+// Stop stops the periodic scan, waiting for a running scan to finish
+func (d *StalledShipmentDetector) Stop() {
+	d.logger.Info("Stopping stalled shipment detector")
+	d.cancel()
+
+	if d.loopDone != nil && !waitForDrain(d.loopDone, defaultDrainTimeout) {
+		d.logger.Warn("Timed out waiting for stalled shipment scan to finish")
+	}
+}
+
+// detectLoop scans once at startup and then on the configured interval
+func (d *StalledShipmentDetector) detectLoop() {
+	defer close(d.loopDone)
+
+	ticker := time.NewTicker(d.config.StalledCheckInterval)
+	defer ticker.Stop()
+
+	if _, err := d.RunOnce(time.Now()); err != nil {
+		d.logger.Error("Stalled shipment scan failed", "error", err)
+	}
+
+	for {
+		select {
+		case <-d.ctx.Done():
+			d.logger.Info("Stalled shipment detector stopped")
+			return
+
+		case <-ticker.C:
+			if _, err := d.RunOnce(time.Now()); err != nil {
+				d.logger.Error("Stalled shipment scan failed", "error", err)
+			}
+		}
+	}
+}
+
+// RunOnce flags shipments without a new event within their carrier's threshold as stalled
+// and clears the flag from shipments that have since moved or been delivered
+func (d *StalledShipmentDetector) RunOnce(now time.Time) (*StalledDetectionReport, error) {
+	d.runMu.Lock()
+	defer d.runMu.Unlock()
+
+	candidates, err := d.shipmentStore.GetStalledCandidates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load stalled shipment candidates: %w", err)
+	}
+
+	report := &StalledDetectionReport{
+		RanAt:        now,
+		Checked:      len(candidates),
+		NewlyStalled: []StalledShipment{},
+	}
+
+	for _, c := range candidates {
+		lastActivity := c.CreatedAt
+		if c.LastEventAt != nil {
+			lastActivity = *c.LastEventAt
+		}
+
+		threshold := d.config.StalledThresholdFor(strings.ToLower(c.Carrier))
+		stalled := !c.IsDelivered && now.Sub(lastActivity) > time.Duration(threshold)*24*time.Hour
+
+		switch {
+		case stalled && !c.IsStalled:
+			if err := d.shipmentStore.SetStalled(c.ID, true, now); err != nil {
+				d.logger.Error("Failed to flag shipment as stalled", "shipment_id", c.ID, "error", err)
+				continue
+			}
+			report.NewlyStalled = append(report.NewlyStalled, StalledShipment{
+				ID:             c.ID,
+				TrackingNumber: c.TrackingNumber,
+				Carrier:        c.Carrier,
+				Description:    c.Description,
+				Status:         c.Status,
+				LastEventAt:    c.LastEventAt,
+				StalledSince:   now,
+				ThresholdDays:  threshold,
+			})
+			d.logger.Warn("Shipment appears stalled",
+				"shipment_id", c.ID,
+				"tracking_number", c.TrackingNumber,
+				"carrier", c.Carrier,
+				"last_activity", lastActivity,
+				"threshold_days", threshold)
+
+		case !stalled && c.IsStalled:
+			if err := d.shipmentStore.SetStalled(c.ID, false, time.Time{}); err != nil {
+				d.logger.Error("Failed to clear stalled flag", "shipment_id", c.ID, "error", err)
+				continue
+			}
+			report.Cleared++
+		}
+	}
+
+	if len(report.NewlyStalled) > 0 || report.Cleared > 0 {
+		d.logger.Info("Stalled shipment scan completed",
+			"checked", report.Checked,
+			"newly_stalled", len(report.NewlyStalled),
+			"cleared", report.Cleared)
+	}
+
+	if len(report.NewlyStalled) > 0 && d.config.StalledWebhookURL != "" {
+		if err := d.notify(report); err != nil {
+			// Notification is best effort; the flags are already persisted for the dashboard
+			d.logger.Error("Failed to send stalled shipment notification", "error", err)
+		}
+	}
+
+	return report, nil
+}
+
+// notify posts the newly stalled shipments to the configured webhook
+func (d *StalledShipmentDetector) notify(report *StalledDetectionReport) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"event":     "shipments_stalled",
+		"timestamp": report.RanAt,
+		"shipments": report.NewlyStalled,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodPost, d.config.StalledWebhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("notification request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notification webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
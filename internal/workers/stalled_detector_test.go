@@ -0,0 +1,101 @@
+package workers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"package-tracking/internal/config"
+	"package-tracking/internal/database"
+)
+
+func TestStalledShipmentDetector_RunOnce(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	var notified []StalledShipment
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Shipments []StalledShipment `json:"shipments"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode webhook payload: %v", err)
+		}
+		notified = append(notified, payload.Shipments...)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		StalledDetectionEnabled:     true,
+		StalledCheckInterval:        6 * time.Hour,
+		StalledThresholdDays:        7,
+		StalledCarrierThresholdDays: map[string]int{"dhl": 14},
+		StalledWebhookURL:           server.URL,
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	detector := NewStalledShipmentDetector(cfg, db.Shipments, logger)
+
+	ups := &database.Shipment{TrackingNumber: "1Z999AA10123456784", Carrier: "ups", Description: "UPS Package", Status: "in_transit"}
+	dhl := &database.Shipment{TrackingNumber: "1234567890", Carrier: "dhl", Description: "DHL Package", Status: "in_transit"}
+	for _, s := range []*database.Shipment{ups, dhl} {
+		if err := db.Shipments.Create(s); err != nil {
+			t.Fatalf("Failed to create shipment: %v", err)
+		}
+	}
+
+	// Both shipments last moved ten days ago: past the default threshold but not DHL's
+	lastEvent := time.Now().AddDate(0, 0, -10)
+	for _, s := range []*database.Shipment{ups, dhl} {
+		event := &database.TrackingEvent{ShipmentID: s.ID, Timestamp: lastEvent, Location: "Memphis, TN", Status: "in_transit", Description: "In transit"}
+		if err := db.TrackingEvents.CreateEvent(event); err != nil {
+			t.Fatalf("Failed to create event: %v", err)
+		}
+	}
+
+	report, err := detector.RunOnce(time.Now())
+	if err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+	if len(report.NewlyStalled) != 1 || report.NewlyStalled[0].ID != ups.ID {
+		t.Fatalf("Expected only the UPS shipment to stall, got %+v", report.NewlyStalled)
+	}
+	if len(notified) != 1 || notified[0].TrackingNumber != ups.TrackingNumber {
+		t.Errorf("Expected webhook notification for the UPS shipment, got %+v", notified)
+	}
+
+	// A second run doesn't report or notify about shipments already flagged
+	report, err = detector.RunOnce(time.Now())
+	if err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+	if len(report.NewlyStalled) != 0 || len(notified) != 1 {
+		t.Errorf("Expected no new stalled shipments, got %+v", report.NewlyStalled)
+	}
+
+	// New activity clears the flag
+	event := &database.TrackingEvent{ShipmentID: ups.ID, Timestamp: time.Now(), Location: "Louisville, KY", Status: "in_transit", Description: "Arrived at facility"}
+	if err := db.TrackingEvents.CreateEvent(event); err != nil {
+		t.Fatalf("Failed to create event: %v", err)
+	}
+
+	report, err = detector.RunOnce(time.Now())
+	if err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+	if report.Cleared != 1 {
+		t.Errorf("Expected 1 cleared shipment, got %d", report.Cleared)
+	}
+
+	stored, err := db.Shipments.GetByID(ups.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if stored.IsStalled {
+		t.Error("Expected stalled flag to be cleared after new activity")
+	}
+}
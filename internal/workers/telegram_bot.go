@@ -0,0 +1,271 @@
+package workers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"package-tracking/internal/config"
+	"package-tracking/internal/database"
+	"package-tracking/internal/services"
+	"package-tracking/internal/telegram"
+)
+
+const (
+	// telegramPollTimeout is how long each getUpdates call waits for a message
+	telegramPollTimeout = 30 * time.Second
+	// telegramRetryDelay is the wait after a failed poll
+	telegramRetryDelay = 5 * time.Second
+	// telegramListLimit caps the shipments /list replies with
+	telegramListLimit = 20
+	// telegramStatusEvents is how many recent events /status shows
+	telegramStatusEvents = 3
+)
+
+// telegramHelp is the reply to /start, /help and unknown commands
+const telegramHelp = `Commands:
+/add <tracking number> <carrier> [description] - track a shipment
+/list - list shipments that haven't been delivered
+/status <id> - show a shipment and its latest events`
+
+// TelegramBot answers commands sent to the Telegram bot from the configured chats, so
+// shipments can be added and checked from a phone. Notifications are sent by the
+// notification dispatcher, not the bot.
+type TelegramBot struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	config   *config.Config
+	client   *telegram.Client
+	db       *database.DB
+	allowed  map[int64]bool
+	logger   *slog.Logger
+	loopDone chan struct{}
+}
+
+// NewTelegramBot creates a bot that polls for commands with the configured token
+func NewTelegramBot(cfg *config.Config, db *database.DB, logger *slog.Logger) *TelegramBot {
+	ctx, cancel := context.WithCancel(context.Background())
+	allowed := make(map[int64]bool, len(cfg.NotificationTelegram.ChatIDs))
+	for _, id := range cfg.NotificationTelegram.ChatIDs {
+		allowed[id] = true
+	}
+	return &TelegramBot{
+		ctx:     ctx,
+		cancel:  cancel,
+		config:  cfg,
+		client:  telegram.NewClient(cfg.NotificationTelegram.BotToken),
+		db:      db,
+		allowed: allowed,
+		logger:  logger,
+	}
+}
+
+// Start begins polling for commands
+func (b *TelegramBot) Start() {
+	if !b.config.NotificationTelegram.CommandsEnabled {
+		b.logger.Info("Telegram bot commands are disabled")
+		return
+	}
+
+	b.logger.Info("Starting Telegram bot", "authorized_chats", len(b.allowed))
+	b.loopDone = make(chan struct{})
+	go b.pollLoop()
+}
+
+// Stop stops polling, waiting for the command being handled to finish
+func (b *TelegramBot) Stop() {
+	b.logger.Info("Stopping Telegram bot")
+	b.cancel()
+
+	if b.loopDone != nil && !waitForDrain(b.loopDone, defaultDrainTimeout) {
+		b.logger.Warn("Timed out waiting for Telegram bot to stop")
+	}
+}
+
+// pollLoop long polls for messages and replies to each until the bot is stopped
+func (b *TelegramBot) pollLoop() {
+	defer close(b.loopDone)
+
+	var offset int64
+	for {
+		updates, err := b.client.GetUpdates(b.ctx, offset, telegramPollTimeout)
+		if b.ctx.Err() != nil {
+			b.logger.Info("Telegram bot stopped")
+			return
+		}
+		if err != nil {
+			b.logger.Warn("Failed to poll Telegram for commands", "error", err)
+			select {
+			case <-b.ctx.Done():
+				return
+			case <-time.After(telegramRetryDelay):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			if update.Message == nil {
+				continue
+			}
+			reply := b.HandleMessage(*update.Message)
+			if reply == "" {
+				continue
+			}
+			if err := b.client.SendMessage(b.ctx, update.Message.Chat.ID, reply); err != nil {
+				b.logger.Warn("Failed to reply to Telegram command",
+					"chat_id", update.Message.Chat.ID,
+					"error", err)
+			}
+		}
+	}
+}
+
+// HandleMessage runs the command in msg and returns the reply, or "" for messages that
+// aren't commands. Commands from chats that aren't configured are refused.
+func (b *TelegramBot) HandleMessage(msg telegram.Message) string {
+	fields := strings.Fields(msg.Text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return ""
+	}
+	// In groups, commands may be addressed to the bot, e.g. /list@TrackerBot
+	command, _, _ := strings.Cut(strings.ToLower(fields[0]), "@")
+	args := fields[1:]
+
+	if !b.allowed[msg.Chat.ID] {
+		b.logger.Warn("Refused Telegram command from an unauthorized chat",
+			"chat_id", msg.Chat.ID,
+			"command", command)
+		return fmt.Sprintf("This chat isn't authorized to use this bot. Add %d to NOTIFICATIONS_TELEGRAM_CHAT_IDS to allow it.", msg.Chat.ID)
+	}
+
+	switch command {
+	case "/add":
+		return b.addShipment(args)
+	case "/list":
+		return b.listShipments()
+	case "/status":
+		return b.shipmentStatus(args)
+	default:
+		return telegramHelp
+	}
+}
+
+// addShipment handles /add <tracking number> <carrier> [description]
+func (b *TelegramBot) addShipment(args []string) string {
+	if len(args) < 2 {
+		return "Usage: /add <tracking number> <carrier> [description]"
+	}
+
+	shipment := database.Shipment{
+		TrackingNumber: args[0],
+		Carrier:        strings.ToLower(args[1]),
+		Description:    strings.Join(args[2:], " "),
+		Status:         "pending",
+	}
+	if shipment.Description == "" {
+		shipment.Description = shipment.TrackingNumber
+	}
+
+	// The same checks the API applies to new shipments
+	if err := services.ValidateShipment(&shipment); err != nil {
+		return "Can't add shipment: " + err.Error()
+	}
+
+	if err := b.db.Shipments.Create(&shipment); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return "Tracking number " + shipment.TrackingNumber + " is already tracked"
+		}
+		b.logger.Error("Failed to create shipment from Telegram", "error", err)
+		return "Failed to add shipment, please try again later"
+	}
+
+	b.logger.Info("Added shipment from Telegram",
+		"shipment_id", shipment.ID,
+		"carrier", shipment.Carrier)
+	return fmt.Sprintf("Added #%d: %s %s", shipment.ID, strings.ToUpper(shipment.Carrier), shipment.TrackingNumber)
+}
+
+// listShipments handles /list
+func (b *TelegramBot) listShipments() string {
+	shipments, err := b.db.Shipments.GetAll()
+	if err != nil {
+		b.logger.Error("Failed to list shipments for Telegram", "error", err)
+		return "Failed to list shipments, please try again later"
+	}
+
+	var lines []string
+	active := 0
+	for _, shipment := range shipments {
+		if shipment.IsDelivered {
+			continue
+		}
+		active++
+		if len(lines) < telegramListLimit {
+			lines = append(lines, fmt.Sprintf("#%d %s - %s (%s)",
+				shipment.ID, shipment.Description, humanizeTelegramStatus(shipment.Status), strings.ToUpper(shipment.Carrier)))
+		}
+	}
+
+	if active == 0 {
+		return "No shipments in transit"
+	}
+	if active > len(lines) {
+		lines = append(lines, fmt.Sprintf("…and %d more", active-len(lines)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// shipmentStatus handles /status <id>
+func (b *TelegramBot) shipmentStatus(args []string) string {
+	if len(args) != 1 {
+		return "Usage: /status <id>"
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+	if err != nil {
+		return "Invalid shipment ID: " + args[0]
+	}
+
+	shipment, err := b.db.Shipments.GetByID(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Sprintf("Shipment #%d not found", id)
+	}
+	if err != nil {
+		b.logger.Error("Failed to get shipment for Telegram", "shipment_id", id, "error", err)
+		return "Failed to get shipment, please try again later"
+	}
+
+	lines := []string{
+		fmt.Sprintf("#%d %s", shipment.ID, shipment.Description),
+		fmt.Sprintf("%s %s", strings.ToUpper(shipment.Carrier), shipment.TrackingNumber),
+		"Status: " + humanizeTelegramStatus(shipment.Status),
+	}
+	if shipment.ExpectedDelivery != nil {
+		lines = append(lines, "Expected: "+shipment.ExpectedDelivery.Format("Mon, Jan 2"))
+	}
+
+	events, err := b.db.TrackingEvents.GetByShipmentID(id)
+	if err != nil {
+		b.logger.Warn("Failed to get events for Telegram", "shipment_id", id, "error", err)
+	}
+	// Events are oldest first
+	for i := len(events) - 1; i >= 0 && i >= len(events)-telegramStatusEvents; i-- {
+		event := events[i]
+		line := event.Timestamp.Format("Jan 2 3:04 PM") + " - " + event.Description
+		if event.Location != "" {
+			line += " (" + event.Location + ")"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// humanizeTelegramStatus turns a status like "out_for_delivery" into "out for delivery"
+func humanizeTelegramStatus(status string) string {
+	return strings.ReplaceAll(status, "_", " ")
+}
@@ -0,0 +1,116 @@
+package workers
+
+import (
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"package-tracking/internal/config"
+	"package-tracking/internal/database"
+	"package-tracking/internal/telegram"
+)
+
+func newTestTelegramBot(t *testing.T) (*TelegramBot, *database.DB) {
+	db, cleanup := setupTestDB(t)
+	t.Cleanup(cleanup)
+
+	cfg := &config.Config{
+		NotificationTelegram: config.TelegramNotificationConfig{
+			BotToken:        "123:test",
+			ChatIDs:         []int64{42, -100},
+			CommandsEnabled: true,
+		},
+	}
+	return NewTelegramBot(cfg, db, slog.New(slog.NewTextHandler(io.Discard, nil))), db
+}
+
+func telegramCommand(chatID int64, text string) telegram.Message {
+	return telegram.Message{Chat: telegram.Chat{ID: chatID}, Text: text}
+}
+
+func TestTelegramBot_Add(t *testing.T) {
+	bot, db := newTestTelegramBot(t)
+
+	reply := bot.HandleMessage(telegramCommand(42, "/add 1Z999AA10123456784 UPS New headphones"))
+	if !strings.HasPrefix(reply, "Added #") {
+		t.Fatalf("reply = %q, want the shipment to be added", reply)
+	}
+
+	shipment, err := db.Shipments.GetByTrackingNumber("1Z999AA10123456784")
+	if err != nil {
+		t.Fatalf("Expected the shipment to be created: %v", err)
+	}
+	if shipment.Carrier != "ups" || shipment.Description != "New headphones" || shipment.Status != "pending" {
+		t.Errorf("Unexpected shipment: %+v", shipment)
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"duplicate", "/add 1Z999AA10123456784 ups", "already tracked"},
+		{"invalid carrier", "/add 123456 ontrac", "invalid carrier"},
+		{"invalid amazon number", "/add 12345 amazon", "invalid Amazon tracking number"},
+		{"missing carrier", "/add 123456", "Usage: /add"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if reply := bot.HandleMessage(telegramCommand(42, tt.text)); !strings.Contains(reply, tt.want) {
+				t.Errorf("reply = %q, want it to contain %q", reply, tt.want)
+			}
+		})
+	}
+}
+
+func TestTelegramBot_ListAndStatus(t *testing.T) {
+	bot, db := newTestTelegramBot(t)
+
+	active := &database.Shipment{TrackingNumber: "1Z999AA10123456784", Carrier: "ups", Description: "Headphones", Status: "in_transit"}
+	delivered := &database.Shipment{TrackingNumber: "9400111899223197428490", Carrier: "usps", Description: "Books", Status: "delivered", IsDelivered: true}
+	for _, s := range []*database.Shipment{active, delivered} {
+		if err := db.Shipments.Create(s); err != nil {
+			t.Fatalf("Failed to create shipment: %v", err)
+		}
+	}
+	event := &database.TrackingEvent{ShipmentID: active.ID, Timestamp: time.Now(), Location: "Memphis, TN", Status: "in_transit", Description: "Departed facility"}
+	if err := db.TrackingEvents.CreateEvent(event); err != nil {
+		t.Fatalf("Failed to create event: %v", err)
+	}
+
+	list := bot.HandleMessage(telegramCommand(-100, "/list@TrackerBot"))
+	if !strings.Contains(list, "Headphones - in transit (UPS)") || strings.Contains(list, "Books") {
+		t.Errorf("list = %q, want only the shipment in transit", list)
+	}
+
+	status := bot.HandleMessage(telegramCommand(42, "/status #"+strconv.Itoa(active.ID)))
+	if !strings.Contains(status, "Status: in transit") || !strings.Contains(status, "Departed facility (Memphis, TN)") {
+		t.Errorf("status = %q, want the status and latest event", status)
+	}
+
+	if reply := bot.HandleMessage(telegramCommand(42, "/status 999")); !strings.Contains(reply, "not found") {
+		t.Errorf("reply = %q, want not found", reply)
+	}
+}
+
+func TestTelegramBot_Authorization(t *testing.T) {
+	bot, db := newTestTelegramBot(t)
+
+	reply := bot.HandleMessage(telegramCommand(7, "/add 1Z999AA10123456784 ups"))
+	if !strings.Contains(reply, "isn't authorized") {
+		t.Errorf("reply = %q, want the chat to be refused", reply)
+	}
+	if _, err := db.Shipments.GetByTrackingNumber("1Z999AA10123456784"); err == nil {
+		t.Error("Expected no shipment to be created for an unauthorized chat")
+	}
+
+	if reply := bot.HandleMessage(telegramCommand(7, "hello")); reply != "" {
+		t.Errorf("reply = %q, want messages that aren't commands to be ignored", reply)
+	}
+	if reply := bot.HandleMessage(telegramCommand(42, "/start")); reply != telegramHelp {
+		t.Errorf("reply = %q, want the help", reply)
+	}
+}
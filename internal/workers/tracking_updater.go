@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"package-tracking/internal/carriers"
 	"package-tracking/internal/config"
 	"package-tracking/internal/database"
+	"package-tracking/internal/notifications"
 	"package-tracking/internal/ratelimit"
 )
 
@@ -20,26 +22,66 @@ type TrackingUpdater struct {
 	cancel         context.CancelFunc
 	config         *config.Config
 	shipmentStore  *database.ShipmentStore
+	quotaStore     *database.QuotaStore
 	carrierFactory *carriers.ClientFactory
 	cache          *cache.Manager
 	paused         atomic.Bool
 	logger         *slog.Logger
+
+	// notifier receives the status changes found by updates; nil drops them
+	notifier *notifications.Dispatcher
+	// escalator receives the expected delivery changes found by updates; nil drops them
+	escalator *EscalationWorker
+
+	// callCtx governs in-flight carrier calls so they can finish while the updater
+	// drains after ctx has been cancelled
+	callCtx     context.Context
+	callCancel  context.CancelFunc
+	loopDone    chan struct{}
+	reconfigure chan struct{}
+	stopOnce    sync.Once
+	drain       drainTracker
+	drainReport DrainReport
+
+	// statusMu guards the run bookkeeping reported by Status
+	statusMu      sync.Mutex
+	lastRun       *time.Time
+	nextRun       *time.Time
+	carrierStates map[string]*carrierState
 }
 
 // NewTrackingUpdater creates a new tracking updater service
-func NewTrackingUpdater(cfg *config.Config, shipmentStore *database.ShipmentStore, carrierFactory *carriers.ClientFactory, cacheManager *cache.Manager, logger *slog.Logger) *TrackingUpdater {
+func NewTrackingUpdater(cfg *config.Config, shipmentStore *database.ShipmentStore, quotaStore *database.QuotaStore, carrierFactory *carriers.ClientFactory, cacheManager *cache.Manager, logger *slog.Logger) *TrackingUpdater {
 	ctx, cancel := context.WithCancel(context.Background())
+	callCtx, callCancel := context.WithCancel(context.Background())
 	return &TrackingUpdater{
 		ctx:            ctx,
 		cancel:         cancel,
+		callCtx:        callCtx,
+		callCancel:     callCancel,
 		config:         cfg,
 		shipmentStore:  shipmentStore,
+		quotaStore:     quotaStore,
 		carrierFactory: carrierFactory,
 		cache:          cacheManager,
 		logger:         logger,
+		carrierStates:  make(map[string]*carrierState),
+		reconfigure:    make(chan struct{}, 1),
 	}
 }
 
+// SetNotifier sets the dispatcher that status changes found by updates are reported to. It
+// must be called before Start.
+func (u *TrackingUpdater) SetNotifier(notifier *notifications.Dispatcher) {
+	u.notifier = notifier
+}
+
+// SetEscalator sets the escalation worker that expected delivery changes found by updates
+// are reported to. It must be called before Start.
+func (u *TrackingUpdater) SetEscalator(escalator *EscalationWorker) {
+	u.escalator = escalator
+}
+
 // Start begins the background update process
 func (u *TrackingUpdater) Start() {
 	if !u.config.AutoUpdateEnabled {
@@ -52,13 +94,40 @@ func (u *TrackingUpdater) Start() {
 		"cutoff_days", u.config.AutoUpdateCutoffDays,
 		"batch_size", u.config.AutoUpdateBatchSize)
 	
+	u.loopDone = make(chan struct{})
 	go u.updateLoop()
 }
 
-// Stop gracefully stops the background update process
+// Stop gracefully stops the background update process, letting in-flight carrier calls
+// finish and persisting partial batch results before returning
 func (u *TrackingUpdater) Stop() {
-	u.logger.Info("Stopping tracking updater service")
-	u.cancel()
+	u.StopWithTimeout(defaultDrainTimeout)
+}
+
+// StopWithTimeout stops the updater and waits up to timeout for in-flight work to drain.
+// Carrier calls still running when the timeout elapses are abandoned. Subsequent calls
+// return the report from the first stop.
+func (u *TrackingUpdater) StopWithTimeout(timeout time.Duration) DrainReport {
+	u.stopOnce.Do(func() {
+		u.logger.Info("Stopping tracking updater service", "drain_timeout", timeout)
+		startTime := time.Now()
+		u.cancel()
+
+		timedOut := false
+		if u.loopDone != nil && !waitForDrain(u.loopDone, timeout) {
+			timedOut = true
+			// Cut off in-flight carrier calls and give the loop a moment to persist its partial batch
+			u.callCancel()
+			waitForDrain(u.loopDone, drainFlushGrace)
+		}
+		u.callCancel()
+
+		u.drainReport = u.drain.snapshot()
+		u.drainReport.Duration = time.Since(startTime)
+		u.drainReport.TimedOut = timedOut
+		logDrainReport(u.logger, "tracking_updater", u.drainReport)
+	})
+	return u.drainReport
 }
 
 // Pause temporarily pauses automatic updates
@@ -73,6 +142,15 @@ func (u *TrackingUpdater) Resume() {
 	u.logger.Info("Tracking updater resumed")
 }
 
+// Reconfigure reschedules the next update after the update interval in the configuration
+// has changed. Other settings are read at the start of each update.
+func (u *TrackingUpdater) Reconfigure() {
+	select {
+	case u.reconfigure <- struct{}{}:
+	default:
+	}
+}
+
 // IsPaused returns true if the updater is currently paused
 func (u *TrackingUpdater) IsPaused() bool {
 	return u.paused.Load()
@@ -90,12 +168,15 @@ func (u *TrackingUpdater) IsRunning() bool {
 
 // updateLoop is the main background loop that performs periodic updates
 func (u *TrackingUpdater) updateLoop() {
+	defer close(u.loopDone)
+
 	ticker := time.NewTicker(u.config.UpdateInterval)
 	defer ticker.Stop()
 
 	// Perform initial update after a short delay
 	initialDelay := time.NewTimer(30 * time.Second)
 	defer initialDelay.Stop()
+	u.setNextRun(time.Now().Add(30 * time.Second))
 
 	for {
 		select {
@@ -104,12 +185,22 @@ func (u *TrackingUpdater) updateLoop() {
 			return
 
 		case <-initialDelay.C:
-			// Perform first update
-			u.performUpdates()
+			// Run a prioritized catch-up pass if the server was down for a while,
+			// otherwise perform the first regular update
+			if !u.runCatchUpIfNeeded() {
+				u.performUpdates()
+			}
+			u.setNextRun(time.Now().Add(u.config.UpdateInterval))
 
 		case <-ticker.C:
 			// Perform periodic updates
 			u.performUpdates()
+			u.setNextRun(time.Now().Add(u.config.UpdateInterval))
+
+		case <-u.reconfigure:
+			ticker.Reset(u.config.UpdateInterval)
+			u.setNextRun(time.Now().Add(u.config.UpdateInterval))
+			u.logger.Info("Tracking updater rescheduled", "interval", u.config.UpdateInterval)
 		}
 	}
 }
@@ -124,25 +215,33 @@ func (u *TrackingUpdater) performUpdates() {
 	u.logger.Info("Starting automatic tracking updates")
 	startTime := time.Now()
 
-	// Update USPS shipments
-	u.updateUSPSShipments()
+	// Collect eligible shipments from all carriers into a single priority queue so that
+	// out-for-delivery and due-today shipments are refreshed before anything else
+	queue := newRefreshQueue(refreshPriorityLess(startTime, u.config.UpdateInterval))
+
+	// Queue USPS shipments
+	u.queueUSPSShipments(queue)
 	
-	// Update UPS shipments if enabled
+	// Queue UPS shipments if enabled
 	if u.config.UPSAutoUpdateEnabled {
-		u.updateUPSShipments()
+		u.queueUPSShipments(queue)
 	}
 	
-	// Update DHL shipments if enabled
+	// Queue DHL shipments if enabled
 	if u.config.DHLAutoUpdateEnabled {
-		u.updateDHLShipments()
+		u.queueDHLShipments(queue)
 	}
 
+	// Process shipments in priority order with unified cache-based rate limiting
+	u.processShipmentsWithCache(queue, defaultAPICallDelay)
+	u.markRun(u.enabledCarriers(), time.Now())
+
 	duration := time.Since(startTime)
 	u.logger.Info("Completed automatic tracking updates", "duration", duration)
 }
 
-// updateUSPSShipments updates all eligible USPS shipments
-func (u *TrackingUpdater) updateUSPSShipments() {
+// queueUSPSShipments adds all eligible USPS shipments to the refresh queue
+func (u *TrackingUpdater) queueUSPSShipments(queue *refreshQueue) {
 	cutoffDate := time.Now().AddDate(0, 0, -u.config.AutoUpdateCutoffDays)
 	
 	u.logger.Debug("Fetching USPS shipments for auto-update",
@@ -162,14 +261,11 @@ func (u *TrackingUpdater) updateUSPSShipments() {
 
 	u.logger.Info("Found USPS shipments for auto-update", "count", len(shipments))
 
-	u.logger.Info("Processing USPS shipments with cache-aware rate limiting", "count", len(shipments))
-
-	// Process shipments with unified cache-based rate limiting
-	u.processShipmentsWithCache(shipments)
+	queue.Enqueue(shipments...)
 }
 
-// updateUPSShipments updates all eligible UPS shipments
-func (u *TrackingUpdater) updateUPSShipments() {
+// queueUPSShipments adds all eligible UPS shipments to the refresh queue
+func (u *TrackingUpdater) queueUPSShipments(queue *refreshQueue) {
 	// Use UPS-specific cutoff days if configured, otherwise use global setting
 	cutoffDays := u.config.UPSAutoUpdateCutoffDays
 	if cutoffDays == 0 {
@@ -195,12 +291,11 @@ func (u *TrackingUpdater) updateUPSShipments() {
 
 	u.logger.Info("Found UPS shipments for auto-update", "count", len(shipments))
 
-	// Process shipments with unified cache-based rate limiting
-	u.processShipmentsWithCache(shipments)
+	queue.Enqueue(shipments...)
 }
 
-// updateDHLShipments updates all eligible DHL shipments
-func (u *TrackingUpdater) updateDHLShipments() {
+// queueDHLShipments adds all eligible DHL shipments to the refresh queue
+func (u *TrackingUpdater) queueDHLShipments(queue *refreshQueue) {
 	// Use DHL-specific cutoff days if configured, otherwise use global setting
 	cutoffDays := u.config.DHLAutoUpdateCutoffDays
 	if cutoffDays == 0 {
@@ -229,98 +324,230 @@ func (u *TrackingUpdater) updateDHLShipments() {
 	// Check for rate limit warning (80% of 250 daily limit = 200 calls)
 	u.checkDHLRateLimitWarning(shipments)
 
-	// Process shipments with unified cache-based rate limiting
-	u.processShipmentsWithCache(shipments)
+	queue.Enqueue(shipments...)
+}
+
+// defaultAPICallDelay is the pause between carrier API calls during regular update cycles
+const defaultAPICallDelay = 1 * time.Second
+
+// processingStats summarizes the outcome of processing a list of shipments
+type processingStats struct {
+	Total       int
+	APICalls    int
+	CacheHits   int
+	RateLimited int
+	Deferred    int // Left for a later cycle to stay within the carrier's daily API budget
+	Failures    int
 }
 
-// processShipmentsWithCache processes shipments with cache-aware rate limiting
+// processShipmentsWithCache drains the refresh queue in priority order with cache-aware rate limiting
 // This replaces the old filterRecentlyRefreshed approach with unified cache-based logic
-func (u *TrackingUpdater) processShipmentsWithCache(shipments []database.Shipment) {
-	apiCallCount := 0
+func (u *TrackingUpdater) processShipmentsWithCache(queue *refreshQueue, delay time.Duration) processingStats {
+	stats := processingStats{Total: queue.Len()}
+
+	// Results are written in batches so the updater holds the database write lock
+	// once per batch instead of once per shipment
+	batchSize := u.config.AutoUpdateBatchSize
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	batch := make([]database.AutoUpdateResult, 0, batchSize)
+	defer func() {
+		u.applyAutoUpdateBatch(batch)
+	}()
+
+	// Carriers with a daily API budget only get their share of the remaining budget per cycle
+	allowances := u.budgetAllowances(time.Now())
 	
-	for i, shipment := range shipments {
+	for processed := 1; ; processed++ {
 		if u.ctx.Err() != nil {
-			return // Service is stopping
+			// Service is stopping, leave the rest for the next run
+			u.drain.requeued(queue.Len())
+			return stats
+		}
+
+		next, ok := queue.Next()
+		if !ok {
+			break
+		}
+		shipment := *next
+
+		// Leave shipments for carriers that recently rate limited us until their backoff expires
+		if u.inBackoff(shipment.Carrier, time.Now()) {
+			u.logger.Debug("Skipping shipment while carrier is backing off",
+				"shipment_id", shipment.ID,
+				"carrier", shipment.Carrier)
+			stats.RateLimited++
+			continue
 		}
 
 		u.logger.Debug("Processing shipment",
 			"shipment_id", shipment.ID,
 			"tracking_number", shipment.TrackingNumber,
-			"progress", fmt.Sprintf("%d/%d", i+1, len(shipments)))
+			"status", shipment.Status,
+			"progress", fmt.Sprintf("%d/%d", processed, stats.Total))
 
 		// Check cache first (same as manual refresh)
 		if cachedResponse, err := u.cache.Get(shipment.ID); err == nil && cachedResponse != nil {
 			u.logger.Debug("Using cached data for auto-update",
 				"shipment_id", shipment.ID,
 				"cache_age", time.Since(cachedResponse.UpdatedAt))
-			u.processCachedResponse(&shipment, cachedResponse)
+			batch = append(batch, u.processCachedResponse(&shipment, cachedResponse))
+			u.recordCacheHit(shipment.Carrier)
+			stats.CacheHits++
+			batch = u.flushAutoUpdateBatch(batch, batchSize)
 			continue
 		}
 
 		// Check rate limiting using unified logic (no force refresh for auto-updates)
-		rateLimitResult := ratelimit.CheckRefreshRateLimit(u.config, shipment.LastManualRefresh, false)
+		lastManualRefresh := u.cache.ManualRefreshTime(shipment.ID, shipment.LastManualRefresh)
+		rateLimitResult := ratelimit.CheckRefreshRateLimit(u.config, lastManualRefresh, false)
 		if rateLimitResult.ShouldBlock {
 			u.logger.Debug("Skipping shipment due to rate limiting",
 				"shipment_id", shipment.ID,
-				"last_manual_refresh", shipment.LastManualRefresh,
+				"last_manual_refresh", lastManualRefresh,
 				"remaining_time", rateLimitResult.RemainingTime,
 				"reason", rateLimitResult.Reason)
+			stats.RateLimited++
 			continue
 		}
 
+		// Defer the shipment if this cycle's share of the carrier's daily budget is used up.
+		// The queue is in priority order, so the most urgent shipments get the budget first.
+		if allowance, budgeted := allowances[shipment.Carrier]; budgeted {
+			if allowance <= 0 {
+				u.logger.Debug("Deferring shipment to stay within carrier daily API budget",
+					"shipment_id", shipment.ID,
+					"carrier", shipment.Carrier)
+				stats.Deferred++
+				continue
+			}
+			allowances[shipment.Carrier] = allowance - 1
+		}
+
 		// Proceed with API call and cache the result
-		u.performAPICallAndCache(&shipment)
-		apiCallCount++
+		result, err := u.performAPICallAndCache(&shipment)
+		if u.ctx.Err() != nil {
+			if u.callCtx.Err() != nil {
+				// The drain timeout cut this call off, so don't count it against the shipment
+				u.drain.abandoned(1)
+				u.drain.requeued(queue.Len())
+				return stats
+			}
+			u.drain.completed(1)
+		}
+		batch = append(batch, result)
+		u.recordResult(shipment.Carrier, err, time.Now())
+		if err != nil {
+			stats.Failures++
+		}
+		stats.APICalls++
+		batch = u.flushAutoUpdateBatch(batch, batchSize)
 
 		// Add delay between API calls to be respectful to the carrier API
 		// Only delay if there are more shipments to process
-		if i < len(shipments)-1 {
+		if queue.Len() > 0 {
 			select {
 			case <-u.ctx.Done():
-				return
-			case <-time.After(1 * time.Second):
+				u.drain.requeued(queue.Len())
+				return stats
+			case <-time.After(delay):
 				// Continue
 			}
 		}
 	}
 
 	u.logger.Info("Completed shipment processing",
-		"total_shipments", len(shipments),
-		"api_calls_made", apiCallCount,
-		"cache_hits", len(shipments)-apiCallCount)
+		"total_shipments", stats.Total,
+		"api_calls_made", stats.APICalls,
+		"cache_hits", stats.CacheHits,
+		"rate_limited", stats.RateLimited,
+		"deferred_for_budget", stats.Deferred,
+		"failures", stats.Failures)
+
+	return stats
 }
 
 // processCachedResponse processes a shipment using cached data
-func (u *TrackingUpdater) processCachedResponse(shipment *database.Shipment, cachedResponse *database.RefreshResponse) {
-	// Update shipment's auto-refresh timestamp to indicate it was processed
-	// but don't increment counts since this is using cached data
-	err := u.shipmentStore.UpdateAutoRefreshTracking(int64(shipment.ID), true, "")
-	if err != nil {
-		u.logger.Error("Failed to update auto-refresh tracking for cached response",
-			"shipment_id", shipment.ID,
-			"error", err)
-	} else {
-		u.logger.Info("Processed shipment using cached data",
-			"shipment_id", shipment.ID,
-			"tracking_number", shipment.TrackingNumber,
-			"cached_events", len(cachedResponse.Events))
+func (u *TrackingUpdater) processCachedResponse(shipment *database.Shipment, cachedResponse *database.RefreshResponse) database.AutoUpdateResult {
+	// Only touch the auto-refresh bookkeeping to indicate the shipment was processed;
+	// the shipment data itself is unchanged since this is using cached data
+	u.logger.Info("Processed shipment using cached data",
+		"shipment_id", shipment.ID,
+		"tracking_number", shipment.TrackingNumber,
+		"cached_events", len(cachedResponse.Events))
+
+	return database.AutoUpdateResult{ShipmentID: shipment.ID, Success: true}
+}
+
+// flushAutoUpdateBatch writes the pending results once the batch is full and returns the
+// batch to keep accumulating into
+func (u *TrackingUpdater) flushAutoUpdateBatch(batch []database.AutoUpdateResult, batchSize int) []database.AutoUpdateResult {
+	if len(batch) < batchSize {
+		return batch
+	}
+	u.applyAutoUpdateBatch(batch)
+	return batch[:0]
+}
+
+// applyAutoUpdateBatch writes a batch of results in a single transaction. If the batch fails
+// it falls back to writing results one at a time so a single bad row doesn't lose the batch.
+func (u *TrackingUpdater) applyAutoUpdateBatch(batch []database.AutoUpdateResult) {
+	if len(batch) == 0 {
+		return
+	}
+
+	err := u.shipmentStore.ApplyAutoUpdateBatch(batch)
+	if err == nil {
+		u.logger.Debug("Applied auto-update batch", "results", len(batch))
+		for _, result := range batch {
+			u.reportChanges(result)
+		}
+		return
+	}
+
+	u.logger.Warn("Failed to apply auto-update batch, falling back to individual writes",
+		"results", len(batch),
+		"error", err)
+
+	for _, result := range batch {
+		if err := u.shipmentStore.ApplyAutoUpdateBatch([]database.AutoUpdateResult{result}); err != nil {
+			u.logger.Error("Failed to apply auto-update result",
+				"shipment_id", result.ShipmentID,
+				"error", err)
+			continue
+		}
+		u.reportChanges(result)
+	}
+}
+
+// reportChanges reports a written result's status change and expected delivery change,
+// if it has them
+func (u *TrackingUpdater) reportChanges(result database.AutoUpdateResult) {
+	if result.Shipment == nil || result.PreviousStatus == "" {
+		return
+	}
+	u.escalator.ReportETAChange(*result.Shipment, result.PreviousExpectedDelivery)
+	if transition, changed := notifications.NewTransition(*result.Shipment, result.PreviousStatus, notifications.SourceAutoUpdate); changed {
+		transition.SetEvents(result.Events)
+		u.notifier.Notify(transition)
 	}
 }
 
-// performAPICallAndCache makes an API call and caches the result
-func (u *TrackingUpdater) performAPICallAndCache(shipment *database.Shipment) {
+// performAPICallAndCache makes an API call and caches the result. The returned result must be
+// applied to the database by the caller; the error is non-nil if the update failed.
+func (u *TrackingUpdater) performAPICallAndCache(shipment *database.Shipment) (database.AutoUpdateResult, error) {
 	// Create carrier client based on shipment carrier
-	client, _, err := u.carrierFactory.CreateClient(shipment.Carrier)
+	client, clientType, err := u.carrierFactory.CreateClient(shipment.Carrier)
 	if err != nil {
 		u.logger.Error("Failed to create carrier client", 
 			"carrier", shipment.Carrier,
 			"error", err)
-		u.handleUpdateError(shipment, err)
-		return
+		return u.failedUpdateResult(shipment, err), err
 	}
 
 	// Create tracking request with configurable timeout
-	ctx, cancel := context.WithTimeout(u.ctx, u.config.AutoUpdateIndividualTimeout)
+	ctx, cancel := context.WithTimeout(u.callCtx, u.config.AutoUpdateIndividualTimeout)
 	defer cancel()
 
 	req := &carriers.TrackingRequest{
@@ -328,19 +555,31 @@ func (u *TrackingUpdater) performAPICallAndCache(shipment *database.Shipment) {
 		Carrier:         shipment.Carrier,
 	}
 
-	// Make API call
-	resp, err := client.Track(ctx, req)
+	// Make API call, or share one already in flight for the shipment, e.g. a manual
+	// refresh. Only API clients count against the carrier's daily budget.
+	resp, shared, err := u.cache.Track(shipment.ID, func() (*carriers.TrackingResponse, error) {
+		resp, err := client.Track(ctx, req)
+		if clientType == carriers.ClientTypeAPI {
+			u.recordAPICall(shipment.Carrier, time.Now())
+		}
+		return resp, err
+	})
+	if shared {
+		u.logger.Debug("Shared an in-flight carrier lookup", "shipment_id", shipment.ID)
+	}
 	if err != nil {
-		u.handleUpdateError(shipment, err)
-		return
+		return u.failedUpdateResult(shipment, err), err
 	}
 
+	result := database.AutoUpdateResult{ShipmentID: shipment.ID, Success: true}
+
 	// Process the first result if available
 	if len(resp.Results) > 0 {
 		trackingInfo := &resp.Results[0]
 		
 		// Update shipment data
 		originalStatus := shipment.Status
+		originalExpectedDelivery := shipment.ExpectedDelivery
 		if trackingInfo.Status != "" && string(trackingInfo.Status) != shipment.Status {
 			shipment.Status = string(trackingInfo.Status)
 			shipment.IsDelivered = (trackingInfo.Status == carriers.StatusDelivered)
@@ -354,15 +593,12 @@ func (u *TrackingUpdater) performAPICallAndCache(shipment *database.Shipment) {
 			shipment.ExpectedDelivery = trackingInfo.ActualDelivery
 		}
 
-		// Atomically update shipment and auto-refresh tracking
-		err = u.shipmentStore.UpdateShipmentWithAutoRefresh(shipment.ID, shipment, true, "")
-		if err != nil {
-			u.logger.Error("Failed to update shipment with auto-refresh tracking",
-				"shipment_id", shipment.ID,
-				"error", err)
-			u.handleUpdateError(shipment, err)
-			return
-		}
+		// Status changes and events are written with the rest of the batch
+		events := u.convertToTrackingEvents(trackingInfo.Events)
+		result.Shipment = shipment
+		result.PreviousStatus = originalStatus
+		result.PreviousExpectedDelivery = originalExpectedDelivery
+		result.Events = events
 
 		// Cache the response for future manual refreshes
 		refreshResponse := &database.RefreshResponse{
@@ -370,7 +606,7 @@ func (u *TrackingUpdater) performAPICallAndCache(shipment *database.Shipment) {
 			UpdatedAt:       time.Now(),
 			EventsAdded:     len(trackingInfo.Events),
 			TotalEvents:     len(trackingInfo.Events),
-			Events:          u.convertToTrackingEvents(trackingInfo.Events),
+			Events:          events,
 		}
 
 		// Populate cache (same as manual refresh)
@@ -382,7 +618,7 @@ func (u *TrackingUpdater) performAPICallAndCache(shipment *database.Shipment) {
 			// Don't fail the update just because caching failed
 		}
 
-		u.logger.Info("Successfully updated and cached shipment",
+		u.logger.Info("Successfully refreshed and cached shipment",
 			"shipment_id", shipment.ID,
 			"tracking_number", shipment.TrackingNumber,
 			"carrier", shipment.Carrier,
@@ -394,6 +630,8 @@ func (u *TrackingUpdater) performAPICallAndCache(shipment *database.Shipment) {
 			"tracking_number", shipment.TrackingNumber,
 			"carrier", shipment.Carrier)
 	}
+
+	return result, nil
 }
 
 // convertToTrackingEvents converts carrier events to database tracking events
@@ -576,23 +814,30 @@ func (u *TrackingUpdater) processTrackingInfo(shipment *database.Shipment, info
 
 // handleUpdateError records a failed update attempt
 func (u *TrackingUpdater) handleUpdateError(shipment *database.Shipment, err error) {
-	errorMsg := err.Error()
-	if len(errorMsg) > 500 {
-		errorMsg = errorMsg[:500] // Truncate very long error messages
-	}
+	result := u.failedUpdateResult(shipment, err)
 
-	dbErr := u.shipmentStore.UpdateAutoRefreshTracking(int64(shipment.ID), false, errorMsg)
+	dbErr := u.shipmentStore.UpdateAutoRefreshTracking(int64(shipment.ID), false, result.Error)
 	if dbErr != nil {
 		u.logger.Error("Failed to record auto-refresh error",
 			"shipment_id", shipment.ID,
 			"original_error", err,
 			"db_error", dbErr)
 	}
+}
+
+// failedUpdateResult logs a failed update attempt and builds the result recording it
+func (u *TrackingUpdater) failedUpdateResult(shipment *database.Shipment, err error) database.AutoUpdateResult {
+	errorMsg := err.Error()
+	if len(errorMsg) > 500 {
+		errorMsg = errorMsg[:500] // Truncate very long error messages
+	}
 
 	u.logger.Warn("Auto-update failed for shipment",
 		"shipment_id", shipment.ID,
 		"tracking_number", shipment.TrackingNumber,
 		"error", err)
+
+	return database.AutoUpdateResult{ShipmentID: shipment.ID, Success: false, Error: errorMsg}
 }
 
 const (
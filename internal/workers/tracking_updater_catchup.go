@@ -0,0 +1,116 @@
+package workers
+
+import (
+	"time"
+
+	"package-tracking/internal/database"
+)
+
+// needsCatchUp reports whether the gap since the last automatic refresh is large enough
+// to warrant a catch-up pass, along with the detected gap
+func (u *TrackingUpdater) needsCatchUp(now time.Time) (bool, time.Duration) {
+	if !u.config.AutoUpdateCatchUpEnabled {
+		return false, 0
+	}
+
+	lastRefresh, err := u.shipmentStore.GetLastAutoRefreshTime()
+	if err != nil {
+		u.logger.Error("Failed to determine last auto-refresh time", "error", err)
+		return false, 0
+	}
+
+	// A fresh database has never been auto-refreshed, so there is nothing to catch up on
+	if lastRefresh == nil {
+		return false, 0
+	}
+
+	gap := now.Sub(*lastRefresh)
+	threshold := time.Duration(u.config.AutoUpdateCatchUpThreshold) * u.config.UpdateInterval
+	return gap >= threshold, gap
+}
+
+// runCatchUpIfNeeded runs a prioritized catch-up pass when the server has been down for
+// several update intervals. It returns true if a catch-up pass was performed.
+func (u *TrackingUpdater) runCatchUpIfNeeded() bool {
+	if u.paused.Load() {
+		return false
+	}
+
+	needed, gap := u.needsCatchUp(time.Now())
+	if !needed {
+		return false
+	}
+
+	u.logger.Info("Detected auto-update gap, starting catch-up pass",
+		"gap", gap.Truncate(time.Second),
+		"update_interval", u.config.UpdateInterval,
+		"threshold_intervals", u.config.AutoUpdateCatchUpThreshold)
+
+	startTime := time.Now()
+	queue := newRefreshQueue(catchUpLess)
+	queue.Enqueue(u.collectCatchUpShipments()...)
+
+	stats := u.processShipmentsWithCache(queue, u.config.AutoUpdateCatchUpDelay)
+	u.markRun(u.enabledCarriers(), time.Now())
+
+	u.logger.Info("Catch-up pass completed",
+		"gap", gap.Truncate(time.Second),
+		"shipments", stats.Total,
+		"api_calls_made", stats.APICalls,
+		"cache_hits", stats.CacheHits,
+		"rate_limited", stats.RateLimited,
+		"deferred_for_budget", stats.Deferred,
+		"failures", stats.Failures,
+		"duration", time.Since(startTime))
+
+	return true
+}
+
+// collectCatchUpShipments gathers eligible shipments from every carrier that has auto-updates enabled
+func (u *TrackingUpdater) collectCatchUpShipments() []database.Shipment {
+	carrierCutoffs := map[string]int{
+		"usps": u.config.AutoUpdateCutoffDays,
+	}
+	if u.config.UPSAutoUpdateEnabled {
+		carrierCutoffs["ups"] = u.config.UPSAutoUpdateCutoffDays
+	}
+	if u.config.DHLAutoUpdateEnabled {
+		carrierCutoffs["dhl"] = u.config.DHLAutoUpdateCutoffDays
+	}
+
+	var all []database.Shipment
+	for carrier, cutoffDays := range carrierCutoffs {
+		// Fall back to the global cutoff when no carrier-specific value is set
+		if cutoffDays == 0 {
+			cutoffDays = u.config.AutoUpdateCutoffDays
+		}
+		cutoffDate := time.Now().AddDate(0, 0, -cutoffDays)
+
+		shipments, err := u.shipmentStore.GetActiveForAutoUpdate(carrier, cutoffDate, u.config.AutoUpdateFailureThreshold)
+		if err != nil {
+			u.logger.Error("Failed to fetch shipments for catch-up",
+				"carrier", carrier,
+				"error", err)
+			continue
+		}
+
+		all = append(all, shipments...)
+	}
+
+	return all
+}
+
+// catchUpLess orders shipments so that the soonest expected deliveries are refreshed first.
+// Shipments without an expected delivery date are processed last, newest first.
+func catchUpLess(a, b *database.Shipment) bool {
+	switch {
+	case a.ExpectedDelivery != nil && b.ExpectedDelivery != nil:
+		return a.ExpectedDelivery.Before(*b.ExpectedDelivery)
+	case a.ExpectedDelivery != nil:
+		return true
+	case b.ExpectedDelivery != nil:
+		return false
+	default:
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+}
@@ -0,0 +1,241 @@
+package workers
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"package-tracking/internal/carriers"
+)
+
+const (
+	// carrierBackoffBase is the initial backoff applied after a carrier rate limits us
+	carrierBackoffBase = 1 * time.Minute
+	// carrierBackoffMax caps the exponential carrier backoff
+	carrierBackoffMax = 1 * time.Hour
+)
+
+// CarrierStatus describes the auto-update state of a single carrier
+type CarrierStatus struct {
+	Carrier            string     `json:"carrier"`
+	Enabled            bool       `json:"enabled"`
+	LastRun            *time.Time `json:"last_run,omitempty"`
+	NextRun            *time.Time `json:"next_run,omitempty"`
+	ShipmentsProcessed int        `json:"shipments_processed"`
+	SuccessCount       int        `json:"success_count"`
+	ErrorCount         int        `json:"error_count"`
+	CacheHits          int        `json:"cache_hits"`
+	LastError          string     `json:"last_error,omitempty"`
+	InBackoff          bool       `json:"in_backoff"`
+	BackoffUntil       *time.Time `json:"backoff_until,omitempty"`
+	ConsecutiveErrors  int        `json:"consecutive_rate_limits"`
+	DailyBudget        int        `json:"daily_budget,omitempty"`
+	CallsToday         int        `json:"calls_today"`
+	CycleAllowance     *int       `json:"cycle_allowance,omitempty"`
+}
+
+// UpdaterStatus is a snapshot of the tracking updater state
+type UpdaterStatus struct {
+	Running  bool            `json:"running"`
+	Paused   bool            `json:"paused"`
+	LastRun  *time.Time      `json:"last_run,omitempty"`
+	NextRun  *time.Time      `json:"next_run,omitempty"`
+	Carriers []CarrierStatus `json:"carriers"`
+}
+
+// carrierState holds the mutable per-carrier counters behind CarrierStatus
+type carrierState struct {
+	lastRun            *time.Time
+	shipmentsProcessed int
+	successCount       int
+	errorCount         int
+	cacheHits          int
+	lastError          string
+	backoffUntil       *time.Time
+	rateLimitStreak    int
+}
+
+// Status returns a snapshot of the updater state including per-carrier detail
+func (u *TrackingUpdater) Status() UpdaterStatus {
+	now := time.Now()
+
+	// Read the usage ledger before taking statusMu so a slow query doesn't block workers
+	callsToday := make(map[string]int)
+	for carrier := range u.autoUpdateCarriers() {
+		calls, err := u.quotaStore.GetUsage(carrier, now)
+		if err != nil {
+			u.logger.Warn("Failed to read API usage ledger for status", "carrier", carrier, "error", err)
+			continue
+		}
+		callsToday[carrier] = calls
+	}
+
+	u.statusMu.Lock()
+	defer u.statusMu.Unlock()
+
+	status := UpdaterStatus{
+		Running:  u.IsRunning(),
+		Paused:   u.IsPaused(),
+		LastRun:  copyTime(u.lastRun),
+		NextRun:  copyTime(u.nextRun),
+		Carriers: make([]CarrierStatus, 0, len(u.autoUpdateCarriers())),
+	}
+
+	for carrier, enabled := range u.autoUpdateCarriers() {
+		state := u.carrierStateLocked(carrier)
+		carrierStatus := CarrierStatus{
+			Carrier:            carrier,
+			Enabled:            enabled && u.config.AutoUpdateEnabled,
+			LastRun:            copyTime(state.lastRun),
+			ShipmentsProcessed: state.shipmentsProcessed,
+			SuccessCount:       state.successCount,
+			ErrorCount:         state.errorCount,
+			CacheHits:          state.cacheHits,
+			LastError:          state.lastError,
+			ConsecutiveErrors:  state.rateLimitStreak,
+			DailyBudget:        u.config.DailyAPIBudgetFor(carrier),
+			CallsToday:         callsToday[carrier],
+		}
+
+		if carrierStatus.DailyBudget > 0 {
+			allowance := cycleAllowance(carrierStatus.DailyBudget, carrierStatus.CallsToday, now, u.config.UpdateInterval)
+			carrierStatus.CycleAllowance = &allowance
+		}
+
+		if state.backoffUntil != nil && now.Before(*state.backoffUntil) {
+			carrierStatus.InBackoff = true
+			carrierStatus.BackoffUntil = copyTime(state.backoffUntil)
+		}
+
+		// The next run for a carrier is the next cycle, unless it is still backing off then
+		if carrierStatus.Enabled && u.nextRun != nil {
+			nextRun := *u.nextRun
+			if carrierStatus.InBackoff && state.backoffUntil.After(nextRun) {
+				nextRun = *state.backoffUntil
+			}
+			carrierStatus.NextRun = &nextRun
+		}
+
+		status.Carriers = append(status.Carriers, carrierStatus)
+	}
+
+	sort.Slice(status.Carriers, func(i, j int) bool {
+		return status.Carriers[i].Carrier < status.Carriers[j].Carrier
+	})
+
+	return status
+}
+
+// autoUpdateCarriers returns the carriers handled by the updater and whether each is enabled
+func (u *TrackingUpdater) autoUpdateCarriers() map[string]bool {
+	return map[string]bool{
+		"usps": true,
+		"ups":  u.config.UPSAutoUpdateEnabled,
+		"dhl":  u.config.DHLAutoUpdateEnabled,
+	}
+}
+
+// enabledCarriers returns the carriers that take part in auto-update cycles
+func (u *TrackingUpdater) enabledCarriers() []string {
+	var enabled []string
+	for carrier, on := range u.autoUpdateCarriers() {
+		if on {
+			enabled = append(enabled, carrier)
+		}
+	}
+	return enabled
+}
+
+// carrierStateLocked returns the state for a carrier, creating it if needed. statusMu must be held.
+func (u *TrackingUpdater) carrierStateLocked(carrier string) *carrierState {
+	state, ok := u.carrierStates[carrier]
+	if !ok {
+		state = &carrierState{}
+		u.carrierStates[carrier] = state
+	}
+	return state
+}
+
+// recordCacheHit records a shipment that was satisfied from the refresh cache
+func (u *TrackingUpdater) recordCacheHit(carrier string) {
+	u.statusMu.Lock()
+	defer u.statusMu.Unlock()
+
+	state := u.carrierStateLocked(carrier)
+	state.shipmentsProcessed++
+	state.successCount++
+	state.cacheHits++
+}
+
+// recordResult records the outcome of a carrier API call and manages rate limit backoff
+func (u *TrackingUpdater) recordResult(carrier string, err error, now time.Time) {
+	u.statusMu.Lock()
+	defer u.statusMu.Unlock()
+
+	state := u.carrierStateLocked(carrier)
+	state.shipmentsProcessed++
+
+	if err == nil {
+		state.successCount++
+		state.rateLimitStreak = 0
+		state.backoffUntil = nil
+		return
+	}
+
+	state.errorCount++
+	state.lastError = err.Error()
+
+	var carrierErr *carriers.CarrierError
+	if errors.As(err, &carrierErr) && carrierErr.RateLimit {
+		state.rateLimitStreak++
+		backoff := carrierBackoffBase << (state.rateLimitStreak - 1)
+		if backoff <= 0 || backoff > carrierBackoffMax {
+			backoff = carrierBackoffMax
+		}
+		until := now.Add(backoff)
+		state.backoffUntil = &until
+
+		u.logger.Warn("Carrier rate limited auto-updates, backing off",
+			"carrier", carrier,
+			"backoff", backoff,
+			"backoff_until", until)
+	}
+}
+
+// inBackoff reports whether auto-updates for a carrier are currently backing off
+func (u *TrackingUpdater) inBackoff(carrier string, now time.Time) bool {
+	u.statusMu.Lock()
+	defer u.statusMu.Unlock()
+
+	state := u.carrierStateLocked(carrier)
+	return state.backoffUntil != nil && now.Before(*state.backoffUntil)
+}
+
+// markRun records the completion of an update cycle for the given carriers
+func (u *TrackingUpdater) markRun(carriers []string, finishedAt time.Time) {
+	u.statusMu.Lock()
+	defer u.statusMu.Unlock()
+
+	u.lastRun = &finishedAt
+	for _, carrier := range carriers {
+		runAt := finishedAt
+		u.carrierStateLocked(carrier).lastRun = &runAt
+	}
+}
+
+// setNextRun records when the next update cycle is scheduled
+func (u *TrackingUpdater) setNextRun(next time.Time) {
+	u.statusMu.Lock()
+	defer u.statusMu.Unlock()
+
+	u.nextRun = &next
+}
+
+// copyTime returns a copy of a time pointer so snapshots don't share state
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	return &copied
+}
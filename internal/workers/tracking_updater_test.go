@@ -1,8 +1,11 @@
 package workers
 
 import (
+	"context"
+	"io"
 	"log/slog"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -10,6 +13,7 @@ import (
 	"package-tracking/internal/carriers"
 	"package-tracking/internal/config"
 	"package-tracking/internal/database"
+	"package-tracking/internal/notifications"
 	"package-tracking/internal/ratelimit"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -93,7 +97,7 @@ func setupTestTrackingUpdater(t *testing.T, cfg *config.Config, db *database.DB)
 	factory := carriers.NewClientFactory()
 	cacheManager := cache.NewManager(db.RefreshCache, false, 5*time.Minute)
 	
-	return NewTrackingUpdater(cfg, db.Shipments, factory, cacheManager, logger)
+	return NewTrackingUpdater(cfg, db.Shipments, db.Quota, factory, cacheManager, logger)
 }
 
 func TestTrackingUpdater_UnifiedRateLimiting(t *testing.T) {
@@ -682,4 +686,229 @@ func TestTrackingUpdater_DHLRateLimitWarning(t *testing.T) {
 	}
 	
 	t.Logf("DHL rate limit warning threshold: %d calls (%.1f%% of 250)", expectedWarningThreshold, DHLRateLimitWarningThreshold)
-}
\ No newline at end of file
+}
+func TestTrackingUpdater_CatchUpOrdering(t *testing.T) {
+	now := time.Now()
+	today := now.Add(2 * time.Hour)
+	tomorrow := now.Add(26 * time.Hour)
+
+	shipments := []database.Shipment{
+		{ID: 1, CreatedAt: now.Add(-48 * time.Hour)},
+		{ID: 2, ExpectedDelivery: &tomorrow},
+		{ID: 3, CreatedAt: now.Add(-1 * time.Hour)},
+		{ID: 4, ExpectedDelivery: &today},
+	}
+
+	queue := newRefreshQueue(catchUpLess)
+	queue.Enqueue(shipments...)
+
+	expectedOrder := []int{4, 2, 3, 1}
+	for i, id := range expectedOrder {
+		next, ok := queue.Next()
+		if !ok {
+			t.Fatalf("Queue exhausted at position %d", i)
+		}
+		if next.ID != id {
+			t.Errorf("Position %d: expected shipment %d, got %d", i, id, next.ID)
+		}
+	}
+}
+
+func TestTrackingUpdater_NeedsCatchUp(t *testing.T) {
+	cfg := getTestConfig()
+	cfg.UpdateInterval = time.Hour
+	cfg.AutoUpdateCatchUpEnabled = true
+	cfg.AutoUpdateCatchUpThreshold = 3
+
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	updater := setupTestTrackingUpdater(t, cfg, db)
+	defer updater.Stop()
+
+	// A database that was never auto-refreshed should not trigger a catch-up
+	if needed, _ := updater.needsCatchUp(time.Now()); needed {
+		t.Error("Expected no catch-up for a database without auto-refresh history")
+	}
+
+	shipment := createTestShipment(t, db, "CATCHUP123", nil)
+	lastRefresh := time.Now().Add(-30 * time.Minute).UTC()
+	shipment.LastAutoRefresh = &lastRefresh
+	if err := db.Shipments.Update(shipment.ID, shipment); err != nil {
+		t.Fatalf("Failed to set last auto refresh: %v", err)
+	}
+
+	// Gap smaller than the threshold
+	if needed, _ := updater.needsCatchUp(time.Now()); needed {
+		t.Error("Expected no catch-up for a gap below the threshold")
+	}
+
+	// Gap of several intervals
+	if needed, gap := updater.needsCatchUp(time.Now().Add(4 * time.Hour)); !needed {
+		t.Errorf("Expected catch-up for a gap of %v", gap)
+	}
+
+	// Disabled via configuration
+	cfg.AutoUpdateCatchUpEnabled = false
+	if needed, _ := updater.needsCatchUp(time.Now().Add(4 * time.Hour)); needed {
+		t.Error("Expected no catch-up when catch-up mode is disabled")
+	}
+}
+
+func TestTrackingUpdater_StatusCarrierBackoff(t *testing.T) {
+	cfg := getTestConfig()
+	cfg.UpdateInterval = time.Hour
+	cfg.DHLAutoUpdateEnabled = false
+
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	updater := setupTestTrackingUpdater(t, cfg, db)
+	defer updater.Stop()
+
+	now := time.Now()
+	updater.recordResult("usps", nil, now)
+	updater.recordCacheHit("usps")
+	updater.recordResult("ups", &carriers.CarrierError{Carrier: "ups", Message: "too many requests", RateLimit: true}, now)
+	updater.markRun(updater.enabledCarriers(), now)
+	updater.setNextRun(now.Add(cfg.UpdateInterval))
+
+	status := updater.Status()
+	if !status.Running || status.Paused {
+		t.Errorf("Expected running and not paused, got running=%v paused=%v", status.Running, status.Paused)
+	}
+	if len(status.Carriers) != 3 {
+		t.Fatalf("Expected 3 carriers in status, got %d", len(status.Carriers))
+	}
+
+	byCarrier := make(map[string]CarrierStatus)
+	for _, c := range status.Carriers {
+		byCarrier[c.Carrier] = c
+	}
+
+	usps := byCarrier["usps"]
+	if usps.ShipmentsProcessed != 2 || usps.SuccessCount != 2 || usps.CacheHits != 1 || usps.ErrorCount != 0 {
+		t.Errorf("Unexpected USPS counters: %+v", usps)
+	}
+	if usps.LastRun == nil || usps.NextRun == nil {
+		t.Error("Expected USPS last and next run to be set")
+	}
+
+	ups := byCarrier["ups"]
+	if !ups.InBackoff || ups.BackoffUntil == nil || ups.ErrorCount != 1 {
+		t.Errorf("Expected UPS to be backing off after a rate limit, got %+v", ups)
+	}
+	if ups.NextRun == nil || !ups.NextRun.Equal(*status.NextRun) {
+		t.Errorf("Expected UPS next run to match the next cycle when backoff expires first, got %v", ups.NextRun)
+	}
+	if !updater.inBackoff("ups", now) {
+		t.Error("Expected UPS shipments to be skipped during backoff")
+	}
+
+	dhl := byCarrier["dhl"]
+	if dhl.Enabled || dhl.LastRun != nil || dhl.NextRun != nil {
+		t.Errorf("Expected disabled DHL to have no run information, got %+v", dhl)
+	}
+
+	// A successful call clears the backoff
+	updater.recordResult("ups", nil, now)
+	if updater.inBackoff("ups", now) {
+		t.Error("Expected UPS backoff to be cleared after a successful call")
+	}
+}
+
+func TestTrackingUpdater_StopDrainReport(t *testing.T) {
+	cfg := getTestConfig()
+	cfg.UpdateInterval = time.Hour
+
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	updater := setupTestTrackingUpdater(t, cfg, db)
+
+	var queued []database.Shipment
+	for _, number := range []string{"DRAIN1", "DRAIN2", "DRAIN3"} {
+		queued = append(queued, *createTestShipment(t, db, number, nil))
+	}
+
+	// Stop before the queue is processed; nothing is in flight so everything is re-queued
+	report := updater.StopWithTimeout(time.Second)
+	if report.TimedOut {
+		t.Error("Expected drain not to time out when no update loop is running")
+	}
+
+	queue := newRefreshQueue(refreshPriorityLess(time.Now(), cfg.UpdateInterval))
+	queue.Enqueue(queued...)
+	stats := updater.processShipmentsWithCache(queue, 0)
+	if stats.APICalls != 0 {
+		t.Errorf("Expected no API calls after stop, got %d", stats.APICalls)
+	}
+
+	drained := updater.drain.snapshot()
+	if drained.Requeued != 3 || drained.Completed != 0 || drained.Abandoned != 0 {
+		t.Errorf("Expected 3 re-queued shipments, got %+v", drained)
+	}
+
+	// Stopping again returns the original report
+	if again := updater.StopWithTimeout(time.Second); again != report {
+		t.Errorf("Expected repeated stop to return the first report, got %+v", again)
+	}
+}
+
+// recordingNotifier records the transitions and digests it is sent
+type recordingNotifier struct {
+	mu          sync.Mutex
+	transitions []notifications.Transition
+	subjects    []string
+	digests     []notifications.Digest
+}
+
+func (n *recordingNotifier) Name() string { return "recording" }
+
+func (n *recordingNotifier) Send(ctx context.Context, msg notifications.Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if msg.Digest != nil {
+		n.digests = append(n.digests, *msg.Digest)
+		return nil
+	}
+	n.transitions = append(n.transitions, msg.Transition)
+	n.subjects = append(n.subjects, msg.Subject)
+	return nil
+}
+
+func TestTrackingUpdater_NotifiesStatusChanges(t *testing.T) {
+	cfg := getTestConfig()
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	updater := setupTestTrackingUpdater(t, cfg, db)
+	notifier := &recordingNotifier{}
+	dispatcher := notifications.NewDispatcher(notifications.RetryPolicy{MaxAttempts: 1}, 10, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := dispatcher.AddChannel(notifier, notifications.ChannelConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	dispatcher.Start()
+	updater.SetNotifier(dispatcher)
+
+	changed := createTestShipment(t, db, "NOTIFY1", nil)
+	unchanged := createTestShipment(t, db, "NOTIFY2", nil)
+	changed.Status = "delivered"
+	changed.IsDelivered = true
+
+	updater.applyAutoUpdateBatch([]database.AutoUpdateResult{
+		{ShipmentID: changed.ID, Shipment: changed, PreviousStatus: "pending", Success: true},
+		{ShipmentID: unchanged.ID, Shipment: unchanged, PreviousStatus: "pending", Success: true},
+		{ShipmentID: unchanged.ID, Success: true}, // Bookkeeping only
+	})
+	dispatcher.Stop()
+
+	if len(notifier.transitions) != 1 {
+		t.Fatalf("Expected one status change notification, got %d", len(notifier.transitions))
+	}
+	transition := notifier.transitions[0]
+	if transition.Shipment.ID != changed.ID || transition.FromStatus != "pending" || transition.ToStatus != "delivered" ||
+		transition.Source != notifications.SourceAutoUpdate {
+		t.Errorf("Unexpected transition %+v", transition)
+	}
+}
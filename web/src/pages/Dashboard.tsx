@@ -1,4 +1,4 @@
-import { Package, Truck, CheckCircle, AlertTriangle, Plus, Clock, MapPin } from 'lucide-react';
+import { Package, Truck, CheckCircle, AlertTriangle, Plus, Clock, MapPin, Hourglass } from 'lucide-react';
 import { useDashboardStats, useShipments } from '../hooks/api';
 import { Button } from '@/components/ui/button';
 import { Card, CardContent, CardHeader, CardTitle } from '@/components/ui/card';
@@ -90,7 +90,7 @@ export function Dashboard() {
       </div>
 
       {/* Stats Grid */}
-      <div className="grid gap-4 md:grid-cols-2 lg:grid-cols-4">
+      <div className="grid gap-4 md:grid-cols-2 lg:grid-cols-5">
         <StatCard
           title="Total Shipments"
           value={stats?.total_shipments || 0}
@@ -118,6 +118,13 @@ export function Dashboard() {
           description="Issues or exceptions"
           loading={statsLoading}
         />
+        <StatCard
+          title="Stalled"
+          value={stats?.stalled || 0}
+          icon={Hourglass}
+          description="No recent tracking activity"
+          loading={statsLoading}
+        />
       </div>
 
       {/* Recent Shipments */}
@@ -12,6 +12,8 @@ export interface Shipment {
   is_delivered: boolean;
   last_manual_refresh?: string;
   manual_refresh_count: number;
+  is_stalled?: boolean;
+  stalled_since?: string;
 }
 
 export interface TrackingEvent {
@@ -70,6 +72,7 @@ export interface DashboardStats {
   in_transit: number;
   delivered: number;
   requiring_attention: number;
+  stalled: number;
 }
 
 // Shipment status types